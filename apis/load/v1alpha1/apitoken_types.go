@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// An APITokenScope grants an API token access to part of the StormForge API.
+// +kubebuilder:validation:Enum=read;launch;write;admin
+type APITokenScope string
+
+// Scopes of an API token.
+const (
+	// APITokenScopeRead grants read access to test cases and their runs.
+	APITokenScopeRead APITokenScope = "read"
+
+	// APITokenScopeLaunch grants access to launch and abort test runs.
+	APITokenScopeLaunch APITokenScope = "launch"
+
+	// APITokenScopeWrite grants access to create, update and delete test
+	// cases and the resources they use.
+	APITokenScopeWrite APITokenScope = "write"
+
+	// APITokenScopeAdmin grants access to manage the organization.
+	APITokenScopeAdmin APITokenScope = "admin"
+)
+
+// APITokenParameters are the configurable fields of an APIToken.
+type APITokenParameters struct {
+	// Org is the StormForge organization the token belongs to. It cannot be
+	// changed once the token has been created.
+	// +kubebuilder:validation:MinLength=1
+	Org string `json:"org"`
+
+	// Name of the token, for example the name of the CI system it is issued
+	// to.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Scopes the token grants access to.
+	// +kubebuilder:validation:MinItems=1
+	Scopes []APITokenScope `json:"scopes"`
+
+	// ExpiresAt is the time at which the token expires. Tokens without an
+	// expiry never expire. It cannot be changed once the token has been
+	// created.
+	// +optional
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+}
+
+// APITokenObservation are the observable fields of an APIToken.
+type APITokenObservation struct {
+	// ID of the token in StormForge.
+	ID string `json:"id,omitempty"`
+
+	// Org the token belongs to.
+	Org string `json:"org,omitempty"`
+
+	// CreatedAt is the time at which the token was created.
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// ExpiresAt is the time at which the token expires, if it does.
+	ExpiresAt *metav1.Time `json:"expiresAt,omitempty"`
+
+	// LastUsedAt is the time at which the token was last used, if it has
+	// been.
+	LastUsedAt *metav1.Time `json:"lastUsedAt,omitempty"`
+}
+
+// An APITokenSpec defines the desired state of an APIToken.
+type APITokenSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       APITokenParameters `json:"forProvider"`
+}
+
+// An APITokenStatus represents the observed state of an APIToken.
+type APITokenStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          APITokenObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An APIToken is a StormForge service credential. The token is written to the
+// connection secret of the APIToken under the key "token" when it is created;
+// StormForge never returns it again, so it cannot be recovered if the secret
+// is lost. Its external name is the ID of the token.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ORG",type="string",JSONPath=".spec.forProvider.org"
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".spec.forProvider.name"
+// +kubebuilder:printcolumn:name="EXPIRES",type="date",JSONPath=".status.atProvider.expiresAt"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type APIToken struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   APITokenSpec   `json:"spec"`
+	Status APITokenStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// APITokenList contains a list of APIToken
+type APITokenList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []APIToken `json:"items"`
+}
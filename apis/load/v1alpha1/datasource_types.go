@@ -0,0 +1,106 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// DataSourceParameters are the configurable fields of a DataSource. Exactly
+// one source should be set.
+type DataSourceParameters struct {
+	// Org is the StormForge organization the data source belongs to. It
+	// cannot be changed once the data source has been uploaded.
+	// +kubebuilder:validation:MinLength=1
+	Org string `json:"org"`
+
+	// Name of the data source, for example users.csv. Test case scripts refer
+	// to the data source by name. Changing it renames the data source.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9_.-]*$`
+	Name string `json:"name"`
+
+	// ConfigMapRef references a key of a ConfigMap containing the file.
+	// +optional
+	ConfigMapRef *ConfigMapKeySelector `json:"configMapRef,omitempty"`
+
+	// SecretRef references a key of a Secret containing the file.
+	// +optional
+	SecretRef *xpv1.SecretKeySelector `json:"secretRef,omitempty"`
+}
+
+// DataSourceObservation are the observable fields of a DataSource.
+type DataSourceObservation struct {
+	// ID of the data source in StormForge.
+	ID string `json:"id,omitempty"`
+
+	// Org the data source belongs to.
+	Org string `json:"org,omitempty"`
+
+	// Name of the data source in StormForge.
+	Name string `json:"name,omitempty"`
+
+	// Checksum is the SHA-256 checksum of the content last uploaded. The
+	// content is uploaded again whenever its checksum changes.
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// A DataSourceSpec defines the desired state of a DataSource.
+type DataSourceSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       DataSourceParameters `json:"forProvider"`
+}
+
+// A DataSourceStatus represents the observed state of a DataSource.
+type DataSourceStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          DataSourceObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A DataSource is a file, such as a CSV of user credentials, uploaded to a
+// StormForge organization for its test cases to draw data from. Unlike the
+// data sources of a TestCase, a DataSource may be shared by many test cases.
+// Its external name is the ID of the data source.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ORG",type="string",JSONPath=".spec.forProvider.org"
+// +kubebuilder:printcolumn:name="NAME",type="string",JSONPath=".spec.forProvider.name"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type DataSource struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   DataSourceSpec   `json:"spec"`
+	Status DataSourceStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// DataSourceList contains a list of DataSource
+type DataSourceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []DataSource `json:"items"`
+}
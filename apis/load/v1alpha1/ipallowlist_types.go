@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// IPAllowlistParameters are the configurable fields of an IPAllowlist.
+type IPAllowlistParameters struct {
+	// Regions whose load generator IP ranges are observed, for example
+	// eu-central-1. The ranges of every region are observed if it is
+	// omitted.
+	// +optional
+	Regions []string `json:"regions,omitempty"`
+}
+
+// An IPAllowlistRegion is the set of IP ranges of the load generators of a
+// region.
+type IPAllowlistRegion struct {
+	// Name of the region.
+	Name string `json:"name"`
+
+	// CIDRs of the IP ranges of the region.
+	CIDRs []string `json:"cidrs"`
+}
+
+// IPAllowlistObservation are the observable fields of an IPAllowlist.
+type IPAllowlistObservation struct {
+	// CIDRs of the IP ranges of all observed regions.
+	CIDRs []string `json:"cidrs,omitempty"`
+
+	// Regions and their IP ranges.
+	Regions []IPAllowlistRegion `json:"regions,omitempty"`
+}
+
+// An IPAllowlistSpec defines the desired state of an IPAllowlist.
+type IPAllowlistSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       IPAllowlistParameters `json:"forProvider,omitempty"`
+}
+
+// An IPAllowlistStatus represents the observed state of an IPAllowlist.
+type IPAllowlistStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          IPAllowlistObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An IPAllowlist observes the IP ranges StormForge load generators send
+// requests from, so that firewalls can allow them. The ranges are also
+// written to the connection secret of the IPAllowlist, one CIDR per line:
+// those of all observed regions under the key "cidrs", and those of each
+// region under the key "cidrs.<region>". Its external name is not used.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="CIDRS",type="string",JSONPath=".status.atProvider.cidrs"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type IPAllowlist struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   IPAllowlistSpec   `json:"spec"`
+	Status IPAllowlistStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// IPAllowlistList contains a list of IPAllowlist
+type IPAllowlistList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []IPAllowlist `json:"items"`
+}
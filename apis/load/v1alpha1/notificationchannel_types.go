@@ -0,0 +1,161 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A NotificationEvent is a lifecycle event of a test run.
+// +kubebuilder:validation:Enum=TestRunStarted;TestRunFinished;TestRunFailed;TestRunAborted
+type NotificationEvent string
+
+// Lifecycle events of a test run.
+const (
+	// NotificationTestRunStarted is sent when a run starts generating load.
+	NotificationTestRunStarted NotificationEvent = "TestRunStarted"
+
+	// NotificationTestRunFinished is sent when a run has finished.
+	NotificationTestRunFinished NotificationEvent = "TestRunFinished"
+
+	// NotificationTestRunFailed is sent when a run could not be completed.
+	NotificationTestRunFailed NotificationEvent = "TestRunFailed"
+
+	// NotificationTestRunAborted is sent when a run was aborted.
+	NotificationTestRunAborted NotificationEvent = "TestRunAborted"
+)
+
+// NotificationChannelParameters are the configurable fields of a
+// NotificationChannel. Exactly one of Email, Slack and Webhook should be set.
+type NotificationChannelParameters struct {
+	// Org is the StormForge organization whose test runs notify the channel.
+	// It cannot be changed once the channel has been created.
+	// +kubebuilder:validation:MinLength=1
+	Org string `json:"org"`
+
+	// Name of the channel. It must be unique within its organization.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	Name string `json:"name"`
+
+	// Events the channel is notified of.
+	// +kubebuilder:validation:MinItems=1
+	Events []NotificationEvent `json:"events"`
+
+	// Email notifies a list of email addresses.
+	// +optional
+	Email *EmailNotification `json:"email,omitempty"`
+
+	// Slack notifies a Slack channel using an incoming webhook.
+	// +optional
+	Slack *SlackNotification `json:"slack,omitempty"`
+
+	// Webhook sends each event as a JSON document to a URL.
+	// +optional
+	Webhook *WebhookNotification `json:"webhook,omitempty"`
+}
+
+// An EmailNotification notifies a list of email addresses.
+type EmailNotification struct {
+	// Recipients are the email addresses notified.
+	// +kubebuilder:validation:MinItems=1
+	Recipients []string `json:"recipients"`
+}
+
+// A SlackNotification notifies a Slack channel using an incoming webhook.
+type SlackNotification struct {
+	// WebhookURLSecretRef references a key of a Secret containing the URL of
+	// the incoming webhook.
+	WebhookURLSecretRef xpv1.SecretKeySelector `json:"webhookURLSecretRef"`
+
+	// Channel overrides the Slack channel the incoming webhook posts to, for
+	// example #load-tests.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+}
+
+// A WebhookNotification sends each event as a JSON document to a URL. Exactly
+// one of URL and URLSecretRef should be set.
+type WebhookNotification struct {
+	// URL the events are sent to.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// URLSecretRef references a key of a Secret containing the URL the events
+	// are sent to, for URLs that embed credentials.
+	// +optional
+	URLSecretRef *xpv1.SecretKeySelector `json:"urlSecretRef,omitempty"`
+}
+
+// NotificationChannelObservation are the observable fields of a
+// NotificationChannel.
+type NotificationChannelObservation struct {
+	// ID of the channel in StormForge.
+	ID string `json:"id,omitempty"`
+
+	// Org the channel belongs to.
+	Org string `json:"org,omitempty"`
+
+	// Type of the channel; email, slack or webhook.
+	Type string `json:"type,omitempty"`
+}
+
+// A NotificationChannelSpec defines the desired state of a
+// NotificationChannel.
+type NotificationChannelSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       NotificationChannelParameters `json:"forProvider"`
+}
+
+// A NotificationChannelStatus represents the observed state of a
+// NotificationChannel.
+type NotificationChannelStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          NotificationChannelObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A NotificationChannel is notified of the lifecycle events of the test runs
+// of a StormForge organization, by email, Slack or webhook. Its external name
+// is the ID of the channel.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ORG",type="string",JSONPath=".spec.forProvider.org"
+// +kubebuilder:printcolumn:name="TYPE",type="string",JSONPath=".status.atProvider.type"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type NotificationChannel struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   NotificationChannelSpec   `json:"spec"`
+	Status NotificationChannelStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotificationChannelList contains a list of NotificationChannel
+type NotificationChannelList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationChannel `json:"items"`
+}
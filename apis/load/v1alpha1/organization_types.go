@@ -0,0 +1,102 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// OrganizationObservation are the observable fields of an Organization.
+type OrganizationObservation struct {
+	// ID of the organization.
+	ID string `json:"id,omitempty"`
+
+	// Name of the organization.
+	Name string `json:"name,omitempty"`
+
+	// Plan the organization is subscribed to.
+	Plan string `json:"plan,omitempty"`
+
+	// MemberCount is the number of users that are members of the
+	// organization.
+	MemberCount int64 `json:"memberCount,omitempty"`
+
+	// Limits of the organization's plan.
+	Limits OrganizationLimits `json:"limits,omitempty"`
+}
+
+// OrganizationLimits are the entitlements of an organization's plan. An unset
+// limit is unlimited.
+type OrganizationLimits struct {
+	// TestCases is the number of test cases the organization may have.
+	TestCases *int64 `json:"testCases,omitempty"`
+
+	// ConcurrentTestRuns is the number of runs the organization may have
+	// generating load at once.
+	ConcurrentTestRuns *int64 `json:"concurrentTestRuns,omitempty"`
+
+	// ArrivalRate is the highest rate, in clients per second, at which a run
+	// may launch clients.
+	ArrivalRate *int64 `json:"arrivalRate,omitempty"`
+
+	// TestDuration is the longest a run may last.
+	TestDuration *metav1.Duration `json:"testDuration,omitempty"`
+}
+
+// An OrganizationSpec defines the desired state of an Organization.
+// Organizations are only observed, so they have no configurable fields.
+type OrganizationSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+}
+
+// An OrganizationStatus represents the observed state of an Organization.
+type OrganizationStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          OrganizationObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An Organization is a StormForge organization. Organizations cannot be
+// created or deleted using Crossplane; an Organization observes the existing
+// organization whose ID is its external name, which defaults to its name.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="PLAN",type="string",JSONPath=".status.atProvider.plan"
+// +kubebuilder:printcolumn:name="MEMBERS",type="integer",JSONPath=".status.atProvider.memberCount"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type Organization struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   OrganizationSpec   `json:"spec"`
+	Status OrganizationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// OrganizationList contains a list of Organization
+type OrganizationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Organization `json:"items"`
+}
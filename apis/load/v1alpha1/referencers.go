@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reference"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// ProjectName extracts the name of the StormForge project of a Project.
+func ProjectName() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		p, ok := mg.(*Project)
+		if !ok {
+			return ""
+		}
+		return p.Spec.ForProvider.Name
+	}
+}
+
+// ResourceName extracts the name of a managed resource, for example of the
+// TestCase a TestRun is launched from.
+func ResourceName() reference.ExtractValueFn {
+	return func(mg resource.Managed) string {
+		return mg.GetName()
+	}
+}
+
+// ResolveReferences of this TestCase.
+func (mg *TestCase) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Org,
+		Reference:    mg.Spec.ForProvider.OrgRef,
+		Selector:     mg.Spec.ForProvider.OrgSelector,
+		To:           reference.To{Managed: &Organization{}, List: &OrganizationList{}},
+		Extract:      reference.ExternalName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.org")
+	}
+	mg.Spec.ForProvider.Org = rsp.ResolvedValue
+	mg.Spec.ForProvider.OrgRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.Project,
+		Reference:    mg.Spec.ForProvider.ProjectRef,
+		Selector:     mg.Spec.ForProvider.ProjectSelector,
+		To:           reference.To{Managed: &Project{}, List: &ProjectList{}},
+		Extract:      ProjectName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.project")
+	}
+	mg.Spec.ForProvider.Project = rsp.ResolvedValue
+	mg.Spec.ForProvider.ProjectRef = rsp.ResolvedReference
+
+	rsp, err = r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.CloneFrom,
+		Reference:    mg.Spec.ForProvider.CloneFromRef,
+		Selector:     mg.Spec.ForProvider.CloneFromSelector,
+		To:           reference.To{Managed: &TestCase{}, List: &TestCaseList{}},
+		Extract:      ResourceName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.cloneFrom")
+	}
+	mg.Spec.ForProvider.CloneFrom = rsp.ResolvedValue
+	mg.Spec.ForProvider.CloneFromRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this TestRun.
+func (mg *TestRun) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.TestCase,
+		Reference:    mg.Spec.ForProvider.TestCaseRef,
+		Selector:     mg.Spec.ForProvider.TestCaseSelector,
+		To:           reference.To{Managed: &TestCase{}, List: &TestCaseList{}},
+		Extract:      ResourceName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.testCase")
+	}
+	mg.Spec.ForProvider.TestCase = rsp.ResolvedValue
+	mg.Spec.ForProvider.TestCaseRef = rsp.ResolvedReference
+
+	return nil
+}
+
+// ResolveReferences of this ResultExport.
+func (mg *ResultExport) ResolveReferences(ctx context.Context, c client.Reader) error {
+	r := reference.NewAPIResolver(c, mg)
+
+	rsp, err := r.Resolve(ctx, reference.ResolutionRequest{
+		CurrentValue: mg.Spec.ForProvider.TestRun,
+		Reference:    mg.Spec.ForProvider.TestRunRef,
+		Selector:     mg.Spec.ForProvider.TestRunSelector,
+		To:           reference.To{Managed: &TestRun{}, List: &TestRunList{}},
+		Extract:      ResourceName(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "spec.forProvider.testRun")
+	}
+	mg.Spec.ForProvider.TestRun = rsp.ResolvedValue
+	mg.Spec.ForProvider.TestRunRef = rsp.ResolvedReference
+
+	return nil
+}
@@ -45,6 +45,114 @@ var (
 	TestCaseGroupVersionKind = SchemeGroupVersion.WithKind(TestCaseKind)
 )
 
+// TestRun type metadata.
+var (
+	TestRunKind             = reflect.TypeOf(TestRun{}).Name()
+	TestRunGroupKind        = schema.GroupKind{Group: Group, Kind: TestRunKind}.String()
+	TestRunKindAPIVersion   = TestRunKind + "." + SchemeGroupVersion.String()
+	TestRunGroupVersionKind = SchemeGroupVersion.WithKind(TestRunKind)
+)
+
+// TestRunSchedule type metadata.
+var (
+	TestRunScheduleKind             = reflect.TypeOf(TestRunSchedule{}).Name()
+	TestRunScheduleGroupKind        = schema.GroupKind{Group: Group, Kind: TestRunScheduleKind}.String()
+	TestRunScheduleKindAPIVersion   = TestRunScheduleKind + "." + SchemeGroupVersion.String()
+	TestRunScheduleGroupVersionKind = SchemeGroupVersion.WithKind(TestRunScheduleKind)
+)
+
+// Organization type metadata.
+var (
+	OrganizationKind             = reflect.TypeOf(Organization{}).Name()
+	OrganizationGroupKind        = schema.GroupKind{Group: Group, Kind: OrganizationKind}.String()
+	OrganizationKindAPIVersion   = OrganizationKind + "." + SchemeGroupVersion.String()
+	OrganizationGroupVersionKind = SchemeGroupVersion.WithKind(OrganizationKind)
+)
+
+// DataSource type metadata.
+var (
+	DataSourceKind             = reflect.TypeOf(DataSource{}).Name()
+	DataSourceGroupKind        = schema.GroupKind{Group: Group, Kind: DataSourceKind}.String()
+	DataSourceKindAPIVersion   = DataSourceKind + "." + SchemeGroupVersion.String()
+	DataSourceGroupVersionKind = SchemeGroupVersion.WithKind(DataSourceKind)
+)
+
+// NotificationChannel type metadata.
+var (
+	NotificationChannelKind             = reflect.TypeOf(NotificationChannel{}).Name()
+	NotificationChannelGroupKind        = schema.GroupKind{Group: Group, Kind: NotificationChannelKind}.String()
+	NotificationChannelKindAPIVersion   = NotificationChannelKind + "." + SchemeGroupVersion.String()
+	NotificationChannelGroupVersionKind = SchemeGroupVersion.WithKind(NotificationChannelKind)
+)
+
+// SLO type metadata.
+var (
+	SLOKind             = reflect.TypeOf(SLO{}).Name()
+	SLOGroupKind        = schema.GroupKind{Group: Group, Kind: SLOKind}.String()
+	SLOKindAPIVersion   = SLOKind + "." + SchemeGroupVersion.String()
+	SLOGroupVersionKind = SchemeGroupVersion.WithKind(SLOKind)
+)
+
+// APIToken type metadata.
+var (
+	APITokenKind             = reflect.TypeOf(APIToken{}).Name()
+	APITokenGroupKind        = schema.GroupKind{Group: Group, Kind: APITokenKind}.String()
+	APITokenKindAPIVersion   = APITokenKind + "." + SchemeGroupVersion.String()
+	APITokenGroupVersionKind = SchemeGroupVersion.WithKind(APITokenKind)
+)
+
+// Project type metadata.
+var (
+	ProjectKind             = reflect.TypeOf(Project{}).Name()
+	ProjectGroupKind        = schema.GroupKind{Group: Group, Kind: ProjectKind}.String()
+	ProjectKindAPIVersion   = ProjectKind + "." + SchemeGroupVersion.String()
+	ProjectGroupVersionKind = SchemeGroupVersion.WithKind(ProjectKind)
+)
+
+// ResultExport type metadata.
+var (
+	ResultExportKind             = reflect.TypeOf(ResultExport{}).Name()
+	ResultExportGroupKind        = schema.GroupKind{Group: Group, Kind: ResultExportKind}.String()
+	ResultExportKindAPIVersion   = ResultExportKind + "." + SchemeGroupVersion.String()
+	ResultExportGroupVersionKind = SchemeGroupVersion.WithKind(ResultExportKind)
+)
+
+// TestResult type metadata.
+var (
+	TestResultKind             = reflect.TypeOf(TestResult{}).Name()
+	TestResultGroupKind        = schema.GroupKind{Group: Group, Kind: TestResultKind}.String()
+	TestResultKindAPIVersion   = TestResultKind + "." + SchemeGroupVersion.String()
+	TestResultGroupVersionKind = SchemeGroupVersion.WithKind(TestResultKind)
+)
+
+// TrafficModel type metadata.
+var (
+	TrafficModelKind             = reflect.TypeOf(TrafficModel{}).Name()
+	TrafficModelGroupKind        = schema.GroupKind{Group: Group, Kind: TrafficModelKind}.String()
+	TrafficModelKindAPIVersion   = TrafficModelKind + "." + SchemeGroupVersion.String()
+	TrafficModelGroupVersionKind = SchemeGroupVersion.WithKind(TrafficModelKind)
+)
+
+// IPAllowlist type metadata.
+var (
+	IPAllowlistKind             = reflect.TypeOf(IPAllowlist{}).Name()
+	IPAllowlistGroupKind        = schema.GroupKind{Group: Group, Kind: IPAllowlistKind}.String()
+	IPAllowlistKindAPIVersion   = IPAllowlistKind + "." + SchemeGroupVersion.String()
+	IPAllowlistGroupVersionKind = SchemeGroupVersion.WithKind(IPAllowlistKind)
+)
+
 func init() {
 	SchemeBuilder.Register(&TestCase{}, &TestCaseList{})
+	SchemeBuilder.Register(&TestRun{}, &TestRunList{})
+	SchemeBuilder.Register(&TestRunSchedule{}, &TestRunScheduleList{})
+	SchemeBuilder.Register(&Organization{}, &OrganizationList{})
+	SchemeBuilder.Register(&DataSource{}, &DataSourceList{})
+	SchemeBuilder.Register(&NotificationChannel{}, &NotificationChannelList{})
+	SchemeBuilder.Register(&SLO{}, &SLOList{})
+	SchemeBuilder.Register(&APIToken{}, &APITokenList{})
+	SchemeBuilder.Register(&Project{}, &ProjectList{})
+	SchemeBuilder.Register(&ResultExport{}, &ResultExportList{})
+	SchemeBuilder.Register(&TestResult{}, &TestResultList{})
+	SchemeBuilder.Register(&TrafficModel{}, &TrafficModelList{})
+	SchemeBuilder.Register(&IPAllowlist{}, &IPAllowlistList{})
 }
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A ResultArtifact is a raw artifact of a finished test run.
+// +kubebuilder:validation:Enum=Metrics;Logs
+type ResultArtifact string
+
+// Artifacts of a test run.
+const (
+	// ResultArtifactMetrics are the raw metrics of every request sent during
+	// the run.
+	ResultArtifactMetrics ResultArtifact = "Metrics"
+
+	// ResultArtifactLogs are the logs of the load generators of the run.
+	ResultArtifactLogs ResultArtifact = "Logs"
+)
+
+// Keys of the credentials Secret of a ResultExport.
+const (
+	// ResultExportAccessKeyIDKey is the key of the access key ID of an S3
+	// destination, or of the HMAC key ID of a GCS destination.
+	ResultExportAccessKeyIDKey = "accessKeyID"
+
+	// ResultExportSecretAccessKeyKey is the key of the secret access key of
+	// an S3 destination, or of the HMAC secret of a GCS destination.
+	ResultExportSecretAccessKeyKey = "secretAccessKey"
+
+	// ResultExportSessionTokenKey is the optional key of the session token of
+	// temporary S3 credentials.
+	ResultExportSessionTokenKey = "sessionToken"
+
+	// ResultExportSASTokenKey is the key of the shared access signature of an
+	// Azure Blob destination.
+	ResultExportSASTokenKey = "sasToken"
+)
+
+// An S3Destination is a bucket of Amazon S3 or of an S3 compatible service.
+type S3Destination struct {
+	// Bucket artifacts are uploaded to.
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// Region of the bucket.
+	// +kubebuilder:validation:MinLength=1
+	Region string `json:"region"`
+
+	// Endpoint of an S3 compatible service. Artifacts are uploaded to Amazon
+	// S3 if it is omitted.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// A GCSDestination is a bucket of Google Cloud Storage. Artifacts are uploaded
+// using an HMAC key.
+type GCSDestination struct {
+	// Bucket artifacts are uploaded to.
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+}
+
+// An AzureBlobDestination is a container of an Azure Storage account.
+type AzureBlobDestination struct {
+	// Account is the name of the storage account.
+	// +kubebuilder:validation:MinLength=1
+	Account string `json:"account"`
+
+	// Container artifacts are uploaded to.
+	// +kubebuilder:validation:MinLength=1
+	Container string `json:"container"`
+
+	// Endpoint of the Blob service, if it is not that of the storage account
+	// in the Azure public cloud.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// ResultExportParameters are the configurable fields of a ResultExport.
+// Exactly one of S3, GCS and AzureBlob must be set.
+type ResultExportParameters struct {
+	// TestRun is the name of the TestRun whose artifacts are exported. They
+	// are exported once the run has ended. Either it, TestRunRef or
+	// TestRunSelector must be set.
+	// +optional
+	TestRun string `json:"testRun,omitempty"`
+
+	// TestRunRef references a TestRun whose name sets TestRun.
+	// +optional
+	TestRunRef *xpv1.Reference `json:"testRunRef,omitempty"`
+
+	// TestRunSelector selects a TestRun whose name sets TestRun.
+	// +optional
+	TestRunSelector *xpv1.Selector `json:"testRunSelector,omitempty"`
+
+	// Artifacts to export. Both the metrics and the logs of the run are
+	// exported if it is omitted.
+	// +optional
+	Artifacts []ResultArtifact `json:"artifacts,omitempty"`
+
+	// Prefix of the keys artifacts are uploaded under. Each artifact is
+	// uploaded as <prefix><run ID>/<artifact>.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// S3 is the S3 bucket artifacts are uploaded to.
+	// +optional
+	S3 *S3Destination `json:"s3,omitempty"`
+
+	// GCS is the Google Cloud Storage bucket artifacts are uploaded to.
+	// +optional
+	GCS *GCSDestination `json:"gcs,omitempty"`
+
+	// AzureBlob is the Azure Blob Storage container artifacts are uploaded
+	// to.
+	// +optional
+	AzureBlob *AzureBlobDestination `json:"azureBlob,omitempty"`
+
+	// CredentialsSecretRef references a Secret containing the credentials
+	// used to upload artifacts: the keys accessKeyID, secretAccessKey and
+	// optionally sessionToken for S3 and GCS, or the key sasToken for Azure
+	// Blob.
+	CredentialsSecretRef xpv1.SecretReference `json:"credentialsSecretRef"`
+}
+
+// An ExportedArtifact is an artifact that has been uploaded to object storage.
+type ExportedArtifact struct {
+	// Name of the artifact.
+	Name ResultArtifact `json:"name"`
+
+	// URL of the uploaded artifact.
+	URL string `json:"url"`
+
+	// Size of the artifact in bytes.
+	Size int64 `json:"size"`
+
+	// Digest of the artifact, as sha256:<hex>. An artifact whose digest and
+	// URL are unchanged is not uploaded again.
+	// +optional
+	Digest string `json:"digest,omitempty"`
+}
+
+// ResultExportObservation are the observable fields of a ResultExport.
+type ResultExportObservation struct {
+	// TestRunID is the ID of the run whose artifacts were exported, or the
+	// comma separated IDs of the runs of a TestRun launched against
+	// environments.
+	TestRunID string `json:"testRunID,omitempty"`
+
+	// Artifacts that were exported.
+	Artifacts []ExportedArtifact `json:"artifacts,omitempty"`
+
+	// ExportedAt is the time at which the artifacts were exported.
+	ExportedAt *metav1.Time `json:"exportedAt,omitempty"`
+}
+
+// A ResultExportSpec defines the desired state of a ResultExport.
+type ResultExportSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ResultExportParameters `json:"forProvider"`
+}
+
+// A ResultExportStatus represents the observed state of a ResultExport.
+type ResultExportStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ResultExportObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A ResultExport archives the raw artifacts of a finished test run to object
+// storage. Exported artifacts are left in object storage when the
+// ResultExport is deleted.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="TEST-RUN",type="string",JSONPath=".spec.forProvider.testRun"
+// +kubebuilder:printcolumn:name="EXPORTED",type="date",JSONPath=".status.atProvider.exportedAt"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type ResultExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ResultExportSpec   `json:"spec"`
+	Status ResultExportStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ResultExportList contains a list of ResultExport
+type ResultExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ResultExport `json:"items"`
+}
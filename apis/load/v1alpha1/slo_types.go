@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// A LatencyPercentile is a percentile of the latency of the requests of a
+// test run.
+type LatencyPercentile string
+
+// Percentiles of request latency.
+const (
+	LatencyP50 LatencyPercentile = "P50"
+	LatencyP95 LatencyPercentile = "P95"
+	LatencyP99 LatencyPercentile = "P99"
+)
+
+// An SLOSpec defines the thresholds of a service level objective. Every
+// threshold that is set must be met for a run to succeed.
+type SLOSpec struct {
+	// Latency objectives of the requests of a run.
+	// +optional
+	Latency []LatencyObjective `json:"latency,omitempty"`
+
+	// MaxErrorRate is the highest percentage of the requests of a run that
+	// may fail, for example "0.5".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^(100(\.0+)?|[0-9]{1,2}(\.[0-9]+)?)$`
+	MaxErrorRate string `json:"maxErrorRate,omitempty"`
+
+	// Apdex objective of the requests of a run.
+	// +optional
+	Apdex *ApdexObjective `json:"apdex,omitempty"`
+}
+
+// A LatencyObjective bounds a percentile of request latency.
+type LatencyObjective struct {
+	// Percentile of request latency the objective applies to.
+	// +kubebuilder:validation:Enum=P50;P95;P99
+	Percentile LatencyPercentile `json:"percentile"`
+
+	// Max is the highest the percentile may be, for example 250ms.
+	Max metav1.Duration `json:"max"`
+}
+
+// An ApdexObjective bounds the Apdex score of a run. Requests faster than the
+// threshold satisfy users; requests faster than four times the threshold are
+// tolerated.
+type ApdexObjective struct {
+	// Threshold is the Apdex threshold T, for example 500ms.
+	Threshold metav1.Duration `json:"threshold"`
+
+	// MinScore is the lowest the Apdex score may be, between 0 and 1, for
+	// example "0.9".
+	// +kubebuilder:validation:Pattern=`^(0(\.[0-9]+)?|1(\.0+)?)$`
+	MinScore string `json:"minScore"`
+}
+
+// +kubebuilder:object:root=true
+
+// An SLO is a service level objective that TestCases and TestRuns reference by
+// name. Its thresholds are enforced on every run the provider launches for
+// them. Where several SLOs set the same threshold, the strictest applies.
+// +kubebuilder:printcolumn:name="MAX-ERROR-RATE",type="string",JSONPath=".spec.maxErrorRate"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,path=slos,categories={crossplane,stormforge}
+type SLO struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec SLOSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// SLOList contains a list of SLO
+type SLOList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []SLO `json:"items"`
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Labels of a TestResult.
+const (
+	// LabelTestRun is the name of the TestRun a TestResult records the
+	// result of.
+	LabelTestRun = "load.stormforge.io/test-run"
+
+	// LabelTestCase is the name of the TestCase the run was launched from.
+	LabelTestCase = "load.stormforge.io/test-case"
+)
+
+// An SLOVerdictResult is whether a threshold of the SLOs of a run was met.
+type SLOVerdictResult string
+
+// Results of an SLO verdict.
+const (
+	// SLOMet thresholds were met by the run.
+	SLOMet SLOVerdictResult = "Met"
+
+	// SLOMissed thresholds were not met by the run.
+	SLOMissed SLOVerdictResult = "Missed"
+
+	// SLOUnknown thresholds apply to a metric that is not summarized.
+	SLOUnknown SLOVerdictResult = "Unknown"
+)
+
+// An SLOVerdict records whether a run met a threshold of its SLOs.
+type SLOVerdict struct {
+	// Metric the threshold applies to, for example latency_p95. Latencies are
+	// in milliseconds and the error rate is a percentage of all requests.
+	Metric string `json:"metric"`
+
+	// Threshold of the metric.
+	Threshold string `json:"threshold"`
+
+	// Actual value of the metric, if it is summarized.
+	// +optional
+	Actual string `json:"actual,omitempty"`
+
+	// Result of the verdict.
+	// +kubebuilder:validation:Enum=Met;Missed;Unknown
+	Result SLOVerdictResult `json:"result"`
+}
+
+// A TestResultSpec is the summarized result of a finished test run.
+type TestResultSpec struct {
+	// TestRun is the name of the TestRun the result was recorded for.
+	TestRun string `json:"testRun"`
+
+	// TestRunID is the ID of the run in StormForge.
+	TestRunID string `json:"testRunID"`
+
+	// TestCaseID is the ID of the test case the run was launched from.
+	// +optional
+	TestCaseID string `json:"testCaseID,omitempty"`
+
+	// Phase the run ended in.
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Aborted;Unknown
+	Phase TestRunPhase `json:"phase"`
+
+	// StartedAt is the time at which the run started generating load.
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// EndedAt is the time at which the run ended.
+	// +optional
+	EndedAt *metav1.Time `json:"endedAt,omitempty"`
+
+	// ErrorRate is the percentage of the requests of the run that failed.
+	ErrorRate string `json:"errorRate"`
+
+	TestRunResult `json:",inline"`
+
+	// SLOs are the verdicts of the thresholds of the SLOs of the run.
+	// +optional
+	SLOs []SLOVerdict `json:"slos,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A TestResult is the immutable result of a finished test run. The TestRun
+// controller records one for every run it observes ending, and never changes
+// or deletes it, so results remain available after their TestRun is deleted.
+// +kubebuilder:printcolumn:name="TEST-RUN",type="string",JSONPath=".spec.testRun"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".spec.phase"
+// +kubebuilder:printcolumn:name="REQUESTS",type="integer",JSONPath=".spec.requests"
+// +kubebuilder:printcolumn:name="ERROR-RATE",type="string",JSONPath=".spec.errorRate"
+// +kubebuilder:printcolumn:name="P95",type="string",JSONPath=".spec.latencyP95"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,stormforge}
+type TestResult struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec TestResultSpec `json:"spec"`
+}
+
+// +kubebuilder:object:root=true
+
+// TestResultList contains a list of TestResult
+type TestResultList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TestResult `json:"items"`
+}
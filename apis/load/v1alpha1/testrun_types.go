@@ -0,0 +1,304 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TestRunParameters are the configurable fields of a TestRun. A run cannot be
+// changed once it has been launched.
+type TestRunParameters struct {
+	// TestCase is the name of the TestCase to launch a run of. The TestCase
+	// must have been created in StormForge before the run can be launched.
+	// Either it, TestCaseRef or TestCaseSelector must be set.
+	// +optional
+	TestCase string `json:"testCase,omitempty"`
+
+	// TestCaseRef references a TestCase whose name sets TestCase.
+	// +optional
+	TestCaseRef *xpv1.Reference `json:"testCaseRef,omitempty"`
+
+	// TestCaseSelector selects a TestCase whose name sets TestCase.
+	// +optional
+	TestCaseSelector *xpv1.Selector `json:"testCaseSelector,omitempty"`
+
+	// Title of the run.
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Notes about the run.
+	// +optional
+	Notes string `json:"notes,omitempty"`
+
+	// SLORefs reference SLOs whose thresholds are enforced on the run, in
+	// addition to those of its TestCase.
+	// +optional
+	SLORefs []xpv1.Reference `json:"sloRefs,omitempty"`
+
+	// Stages ramp the load of the run, replacing the arrival phases of its
+	// test case. Stages run in the order they are listed, each ramping the
+	// arrival rate linearly from the target rate of the previous stage, or
+	// zero for the first stage, to its own target rate.
+	// +optional
+	Stages []Stage `json:"stages,omitempty"`
+
+	// MinDuration is how long the run must generate load before it may be
+	// aborted, so that it collects enough samples. Deleting the TestRun of a
+	// run that has not run for this long waits until it has. The stages of
+	// the run, if any, must last at least this long.
+	// +optional
+	MinDuration *metav1.Duration `json:"minDuration,omitempty"`
+
+	// MaxDuration is how long the run may generate load before it is
+	// aborted, to cap its cost. It must not be less than MinDuration, and
+	// the stages of the run, if any, must not last longer.
+	// +optional
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+
+	// Environments to launch the run against, for example to compare them.
+	// A run is launched and tracked per environment, and their results are
+	// aggregated. The run is launched against the target of its test case
+	// if no environments are listed.
+	// +optional
+	// +kubebuilder:validation:MaxItems=10
+	Environments []Environment `json:"environments,omitempty"`
+
+	// ResultMetrics are the metrics of the result of the run that are
+	// reported in its status. Requests, Errors and LatencyP95 are reported
+	// if none are listed. The TestResults recording the result of the run
+	// include every metric regardless.
+	// +optional
+	ResultMetrics []ResultMetric `json:"resultMetrics,omitempty"`
+}
+
+// A ResultMetric is a metric of the result of a test run.
+// +kubebuilder:validation:Enum=Requests;Errors;LatencyP50;LatencyP95;LatencyP99
+type ResultMetric string
+
+// Metrics of the result of a test run.
+const (
+	ResultMetricRequests   ResultMetric = "Requests"
+	ResultMetricErrors     ResultMetric = "Errors"
+	ResultMetricLatencyP50 ResultMetric = "LatencyP50"
+	ResultMetricLatencyP95 ResultMetric = "LatencyP95"
+	ResultMetricLatencyP99 ResultMetric = "LatencyP99"
+)
+
+// An Environment a test run is launched against.
+type Environment struct {
+	// Name of the environment. It must be unique within the TestRun.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Target URL of the environment, replacing that of the test case's
+	// definition.
+	// +kubebuilder:validation:MinLength=1
+	Target string `json:"target"`
+
+	// Headers sent with every request to the environment.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// A Stage of the load generated by a test run.
+type Stage struct {
+	// Duration of the stage. It must be at least one second.
+	Duration metav1.Duration `json:"duration"`
+
+	// TargetRate is the number of new clients arriving per second at the end
+	// of the stage.
+	// +kubebuilder:validation:Minimum=0
+	TargetRate int32 `json:"targetRate"`
+
+	// MaxClients limits the number of clients that may be active at once
+	// during the stage.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	MaxClients *int32 `json:"maxClients,omitempty"`
+}
+
+// A StageObservation is a stage a test run was launched with.
+type StageObservation struct {
+	Stage `json:",inline"`
+
+	// Start of the stage, relative to the start of the run.
+	Start metav1.Duration `json:"start"`
+}
+
+// A TestRunPhase is a simplified state of a test run.
+type TestRunPhase string
+
+// Phases of a test run.
+const (
+	// TestRunPending runs have been launched, but are not yet generating
+	// load.
+	TestRunPending TestRunPhase = "Pending"
+
+	// TestRunRunning runs are generating load.
+	TestRunRunning TestRunPhase = "Running"
+
+	// TestRunSucceeded runs have finished.
+	TestRunSucceeded TestRunPhase = "Succeeded"
+
+	// TestRunFailed runs could not be completed.
+	TestRunFailed TestRunPhase = "Failed"
+
+	// TestRunAborted runs were aborted before they finished.
+	TestRunAborted TestRunPhase = "Aborted"
+
+	// TestRunUnknown runs are in a state the provider does not recognize.
+	TestRunUnknown TestRunPhase = "Unknown"
+)
+
+// An EnvironmentObservation is the observed state of the run launched
+// against an environment.
+type EnvironmentObservation struct {
+	// Name of the environment.
+	Name string `json:"name"`
+
+	// ID of the run in StormForge.
+	ID string `json:"id,omitempty"`
+
+	// State of the run as reported by StormForge. It is not set if the run
+	// no longer exists in StormForge, in which case its phase is Aborted.
+	State string `json:"state,omitempty"`
+
+	// Phase of the run.
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Aborted;Unknown
+	Phase TestRunPhase `json:"phase,omitempty"`
+
+	// StartedAt is the time at which the run started generating load.
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// EndedAt is the time at which the run ended.
+	EndedAt *metav1.Time `json:"endedAt,omitempty"`
+
+	// Result of the run. It is reported once the run has ended.
+	Result *TestRunResult `json:"result,omitempty"`
+}
+
+// TestRunObservation are the observable fields of a TestRun. The fields of a
+// TestRun launched against environments aggregate those of their runs.
+type TestRunObservation struct {
+	// ID of the run in StormForge. It is not set for a TestRun launched
+	// against environments; see Environments.
+	ID string `json:"id,omitempty"`
+
+	// TestCaseID is the ID of the test case the run was launched from.
+	TestCaseID string `json:"testCaseID,omitempty"`
+
+	// State of the run as reported by StormForge.
+	State string `json:"state,omitempty"`
+
+	// Phase of the run. The phase of a TestRun launched against environments
+	// is Running, Pending or Unknown while any of its runs is, then Failed or
+	// Aborted if any of them was, and Succeeded otherwise.
+	// +kubebuilder:validation:Enum=Pending;Running;Succeeded;Failed;Aborted;Unknown
+	Phase TestRunPhase `json:"phase,omitempty"`
+
+	// StartedAt is the time at which the run started generating load.
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// EndedAt is the time at which the run ended.
+	EndedAt *metav1.Time `json:"endedAt,omitempty"`
+
+	// Result of the run. It is reported once the run has ended. The result
+	// of a TestRun launched against environments sums the requests and
+	// errors of their runs, and reports their highest latencies.
+	Result *TestRunResult `json:"result,omitempty"`
+
+	// Environments the run was launched against, if any.
+	Environments []EnvironmentObservation `json:"environments,omitempty"`
+
+	// Stages the run was launched with, if any.
+	Stages []StageObservation `json:"stages,omitempty"`
+
+	// MinDuration is the minimum duration enforced on the run, if any.
+	MinDuration *metav1.Duration `json:"minDuration,omitempty"`
+
+	// MaxDuration is the maximum duration enforced on the run, if any.
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+}
+
+// A TestRunResult summarizes the results of a finished test run. The status
+// of a TestRun only reports the metrics selected by its ResultMetrics.
+type TestRunResult struct {
+	// Requests is the number of requests sent during the run.
+	// +optional
+	Requests *int64 `json:"requests,omitempty"`
+
+	// Errors is the number of requests that failed.
+	// +optional
+	Errors *int64 `json:"errors,omitempty"`
+
+	// LatencyP50 is the median latency of the requests.
+	// +optional
+	LatencyP50 *metav1.Duration `json:"latencyP50,omitempty"`
+
+	// LatencyP95 is the 95th percentile latency of the requests.
+	// +optional
+	LatencyP95 *metav1.Duration `json:"latencyP95,omitempty"`
+
+	// LatencyP99 is the 99th percentile latency of the requests.
+	// +optional
+	LatencyP99 *metav1.Duration `json:"latencyP99,omitempty"`
+}
+
+// A TestRunSpec defines the desired state of a TestRun.
+type TestRunSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       TestRunParameters `json:"forProvider"`
+}
+
+// A TestRunStatus represents the observed state of a TestRun.
+type TestRunStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          TestRunObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A TestRun is a single launch of a StormForge test case. Its external name is
+// the ID of the run, or the comma separated IDs of its runs if it was launched
+// against several environments.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="TEST-CASE",type="string",JSONPath=".spec.forProvider.testCase"
+// +kubebuilder:printcolumn:name="PHASE",type="string",JSONPath=".status.atProvider.phase"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type TestRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TestRunSpec   `json:"spec"`
+	Status TestRunStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TestRunList contains a list of TestRun
+type TestRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TestRun `json:"items"`
+}
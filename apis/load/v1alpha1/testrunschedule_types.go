@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A TestRunScheduleSpec defines when to launch TestRuns.
+type TestRunScheduleSpec struct {
+	// Schedule in cron format, e.g. "0 2 * * *" to launch a run at 2am every
+	// night. Schedules are evaluated in UTC.
+	// +kubebuilder:validation:MinLength=1
+	Schedule string `json:"schedule"`
+
+	// Suspend stops runs from being launched until it is unset. Runs that
+	// were missed while suspended are not launched.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// HistoryLimit is the number of TestRuns that have ended to keep. Older
+	// TestRuns are deleted; their runs remain in StormForge.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=3
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+
+	// RunTemplate is the spec of the TestRuns launched by the schedule.
+	RunTemplate TestRunSpec `json:"runTemplate"`
+}
+
+// A TestRunScheduleStatus represents the observed state of a TestRunSchedule.
+type TestRunScheduleStatus struct {
+	xpv1.ConditionedStatus `json:",inline"`
+
+	// LastScheduleTime is the time at which a run was last scheduled.
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// Active are the names of the TestRuns launched by the schedule whose runs
+	// have not yet ended.
+	Active []string `json:"active,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A TestRunSchedule launches TestRuns on a recurring schedule. The TestRuns it
+// launches are owned by it, and deleted with it.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="SCHEDULE",type="string",JSONPath=".spec.schedule"
+// +kubebuilder:printcolumn:name="TEST-CASE",type="string",JSONPath=".spec.runTemplate.forProvider.testCase"
+// +kubebuilder:printcolumn:name="SUSPEND",type="boolean",JSONPath=".spec.suspend"
+// +kubebuilder:printcolumn:name="LAST-SCHEDULE",type="date",JSONPath=".status.lastScheduleTime"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,stormforge}
+type TestRunSchedule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TestRunScheduleSpec   `json:"spec"`
+	Status TestRunScheduleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TestRunScheduleList contains a list of TestRunSchedule
+type TestRunScheduleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []TestRunSchedule `json:"items"`
+}
@@ -22,24 +22,806 @@ import (
 	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 )
 
-// MyTypeParameters are the configurable fields of a MyType.
+// TestCaseParameters are the configurable fields of a TestCase.
 type TestCaseParameters struct {
+	// Org is the StormForge organization the test case belongs to. It cannot
+	// be changed once the test case has been created. Either it, OrgRef or
+	// OrgSelector must be set.
+	// +optional
+	Org string `json:"org,omitempty"`
+
+	// OrgRef references an Organization whose external name sets Org.
+	// +optional
+	OrgRef *xpv1.Reference `json:"orgRef,omitempty"`
+
+	// OrgSelector selects an Organization whose external name sets Org.
+	// +optional
+	OrgSelector *xpv1.Selector `json:"orgSelector,omitempty"`
+
+	// Name of the test case. It must be unique within its organization. The
+	// TestCase is bound to the ID of its test case once the test case has
+	// been created or observed, so changing the name renames the test case,
+	// and a test case renamed in StormForge is renamed back.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9_.-]*$`
+	Name string `json:"name"`
+
+	// Labels of the test case in StormForge, for example the team owning
+	// it. Defaults to the labels of the test case in StormForge.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Notes of the test case in StormForge. Defaults to the notes of the
+	// test case in StormForge.
+	// +optional
+	Notes string `json:"notes,omitempty"`
+
+	// Project is the name of the project of the organization the test case
+	// belongs to. The project of the test case is not changed unless set.
+	// +optional
+	Project string `json:"project,omitempty"`
+
+	// ProjectRef references a Project whose name sets Project.
+	// +optional
+	ProjectRef *xpv1.Reference `json:"projectRef,omitempty"`
+
+	// ProjectSelector selects a Project whose name sets Project.
+	// +optional
+	ProjectSelector *xpv1.Selector `json:"projectSelector,omitempty"`
+
+	// Script is the source of the JavaScript definition of the test case. A
+	// test case cannot be created without a script, a scenario or a test
+	// case to clone.
+	// +optional
+	Script *ScriptSource `json:"script,omitempty"`
+
+	// Scenario declares the session of the test case as a sequence of HTTP
+	// steps, which is rendered into its JavaScript definition. It is ignored
+	// if a script is set.
+	// +optional
+	Scenario *Scenario `json:"scenario,omitempty"`
+
+	// CloneFrom is the name of a TestCase to clone, for example to promote a
+	// test case from a staging org or project to a production one. The test
+	// case is created in its own org and project with the definition of the
+	// cloned test case as last synced to StormForge, and with its labels and
+	// notes unless it sets its own. Its traffic model, launch options,
+	// targets and environment are applied to the cloned definition. It is
+	// ignored if a script or scenario is set.
+	// +optional
+	CloneFrom string `json:"cloneFrom,omitempty"`
+
+	// CloneFromRef references a TestCase whose name sets CloneFrom.
+	// +optional
+	CloneFromRef *xpv1.Reference `json:"cloneFromRef,omitempty"`
+
+	// CloneFromSelector selects a TestCase whose name sets CloneFrom.
+	// +optional
+	CloneFromSelector *xpv1.Selector `json:"cloneFromSelector,omitempty"`
+
+	// ScriptFormat is the format of the script. Scripts in the k6 format are
+	// translated into StormForge test case definitions before they are
+	// uploaded; scripts using constructs that cannot be translated are
+	// rejected. The format does not apply to HAR recordings.
+	// +optional
+	// +kubebuilder:validation:Enum=stormforge;k6
+	// +kubebuilder:default=stormforge
+	ScriptFormat ScriptFormat `json:"scriptFormat,omitempty"`
+
+	// TrafficModel describes the load the test case generates. It replaces
+	// any arrival phases set by the script.
+	// +optional
+	TrafficModel *TrafficModelSpec `json:"trafficModel,omitempty"`
+
+	// TrafficModelRef references a TrafficModel describing the load the test
+	// case generates, so that several test cases can share it. It takes
+	// precedence over TrafficModel. Changes to the TrafficModel are uploaded
+	// to every test case that references it.
+	// +optional
+	TrafficModelRef *xpv1.Reference `json:"trafficModelRef,omitempty"`
+
+	// Env are variables made available to the script as properties of a
+	// global env object, for example env.API_KEY. Values read from Secrets
+	// are injected into the definition uploaded to StormForge, never into the
+	// script source.
+	// +optional
+	Env []EnvVar `json:"env,omitempty"`
+
+	// Targets are systems under test whose URLs and authentication headers
+	// are made available to the script as properties of a global targets
+	// object, for example targets.api.url and targets.api.headers.
+	// Credentials read from Secrets are injected into the definition
+	// uploaded to StormForge, never into the script source.
+	// +optional
+	Targets []Target `json:"targets,omitempty"`
+
+	// ClientCertificateSecretRef references a TLS Secret whose certificate
+	// and private key, under the keys 'tls.crt' and 'tls.key', are attached
+	// to the test case and presented to targets that require mutual TLS.
+	// +optional
+	ClientCertificateSecretRef *xpv1.SecretReference `json:"clientCertificateSecretRef,omitempty"`
+
+	// DataSources are files, such as CSV fixtures of user credentials, that
+	// are uploaded to the organization of the test case before its script.
+	// The script refers to them by name.
+	// +optional
+	DataSources []TestCaseDataSource `json:"dataSources,omitempty"`
+
+	// DefaultDataSource is the name of the data source, one of DataSources,
+	// that the test case draws data from by default. It is late initialized
+	// from StormForge if unset, unless the default in StormForge is not one
+	// of DataSources. A default that is not one of DataSources is rejected,
+	// so a test case without data sources never has a default.
+	// +optional
+	DefaultDataSource string `json:"defaultDataSource,omitempty"`
+
+	// DataSourceRefs reference DataSources in the organization of the test
+	// case that its script draws data from. The test case is not created or
+	// updated until every one of them is Ready, so that its script never
+	// refers to a data source that has not been uploaded yet. They are then
+	// attached to the test case, replacing the data sources attached to it
+	// in StormForge. The attached data sources are left unchanged if none
+	// are referenced.
+	// +optional
+	DataSourceRefs []xpv1.Reference `json:"dataSourceRefs,omitempty"`
+
+	// Launch configures the runs of the test case launched by the provider.
+	// +optional
+	Launch *LaunchOptions `json:"launch,omitempty"`
+
+	// ObserveLastRun reports the outcome and key metrics of the latest run
+	// of the test case in its status, whether or not it was launched by a
+	// TestRun. It is off by default, as fetching the results of a run that
+	// has ended costs an additional call to the StormForge API.
+	// +optional
+	ObserveLastRun bool `json:"observeLastRun,omitempty"`
+
+	// ActiveRunsPolicy determines how the test case is deleted while runs of
+	// it are active. Wait blocks its deletion until they have ended, while
+	// Abort aborts them before deleting it.
+	// +optional
+	// +kubebuilder:validation:Enum=Wait;Abort
+	// +kubebuilder:default=Wait
+	ActiveRunsPolicy ActiveRunsPolicy `json:"activeRunsPolicy,omitempty"`
+
+	// DeletionBehavior determines what happens to the test case when the
+	// TestCase is deleted, unless its deletion policy is Orphan. Delete
+	// deletes it, while Archive archives it so that its runs and their
+	// results are kept in StormForge.
+	// +optional
+	// +kubebuilder:validation:Enum=Delete;Archive
+	// +kubebuilder:default=Delete
+	DeletionBehavior DeletionBehavior `json:"deletionBehavior,omitempty"`
+
+	// Archived determines whether the test case is archived. An archived test
+	// case can no longer be run, but its runs and their results are kept. A
+	// test case archived or unarchived outside of Kubernetes is changed back.
+	// +optional
+	Archived bool `json:"archived,omitempty"`
+
+	// SLORefs reference SLOs whose thresholds are enforced on every run of
+	// the test case launched by the provider, whether on creation or by a
+	// TestRun.
+	// +optional
+	SLORefs []xpv1.Reference `json:"sloRefs,omitempty"`
+
+	// AlertThresholds alert the notification channels of the organization
+	// of the test case whenever one of its runs crosses them, whether or not
+	// the provider launched the run. Unlike the thresholds of SLOs they do
+	// not fail the run. Thresholds added to the test case outside of
+	// Kubernetes are removed.
+	// +optional
+	AlertThresholds []AlertThreshold `json:"alertThresholds,omitempty"`
+}
+
+// A ScriptFormat is the format of a test case script.
+type ScriptFormat string
+
+// Script formats.
+const (
+	// ScriptFormatStormForge scripts are StormForge test case definitions.
+	ScriptFormatStormForge ScriptFormat = "stormforge"
+
+	// ScriptFormatK6 scripts are k6 scripts.
+	ScriptFormatK6 ScriptFormat = "k6"
+)
+
+// A ScriptSource is the source of the JavaScript definition of a test case.
+// Exactly one source should be set. The definition is uploaded again whenever
+// the content of its source changes.
+type ScriptSource struct {
+	// Inline is the JavaScript definition of the test case. It is uploaded
+	// to StormForge exactly as written.
+	// +optional
+	Inline *string `json:"inline,omitempty"`
+
+	// ConfigMapRef references a key of a ConfigMap containing the JavaScript
+	// definition of the test case.
+	// +optional
+	ConfigMapRef *ConfigMapKeySelector `json:"configMapRef,omitempty"`
+
+	// SecretRef references a key of a Secret containing the JavaScript
+	// definition of the test case.
+	// +optional
+	SecretRef *xpv1.SecretKeySelector `json:"secretRef,omitempty"`
+
+	// URL fetches the JavaScript definition of the test case from a remote
+	// URL.
+	// +optional
+	URL *URLSource `json:"url,omitempty"`
+
+	// Git fetches the JavaScript definition of the test case from a file of a
+	// Git repository.
+	// +optional
+	Git *GitSource `json:"git,omitempty"`
+
+	// HAR references a key of a ConfigMap containing an HTTP Archive (HAR)
+	// recording. The recorded requests are converted into a single session
+	// of the test case.
+	// +optional
+	HAR *ConfigMapKeySelector `json:"har,omitempty"`
+}
+
+// A URLSource is a remote URL serving the JavaScript definition of a test
+// case. The URL is requested on every reconcile; the definition is only
+// downloaded again if the server reports that it changed.
+type URLSource struct {
+	// URL of the definition.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// AuthSecretRef references a Secret containing the credentials used to
+	// fetch the definition: either a bearer token under the key 'token', or
+	// the keys 'username' and 'password' for basic authentication.
+	// +optional
+	AuthSecretRef *xpv1.SecretReference `json:"authSecretRef,omitempty"`
+}
+
+// A GitSource is a file of a Git repository containing the JavaScript
+// definition of a test case. The tip of the ref is resolved on every
+// reconcile; the file is only fetched again once the ref moves.
+type GitSource struct {
+	// Repository is the HTTPS URL of the Git repository.
+	// +kubebuilder:validation:Pattern=`^https://`
+	Repository string `json:"repository"`
+
+	// Ref is the branch, tag or commit to fetch. Defaults to the default
+	// branch of the repository.
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// Path of the definition within the repository.
+	// +kubebuilder:validation:MinLength=1
+	Path string `json:"path"`
+
+	// AuthSecretRef references a Secret containing the credentials used to
+	// fetch the repository: either a token under the key 'token', or the keys
+	// 'username' and 'password'.
+	// +optional
+	AuthSecretRef *xpv1.SecretReference `json:"authSecretRef,omitempty"`
+}
+
+// A ConfigMapKeySelector references a key of a ConfigMap in an arbitrary
+// namespace.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Key within the ConfigMap.
+	Key string `json:"key"`
+}
+
+// A Scenario is a session of a test case declared as a sequence of HTTP steps.
+// Its load is described by the traffic model of the test case.
+type Scenario struct {
+	// Target is the base URL that relative step URLs are resolved against.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Target string `json:"target"`
+
+	// Name of the session. Defaults to the name of the test case.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Steps of the session, run one after another.
+	// +kubebuilder:validation:MinItems=1
+	Steps []ScenarioStep `json:"steps"`
+}
+
+// A ScenarioStep is an HTTP request of a scenario. The URL, header values and
+// body may refer to variables extracted by earlier steps as ${name}.
+type ScenarioStep struct {
+	// Name of the step. Requests are tagged with their step name in reports.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Method of the request. Defaults to GET.
+	// +optional
+	// +kubebuilder:validation:Enum=GET;POST;PUT;PATCH;DELETE;HEAD;OPTIONS
+	Method string `json:"method,omitempty"`
+
+	// URL of the request, either absolute or relative to the target.
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Headers of the request.
+	// +optional
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Body of the request.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// Extract values of the response into variables for later steps.
+	// +optional
+	Extract []Extraction `json:"extract,omitempty"`
+
+	// ThinkTime is how long the session waits after the step.
+	// +optional
+	ThinkTime *metav1.Duration `json:"thinkTime,omitempty"`
+}
+
+// An Extraction extracts a value of a response into a variable. Exactly one of
+// JSONPath and Header should be set.
+type Extraction struct {
+	// Name of the variable.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// JSONPath extracts the value at a JSON path of the response body.
+	// +optional
+	JSONPath string `json:"jsonPath,omitempty"`
+
+	// Header extracts the value of a response header.
+	// +optional
+	Header string `json:"header,omitempty"`
+}
+
+// A TrafficModelSpec describes the load a test case generates as a sequence
+// of arrival phases.
+type TrafficModelSpec struct {
+	// TimeUnit is the period the arrival rates of the phases refer to.
+	// Defaults to one second.
+	// +optional
+	TimeUnit *metav1.Duration `json:"timeUnit,omitempty"`
+
+	// Phases of arrivals, run one after another.
+	// +kubebuilder:validation:MinItems=1
+	Phases []ArrivalPhase `json:"phases"`
+}
+
+// An ArrivalPhase is a period during which new clients arrive at a constant or
+// linearly changing rate.
+type ArrivalPhase struct {
+	// Duration of the phase.
+	Duration metav1.Duration `json:"duration"`
+
+	// Rate is the number of clients arriving per time unit at the start of
+	// the phase.
+	// +kubebuilder:validation:Minimum=0
+	Rate int32 `json:"rate"`
+
+	// TargetRate is the number of clients arriving per time unit at the end
+	// of the phase. The rate changes linearly from Rate to TargetRate.
+	// Defaults to Rate.
+	// +optional
+	// +kubebuilder:validation:Minimum=0
+	TargetRate *int32 `json:"targetRate,omitempty"`
+
+	// MaxClients caps the number of concurrently active clients during the
+	// phase.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	MaxClients *int32 `json:"maxClients,omitempty"`
+}
+
+// An EnvVar is a variable made available to a test case script.
+type EnvVar struct {
+	// Name of the variable.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// Value of the variable.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// ValueFrom reads the value of the variable from a ConfigMap or Secret.
+	// It takes precedence over Value.
+	// +optional
+	ValueFrom *EnvVarSource `json:"valueFrom,omitempty"`
+}
+
+// An EnvVarSource is the source of the value of an EnvVar. Exactly one source
+// should be set.
+type EnvVarSource struct {
+	// SecretKeyRef references a key of a Secret.
+	// +optional
+	SecretKeyRef *xpv1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+
+	// ConfigMapKeyRef references a key of a ConfigMap.
+	// +optional
+	ConfigMapKeyRef *ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+}
+
+// A Target is a system under test.
+type Target struct {
+	// Name of the target.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z_][A-Za-z0-9_]*$`
+	Name string `json:"name"`
+
+	// URL of the target.
+	// +kubebuilder:validation:Pattern=`^https?://`
+	URL string `json:"url"`
+
+	// Auth configures how requests to the target are authenticated.
+	// +optional
+	Auth *TargetAuth `json:"auth,omitempty"`
+}
+
+// TargetAuth configures how requests to a target are authenticated. Each
+// method contributes headers to the requests of the target.
+type TargetAuth struct {
+	// BearerTokenSecretRef references a key of a Secret containing a bearer
+	// token, sent as an Authorization header.
+	// +optional
+	BearerTokenSecretRef *xpv1.SecretKeySelector `json:"bearerTokenSecretRef,omitempty"`
+
+	// BasicAuthSecretRef references a Secret containing the keys 'username'
+	// and 'password', sent as a basic Authorization header.
+	// +optional
+	BasicAuthSecretRef *xpv1.SecretReference `json:"basicAuthSecretRef,omitempty"`
+
+	// Headers are custom headers, such as API keys, sent with the requests.
+	// +optional
+	Headers []TargetHeader `json:"headers,omitempty"`
+}
+
+// A TargetHeader is a custom header sent with the requests to a target.
+type TargetHeader struct {
+	// Name of the header.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Value of the header.
+	// +optional
+	Value string `json:"value,omitempty"`
+
+	// SecretKeyRef references a key of a Secret containing the value of the
+	// header. It takes precedence over Value.
+	// +optional
+	SecretKeyRef *xpv1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+}
+
+// A TestCaseDataSource is a file uploaded as a StormForge data source for a
+// test case. Exactly one source should be set. The file is uploaded again
+// whenever its content changes.
+type TestCaseDataSource struct {
+	// Name of the data source, for example users.csv.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=255
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9][A-Za-z0-9_.-]*$`
 	Name string `json:"name"`
-	Org  string `json:"org"`
+
+	// ConfigMapRef references a key of a ConfigMap containing the file.
+	// +optional
+	ConfigMapRef *ConfigMapKeySelector `json:"configMapRef,omitempty"`
+
+	// SecretRef references a key of a Secret containing the file.
+	// +optional
+	SecretRef *xpv1.SecretKeySelector `json:"secretRef,omitempty"`
 }
 
-// MyTypeObservation are the observable fields of a MyType.
+// An AlertMetric is a result of a test run that an alert threshold applies
+// to.
+type AlertMetric string
+
+// Alert metrics.
+const (
+	AlertMetricLatencyP50 AlertMetric = "LatencyP50"
+	AlertMetricLatencyP95 AlertMetric = "LatencyP95"
+	AlertMetricLatencyP99 AlertMetric = "LatencyP99"
+	AlertMetricErrorRate  AlertMetric = "ErrorRate"
+	AlertMetricApdex      AlertMetric = "Apdex"
+)
+
+// An AlertOperator determines when an alert threshold is crossed.
+type AlertOperator string
+
+// Alert operators.
+const (
+	// AlertAbove thresholds are crossed when the metric is above their value.
+	AlertAbove AlertOperator = "Above"
+
+	// AlertBelow thresholds are crossed when the metric is below their value.
+	AlertBelow AlertOperator = "Below"
+)
+
+// An AlertThreshold of a test case alerts whenever a run of the test case
+// crosses it.
+type AlertThreshold struct {
+	// Name of the threshold. It must be unique among the thresholds of the
+	// test case.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Metric the threshold applies to.
+	// +kubebuilder:validation:Enum=LatencyP50;LatencyP95;LatencyP99;ErrorRate;Apdex
+	Metric AlertMetric `json:"metric"`
+
+	// Operator determines whether the threshold is crossed when the metric
+	// is above or below its value.
+	// +optional
+	// +kubebuilder:validation:Enum=Above;Below
+	// +kubebuilder:default=Above
+	Operator AlertOperator `json:"operator,omitempty"`
+
+	// Value of the threshold, for example "250". Latencies are in
+	// milliseconds, error rates in percent of the requests of the run, and
+	// Apdex scores between 0 and 1.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Value string `json:"value"`
+}
+
+// An ActiveRunsPolicy determines how a test case is deleted while runs of it
+// are active.
+type ActiveRunsPolicy string
+
+// Active run policies.
+const (
+	// ActiveRunsWait blocks the deletion of a test case until its runs have
+	// ended.
+	ActiveRunsWait ActiveRunsPolicy = "Wait"
+
+	// ActiveRunsAbort aborts the active runs of a test case before deleting
+	// it.
+	ActiveRunsAbort ActiveRunsPolicy = "Abort"
+)
+
+// A DeletionBehavior determines what happens to a test case when its TestCase
+// is deleted.
+type DeletionBehavior string
+
+// Deletion behaviors.
+const (
+	// DeletionBehaviorDelete deletes the test case.
+	DeletionBehaviorDelete DeletionBehavior = "Delete"
+
+	// DeletionBehaviorArchive archives the test case, keeping its runs and
+	// their results.
+	DeletionBehaviorArchive DeletionBehavior = "Archive"
+)
+
+// LaunchOptions configure the runs of a test case. The sizing and region apply
+// to every run of the test case, whether launched by the provider or not.
+type LaunchOptions struct {
+	// OnCreate launches a run of the test case once it has been created.
+	// +optional
+	OnCreate bool `json:"onCreate,omitempty"`
+
+	// Title of the launched runs.
+	// +optional
+	// +kubebuilder:validation:MaxLength=255
+	Title string `json:"title,omitempty"`
+
+	// Notes of the launched runs.
+	// +optional
+	Notes string `json:"notes,omitempty"`
+
+	// Sizing of the load generator cluster. Each sizing determines the
+	// number of load generator instances and the clients each runs; preflight
+	// runs a minimal cluster to validate the test case. Defaults to the
+	// sizing StormForge chooses for the traffic of the test case.
+	// +optional
+	// +kubebuilder:validation:Enum=preflight;tiny;small;medium;large;xlarge;"2xlarge"
+	Sizing string `json:"sizing,omitempty"`
+
+	// Region the load generators run in, for example eu-central-1.
+	// Defaults to the region StormForge chooses.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^[a-z]{2}-[a-z]+-[0-9]$`
+	Region string `json:"region,omitempty"`
+}
+
+// TestCaseObservation are the observable fields of a TestCase.
 type TestCaseObservation struct {
-	ObservableField string `json:"observableField,omitempty"`
+	// ID of the test case.
+	ID string `json:"id,omitempty"`
+
+	// Org is the organization the test case currently belongs to.
+	Org string `json:"org,omitempty"`
+
+	// Name of the test case.
+	Name string `json:"name,omitempty"`
+
+	// ProjectID is the ID of the project the test case belongs to, if any.
+	ProjectID string `json:"projectID,omitempty"`
+
+	// DefaultDataSource is the name of the default data source of the test
+	// case, if any, or its ID if it is not one of the data sources last
+	// uploaded for the test case.
+	DefaultDataSource string `json:"defaultDataSource,omitempty"`
+
+	// CreatedAt is the time the test case was created.
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// UpdatedAt is the time the test case was last updated.
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// Archived is true if the test case is archived.
+	Archived bool `json:"archived,omitempty"`
+
+	// LastRunID is the ID of the latest run of the test case.
+	LastRunID string `json:"lastRunID,omitempty"`
+
+	// LastRunState is the state of the latest run of the test case.
+	LastRunState string `json:"lastRunState,omitempty"`
+
+	// LastRun summarizes the latest run of the test case. It is reported if
+	// ObserveLastRun is set, and refreshed whenever the runs of the test
+	// case are listed.
+	LastRun *TestCaseLastRun `json:"lastRun,omitempty"`
+
+	// FetchedAt is the time the test case was last read from the StormForge
+	// API rather than from the provider's caches of its responses.
+	FetchedAt *metav1.Time `json:"fetchedAt,omitempty"`
+
+	// RunsObservedAt is the time the runs of the test case were last listed.
+	// They are listed on every poll while the latest run is active, and
+	// otherwise every few minutes.
+	RunsObservedAt *metav1.Time `json:"runsObservedAt,omitempty"`
+
+	// DefinitionChecksum is the SHA-256 checksum of the JavaScript definition
+	// last uploaded to StormForge.
+	DefinitionChecksum string `json:"definitionChecksum,omitempty"`
+
+	// ScriptConfigMapVersion is the resourceVersion of the ConfigMap the
+	// definition is read from, if any, as of the definition last synced to
+	// StormForge. The test case is updated whenever the ConfigMap changes.
+	ScriptConfigMapVersion string `json:"scriptConfigMapVersion,omitempty"`
+
+	// ClientCertificateChecksum is the SHA-256 checksum of the client
+	// certificate and private key last attached to the test case.
+	ClientCertificateChecksum string `json:"clientCertificateChecksum,omitempty"`
+
+	// DataSources are the data sources last uploaded to StormForge.
+	DataSources []TestCaseDataSourceObservation `json:"dataSources,omitempty"`
+
+	// AlertThresholds are the alert thresholds of the test case last
+	// observed in StormForge.
+	AlertThresholds []TestCaseAlertThresholdObservation `json:"alertThresholds,omitempty"`
+
+	// Clone records the test case this test case was cloned from and the
+	// scope it was cloned into, as of when it was first observed.
+	Clone *TestCaseClone `json:"clone,omitempty"`
+
+	// Revisions are the most recent revisions of the definition of the test
+	// case, newest first. StormForge records a revision whenever the
+	// definition changes, including changes made outside of Kubernetes.
+	Revisions []TestCaseRevision `json:"revisions,omitempty"`
+}
+
+// A TestCaseClone records the source and target of a cloned test case.
+type TestCaseClone struct {
+	// Source is the name of the TestCase that was cloned.
+	Source string `json:"source"`
+
+	// SourceID is the ID of the test case that was cloned.
+	SourceID string `json:"sourceID,omitempty"`
+
+	// SourceOrg is the organization of the test case that was cloned.
+	SourceOrg string `json:"sourceOrg,omitempty"`
+
+	// SourceProjectID is the ID of the project of the test case that was
+	// cloned, if any.
+	SourceProjectID string `json:"sourceProjectID,omitempty"`
+
+	// TargetOrg is the organization the test case was cloned into.
+	TargetOrg string `json:"targetOrg,omitempty"`
+
+	// TargetProjectID is the ID of the project the test case was cloned
+	// into, if any.
+	TargetProjectID string `json:"targetProjectID,omitempty"`
+}
+
+// A TestCaseLastRun summarizes the latest run of a test case.
+type TestCaseLastRun struct {
+	// ID of the run.
+	ID string `json:"id"`
+
+	// Phase of the run.
+	Phase TestRunPhase `json:"phase,omitempty"`
+
+	// StartedAt is the time the run started.
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// EndedAt is the time the run ended.
+	EndedAt *metav1.Time `json:"endedAt,omitempty"`
+
+	// Result of the run. It is reported once the run has ended.
+	Result *TestRunResult `json:"result,omitempty"`
+}
+
+// A TestCaseRevision is a version of the definition of a test case.
+type TestCaseRevision struct {
+	// ID of the revision.
+	ID string `json:"id"`
+
+	// CreatedAt is the time at which the revision was created.
+	CreatedAt *metav1.Time `json:"createdAt,omitempty"`
+
+	// Author is the user that created the revision.
+	Author string `json:"author,omitempty"`
+}
+
+// A TestCaseDataSourceObservation is a data source uploaded to StormForge.
+type TestCaseDataSourceObservation struct {
+	// Name of the data source.
+	Name string `json:"name"`
+
+	// ID of the data source.
+	ID string `json:"id"`
+
+	// Checksum is the SHA-256 checksum of the content last uploaded.
+	Checksum string `json:"checksum"`
 }
 
-// A TestCaseSpec defines the desired state of a MyType.
+// A TestCaseAlertThresholdObservation is an alert threshold of a test case in
+// StormForge.
+type TestCaseAlertThresholdObservation struct {
+	// Name of the threshold.
+	Name string `json:"name"`
+
+	// ID of the threshold.
+	ID string `json:"id"`
+}
+
+// A TestCaseSpec defines the desired state of a TestCase.
 type TestCaseSpec struct {
 	xpv1.ResourceSpec `json:",inline"`
 	ForProvider       TestCaseParameters `json:"forProvider"`
+
+	// ManagementPolicies are the actions the provider may take on the test
+	// case. The test case is always observed. For example, [Observe] observes
+	// an existing test case without ever changing or deleting it.
+	// +optional
+	// +kubebuilder:default={"*"}
+	ManagementPolicies []ManagementAction `json:"managementPolicies,omitempty"`
 }
 
-// A TestCaseStatus represents the observed state of a MyType.
+// A ManagementAction is an action the provider may take on an external
+// resource.
+// +kubebuilder:validation:Enum=Observe;Create;Update;Delete;LateInitialize;"*"
+type ManagementAction string
+
+// Management actions.
+const (
+	// ManagementActionObserve observes the external resource.
+	ManagementActionObserve ManagementAction = "Observe"
+
+	// ManagementActionCreate creates the external resource if it does not
+	// exist.
+	ManagementActionCreate ManagementAction = "Create"
+
+	// ManagementActionUpdate updates the external resource if it differs
+	// from the desired state.
+	ManagementActionUpdate ManagementAction = "Update"
+
+	// ManagementActionDelete deletes the external resource when the managed
+	// resource is deleted.
+	ManagementActionDelete ManagementAction = "Delete"
+
+	// ManagementActionLateInitialize sets unset fields of the managed
+	// resource to those of the external resource.
+	ManagementActionLateInitialize ManagementAction = "LateInitialize"
+
+	// ManagementActionAll takes every action.
+	ManagementActionAll ManagementAction = "*"
+)
+
+// A TestCaseStatus represents the observed state of a TestCase.
 type TestCaseStatus struct {
 	xpv1.ResourceStatus `json:",inline"`
 	AtProvider          TestCaseObservation `json:"atProvider,omitempty"`
@@ -47,12 +829,20 @@ type TestCaseStatus struct {
 
 // +kubebuilder:object:root=true
 
-// A MyType is an example API type.
+// A TestCase is a StormForge test case: a load test definition that runs can
+// be launched from. Deleting a TestCase deletes or, if its deletion behavior
+// is Archive, archives its test case, unless its deletion policy is Orphan.
+// Deletion is blocked while the TestCase is
+// annotated stormforge.io/deletion-protection: "true". Its external name is the ID of its test case, or
+// "org/name" until the test case has been created or adopted. A test case can
+// be managed by only one TestCase.
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.bindingPhase"
-// +kubebuilder:printcolumn:name="STATE",type="string",JSONPath=".status.atProvider.state"
+// +kubebuilder:printcolumn:name="ORG",type="string",JSONPath=".spec.forProvider.org"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="LAST-RUN-STATE",type="string",JSONPath=".status.atProvider.lastRunState"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
-// Please replace `PROVIDER-NAME` with your actual provider name, like `aws`, `azure`, `gcp`, `alibaba`
 // +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
 type TestCase struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -64,7 +854,7 @@ type TestCase struct {
 
 // +kubebuilder:object:root=true
 
-// MyTypeList contains a list of MyType
+// TestCaseList contains a list of TestCase
 type TestCaseList struct {
 	metav1.TypeMeta `json:",inline"`
 	metav1.ListMeta `json:"metadata,omitempty"`
@@ -0,0 +1,105 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// ApplicationParameters are the configurable fields of an Application.
+type ApplicationParameters struct {
+	// Resources are the Kubernetes workloads that are optimized.
+	// +kubebuilder:validation:MinItems=1
+	Resources []ApplicationResource `json:"resources"`
+
+	// Scenarios are the loads under which the workloads are optimized.
+	// +optional
+	Scenarios []Scenario `json:"scenarios,omitempty"`
+}
+
+// An ApplicationResource selects workloads in a Kubernetes namespace. All
+// workloads in the namespace are selected if neither a selector nor workloads
+// are specified.
+type ApplicationResource struct {
+	// Namespace of the workloads.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Selector is a label selector for the workloads, for example
+	// "tier=backend".
+	// +optional
+	Selector string `json:"selector,omitempty"`
+
+	// Workloads are selected by kind and name, for example
+	// "deployment/checkout".
+	// +optional
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+// A Scenario is a load under which an application is optimized.
+type Scenario struct {
+	// Name of the scenario.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// TestCase is the StormForge test case that generates the load, as
+	// "organization/name".
+	// +kubebuilder:validation:Pattern=`^[^/]+/[^/]+$`
+	TestCase string `json:"testCase"`
+}
+
+// An ApplicationSpec defines the desired state of an Application.
+type ApplicationSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ApplicationParameters `json:"forProvider"`
+}
+
+// An ApplicationStatus represents the observed state of an Application.
+type ApplicationStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+}
+
+// +kubebuilder:object:root=true
+
+// An Application is a StormForge Optimize application: the Kubernetes
+// workloads that are optimized, and the scenarios under which they are
+// optimized. Its external name is the name of the application, which defaults
+// to its name.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type Application struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ApplicationSpec   `json:"spec"`
+	Status ApplicationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ApplicationList contains a list of Application
+type ApplicationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Application `json:"items"`
+}
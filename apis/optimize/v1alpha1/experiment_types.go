@@ -0,0 +1,144 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A ParameterType is the type of the values of an experiment parameter.
+type ParameterType string
+
+// Types of experiment parameter.
+const (
+	ParameterInt         ParameterType = "int"
+	ParameterDouble      ParameterType = "double"
+	ParameterCategorical ParameterType = "categorical"
+)
+
+// ExperimentParameters are the configurable fields of an Experiment.
+type ExperimentParameters struct {
+	// Parameters are the values the experiment searches, for example the CPU
+	// and memory requests of a workload.
+	// +kubebuilder:validation:MinItems=1
+	Parameters []Parameter `json:"parameters"`
+
+	// Metrics are measured by each trial of the experiment.
+	// +kubebuilder:validation:MinItems=1
+	Metrics []Metric `json:"metrics"`
+
+	// TrialBudget is the number of trials the experiment may run.
+	// +optional
+	// +kubebuilder:validation:Minimum=1
+	TrialBudget *int64 `json:"trialBudget,omitempty"`
+}
+
+// A Parameter of an experiment. Numeric parameters are bounded by Min and Max;
+// categorical parameters take one of their Values.
+type Parameter struct {
+	// Name of the parameter.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Type of the values of the parameter.
+	// +optional
+	// +kubebuilder:validation:Enum=int;double;categorical
+	// +kubebuilder:default=int
+	Type ParameterType `json:"type,omitempty"`
+
+	// Min is the lowest value of a numeric parameter, for example "100".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^-?[0-9]+(\.[0-9]+)?$`
+	Min string `json:"min,omitempty"`
+
+	// Max is the highest value of a numeric parameter, for example "4000".
+	// +optional
+	// +kubebuilder:validation:Pattern=`^-?[0-9]+(\.[0-9]+)?$`
+	Max string `json:"max,omitempty"`
+
+	// Values a categorical parameter may take.
+	// +optional
+	Values []string `json:"values,omitempty"`
+}
+
+// A Metric is measured by each trial of an experiment.
+type Metric struct {
+	// Name of the metric.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Minimize the metric, rather than maximize it.
+	// +optional
+	Minimize bool `json:"minimize,omitempty"`
+
+	// Optimize the metric. Metrics that are not optimized are only measured,
+	// for example to check a constraint.
+	// +optional
+	// +kubebuilder:default=true
+	Optimize *bool `json:"optimize,omitempty"`
+}
+
+// ExperimentObservation are the observable fields of an Experiment.
+type ExperimentObservation struct {
+	// Observations is the number of trials of the experiment that have
+	// completed.
+	Observations int64 `json:"observations,omitempty"`
+}
+
+// An ExperimentSpec defines the desired state of an Experiment.
+type ExperimentSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       ExperimentParameters `json:"forProvider"`
+}
+
+// An ExperimentStatus represents the observed state of an Experiment.
+type ExperimentStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          ExperimentObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// An Experiment is a StormForge Optimize experiment. It searches for the
+// parameter values that optimize its metrics by running trials. Its external
+// name is the name of the experiment, which defaults to its name.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="BUDGET",type="integer",JSONPath=".spec.forProvider.trialBudget"
+// +kubebuilder:printcolumn:name="OBSERVATIONS",type="integer",JSONPath=".status.atProvider.observations"
+// +kubebuilder:printcolumn:name="EXTERNAL-NAME",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type Experiment struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ExperimentSpec   `json:"spec"`
+	Status ExperimentStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ExperimentList contains a list of Experiment
+type ExperimentList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Experiment `json:"items"`
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// An ApplyPolicy determines whether recommendations are generated for a
+// workload, and whether they are applied to it automatically.
+type ApplyPolicy string
+
+// Policies for applying recommendations.
+const (
+	// ApplyPolicyOff generates no recommendations.
+	ApplyPolicyOff ApplyPolicy = "off"
+
+	// ApplyPolicyRecommendOnly generates recommendations but leaves applying
+	// them to the workload's owner.
+	ApplyPolicyRecommendOnly ApplyPolicy = "recommend-only"
+
+	// ApplyPolicyAutoApply generates recommendations and applies them to the
+	// workload automatically.
+	ApplyPolicyAutoApply ApplyPolicy = "auto-apply"
+)
+
+// LiveWorkloadParameters are the configurable fields of a LiveWorkload.
+type LiveWorkloadParameters struct {
+	WorkloadReference `json:",inline"`
+
+	// ApplyPolicy determines whether recommendations are generated for the
+	// workload, and whether they are applied to it automatically.
+	// +optional
+	// +kubebuilder:validation:Enum=off;recommend-only;auto-apply
+	// +kubebuilder:default=recommend-only
+	ApplyPolicy ApplyPolicy `json:"applyPolicy,omitempty"`
+}
+
+// LiveWorkloadObservation are the observable fields of a LiveWorkload.
+type LiveWorkloadObservation struct {
+	// ApplyPolicy of the workload.
+	ApplyPolicy ApplyPolicy `json:"applyPolicy,omitempty"`
+}
+
+// A LiveWorkloadSpec defines the desired state of a LiveWorkload.
+type LiveWorkloadSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       LiveWorkloadParameters `json:"forProvider"`
+}
+
+// A LiveWorkloadStatus represents the observed state of a LiveWorkload.
+type LiveWorkloadStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          LiveWorkloadObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A LiveWorkload configures how StormForge Optimize Live treats a workload.
+// Deleting a LiveWorkload resets the workload to the defaults of its cluster.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="NAMESPACE",type="string",JSONPath=".spec.forProvider.namespace"
+// +kubebuilder:printcolumn:name="WORKLOAD",type="string",JSONPath=".spec.forProvider.name"
+// +kubebuilder:printcolumn:name="POLICY",type="string",JSONPath=".status.atProvider.applyPolicy"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type LiveWorkload struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   LiveWorkloadSpec   `json:"spec"`
+	Status LiveWorkloadStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LiveWorkloadList contains a list of LiveWorkload
+type LiveWorkloadList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LiveWorkload `json:"items"`
+}
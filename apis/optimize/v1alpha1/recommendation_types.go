@@ -0,0 +1,123 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// A WorkloadReference identifies a Kubernetes workload in a cluster reporting
+// to StormForge Optimize Live.
+type WorkloadReference struct {
+	// Cluster the workload runs in, as registered with StormForge.
+	// +kubebuilder:validation:MinLength=1
+	Cluster string `json:"cluster"`
+
+	// Namespace of the workload.
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+
+	// Kind of the workload.
+	// +optional
+	// +kubebuilder:validation:Enum=Deployment;StatefulSet;DaemonSet
+	// +kubebuilder:default=Deployment
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the workload.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// RecommendationParameters identify the workload a Recommendation observes.
+type RecommendationParameters struct {
+	WorkloadReference `json:",inline"`
+}
+
+// RecommendationObservation are the observable fields of a Recommendation.
+type RecommendationObservation struct {
+	// GeneratedAt is the time at which the recommendation was generated.
+	GeneratedAt *metav1.Time `json:"generatedAt,omitempty"`
+
+	// Containers of the workload and the resources recommended for them.
+	Containers []ContainerRecommendation `json:"containers,omitempty"`
+}
+
+// A ContainerRecommendation recommends the resources of a container.
+type ContainerRecommendation struct {
+	// Name of the container.
+	Name string `json:"name"`
+
+	// Requests recommended for the container.
+	Requests RecommendedResources `json:"requests,omitempty"`
+
+	// Limits recommended for the container.
+	Limits RecommendedResources `json:"limits,omitempty"`
+}
+
+// RecommendedResources are recommended quantities of CPU and memory.
+type RecommendedResources struct {
+	// CPU recommended, for example "250m".
+	CPU string `json:"cpu,omitempty"`
+
+	// Memory recommended, for example "512Mi".
+	Memory string `json:"memory,omitempty"`
+}
+
+// A RecommendationSpec defines the desired state of a Recommendation.
+type RecommendationSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       RecommendationParameters `json:"forProvider"`
+}
+
+// A RecommendationStatus represents the observed state of a Recommendation.
+type RecommendationStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          RecommendationObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Recommendation is the latest StormForge resource recommendation for a
+// workload. Recommendations cannot be created or deleted using Crossplane; a
+// Recommendation reflects the CPU and memory StormForge currently recommends
+// for each container of its workload in its status.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="NAMESPACE",type="string",JSONPath=".spec.forProvider.namespace"
+// +kubebuilder:printcolumn:name="WORKLOAD",type="string",JSONPath=".spec.forProvider.name"
+// +kubebuilder:printcolumn:name="GENERATED",type="date",JSONPath=".status.atProvider.generatedAt"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type Recommendation struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RecommendationSpec   `json:"spec"`
+	Status RecommendationStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RecommendationList contains a list of Recommendation
+type RecommendationList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Recommendation `json:"items"`
+}
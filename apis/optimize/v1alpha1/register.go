@@ -0,0 +1,86 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+// Package type metadata.
+const (
+	Group   = "optimize.stormforge.io"
+	Version = "v1alpha1"
+)
+
+var (
+	// SchemeGroupVersion is group version used to register these objects
+	SchemeGroupVersion = schema.GroupVersion{Group: Group, Version: Version}
+
+	// SchemeBuilder is used to add go types to the GroupVersionKind scheme
+	SchemeBuilder = &scheme.Builder{GroupVersion: SchemeGroupVersion}
+)
+
+// Experiment type metadata.
+var (
+	ExperimentKind             = reflect.TypeOf(Experiment{}).Name()
+	ExperimentGroupKind        = schema.GroupKind{Group: Group, Kind: ExperimentKind}.String()
+	ExperimentKindAPIVersion   = ExperimentKind + "." + SchemeGroupVersion.String()
+	ExperimentGroupVersionKind = SchemeGroupVersion.WithKind(ExperimentKind)
+)
+
+// Application type metadata.
+var (
+	ApplicationKind             = reflect.TypeOf(Application{}).Name()
+	ApplicationGroupKind        = schema.GroupKind{Group: Group, Kind: ApplicationKind}.String()
+	ApplicationKindAPIVersion   = ApplicationKind + "." + SchemeGroupVersion.String()
+	ApplicationGroupVersionKind = SchemeGroupVersion.WithKind(ApplicationKind)
+)
+
+// Trial type metadata.
+var (
+	TrialKind             = reflect.TypeOf(Trial{}).Name()
+	TrialGroupKind        = schema.GroupKind{Group: Group, Kind: TrialKind}.String()
+	TrialKindAPIVersion   = TrialKind + "." + SchemeGroupVersion.String()
+	TrialGroupVersionKind = SchemeGroupVersion.WithKind(TrialKind)
+)
+
+// Recommendation type metadata.
+var (
+	RecommendationKind             = reflect.TypeOf(Recommendation{}).Name()
+	RecommendationGroupKind        = schema.GroupKind{Group: Group, Kind: RecommendationKind}.String()
+	RecommendationKindAPIVersion   = RecommendationKind + "." + SchemeGroupVersion.String()
+	RecommendationGroupVersionKind = SchemeGroupVersion.WithKind(RecommendationKind)
+)
+
+// LiveWorkload type metadata.
+var (
+	LiveWorkloadKind             = reflect.TypeOf(LiveWorkload{}).Name()
+	LiveWorkloadGroupKind        = schema.GroupKind{Group: Group, Kind: LiveWorkloadKind}.String()
+	LiveWorkloadKindAPIVersion   = LiveWorkloadKind + "." + SchemeGroupVersion.String()
+	LiveWorkloadGroupVersionKind = SchemeGroupVersion.WithKind(LiveWorkloadKind)
+)
+
+func init() {
+	SchemeBuilder.Register(&Experiment{}, &ExperimentList{})
+	SchemeBuilder.Register(&Trial{}, &TrialList{})
+	SchemeBuilder.Register(&Application{}, &ApplicationList{})
+	SchemeBuilder.Register(&Recommendation{}, &RecommendationList{})
+	SchemeBuilder.Register(&LiveWorkload{}, &LiveWorkloadList{})
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+)
+
+// TrialParameters identify the trial a Trial observes.
+type TrialParameters struct {
+	// Experiment is the name of the experiment the trial belongs to.
+	// +kubebuilder:validation:MinLength=1
+	Experiment string `json:"experiment"`
+}
+
+// TrialObservation are the observable fields of a Trial.
+type TrialObservation struct {
+	// Number of the trial within its experiment.
+	Number int64 `json:"number,omitempty"`
+
+	// Status of the trial; one of active, completed, failed or abandoned.
+	Status string `json:"status,omitempty"`
+
+	// Assignments of a value to each parameter of the experiment.
+	Assignments []TrialAssignment `json:"assignments,omitempty"`
+
+	// Values of the metrics of the experiment observed by the trial.
+	Values []TrialValue `json:"values,omitempty"`
+
+	// StartTime is the time at which the trial started.
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// CompletionTime is the time at which the trial completed, if it has.
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// A TrialAssignment is the value a trial assigned to a parameter.
+type TrialAssignment struct {
+	// Parameter the value was assigned to.
+	Parameter string `json:"parameter"`
+
+	// Value assigned to the parameter.
+	Value string `json:"value"`
+}
+
+// A TrialValue is the value of a metric observed by a trial.
+type TrialValue struct {
+	// Metric that was observed.
+	Metric string `json:"metric"`
+
+	// Value of the metric.
+	Value string `json:"value"`
+
+	// Error is the uncertainty of the value, if known.
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// A TrialSpec defines the desired state of a Trial.
+type TrialSpec struct {
+	xpv1.ResourceSpec `json:",inline"`
+	ForProvider       TrialParameters `json:"forProvider"`
+}
+
+// A TrialStatus represents the observed state of a Trial.
+type TrialStatus struct {
+	xpv1.ResourceStatus `json:",inline"`
+	AtProvider          TrialObservation `json:"atProvider,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// A Trial is a trial of a StormForge Optimize experiment. Trials cannot be
+// created or deleted using Crossplane; a Trial observes the existing trial
+// whose number is its external name, reflecting its parameter assignments and
+// observed metric values in its status each time it is polled.
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="EXPERIMENT",type="string",JSONPath=".spec.forProvider.experiment"
+// +kubebuilder:printcolumn:name="NUMBER",type="string",JSONPath=".metadata.annotations.crossplane\\.io/external-name"
+// +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.atProvider.status"
+// +kubebuilder:printcolumn:name="READY",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="SYNCED",type="string",JSONPath=".status.conditions[?(@.type=='Synced')].status"
+// +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
+// +kubebuilder:resource:scope=Cluster,categories={crossplane,managed,stormforge}
+type Trial struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   TrialSpec   `json:"spec"`
+	Status TrialStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// TrialList contains a list of Trial
+type TrialList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Trial `json:"items"`
+}
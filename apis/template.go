@@ -21,6 +21,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 
 	loadv1alpha1 "github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	optimizev1alpha1 "github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
 	templatev1alpha1 "github.com/luebken/provider-stormforge/apis/v1alpha1"
 )
 
@@ -29,6 +30,7 @@ func init() {
 	AddToSchemes = append(AddToSchemes,
 		templatev1alpha1.SchemeBuilder.AddToScheme,
 		loadv1alpha1.SchemeBuilder.AddToScheme,
+		optimizev1alpha1.SchemeBuilder.AddToScheme,
 	)
 }
 
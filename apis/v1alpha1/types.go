@@ -26,12 +26,65 @@ import (
 type ProviderConfigSpec struct {
 	// Credentials required to authenticate to this provider.
 	Credentials ProviderCredentials `json:"credentials"`
+
+	// Endpoint is the base URL of the StormForge API, for example a staging
+	// environment or a local mock. Defaults to the public StormForge API.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// OptimizeEndpoint is the base URL of the StormForge Optimize API used by
+	// Optimize resources such as Experiments. Defaults to the public
+	// StormForge Optimize API.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	OptimizeEndpoint string `json:"optimizeEndpoint,omitempty"`
+
+	// Proxy is the URL of an HTTP proxy through which requests to the
+	// StormForge API are sent. The proxy configured by the provider's
+	// environment, if any, is used by default.
+	// +optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	Proxy string `json:"proxy,omitempty"`
+
+	// CABundle is a PEM encoded bundle of certificate authorities trusted in
+	// addition to the system's when connecting to the StormForge API or the
+	// proxy.
+	// +optional
+	CABundle *CABundleSource `json:"caBundle,omitempty"`
+}
+
+// A CABundleSource references a PEM encoded certificate authority bundle.
+// Exactly one of its references should be set.
+type CABundleSource struct {
+	// SecretRef references a key of a Secret containing the bundle.
+	// +optional
+	SecretRef *xpv1.SecretKeySelector `json:"secretRef,omitempty"`
+
+	// ConfigMapRef references a key of a ConfigMap containing the bundle.
+	// +optional
+	ConfigMapRef *ConfigMapKeySelector `json:"configMapRef,omitempty"`
+}
+
+// A ConfigMapKeySelector references a key of a ConfigMap in an arbitrary
+// namespace.
+type ConfigMapKeySelector struct {
+	// Name of the ConfigMap.
+	Name string `json:"name"`
+
+	// Namespace of the ConfigMap.
+	Namespace string `json:"namespace"`
+
+	// Key within the ConfigMap.
+	Key string `json:"key"`
 }
 
 // ProviderCredentials required to authenticate.
 type ProviderCredentials struct {
-	// Source of the provider credentials.
-	// +kubebuilder:validation:Enum=None;Secret;InjectedIdentity;Environment;Filesystem
+	// Source of the provider credentials. In addition to None, Secret,
+	// InjectedIdentity, Environment and Filesystem, any source with an
+	// extractor registered with the provider may be used. Connecting with any
+	// other source fails with an error listing the supported sources.
 	Source xpv1.CredentialsSource `json:"source"`
 
 	xpv1.CommonCredentialSelectors `json:",inline"`
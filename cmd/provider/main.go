@@ -19,24 +19,46 @@ package main
 import (
 	"os"
 	"path/filepath"
+	"strconv"
 
 	"gopkg.in/alecthomas/kingpin.v2"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 
 	"github.com/luebken/provider-stormforge/apis"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
 	"github.com/luebken/provider-stormforge/internal/controller"
+	"github.com/luebken/provider-stormforge/internal/controller/testcase"
+	"github.com/luebken/provider-stormforge/internal/controller/testrun"
+	"github.com/luebken/provider-stormforge/internal/fetch"
 )
 
 func main() {
 	var (
 		app            = kingpin.New(filepath.Base(os.Args[0]), "Template support for Crossplane.").DefaultEnvars()
 		debug          = app.Flag("debug", "Run with debug logging.").Short('d').Bool()
+		debugHTTP      = app.Flag("debug-http", "Log requests to and responses from the StormForge API, with credentials redacted.").Bool()
 		syncPeriod     = app.Flag("sync", "Controller manager sync period such as 300ms, 1.5h, or 2h45m").Short('s').Default("1h").Duration()
 		leaderElection = app.Flag("leader-election", "Use leader election for the controller manager.").Short('l').Default("false").OverrideDefaultFromEnvar("LEADER_ELECTION").Bool()
+		apiTimeout     = app.Flag("api-timeout", "Timeout for each call to the StormForge API such as 30s or 1m. Zero disables it.").Default(stormforge.DefaultTimeout.String()).Duration()
+		apiQPS         = app.Flag("api-qps", "Maximum rate of calls to the StormForge API per organization.").Default(strconv.Itoa(stormforge.DefaultQPS)).Float64()
+		apiBurst       = app.Flag("api-burst", "Maximum burst of calls to the StormForge API per organization.").Default(strconv.Itoa(stormforge.DefaultBurst)).Int()
+		apiCacheTTL    = app.Flag("api-cache-ttl", "How long test cases listed from the StormForge API are cached such as 30s or 1m. Zero disables caching.").Default(stormforge.DefaultCacheTTL.String()).Duration()
+		apiLeeway      = app.Flag("api-token-leeway", "How long after its expiry a StormForge API token is still used, to tolerate clock skew, such as 30s or 1m.").Default(stormforge.DefaultTokenLeeway.String()).Duration()
+		apiMinConc     = app.Flag("api-min-concurrency", "Minimum number of requests in flight to the StormForge API while it is rate limiting or failing requests.").Default(strconv.Itoa(stormforge.DefaultMinConcurrency)).Int()
+		apiMaxConc     = app.Flag("api-max-concurrency", "Maximum number of requests in flight to the StormForge API while it is healthy.").Default(strconv.Itoa(stormforge.DefaultMaxConcurrency)).Int()
+		apiCondCache   = app.Flag("api-conditional-cache-size", "How many StormForge API responses are kept to revalidate with conditional GETs. Zero disables conditional GETs.").Default(strconv.Itoa(stormforge.DefaultConditionalCacheSize)).Int()
+		exportResults  = app.Flag("export-run-results", "Export the results of the latest completed run of each TestCase as Prometheus metrics.").Bool()
+		maxResultTCs   = app.Flag("export-run-results-max-test-cases", "Maximum number of TestCases whose latest run result is exported as Prometheus metrics.").Default(strconv.Itoa(testrun.DefaultMaxResultTestCases)).Int()
+		maxRevisions   = app.Flag("max-revisions", "Number of the most recent revisions of a test case's definition reported in the status of its TestCase.").Default(strconv.Itoa(testcase.DefaultMaxRevisions)).Int()
+		scriptTimeout  = app.Flag("script-fetch-timeout", "How long fetching a TestCase script from a URL or Git repository may take.").Default(fetch.DefaultTimeout.String()).Duration()
+		scriptSchemes  = app.Flag("script-fetch-allowed-scheme", "URL scheme TestCase scripts may be fetched from. May be repeated.").Default(fetch.DefaultSchemes...).Strings()
+		scriptHosts    = app.Flag("script-fetch-allowed-host", "Host TestCase scripts may be fetched from, such as github.com or *.example.org. May be repeated. Any host is allowed if unset.").Strings()
+		maxStaleness   = app.Flag("max-observation-staleness", "How long a test case may be observed from cached StormForge API responses before it is read from the API again such as 10m or 1h. Zero disables the limit.").Default(testcase.DefaultMaxStaleness.String()).Duration()
 	)
 	kingpin.MustParse(app.Parse(os.Args[1:]))
 
@@ -49,7 +71,7 @@ func main() {
 		ctrl.SetLogger(zl)
 	}
 
-	log.Debug("Starting", "sync-period", syncPeriod.String())
+	log.Debug("Starting", "sync-period", syncPeriod.String(), "api-timeout", apiTimeout.String(), "api-token-leeway", apiLeeway.String(), "api-qps", *apiQPS, "api-burst", *apiBurst, "api-min-concurrency", *apiMinConc, "api-max-concurrency", *apiMaxConc, "api-cache-ttl", apiCacheTTL.String(), "api-conditional-cache-size", *apiCondCache, "max-observation-staleness", maxStaleness.String(), "max-revisions", *maxRevisions, "script-fetch-timeout", scriptTimeout.String(), "script-fetch-allowed-schemes", *scriptSchemes, "script-fetch-allowed-hosts", *scriptHosts, "export-run-results", *exportResults)
 
 	cfg, err := ctrl.GetConfig()
 	kingpin.FatalIfError(err, "Cannot get API server rest config")
@@ -63,6 +85,28 @@ func main() {
 
 	rl := ratelimiter.NewDefaultProviderRateLimiter(ratelimiter.DefaultProviderRPS)
 	kingpin.FatalIfError(apis.AddToScheme(mgr.GetScheme()), "Cannot add Template APIs to scheme")
-	kingpin.FatalIfError(controller.Setup(mgr, log, rl), "Cannot setup Template controllers")
+	al := stormforge.NewAdaptiveLimiter(*apiMinConc, *apiMaxConc)
+	kingpin.FatalIfError(metrics.Registry.Register(al), "Cannot register StormForge API metrics")
+	if *exportResults {
+		kingpin.FatalIfError(metrics.Registry.Register(testrun.NewResultCollector(mgr.GetClient(), *maxResultTCs)), "Cannot register run result metrics")
+	}
+	co := []stormforge.Option{
+		stormforge.WithTimeout(*apiTimeout),
+		stormforge.WithTokenLeeway(*apiLeeway),
+		stormforge.WithRateLimiter(stormforge.NewRateLimiter(*apiQPS, *apiBurst)),
+		stormforge.WithAdaptiveLimiter(al),
+		stormforge.WithCache(stormforge.NewCache(*apiCacheTTL)),
+		stormforge.WithConditionalCache(stormforge.NewConditionalCache(*apiCondCache)),
+	}
+	if *debugHTTP {
+		co = append(co, stormforge.WithDebugLogger(log))
+	}
+	kingpin.FatalIfError(controller.Setup(mgr, log, rl, testcase.Options{
+		MaxStaleness:  *maxStaleness,
+		MaxRevisions:  *maxRevisions,
+		ScriptTimeout: *scriptTimeout,
+		ScriptSchemes: *scriptSchemes,
+		ScriptHosts:   *scriptHosts,
+	}, co...), "Cannot setup Template controllers")
 	kingpin.FatalIfError(mgr.Start(ctrl.SetupSignalHandler()), "Cannot start controller manager")
 }
@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+// azureVersion is the version of the Azure Blob Storage API requests are sent
+// with.
+const azureVersion = "2020-04-08"
+
+// An AzureBlob Bucket stores block blobs in a container of an Azure Storage
+// account, authenticating with a shared access signature (SAS).
+type AzureBlob struct {
+	*config
+	container string
+	sas       string
+}
+
+// NewAzureBlob returns an AzureBlob Bucket that stores blobs in the supplied
+// container of the supplied storage account. The SAS token must allow blobs to
+// be created and written.
+func NewAzureBlob(account, container, sasToken string, o ...Option) *AzureBlob {
+	return &AzureBlob{
+		config:    newConfig("https://"+account+".blob.core.windows.net", o...),
+		container: container,
+		sas:       strings.TrimPrefix(strings.TrimSpace(sasToken), "?"),
+	}
+}
+
+// Put stores the supplied content as a block blob named by the supplied key.
+func (b *AzureBlob) Put(ctx context.Context, key string, content io.ReadSeeker) error {
+	req, err := newPut(ctx, b.URL(key)+"?"+b.sas, content)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Ms-Blob-Type", "BlockBlob")
+	req.Header.Set("X-Ms-Version", azureVersion)
+	return put(b.http, req, key)
+}
+
+// URL returns the URL of the blob named by the supplied key. The URL does not
+// include the SAS token.
+func (b *AzureBlob) URL(key string) string {
+	return b.endpoint + "/" + escape(b.container) + "/" + escapePath(key)
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package blob contains clients that store objects in Amazon S3, Google Cloud
+// Storage and Azure Blob Storage.
+package blob
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errNewRequest = "cannot create request"
+	errDoRequest  = "cannot send request"
+	errReadObject = "cannot read object content"
+	errPutFmt     = "cannot store object %q: %s"
+)
+
+// DefaultTimeout bounds each request a Bucket sends, including streaming the
+// object, unless another HTTP client is supplied.
+const DefaultTimeout = 10 * time.Minute
+
+// maxErrorBody bounds how much of the body of an unsuccessful response is
+// reported.
+const maxErrorBody = 1 << 10
+
+// A Bucket stores objects.
+type Bucket interface {
+	// Put stores the supplied content under the supplied key. The content is
+	// streamed from its start, and may be read more than once.
+	Put(ctx context.Context, key string, content io.ReadSeeker) error
+
+	// URL returns the URL of the object stored under the supplied key.
+	URL(key string) string
+}
+
+type config struct {
+	endpoint string
+	http     *http.Client
+}
+
+// An Option configures a Bucket.
+type Option func(c *config)
+
+// WithEndpoint configures the endpoint of the object storage service a Bucket
+// talks to, for example that of an S3 compatible service.
+func WithEndpoint(endpoint string) Option {
+	return func(c *config) {
+		c.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithHTTPClient configures the HTTP client used to send requests. By default
+// a client with a timeout of DefaultTimeout is used.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *config) {
+		c.http = hc
+	}
+}
+
+func newConfig(endpoint string, o ...Option) *config {
+	c := &config{endpoint: endpoint, http: &http.Client{Timeout: DefaultTimeout}}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// put sends the supplied PUT request of the object with the supplied key.
+func put(hc *http.Client, req *http.Request, key string) error {
+	rsp, err := hc.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errDoRequest)
+	}
+	defer rsp.Body.Close() //nolint:errcheck
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, maxErrorBody))
+		msg := rsp.Status
+		if s := strings.TrimSpace(string(b)); s != "" {
+			msg += ": " + s
+		}
+		return errors.Errorf(errPutFmt, key, msg)
+	}
+	return nil
+}
+
+// newPut returns a PUT request that streams the supplied content to the
+// supplied URL. Object storage services require the length of the content up
+// front, so it is found by seeking to its end.
+func newPut(ctx context.Context, url string, content io.ReadSeeker) (*http.Request, error) {
+	size, err := content.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, errors.Wrap(err, errReadObject)
+	}
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return nil, errors.Wrap(err, errReadObject)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, ioutil.NopCloser(content))
+	if err != nil {
+		return nil, errors.Wrap(err, errNewRequest)
+	}
+	req.ContentLength = size
+	req.GetBody = func() (io.ReadCloser, error) {
+		_, err := content.Seek(0, io.SeekStart)
+		return ioutil.NopCloser(content), err
+	}
+	if size == 0 {
+		req.Body = http.NoBody
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	return req, nil
+}
+
+// escapePath escapes each segment of the supplied slash separated path, as
+// required by S3 and Azure Blob Storage.
+func escapePath(p string) string {
+	segs := strings.Split(p, "/")
+	for i, s := range segs {
+		segs[i] = escape(s)
+	}
+	return strings.Join(segs, "/")
+}
+
+// escape percent-encodes all but the unreserved characters of RFC 3986.
+func escape(s string) string {
+	b := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if ('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') || c == '-' || c == '.' || c == '_' || c == '~' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
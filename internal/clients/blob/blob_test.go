@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// serve returns the URL of a test server that handles requests with the
+// supplied handler.
+func serve(t *testing.T, h http.HandlerFunc) string {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestSigningKey(t *testing.T) {
+	// The example of the AWS General Reference.
+	got := hex.EncodeToString(signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam"))
+	want := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("signingKey(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestS3Put(t *testing.T) {
+	content := []byte("{\"status\":200}\n")
+	sum := sha256.Sum256(content)
+
+	type want struct {
+		url string
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		new    func(endpoint string) *S3
+		status int
+		path   string
+		want   want
+	}{
+		"S3": {
+			reason: "Objects should be stored by path in the bucket of an S3 compatible endpoint.",
+			new: func(endpoint string) *S3 {
+				return NewS3("results", "eu-west-1", Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret", SessionToken: "session"}, WithEndpoint(endpoint))
+			},
+			status: http.StatusOK,
+			path:   "/results/runs/r%201/metrics",
+			want:   want{url: "s3://results/runs/r 1/metrics"},
+		},
+		"GCS": {
+			reason: "Objects stored in Google Cloud Storage should be reported by their gs:// URL.",
+			new: func(endpoint string) *S3 {
+				return NewGCS("results", Credentials{AccessKeyID: "GOOG1", SecretAccessKey: "secret"}, WithEndpoint(endpoint))
+			},
+			status: http.StatusOK,
+			path:   "/results/runs/r%201/metrics",
+			want:   want{url: "gs://results/runs/r 1/metrics"},
+		},
+		"Denied": {
+			reason: "Unsuccessful responses should be returned as errors.",
+			new: func(endpoint string) *S3 {
+				return NewS3("results", "eu-west-1", Credentials{AccessKeyID: "AKID", SecretAccessKey: "secret"}, WithEndpoint(endpoint))
+			},
+			status: http.StatusForbidden,
+			path:   "/results/runs/r%201/metrics",
+			want: want{
+				url: "s3://results/runs/r 1/metrics",
+				err: errors.Errorf(errPutFmt, "runs/r 1/metrics", "403 Forbidden: <Error><Code>AccessDenied</Code></Error>"),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var b *S3
+			endpoint := serve(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPut || r.URL.EscapedPath() != tc.path {
+					t.Errorf("\n%s\nrequest: want PUT %s, got %s %s", tc.reason, tc.path, r.Method, r.URL.EscapedPath())
+				}
+				auth := r.Header.Get("Authorization")
+				prefix := "AWS4-HMAC-SHA256 Credential=" + b.creds.AccessKeyID + "/20201201/" + b.region + "/s3/aws4_request, SignedHeaders=host;x-amz-content-sha256;x-amz-date"
+				if !strings.HasPrefix(auth, prefix) {
+					t.Errorf("\n%s\nAuthorization header: want prefix %q, got %q", tc.reason, prefix, auth)
+				}
+				if got, want := r.Header.Get("X-Amz-Content-Sha256"), hex.EncodeToString(sum[:]); got != want {
+					t.Errorf("\n%s\nX-Amz-Content-Sha256 header: want %q, got %q", tc.reason, want, got)
+				}
+				if r.ContentLength != int64(len(content)) {
+					t.Errorf("\n%s\nContent-Length: want %d, got %d", tc.reason, len(content), r.ContentLength)
+				}
+				if got, _ := ioutil.ReadAll(r.Body); string(got) != string(content) {
+					t.Errorf("\n%s\nbody: want %q, got %q", tc.reason, content, got)
+				}
+				w.WriteHeader(tc.status)
+				if tc.status != http.StatusOK {
+					_, _ = w.Write([]byte("<Error><Code>AccessDenied</Code></Error>"))
+				}
+			})
+			b = tc.new(endpoint)
+			b.now = func() time.Time { return time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC) }
+
+			err := b.Put(context.Background(), "runs/r 1/metrics", bytes.NewReader(content))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nb.Put(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.url, b.URL("runs/r 1/metrics")); diff != "" {
+				t.Errorf("\n%s\nb.URL(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAzureBlobPut(t *testing.T) {
+	endpoint := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/results/runs/r1/logs" {
+			t.Errorf("request: want PUT /results/runs/r1/logs, got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.RawQuery; got != "sv=2020-04-08&sig=abc" {
+			t.Errorf("query: want the SAS token, got %q", got)
+		}
+		if got := r.Header.Get("X-Ms-Blob-Type"); got != "BlockBlob" {
+			t.Errorf("X-Ms-Blob-Type header: want BlockBlob, got %q", got)
+		}
+		w.WriteHeader(http.StatusCreated)
+	})
+
+	b := NewAzureBlob("acme", "results", "?sv=2020-04-08&sig=abc\n", WithEndpoint(endpoint))
+	if err := b.Put(context.Background(), "runs/r1/logs", strings.NewReader("started\n")); err != nil {
+		t.Fatalf("b.Put(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(endpoint+"/results/runs/r1/logs", b.URL("runs/r1/logs")); diff != "" {
+		t.Errorf("b.URL(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestDefaultHTTPClient(t *testing.T) {
+	b := NewS3("results", "eu-west-1", Credentials{})
+	if b.http == http.DefaultClient || b.http.Timeout != DefaultTimeout {
+		t.Errorf("NewS3(...): want an HTTP client with a timeout of %s, got %s", DefaultTimeout, b.http.Timeout)
+	}
+}
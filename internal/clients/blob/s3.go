@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package blob
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultGCSEndpoint is the S3 compatible XML API of Google Cloud Storage.
+const DefaultGCSEndpoint = "https://storage.googleapis.com"
+
+// Credentials are an access key used to sign requests to Amazon S3, or an HMAC
+// key of Google Cloud Storage.
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+
+	// SessionToken of temporary credentials, if any.
+	SessionToken string
+}
+
+// An S3 Bucket stores objects in Amazon S3 or an S3 compatible service,
+// signing requests with AWS Signature Version 4.
+type S3 struct {
+	*config
+	scheme string
+	bucket string
+	region string
+	creds  Credentials
+	now    func() time.Time
+}
+
+// NewS3 returns an S3 Bucket that stores objects in the supplied bucket of
+// the supplied AWS region. Virtual-hosted style requests are sent to Amazon
+// S3 unless another endpoint is supplied, in which case path style requests
+// are sent to that endpoint.
+func NewS3(bucket, region string, c Credentials, o ...Option) *S3 {
+	return &S3{config: newConfig("", o...), scheme: "s3", bucket: bucket, region: region, creds: c, now: time.Now}
+}
+
+// NewGCS returns an S3 Bucket that stores objects in the supplied bucket of
+// Google Cloud Storage, authenticating with the supplied HMAC key.
+func NewGCS(bucket string, c Credentials, o ...Option) *S3 {
+	return &S3{config: newConfig(DefaultGCSEndpoint, o...), scheme: "gs", bucket: bucket, region: "auto", creds: c, now: time.Now}
+}
+
+// Put stores the supplied content under the supplied key. The content is read
+// twice: once to sign its digest, then again to send it.
+func (b *S3) Put(ctx context.Context, key string, content io.ReadSeeker) error {
+	u := b.endpoint + "/" + escape(b.bucket) + "/" + escapePath(key)
+	if b.endpoint == "" {
+		u = "https://" + b.bucket + ".s3." + b.region + ".amazonaws.com/" + escapePath(key)
+	}
+	payload, err := payloadHash(content)
+	if err != nil {
+		return err
+	}
+	req, err := newPut(ctx, u, content)
+	if err != nil {
+		return err
+	}
+	b.sign(req, payload)
+	return put(b.http, req, key)
+}
+
+// payloadHash returns the hex encoded SHA-256 digest of the supplied content,
+// as signed by AWS Signature Version 4.
+func payloadHash(content io.ReadSeeker) (string, error) {
+	if _, err := content.Seek(0, io.SeekStart); err != nil {
+		return "", errors.Wrap(err, errReadObject)
+	}
+	h := sha256.New()
+	if _, err := io.Copy(h, content); err != nil {
+		return "", errors.Wrap(err, errReadObject)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// URL returns the s3:// or gs:// URL of the object stored under the supplied
+// key.
+func (b *S3) URL(key string) string {
+	return b.scheme + "://" + b.bucket + "/" + key
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to the supplied
+// request, whose body has the supplied payload hash.
+func (b *S3) sign(req *http.Request, payload string) {
+	t := b.now().UTC()
+	date := t.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", t.Format("20060102T150405Z"))
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+	if b.creds.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", b.creds.SessionToken)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for k := range req.Header {
+		if lk := strings.ToLower(k); strings.HasPrefix(lk, "x-amz-") {
+			headers[lk] = strings.TrimSpace(req.Header.Get(k))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	canonical := strings.Builder{}
+	for _, k := range names {
+		canonical.WriteString(k + ":" + headers[k] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	request := strings.Join([]string{req.Method, req.URL.EscapedPath(), req.URL.RawQuery, canonical.String(), signed, payload}, "\n")
+	scope := date + "/" + b.region + "/s3/aws4_request"
+	rsum := sha256.Sum256([]byte(request))
+	toSign := strings.Join([]string{"AWS4-HMAC-SHA256", t.Format("20060102T150405Z"), scope, hex.EncodeToString(rsum[:])}, "\n")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey(b.creds.SecretAccessKey, date, b.region, "s3"), toSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+b.creds.AccessKeyID+"/"+scope+", SignedHeaders="+signed+", Signature="+signature)
+}
+
+// signingKey derives the AWS Signature Version 4 signing key of the supplied
+// secret for the supplied date, region and service.
+func signingKey(secret, date, region, service string) []byte {
+	k := hmacSHA256([]byte("AWS4"+secret), date)
+	k = hmacSHA256(k, region)
+	k = hmacSHA256(k, service)
+	return hmacSHA256(k, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	_, _ = h.Write([]byte(data))
+	return h.Sum(nil)
+}
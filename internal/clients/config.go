@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package clients configures the clients used by the managed resource
+// controllers from a ProviderConfig.
+package clients
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+
+	apisv1alpha1 "github.com/luebken/provider-stormforge/apis/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+// AnnotationKeyDebugHTTP enables logging of the requests to and responses from
+// the StormForge API of clients configured by a ProviderConfig when it is set
+// to "true" on the ProviderConfig. Credentials are redacted from the logs.
+const AnnotationKeyDebugHTTP = "stormforge.io/debug-http"
+
+const (
+	errNoCABundleRef = "CA bundle must reference a Secret or a ConfigMap"
+	errGetCABundle   = "cannot get CA bundle"
+	errNewTransport  = "cannot configure HTTP transport"
+)
+
+// Config is the StormForge client configuration of a ProviderConfig, with any
+// referenced data resolved.
+type Config struct {
+	Endpoint         string
+	OptimizeEndpoint string
+	Proxy            string
+	CABundle         []byte
+	DebugHTTP        bool
+}
+
+// GetConfig returns the StormForge client configuration of the supplied
+// ProviderConfig.
+func GetConfig(ctx context.Context, kube client.Client, pc *apisv1alpha1.ProviderConfig) (*Config, error) {
+	cfg := &Config{
+		Endpoint:         pc.Spec.Endpoint,
+		OptimizeEndpoint: pc.Spec.OptimizeEndpoint,
+		Proxy:            pc.Spec.Proxy,
+		DebugHTTP:        pc.GetAnnotations()[AnnotationKeyDebugHTTP] == "true",
+	}
+	if pc.Spec.CABundle != nil {
+		b, err := caBundle(ctx, kube, pc.Spec.CABundle)
+		if err != nil {
+			return nil, errors.Wrap(err, errGetCABundle)
+		}
+		cfg.CABundle = b
+	}
+	return cfg, nil
+}
+
+// Options returns the StormForge client options of the configuration. Requests
+// and responses are logged to the supplied logger if HTTP debugging is enabled.
+func (cfg *Config) Options(log logging.Logger) ([]stormforge.Option, error) {
+	o := []stormforge.Option{}
+	if cfg.Endpoint != "" {
+		o = append(o, stormforge.WithEndpoint(cfg.Endpoint))
+	}
+	if cfg.OptimizeEndpoint != "" {
+		o = append(o, stormforge.WithOptimizeEndpoint(cfg.OptimizeEndpoint))
+	}
+	if cfg.DebugHTTP {
+		o = append(o, stormforge.WithDebugLogger(log))
+	}
+
+	if cfg.Proxy == "" && len(cfg.CABundle) == 0 {
+		return o, nil
+	}
+
+	t, err := stormforge.NewTransport(cfg.Proxy, cfg.CABundle)
+	if err != nil {
+		return nil, errors.Wrap(err, errNewTransport)
+	}
+	return append(o, stormforge.WithHTTPClient(&http.Client{Transport: t})), nil
+}
+
+// Hash returns a digest of the configuration and the supplied credentials.
+// Clients built from configurations and credentials with the same hash are
+// interchangeable.
+func (cfg *Config) Hash(creds []byte) string {
+	h := sha256.New()
+	for _, b := range [][]byte{creds, []byte(cfg.Endpoint), []byte(cfg.OptimizeEndpoint), []byte(cfg.Proxy), cfg.CABundle, []byte(strconv.FormatBool(cfg.DebugHTTP))} {
+		// Length prefixes keep adjacent fields from running into each other.
+		_ = binary.Write(h, binary.BigEndian, uint64(len(b)))
+		_, _ = h.Write(b)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func caBundle(ctx context.Context, kube client.Client, src *apisv1alpha1.CABundleSource) ([]byte, error) {
+	switch {
+	case src.SecretRef != nil:
+		ref := src.SecretRef
+		s := &corev1.Secret{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return nil, err
+		}
+		return s.Data[ref.Key], nil
+	case src.ConfigMapRef != nil:
+		ref := src.ConfigMapRef
+		cm := &corev1.ConfigMap{}
+		if err := kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+			return nil, err
+		}
+		return []byte(cm.Data[ref.Key]), nil
+	}
+	return nil, errors.New(errNoCABundleRef)
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apisv1alpha1 "github.com/luebken/provider-stormforge/apis/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+func TestConfig(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		options int
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		meta   metav1.ObjectMeta
+		spec   apisv1alpha1.ProviderConfigSpec
+		want   want
+	}{
+		"Defaults": {
+			reason: "A ProviderConfig without client configuration should produce no options.",
+			want:   want{options: 0},
+		},
+		"Endpoint": {
+			reason: "An endpoint should produce an option.",
+			spec:   apisv1alpha1.ProviderConfigSpec{Endpoint: "https://api.stormforge.example"},
+			want:   want{options: 1},
+		},
+		"OptimizeEndpoint": {
+			reason: "An Optimize endpoint should produce an option.",
+			spec:   apisv1alpha1.ProviderConfigSpec{OptimizeEndpoint: "https://api.stormforge.example/optimize"},
+			want:   want{options: 1},
+		},
+		"DebugHTTP": {
+			reason: "The debug HTTP annotation should produce a debug logger option.",
+			meta:   metav1.ObjectMeta{Annotations: map[string]string{AnnotationKeyDebugHTTP: "true"}},
+			want:   want{options: 1},
+		},
+		"Proxy": {
+			reason: "A proxy should produce an HTTP client option.",
+			spec:   apisv1alpha1.ProviderConfigSpec{Proxy: "http://proxy.example:3128"},
+			want:   want{options: 1},
+		},
+		"NoCABundleRef": {
+			reason: "A CA bundle without a reference should return an error.",
+			spec:   apisv1alpha1.ProviderConfigSpec{CABundle: &apisv1alpha1.CABundleSource{}},
+			want:   want{err: errors.Wrap(errors.New(errNoCABundleRef), errGetCABundle)},
+		},
+		"GetSecretError": {
+			reason: "Errors getting the CA bundle Secret should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			spec: apisv1alpha1.ProviderConfigSpec{CABundle: &apisv1alpha1.CABundleSource{
+				SecretRef: &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "ca"}, Key: "ca.crt"},
+			}},
+			want: want{err: errors.Wrap(errBoom, errGetCABundle)},
+		},
+		"InvalidConfigMapBundle": {
+			reason: "A ConfigMap that does not contain a PEM encoded bundle should return an error.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*corev1.ConfigMap).Data = map[string]string{"ca.crt": "not a certificate"}
+				return nil
+			})},
+			spec: apisv1alpha1.ProviderConfigSpec{CABundle: &apisv1alpha1.CABundleSource{
+				ConfigMapRef: &apisv1alpha1.ConfigMapKeySelector{Name: "ca", Key: "ca.crt"},
+			}},
+			want: want{err: errors.Wrap(errors.New("cannot parse CA bundle: no PEM encoded certificates found"), errNewTransport)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pc := &apisv1alpha1.ProviderConfig{ObjectMeta: tc.meta, Spec: tc.spec}
+			var o []stormforge.Option
+			cfg, err := GetConfig(context.Background(), tc.kube, pc)
+			if err == nil {
+				o, err = cfg.Options(logging.NewNopLogger())
+			}
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nOptions(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.options, len(o)); err == nil && diff != "" {
+				t.Errorf("\n%s\nOptions(...): -want options, +got options:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	cfg := &Config{Endpoint: "https://api.stormforge.example"}
+	if cfg.Hash([]byte("a")) == cfg.Hash([]byte("b")) {
+		t.Errorf("cfg.Hash(...): want different hashes for different credentials")
+	}
+	other := &Config{Endpoint: "https://api.stormforge.example", Proxy: "http://proxy.example:3128"}
+	if cfg.Hash([]byte("a")) == other.Hash([]byte("a")) {
+		t.Errorf("cfg.Hash(...): want different hashes for different configurations")
+	}
+	if cfg.Hash([]byte("a")) != (&Config{Endpoint: "https://api.stormforge.example"}).Hash([]byte("a")) {
+		t.Errorf("cfg.Hash(...): want equal hashes for equal configurations and credentials")
+	}
+}
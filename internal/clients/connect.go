@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	apisv1alpha1 "github.com/luebken/provider-stormforge/apis/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/credentials"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+// tokenExpiryWarning is how long before the token of a ProviderConfig expires
+// the Connector starts to warn that it expires soon.
+const tokenExpiryWarning = 7 * 24 * time.Hour
+
+// An expiring client can tell whether its token expires soon.
+type expiring interface {
+	ExpiresWithin(d time.Duration) bool
+}
+
+// A Connector produces StormForge clients for managed resources, using the
+// credentials and client configuration of the ProviderConfig they reference.
+type Connector struct {
+	Kube  client.Client
+	Usage resource.Tracker
+	Log   logging.Logger
+
+	// Pool reuses clients across reconciles. A nil Pool builds a new client
+	// for every managed resource.
+	Pool *Pool
+
+	// NewClient builds a client authenticated with the supplied token.
+	NewClient func(token string, o ...stormforge.Option) stormforge.Client
+}
+
+// NewConnector returns a Connector that pools the clients it builds. The
+// supplied options configure every client, and are overridden by those of
+// each ProviderConfig.
+func NewConnector(kube client.Client, l logging.Logger, co ...stormforge.Option) *Connector {
+	return &Connector{
+		Kube:  kube,
+		Usage: resource.NewProviderConfigUsageTracker(kube, &apisv1alpha1.ProviderConfigUsage{}),
+		Log:   l,
+		Pool:  NewPool(),
+		NewClient: func(token string, o ...stormforge.Option) stormforge.Client {
+			return stormforge.New(token, append(append([]stormforge.Option{}, co...), o...)...)
+		},
+	}
+}
+
+// Connect returns a client for the supplied managed resource by:
+//  1. Tracking that the managed resource is using a ProviderConfig.
+//  2. Getting the managed resource's ProviderConfig.
+//  3. Getting the credentials specified by the ProviderConfig.
+//  4. Using the credentials to form a client, or reusing the one formed from
+//     the same ProviderConfig and credentials by an earlier reconcile. A
+//     warning is logged when a client is formed with a token that expires
+//     soon.
+func (c *Connector) Connect(ctx context.Context, mg resource.Managed) (stormforge.Client, error) {
+	if err := c.Usage.Track(ctx, mg); err != nil {
+		return nil, errors.Wrap(err, errs.TrackPCUsage)
+	}
+
+	pc := &apisv1alpha1.ProviderConfig{}
+	if err := c.Kube.Get(ctx, types.NamespacedName{Name: mg.GetProviderConfigReference().Name}, pc); err != nil {
+		return nil, errors.Wrap(err, errs.GetPC)
+	}
+
+	cd := pc.Spec.Credentials
+	data, err := credentials.Extract(ctx, cd.Source, c.Kube, cd.CommonCredentialSelectors)
+	if err != nil {
+		return nil, errors.Wrap(err, errs.GetCreds)
+	}
+
+	cfg, err := GetConfig(ctx, c.Kube, pc)
+	if err != nil {
+		return nil, errors.Wrap(err, errs.NewClient)
+	}
+	sf, err := c.Pool.Client(pc.GetName(), cfg.Hash(data), func() (stormforge.Client, error) {
+		o, err := cfg.Options(c.Log.WithValues("providerConfig", pc.GetName()))
+		if err != nil {
+			return nil, err
+		}
+		sf := c.NewClient(string(data), o...)
+		if e, ok := sf.(expiring); ok && e.ExpiresWithin(tokenExpiryWarning) {
+			c.Log.Info("The token of the ProviderConfig expires soon; replace it to keep managing resources", "providerConfig", pc.GetName())
+		}
+		return sf, nil
+	})
+	return sf, errors.Wrap(err, errs.NewClient)
+}
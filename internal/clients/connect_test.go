@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	apisv1alpha1 "github.com/luebken/provider-stormforge/apis/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/credentials"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func TestConnect(t *testing.T) {
+	errBoom := errors.New("boom")
+	sourceBoom := xpv1.CredentialsSource("Boom")
+
+	credentials.Register(sourceBoom, func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+		return nil, errBoom
+	})
+	defer credentials.Unregister(sourceBoom)
+
+	sourceToken := xpv1.CredentialsSource("Token")
+	credentials.Register(sourceToken, func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+		return []byte("jwt"), nil
+	})
+	defer credentials.Unregister(sourceToken)
+
+	mg := &fake.Managed{ProviderConfigReferencer: fake.ProviderConfigReferencer{Ref: &xpv1.Reference{Name: "example"}}}
+	track := resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return nil })
+
+	type want struct {
+		token string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		usage  resource.Tracker
+		want   want
+	}{
+		"TrackError": {
+			reason: "Errors tracking ProviderConfig usage should be wrapped.",
+			usage:  resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return errBoom }),
+			want:   want{err: errors.Wrap(errBoom, errs.TrackPCUsage)},
+		},
+		"GetProviderConfigError": {
+			reason: "Errors getting the ProviderConfig should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			usage:  track,
+			want:   want{err: errors.Wrap(errBoom, errs.GetPC)},
+		},
+		"GetCredentialsError": {
+			reason: "Errors extracting credentials should be wrapped.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*apisv1alpha1.ProviderConfig).Spec.Credentials.Source = sourceBoom
+				return nil
+			})},
+			usage: track,
+			want:  want{err: errors.Wrap(errBoom, errs.GetCreds)},
+		},
+		"ClientOptionsError": {
+			reason: "Errors configuring the client from the ProviderConfig should be wrapped.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*apisv1alpha1.ProviderConfig).Spec.Credentials.Source = sourceToken
+				obj.(*apisv1alpha1.ProviderConfig).Spec.CABundle = &apisv1alpha1.CABundleSource{}
+				return nil
+			})},
+			usage: track,
+			want:  want{err: errors.Wrap(errors.Wrap(errors.New(errNoCABundleRef), errGetCABundle), errs.NewClient)},
+		},
+		"Success": {
+			reason: "A client should be built with the credentials of the ProviderConfig.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*apisv1alpha1.ProviderConfig).Spec.Credentials.Source = sourceToken
+				return nil
+			})},
+			usage: track,
+			want:  want{token: "jwt"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			token := ""
+			c := &Connector{
+				Kube:  tc.kube,
+				Usage: tc.usage,
+				Log:   logging.NewNopLogger(),
+				NewClient: func(t string, _ ...stormforge.Option) stormforge.Client {
+					token = t
+					return stormforge.New(t)
+				},
+			}
+			_, err := c.Connect(context.Background(), mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.token, token); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want token, +got token:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+// TypeAPIHealthy is the type of the condition that reports whether the
+// StormForge API could be reached with the credentials of a managed resource
+// the last time it was reconciled.
+const TypeAPIHealthy xpv1.ConditionType = "APIHealthy"
+
+// Reasons of the APIHealthy condition. Credentials that are rejected fail
+// authentication, while credentials that are accepted but do not permit an
+// operation, for example because their token is not granted its scope, are
+// denied permission.
+const (
+	ReasonAPIReachable         xpv1.ConditionReason = "APIReachable"
+	ReasonAPIUnreachable       xpv1.ConditionReason = "APIUnreachable"
+	ReasonAuthenticationFailed xpv1.ConditionReason = "AuthenticationFailed"
+	ReasonPermissionDenied     xpv1.ConditionReason = "PermissionDenied"
+)
+
+// APIHealth returns the APIHealthy condition implied by the supplied error of
+// an external client operation, and whether the error implies one at all. An
+// error that did not come from the API, for example one reading a referenced
+// secret, implies nothing about it.
+func APIHealth(err error) (xpv1.Condition, bool) {
+	c := xpv1.Condition{
+		Type:               TypeAPIHealthy,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonAPIReachable,
+	}
+	switch {
+	case err == nil:
+		return c, true
+	case stormforge.IsUnreachable(err):
+		c.Status, c.Reason, c.Message = corev1.ConditionFalse, ReasonAPIUnreachable, err.Error()
+	case stormforge.IsUnauthorized(err):
+		c.Status, c.Reason, c.Message = corev1.ConditionFalse, ReasonAuthenticationFailed, err.Error()
+	case stormforge.IsForbidden(err):
+		c.Status, c.Reason, c.Message = corev1.ConditionFalse, ReasonPermissionDenied, err.Error()
+	case stormforge.ReasonFor(err) == stormforge.ReasonUnknown:
+		return xpv1.Condition{}, false
+	}
+	return c, true
+}
+
+// WithAPIHealth wraps the supplied external client so that each of its
+// operations reports the APIHealthy condition of the managed resource it
+// operates on, so that a network problem can be told apart from a credentials
+// problem. Its operations share a retry budget of DefaultRetryBudget retries
+// of failed calls to the StormForge API. External clients are connected for
+// each reconcile, so the budget is that of a reconcile.
+func WithAPIHealth(e managed.ExternalClient) managed.ExternalClient {
+	return &healthExternal{client: e, budget: stormforge.NewRetryBudget(stormforge.DefaultRetryBudget)}
+}
+
+type healthExternal struct {
+	client managed.ExternalClient
+	budget *stormforge.RetryBudget
+}
+
+func (h *healthExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	ctx = stormforge.WithRetryBudget(ctx, h.budget)
+	o, err := h.client.Observe(ctx, mg)
+	report(mg, err)
+	return o, err
+}
+
+func (h *healthExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	ctx = stormforge.WithRetryBudget(ctx, h.budget)
+	c, err := h.client.Create(ctx, mg)
+	report(mg, err)
+	return c, err
+}
+
+func (h *healthExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	ctx = stormforge.WithRetryBudget(ctx, h.budget)
+	u, err := h.client.Update(ctx, mg)
+	report(mg, err)
+	return u, err
+}
+
+func (h *healthExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	ctx = stormforge.WithRetryBudget(ctx, h.budget)
+	err := h.client.Delete(ctx, mg)
+	report(mg, err)
+	return err
+}
+
+// report sets the APIHealthy condition implied by the supplied error, if any.
+func report(mg resource.Managed, err error) {
+	if c, ok := APIHealth(err); ok {
+		mg.SetConditions(c)
+	}
+}
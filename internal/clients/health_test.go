@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+func TestAPIHealth(t *testing.T) {
+	unreachable := errors.Wrap(&url.Error{Op: "Get", URL: "https://api.stormforger.com", Err: errors.New("connection refused")}, "cannot do request")
+	unavailable := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	unauthorized := &stormforge.APIError{StatusCode: http.StatusUnauthorized}
+	forbidden := &stormforge.APIError{StatusCode: http.StatusForbidden}
+	scope := &stormforge.ScopeError{Operation: "create test cases", Scope: stormforge.ScopeTestCasesCreate}
+
+	type want struct {
+		c  xpv1.Condition
+		ok bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		err    error
+		want   want
+	}{
+		"Success": {
+			reason: "An operation that succeeded should report the API as reachable.",
+			want:   want{c: xpv1.Condition{Type: TypeAPIHealthy, Status: corev1.ConditionTrue, Reason: ReasonAPIReachable}, ok: true},
+		},
+		"NetworkError": {
+			reason: "A request that could not be sent should report the API as unreachable.",
+			err:    unreachable,
+			want:   want{c: xpv1.Condition{Type: TypeAPIHealthy, Status: corev1.ConditionFalse, Reason: ReasonAPIUnreachable, Message: unreachable.Error()}, ok: true},
+		},
+		"Unavailable": {
+			reason: "A server error should report the API as unreachable.",
+			err:    errors.Wrap(unavailable, "cannot get test case"),
+			want:   want{c: xpv1.Condition{Type: TypeAPIHealthy, Status: corev1.ConditionFalse, Reason: ReasonAPIUnreachable, Message: "cannot get test case: " + unavailable.Error()}, ok: true},
+		},
+		"Unauthorized": {
+			reason: "Rejected credentials should report an authentication failure.",
+			err:    unauthorized,
+			want:   want{c: xpv1.Condition{Type: TypeAPIHealthy, Status: corev1.ConditionFalse, Reason: ReasonAuthenticationFailed, Message: unauthorized.Error()}, ok: true},
+		},
+		"Forbidden": {
+			reason: "Credentials that do not permit a call should report that permission was denied.",
+			err:    forbidden,
+			want:   want{c: xpv1.Condition{Type: TypeAPIHealthy, Status: corev1.ConditionFalse, Reason: ReasonPermissionDenied, Message: forbidden.Error()}, ok: true},
+		},
+		"ScopeError": {
+			reason: "A token that is not granted the scope of a call should report that permission was denied.",
+			err:    errors.Wrap(scope, "cannot create test case"),
+			want:   want{c: xpv1.Condition{Type: TypeAPIHealthy, Status: corev1.ConditionFalse, Reason: ReasonPermissionDenied, Message: "cannot create test case: " + scope.Error()}, ok: true},
+		},
+		"OtherAPIError": {
+			reason: "Any other error returned by the API should report the API as reachable.",
+			err:    &stormforge.APIError{StatusCode: http.StatusNotFound},
+			want:   want{c: xpv1.Condition{Type: TypeAPIHealthy, Status: corev1.ConditionTrue, Reason: ReasonAPIReachable}, ok: true},
+		},
+		"NotAnAPIError": {
+			reason: "An error that did not come from the API should not imply any condition.",
+			err:    errors.New("cannot get referenced secret"),
+			want:   want{},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c, ok := APIHealth(tc.err)
+			if diff := cmp.Diff(tc.want.c, c, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nAPIHealth(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if ok != tc.want.ok {
+				t.Errorf("\n%s\nAPIHealth(...): want ok %t, got %t", tc.reason, tc.want.ok, ok)
+			}
+		})
+	}
+}
+
+func TestWithAPIHealth(t *testing.T) {
+	unauthorized := &stormforge.APIError{StatusCode: http.StatusUnauthorized}
+	e := WithAPIHealth(managed.ExternalClientFns{
+		ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+			return managed.ExternalObservation{}, unauthorized
+		},
+		CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+			return managed.ExternalCreation{}, nil
+		},
+	})
+
+	mg := &fake.Managed{}
+	if _, err := e.Observe(context.Background(), mg); !errors.Is(err, unauthorized) {
+		t.Errorf("e.Observe(...): want error %q, got %v", unauthorized, err)
+	}
+	if got := mg.GetCondition(TypeAPIHealthy).Reason; got != ReasonAuthenticationFailed {
+		t.Errorf("e.Observe(...): want reason %q, got %q", ReasonAuthenticationFailed, got)
+	}
+
+	if _, err := e.Create(context.Background(), mg); err != nil {
+		t.Errorf("e.Create(...): unexpected error: %s", err)
+	}
+	if got := mg.GetCondition(TypeAPIHealthy).Reason; got != ReasonAPIReachable {
+		t.Errorf("e.Create(...): want reason %q, got %q", ReasonAPIReachable, got)
+	}
+}
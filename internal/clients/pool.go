@@ -0,0 +1,62 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"sync"
+
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+// A Pool reuses a StormForge client per ProviderConfig across reconciles, so
+// that its connections are kept alive rather than re-established for every
+// reconcile. A ProviderConfig's client is replaced when the hash of its
+// configuration and credentials changes. A Pool is safe for concurrent use.
+type Pool struct {
+	mu      sync.Mutex
+	clients map[string]pooled
+}
+
+type pooled struct {
+	hash   string
+	client stormforge.Client
+}
+
+// NewPool returns an empty Pool.
+func NewPool() *Pool {
+	return &Pool{clients: map[string]pooled{}}
+}
+
+// Client returns the pooled client of the named ProviderConfig if it was built
+// with the supplied hash. Otherwise it builds a client using newClient and pools
+// it in place of any stale client. A nil Pool always builds a new client.
+func (p *Pool) Client(providerConfig, hash string, newClient func() (stormforge.Client, error)) (stormforge.Client, error) {
+	if p == nil {
+		return newClient()
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if c, ok := p.clients[providerConfig]; ok && c.hash == hash {
+		return c.client, nil
+	}
+	c, err := newClient()
+	if err != nil {
+		return nil, err
+	}
+	p.clients[providerConfig] = pooled{hash: hash, client: c}
+	return c, nil
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+)
+
+func TestPool(t *testing.T) {
+	p := NewPool()
+	built := 0
+	newClient := func() (stormforge.Client, error) {
+		built++
+		return &fake.Client{}, nil
+	}
+
+	cases := []struct {
+		reason         string
+		providerConfig string
+		hash           string
+		built          int
+	}{
+		{reason: "The first client of a ProviderConfig should be built.", providerConfig: "a", hash: "1", built: 1},
+		{reason: "A client with an unchanged hash should be reused.", providerConfig: "a", hash: "1", built: 1},
+		{reason: "Another ProviderConfig should get its own client.", providerConfig: "b", hash: "1", built: 2},
+		{reason: "A client with a changed hash should be rebuilt.", providerConfig: "a", hash: "2", built: 3},
+	}
+
+	for _, tc := range cases {
+		if _, err := p.Client(tc.providerConfig, tc.hash, newClient); err != nil {
+			t.Fatalf("\n%s\np.Client(...): unexpected error: %s", tc.reason, err)
+		}
+		if built != tc.built {
+			t.Errorf("\n%s\nbuilt clients: want %d, got %d", tc.reason, tc.built, built)
+		}
+	}
+
+	errBoom := errors.New("boom")
+	if _, err := p.Client("a", "3", func() (stormforge.Client, error) { return nil, errBoom }); err != errBoom {
+		t.Errorf("p.Client(...): want error %v, got %v", errBoom, err)
+	}
+	if _, err := p.Client("a", "2", newClient); err != nil || built != 3 {
+		t.Errorf("p.Client(...): a failed build should not evict the pooled client")
+	}
+}
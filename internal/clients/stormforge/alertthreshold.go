@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// An AlertThreshold of a test case alerts the notification channels of its
+// organization whenever a run of the test case crosses it.
+type AlertThreshold struct {
+	ID   string
+	Name string
+
+	// Metric is the result of a run the threshold applies to, for example
+	// latency_p95 or error_rate.
+	Metric string
+
+	// Operator is gt if the threshold is crossed when the metric is above its
+	// value, and lt if it is crossed when the metric is below it.
+	Operator string
+
+	// Value of the threshold. Latencies are in milliseconds, and error rates
+	// in percent.
+	Value string
+}
+
+type alertThresholdAttributes struct {
+	Name     string `json:"name"`
+	Metric   string `json:"metric"`
+	Operator string `json:"operator"`
+	Value    string `json:"value"`
+}
+
+func alertThresholdFrom(o resourceObject) (*AlertThreshold, error) {
+	a := alertThresholdAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	return &AlertThreshold{ID: o.ID, Name: a.Name, Metric: a.Metric, Operator: a.Operator, Value: a.Value}, nil
+}
+
+// alertThresholdForm returns the multipart form of a created or updated alert
+// threshold.
+func alertThresholdForm(t AlertThreshold) ([]byte, string, error) {
+	return multipartForm(url.Values{
+		"alert_threshold[name]":     {t.Name},
+		"alert_threshold[metric]":   {t.Metric},
+		"alert_threshold[operator]": {t.Operator},
+		"alert_threshold[value]":    {t.Value},
+	})
+}
+
+// ListAlertThresholds returns the alert thresholds of the test case with the
+// supplied ID.
+func (c *APIClient) ListAlertThresholds(ctx context.Context, testCaseID string) ([]AlertThreshold, error) {
+	ts := []AlertThreshold{}
+	err := c.collection(ctx, "/test_cases/"+url.PathEscape(testCaseID)+"/alert_thresholds", func(o resourceObject, _ included) error {
+		t, err := alertThresholdFrom(o)
+		if err != nil {
+			return err
+		}
+		ts = append(ts, *t)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ts, nil
+}
+
+// CreateAlertThreshold adds the supplied alert threshold to the test case with
+// the supplied ID. The ID of the supplied threshold is ignored.
+func (c *APIClient) CreateAlertThreshold(ctx context.Context, testCaseID string, t AlertThreshold) (*AlertThreshold, error) {
+	body, ct, err := alertThresholdForm(t)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(ctx, http.MethodPost, "/test_cases/"+url.PathEscape(testCaseID)+"/alert_thresholds", body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return alertThresholdFrom(d.Data)
+}
+
+// UpdateAlertThreshold replaces the alert threshold with the supplied ID of
+// the test case with the supplied ID.
+func (c *APIClient) UpdateAlertThreshold(ctx context.Context, testCaseID, id string, t AlertThreshold) (*AlertThreshold, error) {
+	body, ct, err := alertThresholdForm(t)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(ctx, http.MethodPatch, "/test_cases/"+url.PathEscape(testCaseID)+"/alert_thresholds/"+url.PathEscape(id), body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return alertThresholdFrom(d.Data)
+}
+
+// DeleteAlertThreshold deletes the alert threshold with the supplied ID of
+// the test case with the supplied ID.
+func (c *APIClient) DeleteAlertThreshold(ctx context.Context, testCaseID, id string) error {
+	return c.do(ctx, http.MethodDelete, "/test_cases/"+url.PathEscape(testCaseID)+"/alert_thresholds/"+url.PathEscape(id), nil, "", nil)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Scopes of an API token.
+const (
+	APITokenScopeRead   = "read"
+	APITokenScopeLaunch = "launch"
+	APITokenScopeWrite  = "write"
+	APITokenScopeAdmin  = "admin"
+)
+
+// An APIToken is a service credential of an organization, for example for a
+// CI system that launches test runs.
+type APIToken struct {
+	ID     string
+	Name   string
+	Scope  string
+	Scopes []string
+
+	// Token is the secret value of the token. It is only returned when the
+	// token is created.
+	Token string
+
+	CreatedAt  *time.Time
+	ExpiresAt  *time.Time
+	LastUsedAt *time.Time
+}
+
+type apiTokenAttributes struct {
+	Name       string     `json:"name"`
+	Scope      string     `json:"scope"`
+	Scopes     []string   `json:"scopes"`
+	Token      string     `json:"token"`
+	CreatedAt  *time.Time `json:"created_at"`
+	ExpiresAt  *time.Time `json:"expires_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+func apiTokenFrom(o resourceObject) (*APIToken, error) {
+	a := apiTokenAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	return &APIToken{
+		ID:         o.ID,
+		Name:       a.Name,
+		Scope:      a.Scope,
+		Scopes:     a.Scopes,
+		Token:      a.Token,
+		CreatedAt:  a.CreatedAt,
+		ExpiresAt:  a.ExpiresAt,
+		LastUsedAt: a.LastUsedAt,
+	}, nil
+}
+
+// GetAPIToken returns the API token with the supplied ID of the supplied
+// organization. The secret value of the token is not returned.
+func (c *APIClient) GetAPIToken(ctx context.Context, org, id string) (*APIToken, error) {
+	d, err := c.resource(WithOrg(ctx, org), http.MethodGet, "/organisations/"+url.PathEscape(org)+"/api_tokens/"+url.PathEscape(id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return apiTokenFrom(d.Data)
+}
+
+// CreateAPIToken creates the supplied API token in the supplied organization,
+// returning it along with its secret value. The ID and scope of the supplied
+// token are ignored.
+func (c *APIClient) CreateAPIToken(ctx context.Context, org string, t APIToken) (*APIToken, error) {
+	fields := url.Values{
+		"api_token[name]":     {t.Name},
+		"api_token[scopes][]": t.Scopes,
+	}
+	if t.ExpiresAt != nil {
+		fields.Set("api_token[expires_at]", t.ExpiresAt.UTC().Format(time.RFC3339))
+	}
+	body, ct, err := multipartForm(fields)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPost, "/organisations/"+url.PathEscape(org)+"/api_tokens", body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return apiTokenFrom(d.Data)
+}
+
+// UpdateAPIToken renames and rescopes the API token with the supplied ID of
+// the supplied organization. The expiry of a token cannot be changed.
+func (c *APIClient) UpdateAPIToken(ctx context.Context, org, id string, t APIToken) (*APIToken, error) {
+	body, ct, err := multipartForm(url.Values{
+		"api_token[name]":     {t.Name},
+		"api_token[scopes][]": t.Scopes,
+	})
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPatch, "/organisations/"+url.PathEscape(org)+"/api_tokens/"+url.PathEscape(id), body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return apiTokenFrom(d.Data)
+}
+
+// DeleteAPIToken revokes the API token with the supplied ID of the supplied
+// organization.
+func (c *APIClient) DeleteAPIToken(ctx context.Context, org, id string) error {
+	return c.do(WithOrg(ctx, org), http.MethodDelete, "/organisations/"+url.PathEscape(org)+"/api_tokens/"+url.PathEscape(id), nil, "", nil)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultCacheTTL is how long listed test cases are cached by default.
+const DefaultCacheTTL = 30 * time.Second
+
+// A Cache caches the test cases of each organization for a short time, so that
+// observing many test cases of an organization within its TTL lists them only
+// once. It is safe for concurrent use, and is intended to be shared by every
+// APIClient of the provider. Entries are partitioned by endpoint and token, so
+// clients never read test cases listed with other credentials.
+type Cache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+}
+
+type cacheEntry struct {
+	testCases []TestCase
+	expires   time.Time
+}
+
+// NewCache returns a Cache whose entries expire after the supplied TTL. A TTL
+// of zero or less disables caching.
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{ttl: ttl, now: time.Now, entries: map[string]cacheEntry{}}
+}
+
+// WithCache configures the Cache an APIClient lists test cases from. Creating,
+// updating or deleting a test case invalidates every entry of the client.
+func WithCache(cc *Cache) Option {
+	return func(c *APIClient) {
+		c.cache = cc
+	}
+}
+
+func (cc *Cache) get(key string) ([]TestCase, bool) {
+	if cc == nil || cc.ttl <= 0 {
+		return nil, false
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	e, ok := cc.entries[key]
+	if !ok || !cc.now().Before(e.expires) {
+		delete(cc.entries, key)
+		return nil, false
+	}
+	return append([]TestCase{}, e.testCases...), true
+}
+
+func (cc *Cache) set(key string, tcs []TestCase) {
+	if cc == nil || cc.ttl <= 0 {
+		return
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	cc.entries[key] = cacheEntry{testCases: append([]TestCase{}, tcs...), expires: cc.now().Add(cc.ttl)}
+}
+
+// invalidate removes every entry whose key starts with the supplied prefix.
+func (cc *Cache) invalidate(prefix string) {
+	if cc == nil {
+		return
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	for k := range cc.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(cc.entries, k)
+		}
+	}
+}
+
+type freshKey struct{}
+
+// Fresh returns a context in which the client reads from the StormForge API
+// rather than from its Cache, and sends GETs that are not conditional, so that
+// what it reads is current. What it reads still refreshes both caches.
+func Fresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, freshKey{}, true)
+}
+
+// IsFresh returns true if the supplied context was returned by Fresh.
+func IsFresh(ctx context.Context) bool {
+	f, _ := ctx.Value(freshKey{}).(bool)
+	return f
+}
+
+// cachePartition returns the prefix of the client's cache keys.
+func (c *APIClient) cachePartition() string {
+	return c.endpoint + "|" + c.tokenHash() + "|"
+}
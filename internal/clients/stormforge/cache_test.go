@@ -0,0 +1,96 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCache(t *testing.T) {
+	now := time.Now()
+	cc := NewCache(time.Minute)
+	cc.now = func() time.Time { return now }
+
+	lists := 0
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			lists++
+			_, _ = w.Write([]byte(`{"data":[{"id":"a1","type":"test_cases","attributes":{"name":"checkout","scope":"acme"}}]}`))
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+	WithCache(cc)(c)
+
+	cases := []struct {
+		reason string
+		call   func() error
+		lists  int
+	}{
+		{
+			reason: "The first list should list the test cases.",
+			call:   func() error { _, err := c.ListTestCases(context.Background(), "acme"); return err },
+			lists:  1,
+		},
+		{
+			reason: "A list within the TTL should be served from the cache.",
+			call:   func() error { _, err := c.ListTestCases(context.Background(), "acme"); return err },
+			lists:  1,
+		},
+		{
+			reason: "Deleting a test case should invalidate the cache.",
+			call: func() error {
+				if err := c.DeleteTestCase(context.Background(), "a1"); err != nil {
+					return err
+				}
+				_, err := c.ListTestCases(context.Background(), "acme")
+				return err
+			},
+			lists: 2,
+		},
+		{
+			reason: "A list after the TTL should list the test cases again.",
+			call: func() error {
+				now = now.Add(time.Minute)
+				_, err := c.ListTestCases(context.Background(), "acme")
+				return err
+			},
+			lists: 3,
+		},
+		{
+			reason: "A fresh list within the TTL should list the test cases again.",
+			call: func() error {
+				_, err := c.ListTestCases(Fresh(context.Background()), "acme")
+				return err
+			},
+			lists: 4,
+		},
+	}
+
+	for _, tc := range cases {
+		if err := tc.call(); err != nil {
+			t.Fatalf("\n%s\nunexpected error: %s", tc.reason, err)
+		}
+		if lists != tc.lists {
+			t.Errorf("\n%s\nlists: want %d, got %d", tc.reason, tc.lists, lists)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package stormforge contains a client for the StormForge API.
+package stormforge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// DefaultEndpoint is the StormForge API used by an APIClient unless another
+// endpoint is supplied.
+const DefaultEndpoint = "https://api.stormforger.com"
+
+// DefaultOptimizeEndpoint is the StormForge Optimize API used by an APIClient
+// unless another endpoint is supplied.
+const DefaultOptimizeEndpoint = "https://api.stormforge.io"
+
+// AppEndpoint is the StormForge web UI.
+const AppEndpoint = "https://app.stormforger.com"
+
+// DefaultTimeout bounds each call an APIClient makes unless another timeout is
+// supplied.
+const DefaultTimeout = 30 * time.Second
+
+const (
+	errNewRequest     = "cannot create request"
+	errDoRequest      = "cannot send request"
+	errDecodeResponse = "cannot decode response"
+	errReadResponse   = "cannot read response"
+	errEncodeForm     = "cannot encode form"
+)
+
+// Media types of the bodies of the StormForge and StormForge Optimize APIs.
+const (
+	mediaTypeJSONAPI = "application/vnd.api+json"
+	mediaTypeJSON    = "application/json"
+)
+
+// maxErrorBody bounds how much of the body of an unsuccessful response is read.
+const maxErrorBody = 64 << 10
+
+// A Client manages StormForge test cases, the projects that group them, their
+// runs, the data sources they draw from and the channels notified of them, and
+// reads the organizations they belong to and manages their API tokens. It also
+// reads the IP ranges of StormForge load generators, and manages StormForge
+// Optimize resources.
+type Client interface {
+	ListTestCases(ctx context.Context, org string) ([]TestCase, error)
+	GetTestCase(ctx context.Context, id string) (*TestCase, error)
+	TestCaseURL(id string) string
+	CreateTestCase(ctx context.Context, org, name string, script []byte, o ...TestCaseOption) (*TestCase, error)
+	UpdateTestCase(ctx context.Context, id, name string, script []byte, o ...TestCaseOption) (*TestCase, error)
+	GetDefinition(ctx context.Context, id string) ([]byte, error)
+	ArchiveTestCase(ctx context.Context, id string) error
+	UnarchiveTestCase(ctx context.Context, id string) error
+	DeleteTestCase(ctx context.Context, id string) error
+	ListRevisions(ctx context.Context, testCaseID string) ([]Revision, error)
+
+	ListAlertThresholds(ctx context.Context, testCaseID string) ([]AlertThreshold, error)
+	CreateAlertThreshold(ctx context.Context, testCaseID string, t AlertThreshold) (*AlertThreshold, error)
+	UpdateAlertThreshold(ctx context.Context, testCaseID, id string, t AlertThreshold) (*AlertThreshold, error)
+	DeleteAlertThreshold(ctx context.Context, testCaseID, id string) error
+
+	LaunchTestRun(ctx context.Context, testCaseID string, o RunOptions) (*TestRun, error)
+	GetTestRun(ctx context.Context, id string) (*TestRun, error)
+	AbortTestRun(ctx context.Context, id string) error
+	ListTestRuns(ctx context.Context, testCaseID string) ([]TestRun, error)
+	GetTestRunArtifact(ctx context.Context, id, artifact string, w io.Writer) error
+
+	ListOrganizations(ctx context.Context) ([]Organization, error)
+	GetOrganization(ctx context.Context, id string) (*Organization, error)
+
+	ListDataSources(ctx context.Context, org string) ([]DataSource, error)
+	GetDataSource(ctx context.Context, org, id string) (*DataSource, error)
+	CreateDataSource(ctx context.Context, org, name string, content []byte) (*DataSource, error)
+	UpdateDataSource(ctx context.Context, org, id, name string, content []byte) (*DataSource, error)
+	DeleteDataSource(ctx context.Context, org, id string) error
+
+	ListNotificationChannels(ctx context.Context, org string) ([]NotificationChannel, error)
+	GetNotificationChannel(ctx context.Context, org, id string) (*NotificationChannel, error)
+	CreateNotificationChannel(ctx context.Context, org string, nc NotificationChannel) (*NotificationChannel, error)
+	UpdateNotificationChannel(ctx context.Context, org, id string, nc NotificationChannel) (*NotificationChannel, error)
+	DeleteNotificationChannel(ctx context.Context, org, id string) error
+
+	GetAPIToken(ctx context.Context, org, id string) (*APIToken, error)
+	CreateAPIToken(ctx context.Context, org string, t APIToken) (*APIToken, error)
+	UpdateAPIToken(ctx context.Context, org, id string, t APIToken) (*APIToken, error)
+	DeleteAPIToken(ctx context.Context, org, id string) error
+
+	ListProjects(ctx context.Context, org string) ([]Project, error)
+	GetProject(ctx context.Context, org, id string) (*Project, error)
+	CreateProject(ctx context.Context, org string, p Project) (*Project, error)
+	UpdateProject(ctx context.Context, org, id string, p Project) (*Project, error)
+	DeleteProject(ctx context.Context, org, id string) error
+
+	ListIPRanges(ctx context.Context) ([]IPRange, error)
+
+	OptimizeClient
+}
+
+// An APIClient is a Client for the StormForge API. Requests are authenticated
+// with a JWT.
+type APIClient struct {
+	endpoint         string
+	optimizeEndpoint string
+	token            string
+	timeout          time.Duration
+	http             *http.Client
+	backoff          Backoff
+	limiter          *RateLimiter
+	concurrency      *AdaptiveLimiter
+	cache            *Cache
+	conditional      *ConditionalCache
+	debug            logging.Logger
+	leeway           time.Duration
+	now              func() time.Time
+	wait             func(ctx context.Context, d time.Duration) error
+}
+
+// An Option configures an APIClient.
+type Option func(c *APIClient)
+
+// WithEndpoint configures the StormForge API endpoint an APIClient talks to.
+func WithEndpoint(endpoint string) Option {
+	return func(c *APIClient) {
+		c.endpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithOptimizeEndpoint configures the StormForge Optimize API endpoint an
+// APIClient talks to.
+func WithOptimizeEndpoint(endpoint string) Option {
+	return func(c *APIClient) {
+		c.optimizeEndpoint = strings.TrimSuffix(endpoint, "/")
+	}
+}
+
+// WithTimeout configures how long each request to the StormForge API may take,
+// including reading its response. Retries of a call are each given the full
+// timeout. A timeout of zero disables the per-request timeout, leaving only any
+// deadline of the supplied context.
+func WithTimeout(t time.Duration) Option {
+	return func(c *APIClient) {
+		c.timeout = t
+	}
+}
+
+// WithHTTPClient configures the HTTP client used to send requests.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *APIClient) {
+		c.http = hc
+	}
+}
+
+// New returns an APIClient that authenticates using the supplied JWT.
+func New(token string, o ...Option) *APIClient {
+	c := &APIClient{
+		endpoint:         DefaultEndpoint,
+		optimizeEndpoint: DefaultOptimizeEndpoint,
+		token:            strings.TrimSpace(token),
+		timeout:          DefaultTimeout,
+		http:             http.DefaultClient,
+		backoff:          DefaultBackoff,
+		leeway:           DefaultTokenLeeway,
+		now:              time.Now,
+		wait:             wait,
+	}
+	for _, fn := range o {
+		fn(c)
+	}
+	return c
+}
+
+// Ping checks that the StormForge API is reachable and accepts the client's
+// credentials.
+func (c *APIClient) Ping(ctx context.Context) error {
+	return c.do(ctx, http.MethodGet, "/user", nil, "", nil)
+}
+
+// do sends a request to the supplied path of the StormForge API and decodes
+// the response into out, if it is not nil. Requests that fail transiently are
+// retried; see retry.
+func (c *APIClient) do(ctx context.Context, method, path string, body []byte, contentType string, out interface{}) error {
+	return c.retry(ctx, method, func() error {
+		return c.attempt(ctx, method, c.endpoint+path, mediaTypeJSONAPI, body, contentType, out)
+	})
+}
+
+// download copies the raw body of the response to a GET of the supplied path
+// of the StormForge API to the supplied writer. Requests that fail transiently
+// are retried, unless part of the body was already written; see retry.
+func (c *APIClient) download(ctx context.Context, path string, w io.Writer) error {
+	cw := &countingWriter{w: w}
+	return c.retry(ctx, http.MethodGet, func() error {
+		err := c.attempt(ctx, http.MethodGet, c.endpoint+path, "*/*", nil, "", cw)
+		if err != nil && cw.n > 0 {
+			return permanentError{err}
+		}
+		return err
+	})
+}
+
+// A countingWriter counts the bytes written to the writer it wraps.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// attempt sends a single request to the supplied URL once the rate limiter
+// allows it and a concurrency slot is free, accepting a response of the
+// supplied media type. Unsuccessful responses are returned as an *APIError.
+// The response is copied as is if out is an io.Writer, and decoded as JSON
+// otherwise. GETs are conditional if the client has a ConditionalCache and the
+// context was not returned by Fresh; a 304 Not Modified response is answered
+// with the body kept from the last successful response.
+func (c *APIClient) attempt(ctx context.Context, method, rawURL, accept string, body []byte, contentType string, out interface{}) (err error) {
+	if err := c.limit(ctx); err != nil {
+		return err
+	}
+	if err := c.concurrency.acquire(ctx); err != nil {
+		return err
+	}
+	defer func() { c.concurrency.release(err) }()
+
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, r)
+	if err != nil {
+		return errors.Wrap(err, errNewRequest)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Accept", accept)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	cond := method == http.MethodGet && c.conditional != nil
+	key := c.cachePartition() + accept + "|" + rawURL
+	var kept conditionalEntry
+	var ok bool
+	if cond && !IsFresh(ctx) {
+		kept, ok = c.conditional.validate(key, req)
+	}
+
+	c.logRequest(req, body)
+	rsp, err := c.http.Do(req)
+	if err != nil {
+		return errors.Wrap(err, errDoRequest)
+	}
+	c.logResponse(rsp)
+	defer rsp.Body.Close() //nolint:errcheck
+
+	if rsp.StatusCode == http.StatusNotModified && ok {
+		return decode(bytes.NewReader(kept.body), out)
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(io.LimitReader(rsp.Body, maxErrorBody))
+		return newAPIError(rsp.StatusCode, rsp.Header, b)
+	}
+
+	var rb io.Reader = rsp.Body
+	if cond {
+		b, err := ioutil.ReadAll(io.LimitReader(rsp.Body, maxConditionalBody+1))
+		if err != nil {
+			return errors.Wrap(err, errReadResponse)
+		}
+		c.conditional.set(key, rsp.Header, b)
+		rb = io.MultiReader(bytes.NewReader(b), rsp.Body)
+	}
+	return decode(rb, out)
+}
+
+// decode copies the supplied body as is if out is an io.Writer, and decodes it
+// as JSON into out otherwise. The body is ignored if out is nil.
+func decode(body io.Reader, out interface{}) error {
+	if out == nil {
+		return nil
+	}
+	if w, ok := out.(io.Writer); ok {
+		_, err := io.Copy(w, body)
+		return errors.Wrap(err, errReadResponse)
+	}
+	return errors.Wrap(json.NewDecoder(body).Decode(out), errDecodeResponse)
+}
+
+// A formFile is a file to be uploaded as part of a multipart form.
+type formFile struct {
+	field    string
+	filename string
+	content  []byte
+}
+
+// multipartForm encodes the supplied fields and files as a multipart form,
+// returning the encoded body and its content type.
+func multipartForm(fields url.Values, files ...formFile) ([]byte, string, error) {
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	for k, vs := range fields {
+		for _, v := range vs {
+			if err := w.WriteField(k, v); err != nil {
+				return nil, "", errors.Wrap(err, errEncodeForm)
+			}
+		}
+	}
+	for _, f := range files {
+		fw, err := w.CreateFormFile(f.field, f.filename)
+		if err != nil {
+			return nil, "", errors.Wrap(err, errEncodeForm)
+		}
+		if _, err := fw.Write(f.content); err != nil {
+			return nil, "", errors.Wrap(err, errEncodeForm)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", errors.Wrap(err, errEncodeForm)
+	}
+	return body.Bytes(), w.FormDataContentType(), nil
+}
@@ -0,0 +1,831 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const token = "jwt"
+
+// serve returns an APIClient for a test server that handles requests with the
+// supplied handler after checking they are authenticated.
+func serve(t *testing.T, h http.HandlerFunc) *APIClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer "+token {
+			t.Errorf("Authorization header: want %q, got %q", "Bearer "+token, got)
+		}
+		h(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	return New(token+"\n", WithEndpoint(srv.URL+"/"), WithHTTPClient(srv.Client()))
+}
+
+func TestPing(t *testing.T) {
+	cases := map[string]struct {
+		reason  string
+		handler http.HandlerFunc
+		want    error
+	}{
+		"Success": {
+			reason: "A successful response should not return an error.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/user" {
+					t.Errorf("path: want %q, got %q", "/user", r.URL.Path)
+				}
+				w.WriteHeader(http.StatusOK)
+			},
+		},
+		"Unauthorized": {
+			reason: "An unsuccessful response should return an error including the status and body.",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusUnauthorized)
+				_, _ = w.Write([]byte("invalid token\n"))
+			},
+			want: &APIError{StatusCode: http.StatusUnauthorized, Message: "invalid token"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := serve(t, tc.handler).Ping(context.Background())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Ping(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestListTestCases(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/organisations/acme/test_cases" {
+			t.Errorf("request: want GET /organisations/acme/test_cases, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":[
+			{"id":"a1","type":"test_cases","attributes":{"name":"checkout","scope":"acme"}},
+			{"id":"b2","type":"test_cases","attributes":{"name":"search","scope":"acme","state":"creating"}},
+			{"id":"c3","type":"test_cases","attributes":{"name":"legacy","scope":"acme","archived":true}}
+		]}`))
+	})
+
+	got, err := c.ListTestCases(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("c.ListTestCases(...): unexpected error: %s", err)
+	}
+	want := []TestCase{
+		{ID: "a1", Name: "checkout", Scope: "acme"},
+		{ID: "b2", Name: "search", Scope: "acme", State: TestCaseCreating},
+		{ID: "c3", Name: "legacy", Scope: "acme", Archived: true},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.ListTestCases(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestCreateTestCase(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/organisations/acme/test_cases" {
+			t.Errorf("request: want POST /organisations/acme/test_cases, got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.FormValue("test_case[name]"); got != "checkout" {
+			t.Errorf("test_case[name]: want %q, got %q", "checkout", got)
+		}
+		if got := r.FormValue("test_case[project_id]"); got != "p1" {
+			t.Errorf("test_case[project_id]: want %q, got %q", "p1", got)
+		}
+		if got := r.FormValue("test_case[default_data_source_id]"); got != "d1" {
+			t.Errorf("test_case[default_data_source_id]: want %q, got %q", "d1", got)
+		}
+		if diff := cmp.Diff([]string{"d1", "d2"}, r.MultipartForm.Value["test_case[data_source_ids][]"]); diff != "" {
+			t.Errorf("test_case[data_source_ids][]: -want, +got:\n%s\n", diff)
+		}
+		f, _, err := r.FormFile("test_case[javascript_definition]")
+		if err != nil {
+			t.Fatalf("test_case[javascript_definition]: %s", err)
+		}
+		script, _ := ioutil.ReadAll(f)
+		if string(script) != "definition.session();" {
+			t.Errorf("test_case[javascript_definition]: want %q, got %q", "definition.session();", script)
+		}
+		for field, want := range map[string]string{"test_case[client_certificate]": "cert", "test_case[client_key]": "key"} {
+			f, _, err := r.FormFile(field)
+			if err != nil {
+				t.Fatalf("%s: %s", field, err)
+			}
+			if got, _ := ioutil.ReadAll(f); string(got) != want {
+				t.Errorf("%s: want %q, got %q", field, want, got)
+			}
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"id":"a1","type":"test_cases","attributes":{"name":"checkout","scope":"acme","project_id":"p1","default_data_source_id":"d1","data_source_ids":["d1","d2"]}}}`))
+	})
+
+	got, err := c.CreateTestCase(context.Background(), "acme", "checkout", []byte("definition.session();"), WithClientCertificate([]byte("cert"), []byte("key")), WithProject("p1"), WithDefaultDataSource("d1"), WithDataSources("d1", "d2"))
+	if err != nil {
+		t.Fatalf("c.CreateTestCase(...): unexpected error: %s", err)
+	}
+	want := &TestCase{ID: "a1", Name: "checkout", Scope: "acme", ProjectID: "p1", DefaultDataSourceID: "d1", DataSourceIDs: []string{"d1", "d2"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.CreateTestCase(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestUpdateTestCaseWithoutDefinition(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPatch || r.URL.Path != "/test_cases/a1" {
+			t.Errorf("request: want PATCH /test_cases/a1, got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.FormValue("test_case[name]"); got != "checkout" {
+			t.Errorf("test_case[name]: want %q, got %q", "checkout", got)
+		}
+		if _, _, err := r.FormFile("test_case[javascript_definition]"); err != http.ErrMissingFile {
+			t.Errorf("test_case[javascript_definition]: want %v, got %v", http.ErrMissingFile, err)
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"a1","type":"test_cases","attributes":{"name":"checkout","scope":"acme"}}}`))
+	})
+
+	got, err := c.UpdateTestCase(context.Background(), "a1", "checkout", nil)
+	if err != nil {
+		t.Fatalf("c.UpdateTestCase(...): unexpected error: %s", err)
+	}
+	want := &TestCase{ID: "a1", Name: "checkout", Scope: "acme"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.UpdateTestCase(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestArchiveTestCase(t *testing.T) {
+	archived := false
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/test_cases/a1/archive" {
+			t.Errorf("request: want POST /test_cases/a1/archive, got %s %s", r.Method, r.URL.Path)
+		}
+		archived = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := c.ArchiveTestCase(context.Background(), "a1"); err != nil {
+		t.Fatalf("c.ArchiveTestCase(...): unexpected error: %s", err)
+	}
+	if !archived {
+		t.Errorf("c.ArchiveTestCase(...): want the test case to be archived")
+	}
+}
+
+func TestUnarchiveTestCase(t *testing.T) {
+	unarchived := false
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/test_cases/a1/unarchive" {
+			t.Errorf("request: want POST /test_cases/a1/unarchive, got %s %s", r.Method, r.URL.Path)
+		}
+		unarchived = true
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := c.UnarchiveTestCase(context.Background(), "a1"); err != nil {
+		t.Fatalf("c.UnarchiveTestCase(...): unexpected error: %s", err)
+	}
+	if !unarchived {
+		t.Errorf("c.UnarchiveTestCase(...): want the test case to be unarchived")
+	}
+}
+
+func TestTestCaseURL(t *testing.T) {
+	c := New("token", WithEndpoint("https://stormforge.example/"))
+	if got, want := c.TestCaseURL("a1"), "https://stormforge.example/test_cases/a1"; got != want {
+		t.Errorf("c.TestCaseURL(...): want %q, got %q", want, got)
+	}
+	if got, want := TestCaseAppURL("acme", "a1"), "https://app.stormforger.com/acme/test_cases/a1"; got != want {
+		t.Errorf("TestCaseAppURL(...): want %q, got %q", want, got)
+	}
+}
+
+func TestGetDefinition(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/test_cases/a1" {
+			t.Errorf("request: want GET /test_cases/a1, got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.URL.Query().Get("fields[test_cases]"); got != "javascript_definition" {
+			t.Errorf("fields[test_cases]: want %q, got %q", "javascript_definition", got)
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"a1","type":"test_cases","attributes":{"javascript_definition":"definition.session();"}}}`))
+	})
+
+	got, err := c.GetDefinition(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("c.GetDefinition(...): unexpected error: %s", err)
+	}
+	if string(got) != "definition.session();" {
+		t.Errorf("c.GetDefinition(...): want %q, got %q", "definition.session();", got)
+	}
+}
+
+func TestDeadlines(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	slow := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}
+
+	cases := map[string]struct {
+		reason string
+		o      []Option
+		ctx    func() (context.Context, context.CancelFunc)
+	}{
+		"PerCallTimeout": {
+			reason: "A call that outlives the client's timeout should fail with a deadline error.",
+			o:      []Option{WithTimeout(10 * time.Millisecond), WithBackoff(Backoff{Attempts: 1})},
+			ctx:    func() (context.Context, context.CancelFunc) { return context.WithCancel(context.Background()) },
+		},
+		"ContextDeadline": {
+			reason: "A call that outlives the supplied context should fail with a deadline error.",
+			o:      []Option{WithTimeout(0), WithBackoff(Backoff{Attempts: 1})},
+			ctx: func() (context.Context, context.CancelFunc) {
+				return context.WithTimeout(context.Background(), 10*time.Millisecond)
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := serve(t, slow)
+			for _, o := range tc.o {
+				o(c)
+			}
+			ctx, cancel := tc.ctx()
+			defer cancel()
+
+			err := c.Ping(ctx)
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("\n%s\nc.Ping(...): want %v, got %v", tc.reason, context.DeadlineExceeded, err)
+			}
+		})
+	}
+}
+
+func TestGetTestRun(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/test_runs/r1" {
+			t.Errorf("request: want GET /test_runs/r1, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"r1","type":"test_runs",
+			"attributes":{"title":"nightly","state":"done","started_at":"2020-12-01T10:00:00Z","ended_at":null,
+				"summary":{"request_count":1200,"error_count":3,"latency_p50":12.5,"latency_p95":80,"latency_p99":250}},
+			"relationships":{"test_case":{"data":{"id":"a1","type":"test_cases"}}}}}`))
+	})
+
+	got, err := c.GetTestRun(context.Background(), "r1")
+	if err != nil {
+		t.Fatalf("c.GetTestRun(...): unexpected error: %s", err)
+	}
+	started := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	want := &TestRun{ID: "r1", TestCaseID: "a1", Title: "nightly", State: "done", StartedAt: &started, Summary: &RunSummary{
+		Requests:   1200,
+		Errors:     3,
+		LatencyP50: 12500 * time.Microsecond,
+		LatencyP95: 80 * time.Millisecond,
+		LatencyP99: 250 * time.Millisecond,
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.GetTestRun(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestAbortTestRun(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/test_runs/r1/abort" {
+			t.Errorf("request: want POST /test_runs/r1/abort, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := c.AbortTestRun(context.Background(), "r1"); err != nil {
+		t.Errorf("c.AbortTestRun(...): unexpected error: %s", err)
+	}
+}
+
+func TestGetTestRunArtifact(t *testing.T) {
+	metrics := "{\"t\":0,\"status\":200}\n{\"t\":1,\"status\":503}\n"
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/test_runs/r1/exports/metrics" {
+			t.Errorf("request: want GET /test_runs/r1/exports/metrics, got %s %s", r.Method, r.URL.Path)
+		}
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		_, _ = w.Write([]byte(metrics))
+	})
+
+	got := &bytes.Buffer{}
+	if err := c.GetTestRunArtifact(context.Background(), "r1", ArtifactMetrics, got); err != nil {
+		t.Fatalf("c.GetTestRunArtifact(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(metrics, got.String()); diff != "" {
+		t.Errorf("c.GetTestRunArtifact(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestGetTestRunArtifactInterrupted(t *testing.T) {
+	requests := 0
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Length", "64")
+		_, _ = w.Write([]byte("{\"t\":0,\"status\":200}\n"))
+	})
+	c.backoff = Backoff{Attempts: 3}
+
+	got := &bytes.Buffer{}
+	if err := c.GetTestRunArtifact(context.Background(), "r1", ArtifactMetrics, got); err == nil {
+		t.Errorf("c.GetTestRunArtifact(...): want error for a truncated artifact")
+	}
+	if requests != 1 {
+		t.Errorf("c.GetTestRunArtifact(...): a download that failed after writing part of the artifact should not be retried: want 1 request, got %d", requests)
+	}
+}
+
+func TestGetOrganization(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/organisations/acme" {
+			t.Errorf("request: want GET /organisations/acme, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"acme","type":"organisations","attributes":{"name":"ACME","plan":"enterprise","member_count":12,
+			"limits":{"test_cases":null,"concurrent_test_runs":2,"arrival_rate":1000,"test_duration":3600}}}}`))
+	})
+
+	got, err := c.GetOrganization(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("c.GetOrganization(...): unexpected error: %s", err)
+	}
+	runs, rate, duration := int64(2), int64(1000), time.Hour
+	want := &Organization{ID: "acme", Name: "ACME", Plan: "enterprise", MemberCount: 12, Limits: OrganizationLimits{
+		ConcurrentTestRuns: &runs,
+		ArrivalRate:        &rate,
+		TestDuration:       &duration,
+	}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.GetOrganization(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestListDataSources(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/organisations/acme/file_fixtures" {
+			t.Errorf("request: want GET /organisations/acme/file_fixtures, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":[{"id":"f1","type":"file_fixtures","attributes":{"name":"users.csv","scope":"acme"}}]}`))
+	})
+
+	got, err := c.ListDataSources(context.Background(), "acme")
+	if err != nil {
+		t.Fatalf("c.ListDataSources(...): unexpected error: %s", err)
+	}
+	want := []DataSource{{ID: "f1", Name: "users.csv", Scope: "acme"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.ListDataSources(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestListIPRanges(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/ip_ranges" {
+			t.Errorf("request: want GET /ip_ranges, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":[
+			{"id":"1","type":"ip_ranges","attributes":{"region":"eu-central-1","cidr":"203.0.113.0/24"}},
+			{"id":"2","type":"ip_ranges","attributes":{"region":"us-east-1","cidr":"198.51.100.0/24"}}]}`))
+	})
+
+	got, err := c.ListIPRanges(context.Background())
+	if err != nil {
+		t.Fatalf("c.ListIPRanges(...): unexpected error: %s", err)
+	}
+	want := []IPRange{{Region: "eu-central-1", CIDR: "203.0.113.0/24"}, {Region: "us-east-1", CIDR: "198.51.100.0/24"}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.ListIPRanges(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestCreateDataSource(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/organisations/acme/file_fixtures" {
+			t.Errorf("request: want POST /organisations/acme/file_fixtures, got %s %s", r.Method, r.URL.Path)
+		}
+		f, h, err := r.FormFile("file_fixture[original]")
+		if err != nil {
+			t.Fatalf("r.FormFile(...): %s", err)
+		}
+		content, _ := ioutil.ReadAll(f)
+		if h.Filename != "users.csv" || string(content) != "user,password\n" {
+			t.Errorf("file_fixture[original]: want users.csv, got %s: %q", h.Filename, content)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"id":"f1","type":"file_fixtures","attributes":{"name":"users.csv","scope":"acme"}}}`))
+	})
+
+	got, err := c.CreateDataSource(context.Background(), "acme", "users.csv", []byte("user,password\n"))
+	if err != nil {
+		t.Fatalf("c.CreateDataSource(...): unexpected error: %s", err)
+	}
+	want := &DataSource{ID: "f1", Name: "users.csv", Scope: "acme"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.CreateDataSource(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestDeleteDataSource(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/organisations/acme/file_fixtures/f1" {
+			t.Errorf("request: want DELETE /organisations/acme/file_fixtures/f1, got %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	if err := c.DeleteDataSource(context.Background(), "acme", "f1"); err != nil {
+		t.Errorf("c.DeleteDataSource(...): unexpected error: %s", err)
+	}
+}
+
+func TestCreateNotificationChannel(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/organisations/acme/notification_channels" {
+			t.Errorf("request: want POST /organisations/acme/notification_channels, got %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("r.ParseMultipartForm(...): %s", err)
+		}
+		want := map[string][]string{
+			"notification_channel[name]":          {"load-tests"},
+			"notification_channel[channel_type]":  {"slack"},
+			"notification_channel[events][]":      {"test_run.finished", "test_run.failed"},
+			"notification_channel[url]":           {"https://hooks.slack.com/services/T0/B0/x"},
+			"notification_channel[slack_channel]": {"#load"},
+		}
+		if diff := cmp.Diff(want, map[string][]string(r.MultipartForm.Value)); diff != "" {
+			t.Errorf("form: -want, +got:\n%s\n", diff)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"id":"n1","type":"notification_channels","attributes":{"name":"load-tests","scope":"acme",
+			"channel_type":"slack","events":["test_run.finished","test_run.failed"],"url":"https://hooks.slack.com/services/T0/B0/x","slack_channel":"#load"}}}`))
+	})
+
+	nc := NotificationChannel{
+		Name:         "load-tests",
+		Type:         NotificationChannelSlack,
+		Events:       []string{"test_run.finished", "test_run.failed"},
+		URL:          "https://hooks.slack.com/services/T0/B0/x",
+		SlackChannel: "#load",
+	}
+	got, err := c.CreateNotificationChannel(context.Background(), "acme", nc)
+	if err != nil {
+		t.Fatalf("c.CreateNotificationChannel(...): unexpected error: %s", err)
+	}
+	nc.ID, nc.Scope = "n1", "acme"
+	if diff := cmp.Diff(&nc, got); diff != "" {
+		t.Errorf("c.CreateNotificationChannel(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestCreateAlertThreshold(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/test_cases/a1/alert_thresholds" {
+			t.Errorf("request: want POST /test_cases/a1/alert_thresholds, got %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("r.ParseMultipartForm(...): %s", err)
+		}
+		want := map[string][]string{
+			"alert_threshold[name]":     {"slow-checkout"},
+			"alert_threshold[metric]":   {"latency_p95"},
+			"alert_threshold[operator]": {"gt"},
+			"alert_threshold[value]":    {"250"},
+		}
+		if diff := cmp.Diff(want, map[string][]string(r.MultipartForm.Value)); diff != "" {
+			t.Errorf("form: -want, +got:\n%s\n", diff)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"id":"t1","type":"alert_thresholds","attributes":{"name":"slow-checkout","metric":"latency_p95","operator":"gt","value":"250"}}}`))
+	})
+
+	at := AlertThreshold{Name: "slow-checkout", Metric: "latency_p95", Operator: "gt", Value: "250"}
+	got, err := c.CreateAlertThreshold(context.Background(), "a1", at)
+	if err != nil {
+		t.Fatalf("c.CreateAlertThreshold(...): unexpected error: %s", err)
+	}
+	at.ID = "t1"
+	if diff := cmp.Diff(&at, got); diff != "" {
+		t.Errorf("c.CreateAlertThreshold(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestCreateAPIToken(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/organisations/acme/api_tokens" {
+			t.Errorf("request: want POST /organisations/acme/api_tokens, got %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			t.Fatalf("r.ParseMultipartForm(...): %s", err)
+		}
+		want := map[string][]string{
+			"api_token[name]":       {"ci"},
+			"api_token[scopes][]":   {"read", "launch"},
+			"api_token[expires_at]": {"2021-01-01T00:00:00Z"},
+		}
+		if diff := cmp.Diff(want, map[string][]string(r.MultipartForm.Value)); diff != "" {
+			t.Errorf("form: -want, +got:\n%s\n", diff)
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"id":"k1","type":"api_tokens","attributes":{"name":"ci","scope":"acme",
+			"scopes":["read","launch"],"token":"s3cr3t","expires_at":"2021-01-01T00:00:00Z"}}}`))
+	})
+
+	expires := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tok := APIToken{Name: "ci", Scopes: []string{APITokenScopeRead, APITokenScopeLaunch}, ExpiresAt: &expires}
+	got, err := c.CreateAPIToken(context.Background(), "acme", tok)
+	if err != nil {
+		t.Fatalf("c.CreateAPIToken(...): unexpected error: %s", err)
+	}
+	tok.ID, tok.Scope, tok.Token = "k1", "acme", "s3cr3t"
+	if diff := cmp.Diff(&tok, got); diff != "" {
+		t.Errorf("c.CreateAPIToken(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestLaunchTestRun(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/test_cases/a1/test_runs" {
+			t.Errorf("request: want POST /test_cases/a1/test_runs, got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.FormValue("test_run[title]"); got != "nightly" {
+			t.Errorf("test_run[title]: want %q, got %q", "nightly", got)
+		}
+		if got := r.FormValue("test_run[notes]"); got != "" {
+			t.Errorf("test_run[notes]: want none, got %q", got)
+		}
+		if got := r.FormValue("test_run[thresholds][latency_p95]"); got != "250" {
+			t.Errorf("test_run[thresholds][latency_p95]: want %q, got %q", "250", got)
+		}
+		if got := r.FormValue("test_run[thresholds][error_rate]"); got != "0.5" {
+			t.Errorf("test_run[thresholds][error_rate]: want %q, got %q", "0.5", got)
+		}
+		for k, want := range map[string]string{
+			"test_run[arrival_phases][0][duration]":    "60",
+			"test_run[arrival_phases][0][rate]":        "0",
+			"test_run[arrival_phases][0][target_rate]": "10",
+			"test_run[arrival_phases][0][max_clients]": "",
+			"test_run[arrival_phases][1][duration]":    "300",
+			"test_run[arrival_phases][1][rate]":        "10",
+			"test_run[arrival_phases][1][target_rate]": "10",
+			"test_run[arrival_phases][1][max_clients]": "500",
+			"test_run[target]":                         "https://staging.example.com",
+			"test_run[headers][X-Env]":                 "staging",
+		} {
+			if got := r.FormValue(k); got != want {
+				t.Errorf("%s: want %q, got %q", k, want, got)
+			}
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"data":{"id":"r1","type":"test_runs","attributes":{"title":"nightly","state":"launching"}}}`))
+	})
+
+	maxClients := int32(500)
+	ro := RunOptions{
+		Title:      "nightly",
+		Thresholds: map[string]float64{ThresholdLatencyP95: 250, ThresholdErrorRate: 0.5},
+		ArrivalPhases: []ArrivalPhase{
+			{Duration: time.Minute, TargetRate: 10},
+			{Duration: 5 * time.Minute, Rate: 10, TargetRate: 10, MaxClients: &maxClients},
+		},
+		Target:  "https://staging.example.com",
+		Headers: map[string]string{"X-Env": "staging"},
+	}
+	got, err := c.LaunchTestRun(context.Background(), "a1", ro)
+	if err != nil {
+		t.Fatalf("c.LaunchTestRun(...): unexpected error: %s", err)
+	}
+	want := &TestRun{ID: "r1", TestCaseID: "a1", Title: "nightly", State: "launching"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.LaunchTestRun(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestListRevisions(t *testing.T) {
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/test_cases/a1/revisions" {
+			t.Errorf("request: want GET /test_cases/a1/revisions, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"data":[
+			{"id":"v1","type":"revisions","attributes":{"created_at":"2020-12-01T10:00:00Z"},
+				"relationships":{"author":{"data":{"id":"u1","type":"users"}}}},
+			{"id":"v2","type":"revisions","attributes":{"created_at":"2020-12-02T10:00:00Z"},
+				"relationships":{"author":{"data":{"id":"u2","type":"users"}}}}
+		],"included":[{"id":"u2","type":"users","attributes":{"name":"Jane"}}]}`))
+	})
+
+	got, err := c.ListRevisions(context.Background(), "a1")
+	if err != nil {
+		t.Fatalf("c.ListRevisions(...): unexpected error: %s", err)
+	}
+	first, second := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC), time.Date(2020, 12, 2, 10, 0, 0, 0, time.UTC)
+	want := []Revision{
+		{ID: "v2", CreatedAt: &second, Author: "Jane"},
+		{ID: "v1", CreatedAt: &first, Author: "u1"},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.ListRevisions(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestPutExperiment(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/optimize/v1/experiments/checkout" {
+			t.Errorf("request: want PUT /optimize/v1/experiments/checkout, got %s %s", r.Method, r.URL.Path)
+		}
+		if got := r.Header.Get("Content-Type"); got != "application/json" {
+			t.Errorf("Content-Type header: want %q, got %q", "application/json", got)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		want := `{"optimization":[{"name":"experimentBudget","value":"40"}],` +
+			`"parameters":[{"name":"cpu","type":"int","bounds":{"min":100,"max":2000}},{"name":"gc","type":"categorical","values":["G1","Parallel"]}],` +
+			`"metrics":[{"name":"cost","minimize":true,"optimize":true},{"name":"p95","minimize":true,"optimize":false}]}`
+		if diff := cmp.Diff(want, strings.TrimSpace(string(body))); diff != "" {
+			t.Errorf("body: -want, +got:\n%s\n", diff)
+		}
+		_, _ = w.Write([]byte(`{"observations":3,` + want[1:]))
+	}))
+	t.Cleanup(srv.Close)
+	c := New(token, WithEndpoint("https://api.stormforger.example"), WithOptimizeEndpoint(srv.URL+"/optimize/"), WithHTTPClient(srv.Client()))
+
+	e := Experiment{
+		Name: "checkout",
+		Parameters: []Parameter{
+			{Name: "cpu", Type: ParameterInt, Min: "100", Max: "2000"},
+			{Name: "gc", Type: ParameterCategorical, Values: []string{"G1", "Parallel"}},
+		},
+		Metrics: []Metric{
+			{Name: "cost", Minimize: true, Optimize: true},
+			{Name: "p95", Minimize: true},
+		},
+		Budget: 40,
+	}
+	got, err := c.PutExperiment(context.Background(), e)
+	if err != nil {
+		t.Fatalf("c.PutExperiment(...): unexpected error: %s", err)
+	}
+	e.Observations = 3
+	if diff := cmp.Diff(&e, got); diff != "" {
+		t.Errorf("c.PutExperiment(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestGetTrial(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/v1/experiments/checkout/trials/3" {
+			t.Errorf("request: want GET /v1/experiments/checkout/trials/3, got %s %s", r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"number":3,"status":"completed",` +
+			`"assignments":[{"parameterName":"cpu","value":500},{"parameterName":"gc","value":"G1"}],` +
+			`"values":[{"metricName":"cost","value":12.5,"error":0.5},{"metricName":"p95","value":180}],` +
+			`"startTime":"2020-11-01T10:00:00Z","completionTime":"2020-11-01T10:15:00Z"}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := New(token, WithOptimizeEndpoint(srv.URL), WithHTTPClient(srv.Client()))
+
+	got, err := c.GetTrial(context.Background(), "checkout", 3)
+	if err != nil {
+		t.Fatalf("c.GetTrial(...): unexpected error: %s", err)
+	}
+	start := time.Date(2020, 11, 1, 10, 0, 0, 0, time.UTC)
+	completion := start.Add(15 * time.Minute)
+	want := &Trial{
+		Experiment:     "checkout",
+		Number:         3,
+		Status:         TrialCompleted,
+		Assignments:    []Assignment{{Parameter: "cpu", Value: "500"}, {Parameter: "gc", Value: "G1"}},
+		Values:         []Value{{Metric: "cost", Value: "12.5", Error: "0.5"}, {Metric: "p95", Value: "180"}},
+		StartTime:      &start,
+		CompletionTime: &completion,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.GetTrial(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestPutApplication(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Path != "/v1/applications/shop" {
+			t.Errorf("request: want PUT /v1/applications/shop, got %s %s", r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		want := `{"resources":[{"kubernetes":{"namespace":"shop","selector":"tier=backend"}},{"kubernetes":{"namespace":"payments","workloads":["deployment/checkout"]}}],` +
+			`"scenarios":[{"name":"black-friday","stormforger":{"testCase":"acme/checkout"}}]}`
+		if diff := cmp.Diff(want, strings.TrimSpace(string(body))); diff != "" {
+			t.Errorf("body: -want, +got:\n%s\n", diff)
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	c := New(token, WithOptimizeEndpoint(srv.URL), WithHTTPClient(srv.Client()))
+
+	a := Application{
+		Name: "shop",
+		Resources: []ApplicationResource{
+			{Namespace: "shop", Selector: "tier=backend"},
+			{Namespace: "payments", Workloads: []string{"deployment/checkout"}},
+		},
+		Scenarios: []Scenario{{Name: "black-friday", TestCase: "acme/checkout"}},
+	}
+	got, err := c.PutApplication(context.Background(), a)
+	if err != nil {
+		t.Fatalf("c.PutApplication(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(&a, got); diff != "" {
+		t.Errorf("c.PutApplication(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestGetRecommendation(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "/v1/clusters/prod/namespaces/shop/workloads/Deployment/checkout/recommendations/latest"
+		if r.Method != http.MethodGet || r.URL.Path != want {
+			t.Errorf("request: want GET %s, got %s %s", want, r.Method, r.URL.Path)
+		}
+		_, _ = w.Write([]byte(`{"generatedAt":"2020-11-01T10:00:00Z","containers":[` +
+			`{"name":"app","requests":{"cpu":"250m","memory":"512Mi"},"limits":{"memory":"1Gi"}}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := New(token, WithOptimizeEndpoint(srv.URL), WithHTTPClient(srv.Client()))
+
+	wl := Workload{Cluster: "prod", Namespace: "shop", Kind: "Deployment", Name: "checkout"}
+	got, err := c.GetRecommendation(context.Background(), wl)
+	if err != nil {
+		t.Fatalf("c.GetRecommendation(...): unexpected error: %s", err)
+	}
+	generated := time.Date(2020, 11, 1, 10, 0, 0, 0, time.UTC)
+	want := &Recommendation{
+		Workload:    wl,
+		GeneratedAt: &generated,
+		Containers: []ContainerRecommendation{
+			{Name: "app", Requests: Resources{CPU: "250m", Memory: "512Mi"}, Limits: Resources{Memory: "1Gi"}},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("c.GetRecommendation(...): -want, +got:\n%s\n", diff)
+	}
+}
+
+func TestPutWorkloadConfig(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		want := "/v1/clusters/prod/namespaces/shop/workloads/Deployment/checkout/config"
+		if r.Method != http.MethodPut || r.URL.Path != want {
+			t.Errorf("request: want PUT %s, got %s %s", want, r.Method, r.URL.Path)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if diff := cmp.Diff(`{"applyPolicy":"auto-apply"}`, strings.TrimSpace(string(body))); diff != "" {
+			t.Errorf("body: -want, +got:\n%s\n", diff)
+		}
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	c := New(token, WithOptimizeEndpoint(srv.URL), WithHTTPClient(srv.Client()))
+
+	cfg := WorkloadConfig{
+		Workload:    Workload{Cluster: "prod", Namespace: "shop", Kind: "Deployment", Name: "checkout"},
+		ApplyPolicy: ApplyPolicyAutoApply,
+	}
+	got, err := c.PutWorkloadConfig(context.Background(), cfg)
+	if err != nil {
+		t.Fatalf("c.PutWorkloadConfig(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(&cfg, got); diff != "" {
+		t.Errorf("c.PutWorkloadConfig(...): -want, +got:\n%s\n", diff)
+	}
+}
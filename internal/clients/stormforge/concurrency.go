@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Defaults for the number of requests in flight to the StormForge API.
+const (
+	DefaultMinConcurrency = 1
+	DefaultMaxConcurrency = 16
+)
+
+// concurrencyWindow is how many requests complete between two adjustments of
+// the concurrency limit, and maxOverloadRate the share of them that may be
+// rate limited or fail because the API is unavailable before it is lowered.
+const (
+	concurrencyWindow = 20
+	maxOverloadRate   = 0.1
+)
+
+const errConcurrency = "cannot wait for a concurrency slot"
+
+var concurrencyLimitDesc = prometheus.NewDesc(
+	"stormforge_api_concurrency_limit",
+	"Current maximum number of requests in flight to the StormForge API.",
+	nil, nil,
+)
+
+// An AdaptiveLimiter bounds the number of requests in flight to the
+// StormForge API. It halves its limit whenever more than a tenth of recent
+// requests were rate limited or found the API unavailable, and raises it by
+// one whenever fewer were, within the supplied bounds. It thus backs off
+// quickly during an incident and recovers gradually once the API is healthy.
+// It is safe for concurrent use, and is intended to be shared by every
+// APIClient of the provider. Its current limit is exported as a Prometheus
+// metric.
+type AdaptiveLimiter struct {
+	min, max int
+
+	mu         sync.Mutex
+	limit      int
+	inFlight   int
+	completed  int
+	overloaded int
+	freed      chan struct{}
+}
+
+// NewAdaptiveLimiter returns an AdaptiveLimiter whose limit starts at max and
+// stays between min and max. A min of less than one is treated as one.
+func NewAdaptiveLimiter(min, max int) *AdaptiveLimiter {
+	if min < 1 {
+		min = 1
+	}
+	if max < min {
+		max = min
+	}
+	return &AdaptiveLimiter{min: min, max: max, limit: max, freed: make(chan struct{})}
+}
+
+// WithAdaptiveLimiter configures the AdaptiveLimiter an APIClient acquires a
+// slot from before each request.
+func WithAdaptiveLimiter(l *AdaptiveLimiter) Option {
+	return func(c *APIClient) {
+		c.concurrency = l
+	}
+}
+
+// Limit returns the current maximum number of requests in flight.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// Describe implements prometheus.Collector.
+func (l *AdaptiveLimiter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- concurrencyLimitDesc
+}
+
+// Collect implements prometheus.Collector.
+func (l *AdaptiveLimiter) Collect(ch chan<- prometheus.Metric) {
+	ch <- prometheus.MustNewConstMetric(concurrencyLimitDesc, prometheus.GaugeValue, float64(l.Limit()))
+}
+
+// acquire blocks until fewer requests than the limit are in flight, or the
+// supplied context is done. A nil AdaptiveLimiter never blocks.
+func (l *AdaptiveLimiter) acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		freed := l.freed
+		l.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), errConcurrency)
+		case <-freed:
+		}
+	}
+}
+
+// release frees the slot of a request that completed with the supplied
+// error, and adjusts the limit once a window of requests has completed.
+func (l *AdaptiveLimiter) release(err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	l.completed++
+	if overloaded(err) {
+		l.overloaded++
+	}
+	if l.completed >= concurrencyWindow {
+		switch {
+		case float64(l.overloaded)/float64(l.completed) > maxOverloadRate:
+			l.limit /= 2
+			if l.limit < l.min {
+				l.limit = l.min
+			}
+		case l.limit < l.max:
+			l.limit++
+		}
+		l.completed, l.overloaded = 0, 0
+	}
+	// Wake every waiter; those that find no free slot wait again.
+	close(l.freed)
+	l.freed = make(chan struct{})
+}
+
+// overloaded returns true if the supplied error indicates the StormForge API
+// is overloaded: it rate limited the request, was unavailable, or could not be
+// reached. Requests cancelled by the caller say nothing about the API.
+func overloaded(err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	return ReasonFor(err) == ReasonRateLimited || IsUnreachable(err)
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestAdaptiveLimiter(t *testing.T) {
+	rateLimited := &APIError{StatusCode: http.StatusTooManyRequests}
+	unavailable := &APIError{StatusCode: http.StatusServiceUnavailable}
+	invalid := &APIError{StatusCode: http.StatusBadRequest}
+
+	// complete completes a window of requests, the supplied number of which
+	// fail with the supplied error.
+	complete := func(l *AdaptiveLimiter, failed int, err error) {
+		for i := 0; i < concurrencyWindow; i++ {
+			if aerr := l.acquire(context.Background()); aerr != nil {
+				t.Fatalf("l.acquire(...): unexpected error: %s", aerr)
+			}
+			if i < failed {
+				l.release(err)
+				continue
+			}
+			l.release(nil)
+		}
+	}
+
+	l := NewAdaptiveLimiter(2, 8)
+	cases := []struct {
+		reason string
+		failed int
+		err    error
+		want   int
+	}{
+		{reason: "A healthy API should keep the limit at its maximum.", want: 8},
+		{reason: "A window in which many requests were rate limited should halve the limit.", failed: 5, err: rateLimited, want: 4},
+		{reason: "A window in which many requests found the API unavailable should halve the limit.", failed: 3, err: unavailable, want: 2},
+		{reason: "The limit should not fall below its minimum.", failed: concurrencyWindow, err: rateLimited, want: 2},
+		{reason: "Errors that do not indicate an overloaded API should not lower the limit.", failed: concurrencyWindow, err: invalid, want: 3},
+		{reason: "Few overloaded requests should not lower the limit.", failed: 2, err: rateLimited, want: 4},
+		{reason: "Requests cancelled by the caller should not lower the limit.", failed: concurrencyWindow, err: errors.Wrap(context.Canceled, "cancelled"), want: 5},
+	}
+	for _, tc := range cases {
+		complete(l, tc.failed, tc.err)
+		if got := l.Limit(); got != tc.want {
+			t.Errorf("\n%s\nl.Limit(): want %d, got %d", tc.reason, tc.want, got)
+		}
+		if got := testutil.ToFloat64(l); got != float64(tc.want) {
+			t.Errorf("\n%s\nstormforge_api_concurrency_limit: want %d, got %v", tc.reason, tc.want, got)
+		}
+	}
+}
+
+func TestAdaptiveLimiterBlocks(t *testing.T) {
+	l := NewAdaptiveLimiter(1, 1)
+	if err := l.acquire(context.Background()); err != nil {
+		t.Fatalf("l.acquire(...): unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.acquire(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("l.acquire(...): a request beyond the limit should wait until its context is done, got error %v", err)
+	}
+
+	acquired := make(chan error)
+	go func() { acquired <- l.acquire(context.Background()) }()
+	l.release(nil)
+	if err := <-acquired; err != nil {
+		t.Errorf("l.acquire(...): a waiting request should acquire a released slot, got error %s", err)
+	}
+}
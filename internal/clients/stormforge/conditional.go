@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"net/http"
+	"sync"
+)
+
+// DefaultConditionalCacheSize is how many responses are kept for conditional
+// GETs by default.
+const DefaultConditionalCacheSize = 1000
+
+// maxConditionalBody bounds the size of the bodies kept for conditional GETs,
+// so that large downloads such as test run artifacts are not held in memory.
+const maxConditionalBody = 1 << 20
+
+// A ConditionalCache keeps the bodies of successful GET responses along with
+// their ETag and Last-Modified validators. GETs of a URL whose response is
+// kept send If-None-Match or If-Modified-Since, and a 304 Not Modified reuses
+// the kept body, so polling a resource that has not changed transfers no body.
+// It is safe for concurrent use, and is intended to be shared by every
+// APIClient of the provider. Entries are partitioned by endpoint and token, so
+// clients never read responses fetched with other credentials. Once full, the
+// oldest entry is evicted to make room for a new one.
+type ConditionalCache struct {
+	size int
+
+	mu      sync.Mutex
+	entries map[string]conditionalEntry
+	order   []string
+}
+
+type conditionalEntry struct {
+	etag         string
+	lastModified string
+	body         []byte
+}
+
+// NewConditionalCache returns a ConditionalCache that keeps at most the
+// supplied number of responses. A size of zero or less disables conditional
+// GETs.
+func NewConditionalCache(size int) *ConditionalCache {
+	return &ConditionalCache{size: size, entries: map[string]conditionalEntry{}}
+}
+
+// WithConditionalCache configures the ConditionalCache an APIClient sends
+// conditional GETs from.
+func WithConditionalCache(cc *ConditionalCache) Option {
+	return func(c *APIClient) {
+		c.conditional = cc
+	}
+}
+
+// validate adds the validators of the response kept for the supplied key, if
+// any, to the supplied request, and returns the kept response.
+func (cc *ConditionalCache) validate(key string, req *http.Request) (conditionalEntry, bool) {
+	e, ok := cc.get(key)
+	if !ok {
+		return e, false
+	}
+	if e.etag != "" {
+		req.Header.Set("If-None-Match", e.etag)
+	}
+	if e.lastModified != "" {
+		req.Header.Set("If-Modified-Since", e.lastModified)
+	}
+	return e, true
+}
+
+func (cc *ConditionalCache) get(key string) (conditionalEntry, bool) {
+	if cc == nil || cc.size <= 0 {
+		return conditionalEntry{}, false
+	}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	e, ok := cc.entries[key]
+	return e, ok
+}
+
+// set keeps the supplied body for the supplied key if the supplied response
+// headers include a validator, and forgets any body kept otherwise.
+func (cc *ConditionalCache) set(key string, h http.Header, body []byte) {
+	if cc == nil || cc.size <= 0 {
+		return
+	}
+	e := conditionalEntry{etag: h.Get("ETag"), lastModified: h.Get("Last-Modified"), body: body}
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if e.etag == "" && e.lastModified == "" || len(body) > maxConditionalBody {
+		cc.delete(key)
+		return
+	}
+	if _, ok := cc.entries[key]; !ok {
+		if len(cc.order) >= cc.size {
+			delete(cc.entries, cc.order[0])
+			cc.order = cc.order[1:]
+		}
+		cc.order = append(cc.order, key)
+	}
+	cc.entries[key] = e
+}
+
+// delete forgets the body kept for the supplied key. The caller must hold mu.
+func (cc *ConditionalCache) delete(key string) {
+	if _, ok := cc.entries[key]; !ok {
+		return
+	}
+	delete(cc.entries, key)
+	for i, k := range cc.order {
+		if k == key {
+			cc.order = append(cc.order[:i], cc.order[i+1:]...)
+			break
+		}
+	}
+}
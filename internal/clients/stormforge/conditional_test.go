@@ -0,0 +1,82 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestConditionalCache(t *testing.T) {
+	const etag = `"v1"`
+	gets, unchanged := 0, 0
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		gets++
+		if r.Header.Get("If-None-Match") == etag {
+			unchanged++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(`{"data":{"id":"r1","type":"test_runs","attributes":{"title":"nightly","state":"running"}}}`))
+	})
+	WithConditionalCache(NewConditionalCache(10))(c)
+
+	want := &TestRun{ID: "r1", Title: "nightly", State: "running"}
+	for i, reason := range []string{
+		"The first GET should fetch the test run.",
+		"A GET of a test run that has not changed should reuse the response kept from the first GET.",
+	} {
+		got, err := c.GetTestRun(context.Background(), "r1")
+		if err != nil {
+			t.Fatalf("\n%s\nc.GetTestRun(...): unexpected error: %s", reason, err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("\n%s\nc.GetTestRun(...): -want, +got:\n%s\n", reason, diff)
+		}
+		if gets != i+1 || unchanged != i {
+			t.Errorf("\n%s\nc.GetTestRun(...): want %d GETs of which %d unchanged, got %d of which %d unchanged", reason, i+1, i, gets, unchanged)
+		}
+	}
+
+	reason := "A fresh GET should not be conditional."
+	if _, err := c.GetTestRun(Fresh(context.Background()), "r1"); err != nil {
+		t.Fatalf("\n%s\nc.GetTestRun(...): unexpected error: %s", reason, err)
+	}
+	if gets != 3 || unchanged != 1 {
+		t.Errorf("\n%s\nc.GetTestRun(...): want 3 GETs of which 1 unchanged, got %d of which %d unchanged", reason, gets, unchanged)
+	}
+}
+
+func TestConditionalCacheEviction(t *testing.T) {
+	cc := NewConditionalCache(2)
+	h := http.Header{"Etag": []string{`"v1"`}}
+	cc.set("a", h, []byte("a"))
+	cc.set("b", h, []byte("b"))
+	cc.set("a", h, []byte("a2"))
+	cc.set("c", h, []byte("c"))
+	cc.set("b", http.Header{}, []byte("b2"))
+
+	for key, want := range map[string]bool{"a": false, "b": false, "c": true} {
+		if _, ok := cc.get(key); ok != want {
+			t.Errorf("cc.get(%q): want kept %t, got %t", key, want, ok)
+		}
+	}
+}
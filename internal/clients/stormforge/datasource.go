@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// A DataSource is a file of an organization that test cases draw data from,
+// for example a CSV of user credentials. The StormForge API calls data sources
+// file fixtures.
+type DataSource struct {
+	ID    string
+	Name  string
+	Scope string
+}
+
+type dataSourceAttributes struct {
+	Name  string `json:"name"`
+	Scope string `json:"scope"`
+}
+
+func dataSourceFrom(o resourceObject) (*DataSource, error) {
+	a := dataSourceAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	return &DataSource{ID: o.ID, Name: a.Name, Scope: a.Scope}, nil
+}
+
+// ListDataSources returns the data sources of the supplied organization.
+func (c *APIClient) ListDataSources(ctx context.Context, org string) ([]DataSource, error) {
+	dss := []DataSource{}
+	err := c.collection(WithOrg(ctx, org), "/organisations/"+url.PathEscape(org)+"/file_fixtures", func(o resourceObject, _ included) error {
+		ds, err := dataSourceFrom(o)
+		if err != nil {
+			return err
+		}
+		dss = append(dss, *ds)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return dss, nil
+}
+
+// GetDataSource returns the data source with the supplied ID of the supplied
+// organization.
+func (c *APIClient) GetDataSource(ctx context.Context, org, id string) (*DataSource, error) {
+	d, err := c.resource(WithOrg(ctx, org), http.MethodGet, "/organisations/"+url.PathEscape(org)+"/file_fixtures/"+url.PathEscape(id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return dataSourceFrom(d.Data)
+}
+
+// CreateDataSource uploads a data source with the supplied file name and
+// content to the supplied organization.
+func (c *APIClient) CreateDataSource(ctx context.Context, org, name string, content []byte) (*DataSource, error) {
+	body, ct, err := multipartForm(
+		url.Values{"file_fixture[name]": {name}},
+		formFile{field: "file_fixture[original]", filename: name, content: content},
+	)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPost, "/organisations/"+url.PathEscape(org)+"/file_fixtures", body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return dataSourceFrom(d.Data)
+}
+
+// UpdateDataSource uploads new content for the data source with the supplied
+// ID of the supplied organization.
+func (c *APIClient) UpdateDataSource(ctx context.Context, org, id, name string, content []byte) (*DataSource, error) {
+	body, ct, err := multipartForm(
+		url.Values{"file_fixture[name]": {name}},
+		formFile{field: "file_fixture[original]", filename: name, content: content},
+	)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPatch, "/organisations/"+url.PathEscape(org)+"/file_fixtures/"+url.PathEscape(id), body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return dataSourceFrom(d.Data)
+}
+
+// DeleteDataSource deletes the data source with the supplied ID of the
+// supplied organization.
+func (c *APIClient) DeleteDataSource(ctx context.Context, org, id string) error {
+	return c.do(WithOrg(ctx, org), http.MethodDelete, "/organisations/"+url.PathEscape(org)+"/file_fixtures/"+url.PathEscape(id), nil, "", nil)
+}
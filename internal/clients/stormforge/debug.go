@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+)
+
+// maxLoggedBody bounds how much of a request or response body is logged.
+const maxLoggedBody = 4096
+
+const redacted = "REDACTED"
+
+// jwt matches JSON Web Tokens. The header of a matched token is kept, since it
+// only describes the token's algorithm and type.
+var jwt = regexp.MustCompile(`(eyJ[A-Za-z0-9_-]*)\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`)
+
+// WithDebugLogger configures an APIClient to log every request it sends and
+// every response it receives, including their bodies, to the supplied logger.
+// Credentials are redacted from the logged headers and bodies.
+func WithDebugLogger(l logging.Logger) Option {
+	return func(c *APIClient) {
+		c.debug = l
+	}
+}
+
+// redact removes the client's token and any JSON Web Token payloads from the
+// supplied text.
+func (c *APIClient) redact(s string) string {
+	if c.token != "" {
+		s = strings.ReplaceAll(s, c.token, redacted)
+	}
+	return jwt.ReplaceAllString(s, "$1."+redacted)
+}
+
+func (c *APIClient) headers(h http.Header) map[string]string {
+	m := make(map[string]string, len(h))
+	for k, vs := range h {
+		v := strings.Join(vs, ", ")
+		if k == "Authorization" || k == "Cookie" || k == "Set-Cookie" {
+			v = redacted
+		}
+		m[k] = c.redact(v)
+	}
+	return m
+}
+
+func (c *APIClient) body(b []byte) string {
+	s := string(b)
+	if len(s) > maxLoggedBody {
+		s = s[:maxLoggedBody] + "..."
+	}
+	return c.redact(s)
+}
+
+// logRequest logs the supplied request and its body, if debug logging is
+// enabled.
+func (c *APIClient) logRequest(req *http.Request, body []byte) {
+	if c.debug == nil {
+		return
+	}
+	c.debug.Info("StormForge API request",
+		"method", req.Method,
+		"url", c.redact(req.URL.String()),
+		"headers", c.headers(req.Header),
+		"body", c.body(body))
+}
+
+// logResponse logs the supplied response and its body, if debug logging is
+// enabled. The body is read and replaced, so it may still be read afterwards.
+func (c *APIClient) logResponse(rsp *http.Response) {
+	if c.debug == nil {
+		return
+	}
+	b, err := ioutil.ReadAll(rsp.Body)
+	_ = rsp.Body.Close()
+	rsp.Body = ioutil.NopCloser(bytes.NewReader(b))
+	kv := []interface{}{
+		"status", rsp.StatusCode,
+		"url", c.redact(rsp.Request.URL.String()),
+		"headers", c.headers(rsp.Header),
+		"body", c.body(b),
+	}
+	if err != nil {
+		kv = append(kv, "error", err.Error())
+	}
+	c.debug.Info("StormForge API response", kv...)
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A Reason explains why the StormForge API rejected a call.
+type Reason string
+
+// Reasons the StormForge API may reject a call.
+const (
+	ReasonUnknown       Reason = "Unknown"
+	ReasonInvalid       Reason = "Invalid"
+	ReasonNotFound      Reason = "NotFound"
+	ReasonUnauthorized  Reason = "Unauthorized"
+	ReasonForbidden     Reason = "Forbidden"
+	ReasonConflict      Reason = "Conflict"
+	ReasonQuotaExceeded Reason = "QuotaExceeded"
+	ReasonRateLimited   Reason = "RateLimited"
+	ReasonUnavailable   Reason = "Unavailable"
+)
+
+const errAPIFmt = "StormForge API: %s (status %d): %s"
+
+// maxMessage bounds the length of an APIError's message.
+const maxMessage = 1024
+
+// An APIError is returned when the StormForge API responds with an
+// unsuccessful status.
+type APIError struct {
+	// StatusCode of the response.
+	StatusCode int
+
+	// Message describes the errors of the response, or is the (possibly
+	// truncated) body of the response if it did not describe its errors.
+	Message string
+
+	// Errors of the response, if it described them.
+	Errors []ErrorObject
+
+	// RetryAfter is how long the API asked us to wait before retrying, if it
+	// did.
+	RetryAfter time.Duration
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf(errAPIFmt, e.Reason(), e.StatusCode, e.Message)
+}
+
+// newAPIError returns the error for an unsuccessful response with the supplied
+// status, headers and body.
+func newAPIError(status int, h http.Header, body []byte) *APIError {
+	e := &APIError{StatusCode: status, RetryAfter: retryAfter(h.Get("Retry-After"), time.Now())}
+	d := &errorDocument{}
+	if err := json.Unmarshal(body, d); err == nil && len(d.Errors) > 0 {
+		e.Errors = d.Errors
+		msgs := make([]string, len(d.Errors))
+		for i := range d.Errors {
+			msgs[i] = d.Errors[i].String()
+		}
+		e.Message = truncate(strings.Join(msgs, "; "))
+		return e
+	}
+	e.Message = truncate(strings.TrimSpace(string(body)))
+	return e
+}
+
+func truncate(s string) string {
+	if len(s) > maxMessage {
+		return s[:maxMessage]
+	}
+	return s
+}
+
+// Reason the API rejected the call.
+func (e *APIError) Reason() Reason {
+	switch {
+	case e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity:
+		return ReasonInvalid
+	case e.StatusCode == http.StatusNotFound:
+		return ReasonNotFound
+	case e.StatusCode == http.StatusUnauthorized:
+		return ReasonUnauthorized
+	case e.StatusCode == http.StatusForbidden:
+		return ReasonForbidden
+	case e.StatusCode == http.StatusConflict:
+		return ReasonConflict
+	case e.StatusCode == http.StatusPaymentRequired:
+		return ReasonQuotaExceeded
+	case e.StatusCode == http.StatusTooManyRequests:
+		return ReasonRateLimited
+	case e.StatusCode >= 500:
+		return ReasonUnavailable
+	}
+	return ReasonUnknown
+}
+
+// ReasonFor returns the reason the StormForge API rejected the call that
+// returned the supplied error, or ReasonUnknown if the error did not come from
+// the API. Calls not attempted because the client's token lacks their scope
+// are considered forbidden, and those not attempted because it has expired
+// unauthorized.
+func ReasonFor(err error) Reason {
+	var ae *APIError
+	if errors.As(err, &ae) {
+		return ae.Reason()
+	}
+	var se *ScopeError
+	if errors.As(err, &se) {
+		return ReasonForbidden
+	}
+	var ee *ExpiredError
+	if errors.As(err, &ee) {
+		return ReasonUnauthorized
+	}
+	return ReasonUnknown
+}
+
+// IsInvalid returns true if the supplied error indicates the API rejected the
+// request itself, for example a definition that is not valid JavaScript.
+func IsInvalid(err error) bool { return ReasonFor(err) == ReasonInvalid }
+
+// IsUnreachable returns true if the supplied error indicates the API could not
+// be reached, for example because of a network problem or a timeout, or that
+// it was unavailable.
+func IsUnreachable(err error) bool {
+	var ue *url.Error
+	return errors.As(err, &ue) || ReasonFor(err) == ReasonUnavailable
+}
+
+// IsNotFound returns true if the supplied error indicates the requested
+// resource does not exist.
+func IsNotFound(err error) bool { return ReasonFor(err) == ReasonNotFound }
+
+// IsUnauthorized returns true if the supplied error indicates the API did not
+// accept the supplied credentials.
+func IsUnauthorized(err error) bool { return ReasonFor(err) == ReasonUnauthorized }
+
+// IsForbidden returns true if the supplied error indicates the credentials do
+// not permit the call.
+func IsForbidden(err error) bool { return ReasonFor(err) == ReasonForbidden }
+
+// IsConflict returns true if the supplied error indicates the call conflicts
+// with the current state of a resource, e.g. because it already exists.
+func IsConflict(err error) bool { return ReasonFor(err) == ReasonConflict }
+
+// IsQuotaExceeded returns true if the supplied error indicates the
+// organization has exhausted its plan's quota.
+func IsQuotaExceeded(err error) bool { return ReasonFor(err) == ReasonQuotaExceeded }
+
+// IsRateLimited returns true if the supplied error indicates the call was
+// rejected due to rate limiting.
+func IsRateLimited(err error) bool { return ReasonFor(err) == ReasonRateLimited }
+
+// An ErrorObject describes a problem the StormForge API encountered while
+// processing a call.
+type ErrorObject struct {
+	// ID of this occurrence of the problem.
+	ID string `json:"id,omitempty"`
+
+	// Status is the HTTP status code applicable to the problem.
+	Status string `json:"status,omitempty"`
+
+	// Code is an application-specific error code.
+	Code string `json:"code,omitempty"`
+
+	// Title is a short summary of the problem.
+	Title string `json:"title,omitempty"`
+
+	// Detail explains this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+
+	// Source of the problem in the request, if any.
+	Source *ErrorSource `json:"source,omitempty"`
+}
+
+// An ErrorSource references the part of a request that caused a problem.
+type ErrorSource struct {
+	// Pointer is a JSON pointer to the attribute of the request document that
+	// caused the problem, e.g. "/data/attributes/name".
+	Pointer string `json:"pointer,omitempty"`
+
+	// Parameter is the query parameter that caused the problem.
+	Parameter string `json:"parameter,omitempty"`
+}
+
+func (o ErrorObject) String() string {
+	s := o.Title
+	switch {
+	case s == "":
+		s = o.Detail
+	case o.Detail != "" && o.Detail != o.Title:
+		s += ": " + o.Detail
+	}
+	switch {
+	case s == "":
+		s = o.Code
+	case o.Code != "":
+		s += " (code " + o.Code + ")"
+	}
+	if o.Source != nil && o.Source.Pointer != "" {
+		s += " (" + o.Source.Pointer + ")"
+	}
+	if o.Source != nil && o.Source.Parameter != "" {
+		s += " (parameter " + o.Source.Parameter + ")"
+	}
+	return s
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+)
+
+func TestReasonFor(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want Reason
+		is   func(error) bool
+	}{
+		"Invalid":       {err: &APIError{StatusCode: http.StatusUnprocessableEntity}, want: ReasonInvalid, is: IsInvalid},
+		"BadRequest":    {err: &APIError{StatusCode: http.StatusBadRequest}, want: ReasonInvalid, is: IsInvalid},
+		"NotFound":      {err: &APIError{StatusCode: http.StatusNotFound}, want: ReasonNotFound, is: IsNotFound},
+		"Unauthorized":  {err: &APIError{StatusCode: http.StatusUnauthorized}, want: ReasonUnauthorized, is: IsUnauthorized},
+		"Forbidden":     {err: &APIError{StatusCode: http.StatusForbidden}, want: ReasonForbidden, is: IsForbidden},
+		"Conflict":      {err: &APIError{StatusCode: http.StatusConflict}, want: ReasonConflict, is: IsConflict},
+		"QuotaExceeded": {err: &APIError{StatusCode: http.StatusPaymentRequired}, want: ReasonQuotaExceeded, is: IsQuotaExceeded},
+		"RateLimited":   {err: &APIError{StatusCode: http.StatusTooManyRequests}, want: ReasonRateLimited, is: IsRateLimited},
+		"Unavailable":   {err: &APIError{StatusCode: http.StatusBadGateway}, want: ReasonUnavailable},
+		"Wrapped":       {err: errors.Wrap(&APIError{StatusCode: http.StatusNotFound}, "cannot get"), want: ReasonNotFound, is: IsNotFound},
+		"Unknown":       {err: &APIError{StatusCode: http.StatusTeapot}, want: ReasonUnknown},
+		"NotAPIError":   {err: errors.New("connection refused"), want: ReasonUnknown},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := ReasonFor(tc.err); got != tc.want {
+				t.Errorf("ReasonFor(%v): want %s, got %s", tc.err, tc.want, got)
+			}
+			if tc.is != nil && !tc.is(tc.err) {
+				t.Errorf("Is%s(%v): want true, got false", tc.want, tc.err)
+			}
+		})
+	}
+}
+
+func TestNewAPIError(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		body   string
+		want   *APIError
+	}{
+		"ErrorObjects": {
+			reason: "JSON:API error objects should be parsed and summarized in the message.",
+			body: `{"errors":[
+				{"status":"422","title":"Invalid attribute","detail":"name has already been taken","source":{"pointer":"/data/attributes/name"}},
+				{"status":"422","code":"script_invalid","detail":"unexpected token"}
+			]}`,
+			want: &APIError{
+				StatusCode: http.StatusUnprocessableEntity,
+				Message:    "Invalid attribute: name has already been taken (/data/attributes/name); unexpected token (code script_invalid)",
+				Errors: []ErrorObject{
+					{Status: "422", Title: "Invalid attribute", Detail: "name has already been taken", Source: &ErrorSource{Pointer: "/data/attributes/name"}},
+					{Status: "422", Code: "script_invalid", Detail: "unexpected token"},
+				},
+			},
+		},
+		"PlainBody": {
+			reason: "A body that is not a JSON:API error document should be the message.",
+			body:   "<html>Bad Gateway</html>\n",
+			want:   &APIError{StatusCode: http.StatusUnprocessableEntity, Message: "<html>Bad Gateway</html>"},
+		},
+		"LongBody": {
+			reason: "Long bodies should be truncated.",
+			body:   strings.Repeat("x", 2*maxMessage),
+			want:   &APIError{StatusCode: http.StatusUnprocessableEntity, Message: strings.Repeat("x", maxMessage)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := newAPIError(http.StatusUnprocessableEntity, http.Header{}, []byte(tc.body))
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nnewAPIError(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
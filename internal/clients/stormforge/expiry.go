@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// DefaultTokenLeeway is how long after the expiry of its token an APIClient
+// still uses it by default, to tolerate clocks that run ahead of the API's.
+const DefaultTokenLeeway = time.Minute
+
+const errExpiredFmt = "credential has expired: its token expired at %s; replace the token of the ProviderConfig"
+
+// An ExpiredError is returned when the token of an APIClient has expired. The
+// call is not attempted.
+type ExpiredError struct {
+	// ExpiredAt is the time the token expired.
+	ExpiredAt time.Time
+}
+
+func (e *ExpiredError) Error() string {
+	return fmt.Sprintf(errExpiredFmt, e.ExpiredAt.UTC().Format(time.RFC3339))
+}
+
+// WithTokenLeeway configures how long after the expiry of its token an
+// APIClient still uses it. The same leeway applies when telling whether the
+// token expires soon, so both checks agree on when it expires.
+func WithTokenLeeway(d time.Duration) Option {
+	return func(c *APIClient) {
+		c.leeway = d
+	}
+}
+
+// expiry returns the time the supplied JWT expires, and false if it does not
+// expire, or cannot be decoded.
+func expiry(token string) (time.Time, bool) {
+	c, ok := claims(token)
+	if !ok || c.Exp == nil {
+		return time.Time{}, false
+	}
+	s, frac := math.Modf(*c.Exp)
+	return time.Unix(int64(s), int64(frac*float64(time.Second))), true
+}
+
+// expired returns an *ExpiredError if the client's token expired longer than
+// its leeway ago.
+func (c *APIClient) expired() error {
+	if !c.ExpiresWithin(0) {
+		return nil
+	}
+	exp, _ := expiry(c.token)
+	return &ExpiredError{ExpiredAt: exp}
+}
+
+// ExpiresWithin returns true if the client's token expires within the
+// supplied duration from now, allowing for the client's leeway. A token that
+// has already expired expires within any duration.
+func (c *APIClient) ExpiresWithin(d time.Duration) bool {
+	exp, ok := expiry(c.token)
+	return ok && !c.now().Add(d).Before(exp.Add(c.leeway))
+}
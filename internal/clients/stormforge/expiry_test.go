@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestExpiry(t *testing.T) {
+	exp := time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	token := signed(fmt.Sprintf(`{"sub":"jane","exp":%d}`, exp.Unix()))
+
+	type want struct {
+		err   error
+		calls int
+		soon  bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		token  string
+		now    time.Time
+		want   want
+	}{
+		"NoExpiry": {
+			reason: "A token without an exp claim should never expire.",
+			token:  signed(`{"sub":"jane"}`),
+			now:    exp.Add(time.Hour),
+			want:   want{calls: 1},
+		},
+		"BeforeExpiry": {
+			reason: "A token should be used before it expires.",
+			token:  token,
+			now:    exp.Add(-time.Hour),
+			want:   want{calls: 1},
+		},
+		"WithinLeeway": {
+			reason: "A token should still be used until its leeway after its expiry has passed, to tolerate a clock that runs ahead.",
+			token:  token,
+			now:    exp.Add(DefaultTokenLeeway - time.Second),
+			want:   want{calls: 1, soon: true},
+		},
+		"AtLeeway": {
+			reason: "A token should fail clearly without calling the API once its leeway after its expiry has passed.",
+			token:  token,
+			now:    exp.Add(DefaultTokenLeeway),
+			want:   want{err: &ExpiredError{ExpiredAt: exp}, soon: true},
+		},
+		"BeforeWindow": {
+			reason: "A token should not expire soon while its expiry and leeway are further away than the window.",
+			token:  token,
+			now:    exp.Add(DefaultTokenLeeway - time.Minute - time.Second),
+			want:   want{calls: 1},
+		},
+		"AtWindow": {
+			reason: "A token should expire soon once its expiry and leeway are within the window.",
+			token:  token,
+			now:    exp.Add(DefaultTokenLeeway - time.Minute),
+			want:   want{calls: 1, soon: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			t.Cleanup(srv.Close)
+			c := New(tc.token, WithEndpoint(srv.URL), WithHTTPClient(srv.Client()))
+			c.now = func() time.Time { return tc.now }
+
+			err := c.Ping(context.Background())
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Ping(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil && !IsUnauthorized(err) {
+				t.Errorf("\n%s\nIsUnauthorized(...): want true for %v", tc.reason, err)
+			}
+			if calls != tc.want.calls {
+				t.Errorf("\n%s\nAPI calls: want %d, got %d", tc.reason, tc.want.calls, calls)
+			}
+			if got := c.ExpiresWithin(time.Minute); got != tc.want.soon {
+				t.Errorf("\n%s\nc.ExpiresWithin(...): want %t, got %t", tc.reason, tc.want.soon, got)
+			}
+		})
+	}
+}
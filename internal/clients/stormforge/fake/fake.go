@@ -0,0 +1,953 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fake contains an in-memory StormForge client for tests.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+const errNotFound = "%s %q not found"
+
+// notFound returns the error the StormForge API returns for a missing
+// resource of the supplied kind.
+func notFound(kind, id string) error {
+	return &stormforge.APIError{StatusCode: http.StatusNotFound, Message: fmt.Sprintf(errNotFound, kind, id)}
+}
+
+var _ stormforge.Client = &Client{}
+
+// A Client is an in-memory stormforge.Client. Its zero value is ready to use.
+type Client struct {
+	mu     sync.Mutex
+	nextID int
+
+	// TestCases by ID.
+	TestCases map[string]stormforge.TestCase
+
+	// Scripts of test cases by test case ID.
+	Scripts map[string][]byte
+
+	// Options of test cases by test case ID.
+	Options map[string]stormforge.TestCaseOptions
+
+	// Revisions of test cases by test case ID.
+	Revisions map[string][]stormforge.Revision
+
+	// AlertThresholds of test cases by test case ID.
+	AlertThresholds map[string][]stormforge.AlertThreshold
+
+	// Runs by ID.
+	Runs map[string]stormforge.TestRun
+
+	// RunOptions the runs were launched with by run ID.
+	RunOptions map[string]stormforge.RunOptions
+
+	// Artifacts of runs by run ID and artifact.
+	Artifacts map[string]map[string][]byte
+
+	// Organizations by ID.
+	Organizations map[string]stormforge.Organization
+
+	// DataSources by ID.
+	DataSources map[string]stormforge.DataSource
+
+	// Content of data sources by data source ID.
+	DataSourceContent map[string][]byte
+
+	// NotificationChannels by ID.
+	NotificationChannels map[string]stormforge.NotificationChannel
+
+	// Projects by ID.
+	Projects map[string]stormforge.Project
+
+	// IPRanges of load generators.
+	IPRanges []stormforge.IPRange
+
+	// APITokens by ID. Their secret values are not stored.
+	APITokens map[string]stormforge.APIToken
+
+	// Experiments by name.
+	Experiments map[string]stormforge.Experiment
+
+	// Trials by experiment name.
+	Trials map[string][]stormforge.Trial
+
+	// Applications by name.
+	Applications map[string]stormforge.Application
+
+	// Recommendations by workload.
+	Recommendations map[stormforge.Workload]stormforge.Recommendation
+
+	// WorkloadConfigs by workload.
+	WorkloadConfigs map[stormforge.Workload]stormforge.WorkloadConfig
+
+	// Err is returned by every call, if set.
+	Err error
+}
+
+func (c *Client) id() string {
+	c.nextID++
+	return strconv.Itoa(c.nextID)
+}
+
+func (c *Client) init() {
+	if c.TestCases == nil {
+		c.TestCases = map[string]stormforge.TestCase{}
+	}
+	if c.Scripts == nil {
+		c.Scripts = map[string][]byte{}
+	}
+	if c.Options == nil {
+		c.Options = map[string]stormforge.TestCaseOptions{}
+	}
+	if c.AlertThresholds == nil {
+		c.AlertThresholds = map[string][]stormforge.AlertThreshold{}
+	}
+	if c.Runs == nil {
+		c.Runs = map[string]stormforge.TestRun{}
+	}
+	if c.RunOptions == nil {
+		c.RunOptions = map[string]stormforge.RunOptions{}
+	}
+	if c.Organizations == nil {
+		c.Organizations = map[string]stormforge.Organization{}
+	}
+	if c.DataSources == nil {
+		c.DataSources = map[string]stormforge.DataSource{}
+	}
+	if c.DataSourceContent == nil {
+		c.DataSourceContent = map[string][]byte{}
+	}
+	if c.NotificationChannels == nil {
+		c.NotificationChannels = map[string]stormforge.NotificationChannel{}
+	}
+	if c.Projects == nil {
+		c.Projects = map[string]stormforge.Project{}
+	}
+	if c.APITokens == nil {
+		c.APITokens = map[string]stormforge.APIToken{}
+	}
+	if c.Experiments == nil {
+		c.Experiments = map[string]stormforge.Experiment{}
+	}
+	if c.Applications == nil {
+		c.Applications = map[string]stormforge.Application{}
+	}
+	if c.WorkloadConfigs == nil {
+		c.WorkloadConfigs = map[stormforge.Workload]stormforge.WorkloadConfig{}
+	}
+}
+
+// ListTestCases returns the test cases of the supplied organization.
+func (c *Client) ListTestCases(_ context.Context, org string) ([]stormforge.TestCase, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	tcs := []stormforge.TestCase{}
+	for _, tc := range c.TestCases {
+		if tc.Scope == org {
+			tcs = append(tcs, tc)
+		}
+	}
+	return tcs, nil
+}
+
+// GetTestCase returns the test case with the supplied ID.
+func (c *Client) GetTestCase(_ context.Context, id string) (*stormforge.TestCase, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	tc, ok := c.TestCases[id]
+	if !ok {
+		return nil, notFound("test case", id)
+	}
+	return &tc, nil
+}
+
+// CreateTestCase stores a new test case.
+func (c *Client) CreateTestCase(_ context.Context, org, name string, script []byte, o ...stormforge.TestCaseOption) (*stormforge.TestCase, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	opts := stormforge.NewTestCaseOptions(o...)
+	tc := stormforge.TestCase{ID: c.id(), Name: name, Scope: org, Labels: opts.Labels}
+	if opts.Notes != nil {
+		tc.Notes = *opts.Notes
+	}
+	if opts.ProjectID != nil {
+		tc.ProjectID = *opts.ProjectID
+	}
+	if opts.DefaultDataSourceID != nil {
+		tc.DefaultDataSourceID = *opts.DefaultDataSourceID
+	}
+	if opts.DataSourceIDs != nil {
+		tc.DataSourceIDs = opts.DataSourceIDs
+	}
+	c.TestCases[tc.ID] = tc
+	c.Scripts[tc.ID] = script
+	c.Options[tc.ID] = opts
+	return &tc, nil
+}
+
+// UpdateTestCase updates a stored test case.
+func (c *Client) UpdateTestCase(_ context.Context, id, name string, script []byte, o ...stormforge.TestCaseOption) (*stormforge.TestCase, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	tc, ok := c.TestCases[id]
+	if !ok {
+		return nil, notFound("test case", id)
+	}
+	c.init()
+	opts := stormforge.NewTestCaseOptions(o...)
+	tc.Name = name
+	if opts.Labels != nil {
+		tc.Labels = opts.Labels
+	}
+	if opts.Notes != nil {
+		tc.Notes = *opts.Notes
+	}
+	if opts.ProjectID != nil {
+		tc.ProjectID = *opts.ProjectID
+	}
+	if opts.DefaultDataSourceID != nil {
+		tc.DefaultDataSourceID = *opts.DefaultDataSourceID
+	}
+	if opts.DataSourceIDs != nil {
+		tc.DataSourceIDs = opts.DataSourceIDs
+	}
+	c.TestCases[id] = tc
+	if script != nil {
+		c.Scripts[id] = script
+	}
+	c.Options[id] = opts
+	return &tc, nil
+}
+
+// TestCaseURL returns the URL of a test case in the default StormForge API.
+func (c *Client) TestCaseURL(id string) string {
+	return stormforge.DefaultEndpoint + "/test_cases/" + id
+}
+
+// GetDefinition returns the stored script of a test case.
+func (c *Client) GetDefinition(_ context.Context, id string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	if _, ok := c.TestCases[id]; !ok {
+		return nil, notFound("test case", id)
+	}
+	return c.Scripts[id], nil
+}
+
+// ArchiveTestCase marks a stored test case as archived.
+func (c *Client) ArchiveTestCase(_ context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	tc, ok := c.TestCases[id]
+	if !ok {
+		return notFound("test case", id)
+	}
+	tc.Archived = true
+	c.TestCases[id] = tc
+	return nil
+}
+
+// UnarchiveTestCase marks a stored test case as not archived.
+func (c *Client) UnarchiveTestCase(_ context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	tc, ok := c.TestCases[id]
+	if !ok {
+		return notFound("test case", id)
+	}
+	tc.Archived = false
+	c.TestCases[id] = tc
+	return nil
+}
+
+// DeleteTestCase removes a stored test case.
+func (c *Client) DeleteTestCase(_ context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	if _, ok := c.TestCases[id]; !ok {
+		return notFound("test case", id)
+	}
+	delete(c.TestCases, id)
+	delete(c.Scripts, id)
+	delete(c.Options, id)
+	return nil
+}
+
+// ListRevisions returns the stored revisions of the test case with the
+// supplied ID, newest first.
+func (c *Client) ListRevisions(_ context.Context, testCaseID string) ([]stormforge.Revision, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	revs := append([]stormforge.Revision{}, c.Revisions[testCaseID]...)
+	stormforge.SortRevisions(revs)
+	return revs, nil
+}
+
+// LaunchTestRun stores a new run of the test case with the supplied ID.
+func (c *Client) LaunchTestRun(_ context.Context, testCaseID string, o stormforge.RunOptions) (*stormforge.TestRun, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	if _, ok := c.TestCases[testCaseID]; !ok {
+		return nil, notFound("test case", testCaseID)
+	}
+	c.init()
+	r := stormforge.TestRun{ID: c.id(), TestCaseID: testCaseID, Title: o.Title, State: "launching"}
+	c.Runs[r.ID] = r
+	c.RunOptions[r.ID] = o
+	return &r, nil
+}
+
+// GetTestRun returns the stored run with the supplied ID.
+func (c *Client) GetTestRun(_ context.Context, id string) (*stormforge.TestRun, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	r, ok := c.Runs[id]
+	if !ok {
+		return nil, notFound("test run", id)
+	}
+	return &r, nil
+}
+
+// AbortTestRun marks the stored run with the supplied ID as aborted.
+func (c *Client) AbortTestRun(_ context.Context, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	r, ok := c.Runs[id]
+	if !ok {
+		return notFound("test run", id)
+	}
+	r.State = "aborted"
+	c.Runs[id] = r
+	return nil
+}
+
+// ListTestRuns returns the stored runs of the test case with the supplied ID.
+func (c *Client) ListTestRuns(_ context.Context, testCaseID string) ([]stormforge.TestRun, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	runs := []stormforge.TestRun{}
+	for _, r := range c.Runs {
+		if r.TestCaseID == testCaseID {
+			runs = append(runs, r)
+		}
+	}
+	return runs, nil
+}
+
+// GetTestRunArtifact writes the stored artifact of the run with the supplied
+// ID to the supplied writer.
+func (c *Client) GetTestRunArtifact(_ context.Context, id, artifact string, w io.Writer) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	b, ok := c.Artifacts[id][artifact]
+	if !ok {
+		return notFound("test run artifact", id+"/"+artifact)
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// ListOrganizations returns the stored organizations.
+func (c *Client) ListOrganizations(_ context.Context) ([]stormforge.Organization, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	orgs := []stormforge.Organization{}
+	for _, o := range c.Organizations {
+		orgs = append(orgs, o)
+	}
+	return orgs, nil
+}
+
+// GetOrganization returns the stored organization with the supplied ID.
+func (c *Client) GetOrganization(_ context.Context, id string) (*stormforge.Organization, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	o, ok := c.Organizations[id]
+	if !ok {
+		return nil, notFound("organization", id)
+	}
+	return &o, nil
+}
+
+// ListDataSources returns the stored data sources of the supplied
+// organization.
+func (c *Client) ListDataSources(_ context.Context, org string) ([]stormforge.DataSource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	dss := []stormforge.DataSource{}
+	for _, ds := range c.DataSources {
+		if ds.Scope == org {
+			dss = append(dss, ds)
+		}
+	}
+	return dss, nil
+}
+
+// GetDataSource returns the stored data source with the supplied ID of the
+// supplied organization.
+func (c *Client) GetDataSource(_ context.Context, org, id string) (*stormforge.DataSource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	ds, ok := c.DataSources[id]
+	if !ok || ds.Scope != org {
+		return nil, notFound("data source", id)
+	}
+	return &ds, nil
+}
+
+// CreateDataSource stores a new data source.
+func (c *Client) CreateDataSource(_ context.Context, org, name string, content []byte) (*stormforge.DataSource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	ds := stormforge.DataSource{ID: c.id(), Name: name, Scope: org}
+	c.DataSources[ds.ID] = ds
+	c.DataSourceContent[ds.ID] = content
+	return &ds, nil
+}
+
+// UpdateDataSource updates a stored data source.
+func (c *Client) UpdateDataSource(_ context.Context, org, id, name string, content []byte) (*stormforge.DataSource, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	ds, ok := c.DataSources[id]
+	if !ok || ds.Scope != org {
+		return nil, notFound("data source", id)
+	}
+	c.init()
+	ds.Name = name
+	c.DataSources[id] = ds
+	c.DataSourceContent[id] = content
+	return &ds, nil
+}
+
+// DeleteDataSource deletes a stored data source.
+func (c *Client) DeleteDataSource(_ context.Context, org, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	ds, ok := c.DataSources[id]
+	if !ok || ds.Scope != org {
+		return notFound("data source", id)
+	}
+	delete(c.DataSources, id)
+	delete(c.DataSourceContent, id)
+	return nil
+}
+
+// ListNotificationChannels returns the stored notification channels of the
+// supplied organization.
+func (c *Client) ListNotificationChannels(_ context.Context, org string) ([]stormforge.NotificationChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	ncs := []stormforge.NotificationChannel{}
+	for _, nc := range c.NotificationChannels {
+		if nc.Scope == org {
+			ncs = append(ncs, nc)
+		}
+	}
+	return ncs, nil
+}
+
+// GetNotificationChannel returns the stored notification channel with the
+// supplied ID of the supplied organization.
+func (c *Client) GetNotificationChannel(_ context.Context, org, id string) (*stormforge.NotificationChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	nc, ok := c.NotificationChannels[id]
+	if !ok || nc.Scope != org {
+		return nil, notFound("notification channel", id)
+	}
+	return &nc, nil
+}
+
+// CreateNotificationChannel stores a new notification channel.
+func (c *Client) CreateNotificationChannel(_ context.Context, org string, nc stormforge.NotificationChannel) (*stormforge.NotificationChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	nc.ID, nc.Scope = c.id(), org
+	c.NotificationChannels[nc.ID] = nc
+	return &nc, nil
+}
+
+// UpdateNotificationChannel replaces a stored notification channel.
+func (c *Client) UpdateNotificationChannel(_ context.Context, org, id string, nc stormforge.NotificationChannel) (*stormforge.NotificationChannel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	if existing, ok := c.NotificationChannels[id]; !ok || existing.Scope != org {
+		return nil, notFound("notification channel", id)
+	}
+	nc.ID, nc.Scope = id, org
+	c.NotificationChannels[id] = nc
+	return &nc, nil
+}
+
+// DeleteNotificationChannel deletes a stored notification channel.
+func (c *Client) DeleteNotificationChannel(_ context.Context, org, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	nc, ok := c.NotificationChannels[id]
+	if !ok || nc.Scope != org {
+		return notFound("notification channel", id)
+	}
+	delete(c.NotificationChannels, id)
+	return nil
+}
+
+// ListAlertThresholds returns the stored alert thresholds of the test case
+// with the supplied ID.
+func (c *Client) ListAlertThresholds(_ context.Context, testCaseID string) ([]stormforge.AlertThreshold, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return append([]stormforge.AlertThreshold{}, c.AlertThresholds[testCaseID]...), nil
+}
+
+// CreateAlertThreshold stores a new alert threshold of a test case.
+func (c *Client) CreateAlertThreshold(_ context.Context, testCaseID string, t stormforge.AlertThreshold) (*stormforge.AlertThreshold, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	t.ID = c.id()
+	c.AlertThresholds[testCaseID] = append(c.AlertThresholds[testCaseID], t)
+	return &t, nil
+}
+
+// UpdateAlertThreshold replaces a stored alert threshold of a test case.
+func (c *Client) UpdateAlertThreshold(_ context.Context, testCaseID, id string, t stormforge.AlertThreshold) (*stormforge.AlertThreshold, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	for i, existing := range c.AlertThresholds[testCaseID] {
+		if existing.ID == id {
+			t.ID = id
+			c.AlertThresholds[testCaseID][i] = t
+			return &t, nil
+		}
+	}
+	return nil, notFound("alert threshold", id)
+}
+
+// DeleteAlertThreshold deletes a stored alert threshold of a test case.
+func (c *Client) DeleteAlertThreshold(_ context.Context, testCaseID, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	ts := c.AlertThresholds[testCaseID]
+	for i, existing := range ts {
+		if existing.ID == id {
+			c.AlertThresholds[testCaseID] = append(ts[:i:i], ts[i+1:]...)
+			return nil
+		}
+	}
+	return notFound("alert threshold", id)
+}
+
+// ListIPRanges returns the stored IP ranges.
+func (c *Client) ListIPRanges(_ context.Context) ([]stormforge.IPRange, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	return append([]stormforge.IPRange{}, c.IPRanges...), nil
+}
+
+// ListProjects returns the stored projects of the supplied organization.
+func (c *Client) ListProjects(_ context.Context, org string) ([]stormforge.Project, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	ps := []stormforge.Project{}
+	for _, p := range c.Projects {
+		if p.Scope == org {
+			ps = append(ps, p)
+		}
+	}
+	return ps, nil
+}
+
+// GetProject returns the stored project with the supplied ID of the supplied
+// organization.
+func (c *Client) GetProject(_ context.Context, org, id string) (*stormforge.Project, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	p, ok := c.Projects[id]
+	if !ok || p.Scope != org {
+		return nil, notFound("project", id)
+	}
+	return &p, nil
+}
+
+// CreateProject stores a new project.
+func (c *Client) CreateProject(_ context.Context, org string, p stormforge.Project) (*stormforge.Project, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	p.ID, p.Scope = c.id(), org
+	c.Projects[p.ID] = p
+	return &p, nil
+}
+
+// UpdateProject replaces a stored project.
+func (c *Client) UpdateProject(_ context.Context, org, id string, p stormforge.Project) (*stormforge.Project, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	if existing, ok := c.Projects[id]; !ok || existing.Scope != org {
+		return nil, notFound("project", id)
+	}
+	p.ID, p.Scope = id, org
+	c.Projects[id] = p
+	return &p, nil
+}
+
+// DeleteProject deletes a stored project.
+func (c *Client) DeleteProject(_ context.Context, org, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	p, ok := c.Projects[id]
+	if !ok || p.Scope != org {
+		return notFound("project", id)
+	}
+	delete(c.Projects, id)
+	return nil
+}
+
+// GetAPIToken returns the stored API token with the supplied ID of the
+// supplied organization.
+func (c *Client) GetAPIToken(_ context.Context, org, id string) (*stormforge.APIToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	t, ok := c.APITokens[id]
+	if !ok || t.Scope != org {
+		return nil, notFound("API token", id)
+	}
+	return &t, nil
+}
+
+// CreateAPIToken stores a new API token, returning it along with a secret
+// value derived from its ID.
+func (c *Client) CreateAPIToken(_ context.Context, org string, t stormforge.APIToken) (*stormforge.APIToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	t.ID, t.Scope, t.Token = c.id(), org, ""
+	c.APITokens[t.ID] = t
+	t.Token = "token-" + t.ID
+	return &t, nil
+}
+
+// UpdateAPIToken renames and rescopes a stored API token.
+func (c *Client) UpdateAPIToken(_ context.Context, org, id string, t stormforge.APIToken) (*stormforge.APIToken, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	existing, ok := c.APITokens[id]
+	if !ok || existing.Scope != org {
+		return nil, notFound("API token", id)
+	}
+	existing.Name, existing.Scopes = t.Name, t.Scopes
+	c.APITokens[id] = existing
+	return &existing, nil
+}
+
+// DeleteAPIToken deletes a stored API token.
+func (c *Client) DeleteAPIToken(_ context.Context, org, id string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	t, ok := c.APITokens[id]
+	if !ok || t.Scope != org {
+		return notFound("API token", id)
+	}
+	delete(c.APITokens, id)
+	return nil
+}
+
+// GetExperiment returns the stored experiment with the supplied name.
+func (c *Client) GetExperiment(_ context.Context, name string) (*stormforge.Experiment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	e, ok := c.Experiments[name]
+	if !ok {
+		return nil, notFound("experiment", name)
+	}
+	return &e, nil
+}
+
+// PutExperiment stores the supplied experiment, keeping the observations of
+// any experiment it replaces.
+func (c *Client) PutExperiment(_ context.Context, e stormforge.Experiment) (*stormforge.Experiment, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	e.Observations = c.Experiments[e.Name].Observations
+	c.Experiments[e.Name] = e
+	return &e, nil
+}
+
+// DeleteExperiment deletes a stored experiment.
+func (c *Client) DeleteExperiment(_ context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	if _, ok := c.Experiments[name]; !ok {
+		return notFound("experiment", name)
+	}
+	delete(c.Experiments, name)
+	return nil
+}
+
+// GetTrial returns the stored trial of the supplied experiment with the
+// supplied number.
+func (c *Client) GetTrial(_ context.Context, experiment string, number int64) (*stormforge.Trial, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	for _, t := range c.Trials[experiment] {
+		if t.Number == number {
+			return &t, nil
+		}
+	}
+	return nil, notFound("trial", experiment+"/"+strconv.FormatInt(number, 10))
+}
+
+// GetApplication returns the stored application with the supplied name.
+func (c *Client) GetApplication(_ context.Context, name string) (*stormforge.Application, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	a, ok := c.Applications[name]
+	if !ok {
+		return nil, notFound("application", name)
+	}
+	return &a, nil
+}
+
+// PutApplication stores the supplied application.
+func (c *Client) PutApplication(_ context.Context, a stormforge.Application) (*stormforge.Application, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	c.Applications[a.Name] = a
+	return &a, nil
+}
+
+// DeleteApplication deletes a stored application.
+func (c *Client) DeleteApplication(_ context.Context, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	if _, ok := c.Applications[name]; !ok {
+		return notFound("application", name)
+	}
+	delete(c.Applications, name)
+	return nil
+}
+
+// GetRecommendation returns the stored recommendation for the supplied
+// workload.
+func (c *Client) GetRecommendation(_ context.Context, w stormforge.Workload) (*stormforge.Recommendation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	r, ok := c.Recommendations[w]
+	if !ok {
+		return nil, notFound("recommendation", w.Namespace+"/"+w.Name)
+	}
+	return &r, nil
+}
+
+// GetWorkloadConfig returns the stored configuration of the supplied workload.
+func (c *Client) GetWorkloadConfig(_ context.Context, w stormforge.Workload) (*stormforge.WorkloadConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	cfg, ok := c.WorkloadConfigs[w]
+	if !ok {
+		return nil, notFound("workload", w.Namespace+"/"+w.Name)
+	}
+	return &cfg, nil
+}
+
+// PutWorkloadConfig stores the supplied workload configuration.
+func (c *Client) PutWorkloadConfig(_ context.Context, cfg stormforge.WorkloadConfig) (*stormforge.WorkloadConfig, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return nil, c.Err
+	}
+	c.init()
+	c.WorkloadConfigs[cfg.Workload] = cfg
+	return &cfg, nil
+}
+
+// DeleteWorkloadConfig deletes a stored workload configuration.
+func (c *Client) DeleteWorkloadConfig(_ context.Context, w stormforge.Workload) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.Err != nil {
+		return c.Err
+	}
+	if _, ok := c.WorkloadConfigs[w]; !ok {
+		return notFound("workload", w.Namespace+"/"+w.Name)
+	}
+	delete(c.WorkloadConfigs, w)
+	return nil
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+)
+
+// An IPRange is a range of IP addresses that StormForge load generators send
+// requests from.
+type IPRange struct {
+	// Region the load generators run in.
+	Region string
+
+	// CIDR of the range, for example 203.0.113.0/24.
+	CIDR string
+}
+
+type ipRangeAttributes struct {
+	Region string `json:"region"`
+	CIDR   string `json:"cidr"`
+}
+
+// ListIPRanges returns the published IP ranges of the load generators of
+// every region.
+func (c *APIClient) ListIPRanges(ctx context.Context) ([]IPRange, error) {
+	ranges := []IPRange{}
+	err := c.collection(ctx, "/ip_ranges", func(o resourceObject, _ included) error {
+		a := ipRangeAttributes{}
+		if err := o.decode(&a); err != nil {
+			return err
+		}
+		ranges = append(ranges, IPRange{Region: a.Region, CIDR: a.CIDR})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
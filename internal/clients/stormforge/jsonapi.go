@@ -0,0 +1,163 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// The StormForge API speaks JSON:API. Every resource type shares the document
+// and resource object structure below; only the attributes differ. Each
+// resource type therefore only declares its attributes and how they map to its
+// model, while reading, listing and decoding is shared.
+//
+// https://jsonapi.org/format/
+
+// A resourceObject is a JSON:API resource object. Its attributes are decoded
+// into the attributes struct of its resource type by decode.
+type resourceObject struct {
+	ID            string                  `json:"id"`
+	Type          string                  `json:"type"`
+	Attributes    json.RawMessage         `json:"attributes,omitempty"`
+	Relationships map[string]relationship `json:"relationships,omitempty"`
+	Meta          meta                    `json:"meta,omitempty"`
+}
+
+// A relationship of a JSON:API resource object. Only to-one relationships are
+// supported.
+type relationship struct {
+	Data *resourceIdentifier `json:"data"`
+}
+
+// A resourceIdentifier identifies a JSON:API resource object.
+type resourceIdentifier struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+// meta is non-standard meta-information of a JSON:API document or resource
+// object, such as the total number of resources in a collection.
+type meta map[string]json.RawMessage
+
+// A document is a JSON:API document whose primary data is a single resource.
+type document struct {
+	Data     resourceObject `json:"data"`
+	Included included       `json:"included,omitempty"`
+	Meta     meta           `json:"meta,omitempty"`
+	Links    links          `json:"links"`
+}
+
+// A collectionDocument is a JSON:API document whose primary data is a page of
+// a collection of resources.
+type collectionDocument struct {
+	Data     []resourceObject `json:"data"`
+	Included included         `json:"included,omitempty"`
+	Meta     meta             `json:"meta,omitempty"`
+	Links    links            `json:"links"`
+}
+
+// An errorDocument is a JSON:API document describing why a call failed.
+type errorDocument struct {
+	Errors []ErrorObject `json:"errors"`
+	Meta   meta          `json:"meta,omitempty"`
+}
+
+// Included resource objects of a compound JSON:API document.
+type included []resourceObject
+
+// find returns the included resource object the supplied identifier refers to.
+func (in included) find(id resourceIdentifier) (resourceObject, bool) {
+	for _, o := range in {
+		if o.ID == id.ID && o.Type == id.Type {
+			return o, true
+		}
+	}
+	return resourceObject{}, false
+}
+
+// int returns the supplied meta-information as an integer, if it is one.
+func (m meta) int(key string) (int, bool) {
+	raw, ok := m[key]
+	if !ok {
+		return 0, false
+	}
+	var i int
+	if err := json.Unmarshal(raw, &i); err != nil {
+		return 0, false
+	}
+	return i, true
+}
+
+// decode the attributes of the resource object into attrs.
+func (o resourceObject) decode(attrs interface{}) error {
+	if len(o.Attributes) == 0 {
+		return nil
+	}
+	return errors.Wrap(json.Unmarshal(o.Attributes, attrs), errDecodeResponse)
+}
+
+// related returns the ID of the resource the supplied relationship refers to,
+// if any.
+func (o resourceObject) related(name string) string {
+	r, ok := o.Relationships[name]
+	if !ok || r.Data == nil {
+		return ""
+	}
+	return r.Data.ID
+}
+
+// include returns the resource object the supplied relationship refers to, if
+// it is included in the document.
+func (o resourceObject) include(name string, in included) (resourceObject, bool) {
+	r, ok := o.Relationships[name]
+	if !ok || r.Data == nil {
+		return resourceObject{}, false
+	}
+	return in.find(*r.Data)
+}
+
+// resource sends a request to the supplied path and returns the response
+// document.
+func (c *APIClient) resource(ctx context.Context, method, path string, body []byte, contentType string) (*document, error) {
+	d := &document{}
+	if err := c.do(ctx, method, path, body, contentType, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// collection calls fn with each resource object of the collection at the
+// supplied path, and the resources included alongside it, following pagination
+// links until all pages have been read.
+func (c *APIClient) collection(ctx context.Context, path string, fn func(o resourceObject, in included) error) error {
+	return c.paginate(path, func(path string) (links, error) {
+		d := &collectionDocument{}
+		if err := c.do(ctx, http.MethodGet, path, nil, "", d); err != nil {
+			return links{}, err
+		}
+		for i := range d.Data {
+			if err := fn(d.Data[i], d.Included); err != nil {
+				return links{}, err
+			}
+		}
+		return d.Links, nil
+	})
+}
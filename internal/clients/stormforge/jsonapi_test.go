@@ -0,0 +1,63 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCompoundDocument(t *testing.T) {
+	body := `{
+		"data":[{"id":"r1","type":"test_runs","attributes":{"state":"done"},
+			"relationships":{"test_case":{"data":{"id":"a1","type":"test_cases"}}}}],
+		"included":[{"id":"a1","type":"test_cases","attributes":{"name":"checkout","scope":"acme"}}],
+		"meta":{"total":42},
+		"links":{"self":"/test_runs?page[number]=1","next":"/test_runs?page[number]=2","last":"/test_runs?page[number]=5"}
+	}`
+
+	d := &collectionDocument{}
+	if err := json.Unmarshal([]byte(body), d); err != nil {
+		t.Fatalf("json.Unmarshal(...): unexpected error: %s", err)
+	}
+
+	if total, ok := d.Meta.int("total"); !ok || total != 42 {
+		t.Errorf("d.Meta.int(total): want 42, got %d (%t)", total, ok)
+	}
+	wantLinks := links{Self: "/test_runs?page[number]=1", Next: "/test_runs?page[number]=2", Last: "/test_runs?page[number]=5"}
+	if diff := cmp.Diff(wantLinks, d.Links); diff != "" {
+		t.Errorf("d.Links: -want, +got:\n%s\n", diff)
+	}
+
+	o, ok := d.Data[0].include("test_case", d.Included)
+	if !ok {
+		t.Fatalf("o.include(test_case): want included test case, got none")
+	}
+	got, err := testCaseFrom(o)
+	if err != nil {
+		t.Fatalf("testCaseFrom(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(&TestCase{ID: "a1", Name: "checkout", Scope: "acme"}, got); diff != "" {
+		t.Errorf("testCaseFrom(...): -want, +got:\n%s\n", diff)
+	}
+
+	if _, ok := d.Data[0].include("organisation", d.Included); ok {
+		t.Errorf("o.include(organisation): want none, got an included resource")
+	}
+}
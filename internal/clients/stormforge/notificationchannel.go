@@ -0,0 +1,160 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Types of notification channel.
+const (
+	NotificationChannelEmail   = "email"
+	NotificationChannelSlack   = "slack"
+	NotificationChannelWebhook = "webhook"
+)
+
+// A NotificationChannel of an organization is notified of the lifecycle
+// events of its test runs, for example when a run has finished.
+type NotificationChannel struct {
+	ID     string
+	Name   string
+	Scope  string
+	Type   string
+	Events []string
+
+	// Recipients are the email addresses notified by an email channel.
+	Recipients []string
+
+	// URL is the webhook URL notified by a Slack or webhook channel.
+	URL string
+
+	// SlackChannel overrides the Slack channel of a Slack webhook URL.
+	SlackChannel string
+}
+
+type notificationChannelAttributes struct {
+	Name         string   `json:"name"`
+	Scope        string   `json:"scope"`
+	Type         string   `json:"channel_type"`
+	Events       []string `json:"events"`
+	Recipients   []string `json:"recipients"`
+	URL          string   `json:"url"`
+	SlackChannel string   `json:"slack_channel"`
+}
+
+func notificationChannelFrom(o resourceObject) (*NotificationChannel, error) {
+	a := notificationChannelAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	return &NotificationChannel{
+		ID:           o.ID,
+		Name:         a.Name,
+		Scope:        a.Scope,
+		Type:         a.Type,
+		Events:       a.Events,
+		Recipients:   a.Recipients,
+		URL:          a.URL,
+		SlackChannel: a.SlackChannel,
+	}, nil
+}
+
+// notificationChannelForm returns the multipart form of a created or updated
+// notification channel.
+func notificationChannelForm(nc NotificationChannel) ([]byte, string, error) {
+	fields := url.Values{
+		"notification_channel[name]":         {nc.Name},
+		"notification_channel[channel_type]": {nc.Type},
+		"notification_channel[events][]":     nc.Events,
+	}
+	switch nc.Type {
+	case NotificationChannelEmail:
+		fields["notification_channel[recipients][]"] = nc.Recipients
+	case NotificationChannelSlack:
+		fields.Set("notification_channel[url]", nc.URL)
+		if nc.SlackChannel != "" {
+			fields.Set("notification_channel[slack_channel]", nc.SlackChannel)
+		}
+	case NotificationChannelWebhook:
+		fields.Set("notification_channel[url]", nc.URL)
+	}
+	return multipartForm(fields)
+}
+
+// ListNotificationChannels returns the notification channels of the supplied
+// organization.
+func (c *APIClient) ListNotificationChannels(ctx context.Context, org string) ([]NotificationChannel, error) {
+	ncs := []NotificationChannel{}
+	err := c.collection(WithOrg(ctx, org), "/organisations/"+url.PathEscape(org)+"/notification_channels", func(o resourceObject, _ included) error {
+		nc, err := notificationChannelFrom(o)
+		if err != nil {
+			return err
+		}
+		ncs = append(ncs, *nc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ncs, nil
+}
+
+// GetNotificationChannel returns the notification channel with the supplied
+// ID of the supplied organization.
+func (c *APIClient) GetNotificationChannel(ctx context.Context, org, id string) (*NotificationChannel, error) {
+	d, err := c.resource(WithOrg(ctx, org), http.MethodGet, "/organisations/"+url.PathEscape(org)+"/notification_channels/"+url.PathEscape(id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return notificationChannelFrom(d.Data)
+}
+
+// CreateNotificationChannel creates the supplied notification channel in the
+// supplied organization. The ID and scope of the supplied channel are ignored.
+func (c *APIClient) CreateNotificationChannel(ctx context.Context, org string, nc NotificationChannel) (*NotificationChannel, error) {
+	body, ct, err := notificationChannelForm(nc)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPost, "/organisations/"+url.PathEscape(org)+"/notification_channels", body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return notificationChannelFrom(d.Data)
+}
+
+// UpdateNotificationChannel replaces the configuration of the notification
+// channel with the supplied ID of the supplied organization.
+func (c *APIClient) UpdateNotificationChannel(ctx context.Context, org, id string, nc NotificationChannel) (*NotificationChannel, error) {
+	body, ct, err := notificationChannelForm(nc)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPatch, "/organisations/"+url.PathEscape(org)+"/notification_channels/"+url.PathEscape(id), body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return notificationChannelFrom(d.Data)
+}
+
+// DeleteNotificationChannel deletes the notification channel with the supplied
+// ID of the supplied organization.
+func (c *APIClient) DeleteNotificationChannel(ctx context.Context, org, id string) error {
+	return c.do(WithOrg(ctx, org), http.MethodDelete, "/organisations/"+url.PathEscape(org)+"/notification_channels/"+url.PathEscape(id), nil, "", nil)
+}
@@ -0,0 +1,522 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// The StormForge Optimize API is a separate, plain JSON API. Its resources are
+// identified by name rather than by ID, and are created or replaced by PUT.
+
+const errEncodeBody = "cannot encode request body"
+
+// An OptimizeClient manages StormForge Optimize resources.
+type OptimizeClient interface {
+	GetExperiment(ctx context.Context, name string) (*Experiment, error)
+	PutExperiment(ctx context.Context, e Experiment) (*Experiment, error)
+	DeleteExperiment(ctx context.Context, name string) error
+
+	GetTrial(ctx context.Context, experiment string, number int64) (*Trial, error)
+
+	GetApplication(ctx context.Context, name string) (*Application, error)
+	PutApplication(ctx context.Context, a Application) (*Application, error)
+	DeleteApplication(ctx context.Context, name string) error
+
+	GetRecommendation(ctx context.Context, w Workload) (*Recommendation, error)
+
+	GetWorkloadConfig(ctx context.Context, w Workload) (*WorkloadConfig, error)
+	PutWorkloadConfig(ctx context.Context, cfg WorkloadConfig) (*WorkloadConfig, error)
+	DeleteWorkloadConfig(ctx context.Context, w Workload) error
+}
+
+// doOptimize sends a request to the supplied path of the StormForge Optimize
+// API with in encoded as its body, if it is not nil, and decodes the response
+// into out, if it is not nil. Requests that fail transiently are retried; see
+// retry.
+func (c *APIClient) doOptimize(ctx context.Context, method, path string, in, out interface{}) error {
+	var body []byte
+	contentType := ""
+	if in != nil {
+		b := &bytes.Buffer{}
+		if err := json.NewEncoder(b).Encode(in); err != nil {
+			return errors.Wrap(err, errEncodeBody)
+		}
+		body, contentType = b.Bytes(), mediaTypeJSON
+	}
+	return c.retry(ctx, method, func() error {
+		return c.attempt(ctx, method, c.optimizeEndpoint+path, mediaTypeJSON, body, contentType, out)
+	})
+}
+
+// Types of experiment parameter.
+const (
+	ParameterInt         = "int"
+	ParameterDouble      = "double"
+	ParameterCategorical = "categorical"
+)
+
+// An Experiment is a StormForge Optimize experiment. It searches for the
+// values of its parameters that optimize its metrics, by running trials.
+type Experiment struct {
+	Name       string
+	Parameters []Parameter
+	Metrics    []Metric
+
+	// Budget is the number of trials the experiment may run.
+	Budget int64
+
+	// Observations is the number of trials that have completed.
+	Observations int64
+}
+
+// A Parameter of an experiment. Numeric parameters are bounded by Min and Max;
+// categorical parameters take one of their Values.
+type Parameter struct {
+	Name   string
+	Type   string
+	Min    string
+	Max    string
+	Values []string
+}
+
+// A Metric of an experiment is measured by each trial.
+type Metric struct {
+	Name     string
+	Minimize bool
+
+	// Optimize is false for metrics that are only measured, not optimized.
+	Optimize bool
+}
+
+// budgetOption is the optimization option that sets the trial budget of an
+// experiment.
+const budgetOption = "experimentBudget"
+
+type experimentBody struct {
+	Observations int64                `json:"observations,omitempty"`
+	Optimization []optimizationOption `json:"optimization,omitempty"`
+	Parameters   []parameterBody      `json:"parameters"`
+	Metrics      []metricBody         `json:"metrics"`
+}
+
+type optimizationOption struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type parameterBody struct {
+	Name   string      `json:"name"`
+	Type   string      `json:"type"`
+	Bounds *boundsBody `json:"bounds,omitempty"`
+	Values []string    `json:"values,omitempty"`
+}
+
+type boundsBody struct {
+	Min json.Number `json:"min"`
+	Max json.Number `json:"max"`
+}
+
+type metricBody struct {
+	Name     string `json:"name"`
+	Minimize bool   `json:"minimize"`
+	Optimize *bool  `json:"optimize,omitempty"`
+}
+
+func experimentFrom(name string, b *experimentBody) *Experiment {
+	e := &Experiment{Name: name, Observations: b.Observations}
+	for _, o := range b.Optimization {
+		if o.Name == budgetOption {
+			e.Budget, _ = strconv.ParseInt(o.Value, 10, 64)
+		}
+	}
+	for _, p := range b.Parameters {
+		ep := Parameter{Name: p.Name, Type: p.Type, Values: p.Values}
+		if p.Bounds != nil {
+			ep.Min, ep.Max = p.Bounds.Min.String(), p.Bounds.Max.String()
+		}
+		e.Parameters = append(e.Parameters, ep)
+	}
+	for _, m := range b.Metrics {
+		// Metrics are optimized unless the API says otherwise.
+		e.Metrics = append(e.Metrics, Metric{Name: m.Name, Minimize: m.Minimize, Optimize: m.Optimize == nil || *m.Optimize})
+	}
+	return e
+}
+
+func experimentBodyFrom(e Experiment) *experimentBody {
+	b := &experimentBody{
+		Parameters: make([]parameterBody, 0, len(e.Parameters)),
+		Metrics:    make([]metricBody, 0, len(e.Metrics)),
+	}
+	if e.Budget > 0 {
+		b.Optimization = []optimizationOption{{Name: budgetOption, Value: strconv.FormatInt(e.Budget, 10)}}
+	}
+	for _, p := range e.Parameters {
+		pb := parameterBody{Name: p.Name, Type: p.Type, Values: p.Values}
+		if p.Type != ParameterCategorical {
+			pb.Bounds = &boundsBody{Min: json.Number(p.Min), Max: json.Number(p.Max)}
+		}
+		b.Parameters = append(b.Parameters, pb)
+	}
+	for _, m := range e.Metrics {
+		optimize := m.Optimize
+		b.Metrics = append(b.Metrics, metricBody{Name: m.Name, Minimize: m.Minimize, Optimize: &optimize})
+	}
+	return b
+}
+
+// GetExperiment returns the experiment with the supplied name.
+func (c *APIClient) GetExperiment(ctx context.Context, name string) (*Experiment, error) {
+	b := &experimentBody{}
+	if err := c.doOptimize(ctx, http.MethodGet, "/v1/experiments/"+url.PathEscape(name), nil, b); err != nil {
+		return nil, err
+	}
+	return experimentFrom(name, b), nil
+}
+
+// PutExperiment creates the supplied experiment, or replaces the experiment of
+// the same name.
+func (c *APIClient) PutExperiment(ctx context.Context, e Experiment) (*Experiment, error) {
+	b := &experimentBody{}
+	if err := c.doOptimize(ctx, http.MethodPut, "/v1/experiments/"+url.PathEscape(e.Name), experimentBodyFrom(e), b); err != nil {
+		return nil, err
+	}
+	return experimentFrom(e.Name, b), nil
+}
+
+// DeleteExperiment deletes the experiment with the supplied name, including
+// its trials.
+func (c *APIClient) DeleteExperiment(ctx context.Context, name string) error {
+	return c.doOptimize(ctx, http.MethodDelete, "/v1/experiments/"+url.PathEscape(name), nil, nil)
+}
+
+// Statuses of a trial.
+const (
+	TrialActive    = "active"
+	TrialCompleted = "completed"
+	TrialFailed    = "failed"
+	TrialAbandoned = "abandoned"
+)
+
+// A Trial of an experiment assigns a value to each of its parameters, then
+// observes the resulting value of each of its metrics.
+type Trial struct {
+	Experiment  string
+	Number      int64
+	Status      string
+	Assignments []Assignment
+	Values      []Value
+
+	StartTime      *time.Time
+	CompletionTime *time.Time
+}
+
+// An Assignment of a value to a parameter.
+type Assignment struct {
+	Parameter string
+	Value     string
+}
+
+// A Value of a metric observed by a trial.
+type Value struct {
+	Metric string
+	Value  string
+
+	// Error is the uncertainty of the value, if known.
+	Error string
+}
+
+type trialBody struct {
+	Number         int64            `json:"number"`
+	Status         string           `json:"status"`
+	Assignments    []assignmentBody `json:"assignments"`
+	Values         []valueBody      `json:"values"`
+	StartTime      *time.Time       `json:"startTime,omitempty"`
+	CompletionTime *time.Time       `json:"completionTime,omitempty"`
+}
+
+type assignmentBody struct {
+	ParameterName string `json:"parameterName"`
+
+	// Value is a number, or a string for a categorical parameter.
+	Value json.RawMessage `json:"value"`
+}
+
+type valueBody struct {
+	MetricName string      `json:"metricName"`
+	Value      json.Number `json:"value"`
+	Error      json.Number `json:"error,omitempty"`
+}
+
+func trialFrom(experiment string, b *trialBody) *Trial {
+	t := &Trial{
+		Experiment:     experiment,
+		Number:         b.Number,
+		Status:         b.Status,
+		StartTime:      b.StartTime,
+		CompletionTime: b.CompletionTime,
+	}
+	for _, a := range b.Assignments {
+		t.Assignments = append(t.Assignments, Assignment{Parameter: a.ParameterName, Value: scalar(a.Value)})
+	}
+	for _, v := range b.Values {
+		t.Values = append(t.Values, Value{Metric: v.MetricName, Value: v.Value.String(), Error: v.Error.String()})
+	}
+	return t
+}
+
+// scalar returns the supplied JSON number or string as a string.
+func scalar(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// GetTrial returns the trial of the supplied experiment with the supplied
+// number.
+func (c *APIClient) GetTrial(ctx context.Context, experiment string, number int64) (*Trial, error) {
+	b := &trialBody{}
+	path := "/v1/experiments/" + url.PathEscape(experiment) + "/trials/" + strconv.FormatInt(number, 10)
+	if err := c.doOptimize(ctx, http.MethodGet, path, nil, b); err != nil {
+		return nil, err
+	}
+	return trialFrom(experiment, b), nil
+}
+
+// An Application is a StormForge Optimize application: the Kubernetes
+// resources that are optimized, and the scenarios under which they are
+// optimized.
+type Application struct {
+	Name      string
+	Resources []ApplicationResource
+	Scenarios []Scenario
+}
+
+// An ApplicationResource selects workloads in a Kubernetes namespace.
+type ApplicationResource struct {
+	Namespace string
+
+	// Selector is a label selector for the workloads. All workloads in the
+	// namespace are selected if both Selector and Workloads are empty.
+	Selector string
+
+	// Workloads are selected by kind and name, for example
+	// "deployment/checkout".
+	Workloads []string
+}
+
+// A Scenario is a load under which an application is optimized.
+type Scenario struct {
+	Name string
+
+	// TestCase is the StormForge test case that generates the load, as
+	// "organization/name".
+	TestCase string
+}
+
+type applicationBody struct {
+	Resources []resourceBody `json:"resources"`
+	Scenarios []scenarioBody `json:"scenarios"`
+}
+
+type resourceBody struct {
+	Kubernetes kubernetesBody `json:"kubernetes"`
+}
+
+type kubernetesBody struct {
+	Namespace string   `json:"namespace"`
+	Selector  string   `json:"selector,omitempty"`
+	Workloads []string `json:"workloads,omitempty"`
+}
+
+type scenarioBody struct {
+	Name        string          `json:"name"`
+	StormForger stormForgerBody `json:"stormforger"`
+}
+
+type stormForgerBody struct {
+	TestCase string `json:"testCase"`
+}
+
+func applicationFrom(name string, b *applicationBody) *Application {
+	a := &Application{Name: name}
+	for _, r := range b.Resources {
+		a.Resources = append(a.Resources, ApplicationResource{Namespace: r.Kubernetes.Namespace, Selector: r.Kubernetes.Selector, Workloads: r.Kubernetes.Workloads})
+	}
+	for _, s := range b.Scenarios {
+		a.Scenarios = append(a.Scenarios, Scenario{Name: s.Name, TestCase: s.StormForger.TestCase})
+	}
+	return a
+}
+
+func applicationBodyFrom(a Application) *applicationBody {
+	b := &applicationBody{
+		Resources: make([]resourceBody, 0, len(a.Resources)),
+		Scenarios: make([]scenarioBody, 0, len(a.Scenarios)),
+	}
+	for _, r := range a.Resources {
+		b.Resources = append(b.Resources, resourceBody{Kubernetes: kubernetesBody{Namespace: r.Namespace, Selector: r.Selector, Workloads: r.Workloads}})
+	}
+	for _, s := range a.Scenarios {
+		b.Scenarios = append(b.Scenarios, scenarioBody{Name: s.Name, StormForger: stormForgerBody{TestCase: s.TestCase}})
+	}
+	return b
+}
+
+// GetApplication returns the application with the supplied name.
+func (c *APIClient) GetApplication(ctx context.Context, name string) (*Application, error) {
+	b := &applicationBody{}
+	if err := c.doOptimize(ctx, http.MethodGet, "/v1/applications/"+url.PathEscape(name), nil, b); err != nil {
+		return nil, err
+	}
+	return applicationFrom(name, b), nil
+}
+
+// PutApplication creates the supplied application, or replaces the
+// application of the same name.
+func (c *APIClient) PutApplication(ctx context.Context, a Application) (*Application, error) {
+	b := &applicationBody{}
+	if err := c.doOptimize(ctx, http.MethodPut, "/v1/applications/"+url.PathEscape(a.Name), applicationBodyFrom(a), b); err != nil {
+		return nil, err
+	}
+	return applicationFrom(a.Name, b), nil
+}
+
+// DeleteApplication deletes the application with the supplied name.
+func (c *APIClient) DeleteApplication(ctx context.Context, name string) error {
+	return c.doOptimize(ctx, http.MethodDelete, "/v1/applications/"+url.PathEscape(name), nil, nil)
+}
+
+// A Workload is a Kubernetes workload in a cluster reporting to StormForge
+// Optimize Live.
+type Workload struct {
+	Cluster   string
+	Namespace string
+
+	// Kind of the workload, for example Deployment.
+	Kind string
+	Name string
+}
+
+// path returns the path of the supplied workload in the StormForge Optimize
+// API.
+func (w Workload) path() string {
+	return "/v1/clusters/" + url.PathEscape(w.Cluster) +
+		"/namespaces/" + url.PathEscape(w.Namespace) +
+		"/workloads/" + url.PathEscape(w.Kind) + "/" + url.PathEscape(w.Name)
+}
+
+// A Recommendation is the latest resource recommendation for a workload.
+type Recommendation struct {
+	Workload    Workload
+	GeneratedAt *time.Time
+	Containers  []ContainerRecommendation
+}
+
+// A ContainerRecommendation recommends the resources of a container of a
+// workload.
+type ContainerRecommendation struct {
+	Name     string
+	Requests Resources
+	Limits   Resources
+}
+
+// Resources are quantities of CPU and memory, for example "250m" and "512Mi".
+type Resources struct {
+	CPU    string `json:"cpu,omitempty"`
+	Memory string `json:"memory,omitempty"`
+}
+
+type recommendationBody struct {
+	GeneratedAt *time.Time      `json:"generatedAt,omitempty"`
+	Containers  []containerBody `json:"containers"`
+}
+
+type containerBody struct {
+	Name     string    `json:"name"`
+	Requests Resources `json:"requests"`
+	Limits   Resources `json:"limits"`
+}
+
+// GetRecommendation returns the latest recommendation for the supplied
+// workload.
+func (c *APIClient) GetRecommendation(ctx context.Context, w Workload) (*Recommendation, error) {
+	b := &recommendationBody{}
+	if err := c.doOptimize(ctx, http.MethodGet, w.path()+"/recommendations/latest", nil, b); err != nil {
+		return nil, err
+	}
+	r := &Recommendation{Workload: w, GeneratedAt: b.GeneratedAt}
+	for _, ct := range b.Containers {
+		r.Containers = append(r.Containers, ContainerRecommendation{Name: ct.Name, Requests: ct.Requests, Limits: ct.Limits})
+	}
+	return r, nil
+}
+
+// Policies for applying the recommendations for a workload.
+const (
+	ApplyPolicyOff           = "off"
+	ApplyPolicyRecommendOnly = "recommend-only"
+	ApplyPolicyAutoApply     = "auto-apply"
+)
+
+// A WorkloadConfig configures how StormForge Optimize Live treats a workload.
+type WorkloadConfig struct {
+	Workload Workload
+
+	// ApplyPolicy determines whether recommendations are generated for the
+	// workload, and whether they are applied to it automatically.
+	ApplyPolicy string
+}
+
+type workloadConfigBody struct {
+	ApplyPolicy string `json:"applyPolicy"`
+}
+
+// GetWorkloadConfig returns the configuration of the supplied workload.
+func (c *APIClient) GetWorkloadConfig(ctx context.Context, w Workload) (*WorkloadConfig, error) {
+	b := &workloadConfigBody{}
+	if err := c.doOptimize(ctx, http.MethodGet, w.path()+"/config", nil, b); err != nil {
+		return nil, err
+	}
+	return &WorkloadConfig{Workload: w, ApplyPolicy: b.ApplyPolicy}, nil
+}
+
+// PutWorkloadConfig replaces the configuration of a workload.
+func (c *APIClient) PutWorkloadConfig(ctx context.Context, cfg WorkloadConfig) (*WorkloadConfig, error) {
+	b := &workloadConfigBody{}
+	if err := c.doOptimize(ctx, http.MethodPut, cfg.Workload.path()+"/config", &workloadConfigBody{ApplyPolicy: cfg.ApplyPolicy}, b); err != nil {
+		return nil, err
+	}
+	return &WorkloadConfig{Workload: cfg.Workload, ApplyPolicy: b.ApplyPolicy}, nil
+}
+
+// DeleteWorkloadConfig resets the configuration of the supplied workload to
+// the defaults of its cluster.
+func (c *APIClient) DeleteWorkloadConfig(ctx context.Context, w Workload) error {
+	return c.doOptimize(ctx, http.MethodDelete, w.path()+"/config", nil, nil)
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// An Organization is a StormForge organization. Test cases and data sources
+// belong to an organization.
+type Organization struct {
+	ID          string
+	Name        string
+	Plan        string
+	MemberCount int64
+	Limits      OrganizationLimits
+}
+
+// OrganizationLimits are the entitlements of an organization's plan. A nil
+// limit is unlimited.
+type OrganizationLimits struct {
+	TestCases          *int64
+	ConcurrentTestRuns *int64
+	ArrivalRate        *int64
+	TestDuration       *time.Duration
+}
+
+type organizationAttributes struct {
+	Name        string `json:"name"`
+	Plan        string `json:"plan"`
+	MemberCount int64  `json:"member_count"`
+	Limits      struct {
+		TestCases          *int64 `json:"test_cases"`
+		ConcurrentTestRuns *int64 `json:"concurrent_test_runs"`
+		ArrivalRate        *int64 `json:"arrival_rate"`
+
+		// TestDuration is the longest a run may last, in seconds.
+		TestDuration *int64 `json:"test_duration"`
+	} `json:"limits"`
+}
+
+func organizationFrom(o resourceObject) (*Organization, error) {
+	a := organizationAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	org := &Organization{
+		ID:          o.ID,
+		Name:        a.Name,
+		Plan:        a.Plan,
+		MemberCount: a.MemberCount,
+		Limits: OrganizationLimits{
+			TestCases:          a.Limits.TestCases,
+			ConcurrentTestRuns: a.Limits.ConcurrentTestRuns,
+			ArrivalRate:        a.Limits.ArrivalRate,
+		},
+	}
+	if a.Limits.TestDuration != nil {
+		d := time.Duration(*a.Limits.TestDuration) * time.Second
+		org.Limits.TestDuration = &d
+	}
+	return org, nil
+}
+
+// ListOrganizations returns the organizations the client's token has access
+// to.
+func (c *APIClient) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	orgs := []Organization{}
+	err := c.collection(ctx, "/organisations", func(o resourceObject, _ included) error {
+		org, err := organizationFrom(o)
+		if err != nil {
+			return err
+		}
+		orgs = append(orgs, *org)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// GetOrganization returns the organization with the supplied ID.
+func (c *APIClient) GetOrganization(ctx context.Context, id string) (*Organization, error) {
+	d, err := c.resource(WithOrg(ctx, id), http.MethodGet, "/organisations/"+url.PathEscape(id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return organizationFrom(d.Data)
+}
@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errParseLink    = "cannot parse pagination link"
+	errForeignLink  = "refusing to follow pagination link to %q"
+	errTooManyPages = "refusing to list more than %d pages"
+	errRepeatedLink = "pagination link %q was already followed"
+)
+
+const (
+	pageNumberParam = "page[number]"
+	pageSizeParam   = "page[size]"
+	defaultPageSize = 100
+
+	// maxPages bounds how many pages are fetched when listing a collection,
+	// protecting against an API that never stops returning next links.
+	maxPages = 1000
+)
+
+// Links of a JSON:API document. Only next is needed to read a collection; the
+// others are kept for diagnostics.
+type links struct {
+	Self  string `json:"self,omitempty"`
+	First string `json:"first,omitempty"`
+	Prev  string `json:"prev,omitempty"`
+	Next  string `json:"next,omitempty"`
+	Last  string `json:"last,omitempty"`
+}
+
+// firstPage returns the supplied collection path with page parameters for the
+// first page.
+func firstPage(path string) string {
+	q := url.Values{}
+	q.Set(pageNumberParam, "1")
+	q.Set(pageSizeParam, strconv.Itoa(defaultPageSize))
+	return path + "?" + q.Encode()
+}
+
+// nextPath returns the path, relative to the client's endpoint, of the
+// supplied links.next value. The link may be absolute or relative, but must
+// point at the client's endpoint; the client's credentials are never sent to
+// another host.
+func (c *APIClient) nextPath(next string) (string, error) {
+	base, err := url.Parse(c.endpoint + "/")
+	if err != nil {
+		return "", errors.Wrap(err, errParseLink)
+	}
+	ref, err := url.Parse(next)
+	if err != nil {
+		return "", errors.Wrap(err, errParseLink)
+	}
+	u := base.ResolveReference(ref)
+	if u.Scheme != base.Scheme || u.Host != base.Host {
+		return "", errors.Errorf(errForeignLink, next)
+	}
+	p := strings.TrimPrefix(u.EscapedPath(), strings.TrimSuffix(base.EscapedPath(), "/"))
+	if u.RawQuery != "" {
+		p += "?" + u.RawQuery
+	}
+	return p, nil
+}
+
+// paginate calls fetch with the path of each page of a collection, starting
+// with the supplied path, until fetch returns no next link.
+func (c *APIClient) paginate(path string, fetch func(path string) (links, error)) error {
+	seen := map[string]bool{}
+	p := firstPage(path)
+	for i := 0; i < maxPages; i++ {
+		seen[p] = true
+		l, err := fetch(p)
+		if err != nil {
+			return err
+		}
+		if l.Next == "" {
+			return nil
+		}
+		if p, err = c.nextPath(l.Next); err != nil {
+			return err
+		}
+		if seen[p] {
+			return errors.Errorf(errRepeatedLink, l.Next)
+		}
+	}
+	return errors.Errorf(errTooManyPages, maxPages)
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestListTestCasesPagination(t *testing.T) {
+	type want struct {
+		names []string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		base   string
+		next   func(srv string, page string) string
+		want   want
+	}{
+		"RelativeLinks": {
+			reason: "Host relative next links should be followed until the last page.",
+			next: func(_ string, page string) string {
+				return "/organisations/acme/test_cases?page[number]=" + page
+			},
+			want: want{names: []string{"tc-1", "tc-2", "tc-3"}},
+		},
+		"AbsoluteLinks": {
+			reason: "Absolute next links to the client's endpoint should be followed.",
+			next: func(srv string, page string) string {
+				return srv + "/organisations/acme/test_cases?page[number]=" + page
+			},
+			want: want{names: []string{"tc-1", "tc-2", "tc-3"}},
+		},
+		"EndpointWithPath": {
+			reason: "Links should be resolved relative to an endpoint with a base path.",
+			base:   "/api",
+			next: func(srv string, page string) string {
+				return srv + "/api/organisations/acme/test_cases?page[number]=" + page
+			},
+			want: want{names: []string{"tc-1", "tc-2", "tc-3"}},
+		},
+		"ForeignLink": {
+			reason: "Next links to another host must not be followed.",
+			next: func(_ string, page string) string {
+				return "https://example.org/organisations/acme/test_cases?page[number]=" + page
+			},
+			want: want{err: errors.Errorf(errForeignLink, "https://example.org/organisations/acme/test_cases?page[number]=2")},
+		},
+		"RepeatedLink": {
+			reason: "A next link that was already followed should return an error rather than loop forever.",
+			next: func(_ string, _ string) string {
+				return "/organisations/acme/test_cases?page[number]=2"
+			},
+			want: want{err: errors.Errorf(errRepeatedLink, "/organisations/acme/test_cases?page[number]=2")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var srv *httptest.Server
+			srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tc.base+"/organisations/acme/test_cases" {
+					t.Errorf("path: want %q, got %q", tc.base+"/organisations/acme/test_cases", r.URL.Path)
+				}
+				page := r.URL.Query().Get(pageNumberParam)
+				next := ""
+				switch page {
+				case "1":
+					next = tc.next(srv.URL, "2")
+				case "2":
+					next = tc.next(srv.URL, "3")
+				}
+				fmt.Fprintf(w, `{"data":[{"id":%q,"attributes":{"name":"tc-%s","scope":"acme"}}],"links":{"next":%q}}`, page, page, next)
+			}))
+			defer srv.Close()
+
+			c := New(token, WithEndpoint(srv.URL+tc.base), WithHTTPClient(srv.Client()))
+			tcs, err := c.ListTestCases(context.Background(), "acme")
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.ListTestCases(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			var names []string
+			for _, tc := range tcs {
+				names = append(names, tc.Name)
+			}
+			if diff := cmp.Diff(tc.want.names, names); diff != "" {
+				t.Errorf("\n%s\nc.ListTestCases(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// A Project groups the test cases of an organization, for example by the team
+// that owns them.
+type Project struct {
+	ID          string
+	Name        string
+	Scope       string
+	Description string
+}
+
+type projectAttributes struct {
+	Name        string `json:"name"`
+	Scope       string `json:"scope"`
+	Description string `json:"description"`
+}
+
+func projectFrom(o resourceObject) (*Project, error) {
+	a := projectAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	return &Project{ID: o.ID, Name: a.Name, Scope: a.Scope, Description: a.Description}, nil
+}
+
+// projectForm returns the multipart form of a created or updated project.
+func projectForm(p Project) ([]byte, string, error) {
+	return multipartForm(url.Values{
+		"project[name]":        {p.Name},
+		"project[description]": {p.Description},
+	})
+}
+
+// ListProjects returns the projects of the supplied organization.
+func (c *APIClient) ListProjects(ctx context.Context, org string) ([]Project, error) {
+	ps := []Project{}
+	err := c.collection(WithOrg(ctx, org), "/organisations/"+url.PathEscape(org)+"/projects", func(o resourceObject, _ included) error {
+		p, err := projectFrom(o)
+		if err != nil {
+			return err
+		}
+		ps = append(ps, *p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ps, nil
+}
+
+// GetProject returns the project with the supplied ID of the supplied
+// organization.
+func (c *APIClient) GetProject(ctx context.Context, org, id string) (*Project, error) {
+	d, err := c.resource(WithOrg(ctx, org), http.MethodGet, "/organisations/"+url.PathEscape(org)+"/projects/"+url.PathEscape(id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return projectFrom(d.Data)
+}
+
+// CreateProject creates the supplied project in the supplied organization. The
+// ID and scope of the supplied project are ignored.
+func (c *APIClient) CreateProject(ctx context.Context, org string, p Project) (*Project, error) {
+	body, ct, err := projectForm(p)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPost, "/organisations/"+url.PathEscape(org)+"/projects", body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return projectFrom(d.Data)
+}
+
+// UpdateProject renames or redescribes the project with the supplied ID of the
+// supplied organization.
+func (c *APIClient) UpdateProject(ctx context.Context, org, id string, p Project) (*Project, error) {
+	body, ct, err := projectForm(p)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPatch, "/organisations/"+url.PathEscape(org)+"/projects/"+url.PathEscape(id), body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return projectFrom(d.Data)
+}
+
+// DeleteProject deletes the project with the supplied ID of the supplied
+// organization. Its test cases are not deleted; they no longer belong to a
+// project.
+func (c *APIClient) DeleteProject(ctx context.Context, org, id string) error {
+	return c.do(WithOrg(ctx, org), http.MethodDelete, "/organisations/"+url.PathEscape(org)+"/projects/"+url.PathEscape(id), nil, "", nil)
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/time/rate"
+)
+
+// Defaults for the rate at which requests are sent to the StormForge API.
+const (
+	DefaultQPS   = 5
+	DefaultBurst = 10
+)
+
+const errRateLimit = "cannot wait for rate limiter"
+
+// bucketIdle is how long a bucket must go unused before it may be evicted.
+const bucketIdle = 10 * time.Minute
+
+// A RateLimiter limits the rate of requests to the StormForge API using a token
+// bucket per key. It is safe for concurrent use, and is intended to be shared
+// by every APIClient of the provider so that the limit holds regardless of how
+// many managed resources are reconciled. Buckets that are idle and full are
+// evicted, so that keys that are no longer used, such as deleted
+// organizations or rotated tokens, do not accumulate.
+type RateLimiter struct {
+	limit rate.Limit
+	burst int
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	*rate.Limiter
+	used time.Time
+}
+
+// NewRateLimiter returns a RateLimiter that allows qps requests per second per
+// key, with bursts of up to burst requests.
+func NewRateLimiter(qps float64, burst int) *RateLimiter {
+	return &RateLimiter{limit: rate.Limit(qps), burst: burst, now: time.Now, buckets: map[string]*bucket{}}
+}
+
+// Wait blocks until the bucket of the supplied key allows a request, or the
+// supplied context is done. A nil RateLimiter never blocks.
+func (l *RateLimiter) Wait(ctx context.Context, key string) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	now := l.now()
+	l.sweep(now)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{Limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.buckets[key] = b
+	}
+	b.used = now
+	l.mu.Unlock()
+	return b.Wait(ctx)
+}
+
+// sweep evicts the buckets that were not used for bucketIdle and have refilled
+// completely, at most once every bucketIdle. Replacing such a bucket with a
+// new, full one does not change the rate at which requests are allowed. It
+// must be called with the lock held.
+func (l *RateLimiter) sweep(now time.Time) {
+	if now.Sub(l.swept) < bucketIdle {
+		return
+	}
+	l.swept = now
+	for key, b := range l.buckets {
+		if now.Sub(b.used) >= bucketIdle && b.AllowN(now, l.burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// WithRateLimiter configures the RateLimiter an APIClient waits on before each
+// request. Requests are limited per organization where the organization is
+// known, and per token otherwise.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *APIClient) {
+		c.limiter = l
+	}
+}
+
+type orgKey struct{}
+
+// WithOrg returns a context that attributes requests made with it to the
+// supplied organization for rate limiting. Requests for resources addressed
+// by ID alone, such as test cases and their runs, are otherwise attributed to
+// the token. The context is returned unchanged if the organization is empty.
+func WithOrg(ctx context.Context, org string) context.Context {
+	if org == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, orgKey{}, org)
+}
+
+// bucket returns the rate limiter key of requests made with the supplied
+// context.
+func (c *APIClient) bucket(ctx context.Context) string {
+	if org, ok := ctx.Value(orgKey{}).(string); ok {
+		return "org/" + org
+	}
+	return "token/" + c.tokenHash()
+}
+
+// tokenHash identifies the client's token without revealing it.
+func (c *APIClient) tokenHash() string {
+	sum := sha256.Sum256([]byte(c.token))
+	return hex.EncodeToString(sum[:8])
+}
+
+// limit waits until the client's rate limiter allows a request.
+func (c *APIClient) limit(ctx context.Context) error {
+	return errors.Wrap(c.limiter.Wait(ctx, c.bucket(ctx)), errRateLimit)
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	// A limiter that allows a single request and then one per hour.
+	l := NewRateLimiter(1.0/3600, 1)
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	})
+	WithRateLimiter(l)(c)
+	WithBackoff(Backoff{Attempts: 1})(c)
+
+	if _, err := c.ListTestCases(context.Background(), "acme"); err != nil {
+		t.Fatalf("c.ListTestCases(acme): unexpected error: %s", err)
+	}
+
+	// Another organization has its own bucket.
+	if _, err := c.ListTestCases(context.Background(), "initech"); err != nil {
+		t.Errorf("c.ListTestCases(initech): unexpected error: %s", err)
+	}
+
+	// The bucket of the first organization is empty.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := c.ListTestCases(ctx, "acme")
+	if err == nil {
+		t.Errorf("c.ListTestCases(acme): want rate limiter error, got nil")
+	}
+
+	// Requests by ID attributed to the organization share its bucket.
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetTestCase(WithOrg(ctx, "acme"), "1"); err == nil {
+		t.Errorf("c.GetTestCase(acme, 1): want rate limiter error, got nil")
+	}
+}
+
+func TestRateLimiterEvictsIdleBuckets(t *testing.T) {
+	now := time.Now()
+
+	// A limiter that refills a bucket within a second.
+	l := NewRateLimiter(10, 1)
+	l.now = func() time.Time { return now }
+
+	// A limiter that refills a bucket within an hour.
+	slow := NewRateLimiter(1.0/3600, 1)
+	slow.now = l.now
+
+	for _, rl := range []*RateLimiter{l, slow} {
+		if err := rl.Wait(context.Background(), "org/acme"); err != nil {
+			t.Fatalf("rl.Wait(acme): unexpected error: %s", err)
+		}
+	}
+
+	now = now.Add(bucketIdle)
+	for _, rl := range []*RateLimiter{l, slow} {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_ = rl.Wait(ctx, "org/initech")
+		cancel()
+	}
+
+	// The idle, refilled bucket of acme is evicted.
+	if _, ok := l.buckets["org/acme"]; ok {
+		t.Errorf("l.Wait(initech): idle bucket of acme should be evicted")
+	}
+	if _, ok := l.buckets["org/initech"]; !ok {
+		t.Errorf("l.Wait(initech): bucket of initech should be kept")
+	}
+
+	// The idle bucket of acme is kept until it has refilled, so that evicting
+	// it does not allow more requests.
+	if _, ok := slow.buckets["org/acme"]; !ok {
+		t.Errorf("slow.Wait(initech): bucket of acme should be kept until it has refilled")
+	}
+}
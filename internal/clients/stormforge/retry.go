@@ -0,0 +1,220 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// A Backoff configures how failed calls are retried.
+type Backoff struct {
+	// Attempts is the maximum number of times a call is made, including the
+	// first. A value of one or less disables retries.
+	Attempts int
+
+	// Base is the wait before the first retry. It doubles for each further
+	// retry.
+	Base time.Duration
+
+	// Max caps the wait between two attempts, including any wait requested by
+	// the API via Retry-After.
+	Max time.Duration
+}
+
+// DefaultBackoff is used by an APIClient unless another Backoff is supplied.
+var DefaultBackoff = Backoff{Attempts: 4, Base: 500 * time.Millisecond, Max: 30 * time.Second}
+
+// WithBackoff configures how an APIClient retries failed calls.
+func WithBackoff(b Backoff) Option {
+	return func(c *APIClient) {
+		c.backoff = b
+	}
+}
+
+// DefaultRetryBudget is the number of retries shared by the calls made while
+// reconciling a managed resource by default.
+const DefaultRetryBudget = 8
+
+// A RetryBudget caps the retries shared by every call made with a context
+// returned by WithRetryBudget, so that a reconcile that makes many calls to a
+// failing API gives up rather than retrying each of them in turn. It is safe
+// for concurrent use.
+type RetryBudget struct {
+	remaining int64
+}
+
+// NewRetryBudget returns a RetryBudget of the supplied number of retries.
+func NewRetryBudget(retries int) *RetryBudget {
+	return &RetryBudget{remaining: int64(retries)}
+}
+
+// Remaining returns the number of retries left in the budget.
+func (b *RetryBudget) Remaining() int {
+	if r := atomic.LoadInt64(&b.remaining); r > 0 {
+		return int(r)
+	}
+	return 0
+}
+
+// spend takes a retry from the budget, returning false if none is left. A nil
+// budget never runs out.
+func (b *RetryBudget) spend() bool {
+	if b == nil {
+		return true
+	}
+	return atomic.AddInt64(&b.remaining, -1) >= 0
+}
+
+type retryBudgetKey struct{}
+
+// WithRetryBudget returns a context in which the calls of an APIClient retry
+// failures only while the supplied budget has retries left.
+func WithRetryBudget(ctx context.Context, b *RetryBudget) context.Context {
+	return context.WithValue(ctx, retryBudgetKey{}, b)
+}
+
+func retryBudget(ctx context.Context) *RetryBudget {
+	b, _ := ctx.Value(retryBudgetKey{}).(*RetryBudget)
+	return b
+}
+
+// delay returns the jittered wait before the supplied retry, counting from
+// zero. The wait is chosen uniformly between half and all of the exponential
+// delay so that clients that failed together do not retry together.
+func (b Backoff) delay(retry int) time.Duration {
+	d := b.Base
+	for i := 0; i < retry && d < b.Max; i++ {
+		d *= 2
+	}
+	if d > b.Max {
+		d = b.Max
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1)) // nolint:gosec
+}
+
+// retry calls fn until it succeeds, returns an error that should not be
+// retried, or the backoff's attempts or the context's retry budget are
+// exhausted. The error of the final attempt is returned. fn is not called if
+// the client's token has expired.
+func (c *APIClient) retry(ctx context.Context, method string, fn func() error) error {
+	if err := c.expired(); err != nil {
+		return err
+	}
+	var err error
+	b := retryBudget(ctx)
+	for i := 0; ; i++ {
+		if err = fn(); err == nil {
+			return nil
+		}
+		if i+1 >= c.backoff.Attempts || !retryable(method, err) || !b.spend() {
+			return err
+		}
+
+		d := c.backoff.delay(i)
+		if ae, ok := errors.Cause(err).(*APIError); ok && ae.RetryAfter > d {
+			d = ae.RetryAfter
+			if c.backoff.Max > 0 && d > c.backoff.Max {
+				d = c.backoff.Max
+			}
+		}
+		if werr := c.wait(ctx, d); werr != nil {
+			return err
+		}
+	}
+}
+
+// retryable returns true if a call with the supplied method that failed with
+// the supplied error may be retried. Rate limited calls are always retried
+// because the API rejected them before acting on them. Other failures are only
+// retried for idempotent methods, since a non-idempotent call may have taken
+// effect before failing.
+func retryable(method string, err error) bool {
+	if _, ok := err.(permanentError); ok {
+		return false
+	}
+	if ae, ok := errors.Cause(err).(*APIError); ok {
+		switch ae.StatusCode {
+		case http.StatusTooManyRequests:
+			return true
+		case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return idempotent(method)
+		}
+		return false
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	return idempotent(method)
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// retryAfter parses the supplied Retry-After header value, which is either a
+// number of seconds or an HTTP date.
+func retryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0
+		}
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// wait for the supplied duration, or until the supplied context is done.
+func wait(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// A permanentError wraps an error that must not be retried regardless of its
+// cause, for example because part of the response was already consumed.
+type permanentError struct{ error }
+
+// Cause returns the wrapped error.
+func (e permanentError) Cause() error { return e.error }
+
+// Unwrap returns the wrapped error.
+func (e permanentError) Unwrap() error { return e.error }
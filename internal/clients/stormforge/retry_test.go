@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestRetry(t *testing.T) {
+	type response struct {
+		status     int
+		retryAfter string
+	}
+
+	type want struct {
+		err      error
+		requests int
+
+		// waits before each retry. Jitter may shorten an exponential delay by
+		// up to half, but never a wait requested by the API.
+		waits []time.Duration
+	}
+
+	cases := map[string]struct {
+		reason    string
+		method    string
+		responses []response
+		want      want
+	}{
+		"RetryTransientGet": {
+			reason:    "Idempotent calls should be retried after a transient server error.",
+			method:    http.MethodGet,
+			responses: []response{{status: http.StatusServiceUnavailable}, {status: http.StatusOK}},
+			want:      want{requests: 2, waits: []time.Duration{2 * time.Second}},
+		},
+		"NoRetryTransientPost": {
+			reason:    "Non-idempotent calls should not be retried after a server error, since they may have taken effect.",
+			method:    http.MethodPost,
+			responses: []response{{status: http.StatusServiceUnavailable}, {status: http.StatusOK}},
+			want: want{
+				err:      &APIError{StatusCode: http.StatusServiceUnavailable},
+				requests: 1,
+			},
+		},
+		"HonorRetryAfter": {
+			reason:    "Rate limited calls should be retried after the wait requested by the API, regardless of method.",
+			method:    http.MethodPost,
+			responses: []response{{status: http.StatusTooManyRequests, retryAfter: "7"}, {status: http.StatusOK}},
+			want:      want{requests: 2, waits: []time.Duration{7 * time.Second}},
+		},
+		"CapRetryAfter": {
+			reason:    "Waits requested by the API should be capped by the backoff's maximum.",
+			method:    http.MethodGet,
+			responses: []response{{status: http.StatusTooManyRequests, retryAfter: "3600"}, {status: http.StatusOK}},
+			want:      want{requests: 2, waits: []time.Duration{10 * time.Second}},
+		},
+		"NoRetryClientError": {
+			reason:    "Client errors other than rate limiting should not be retried.",
+			method:    http.MethodGet,
+			responses: []response{{status: http.StatusNotFound}, {status: http.StatusOK}},
+			want: want{
+				err:      &APIError{StatusCode: http.StatusNotFound},
+				requests: 1,
+			},
+		},
+		"AttemptsExhausted": {
+			reason: "The error of the final attempt should be returned once attempts are exhausted.",
+			method: http.MethodGet,
+			responses: []response{
+				{status: http.StatusBadGateway},
+				{status: http.StatusBadGateway},
+				{status: http.StatusGatewayTimeout},
+			},
+			want: want{
+				err:      &APIError{StatusCode: http.StatusGatewayTimeout},
+				requests: 3,
+				waits:    []time.Duration{2 * time.Second, 4 * time.Second},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			requests := 0
+			c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+				rsp := tc.responses[requests]
+				requests++
+				if rsp.retryAfter != "" {
+					w.Header().Set("Retry-After", rsp.retryAfter)
+				}
+				w.WriteHeader(rsp.status)
+			})
+
+			c.backoff = Backoff{Attempts: 3, Base: 2 * time.Second, Max: 10 * time.Second}
+			var waits []time.Duration
+			c.wait = func(_ context.Context, d time.Duration) error {
+				waits = append(waits, d)
+				return nil
+			}
+
+			err := c.do(context.Background(), tc.method, "/", nil, "", nil)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.do(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if requests != tc.want.requests {
+				t.Errorf("\n%s\nc.do(...): want %d requests, got %d", tc.reason, tc.want.requests, requests)
+			}
+			if len(waits) != len(tc.want.waits) {
+				t.Fatalf("\n%s\nc.do(...): want waits %v, got %v", tc.reason, tc.want.waits, waits)
+			}
+			for i := range waits {
+				if waits[i] > tc.want.waits[i] || waits[i] < tc.want.waits[i]/2 {
+					t.Errorf("\n%s\nc.do(...): want waits %v, got %v", tc.reason, tc.want.waits, waits)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryBudget(t *testing.T) {
+	requests := 0
+	c := serve(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	c.backoff = Backoff{Attempts: 3}
+	c.wait = func(context.Context, time.Duration) error { return nil }
+
+	b := NewRetryBudget(3)
+	ctx := WithRetryBudget(context.Background(), b)
+	for _, tc := range []struct {
+		reason    string
+		requests  int
+		remaining int
+	}{
+		{reason: "A call should be retried while the budget has retries left.", requests: 3, remaining: 1},
+		{reason: "A call should stop retrying once the budget is exhausted.", requests: 2, remaining: 0},
+		{reason: "A call should not be retried once the budget is exhausted.", requests: 1, remaining: 0},
+	} {
+		requests = 0
+		want := &APIError{StatusCode: http.StatusServiceUnavailable}
+		if diff := cmp.Diff(want, c.do(ctx, http.MethodGet, "/", nil, "", nil), test.EquateErrors()); diff != "" {
+			t.Errorf("\n%s\nc.do(...): -want error, +got error:\n%s\n", tc.reason, diff)
+		}
+		if requests != tc.requests || b.Remaining() != tc.remaining {
+			t.Errorf("\n%s\nc.do(...): want %d requests and %d retries remaining, got %d and %d", tc.reason, tc.requests, tc.remaining, requests, b.Remaining())
+		}
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := map[string]struct {
+		value string
+		want  time.Duration
+	}{
+		"Empty":   {value: "", want: 0},
+		"Seconds": {value: "120", want: 2 * time.Minute},
+		"Date":    {value: now.Add(time.Minute).Format(http.TimeFormat), want: time.Minute},
+		"Past":    {value: now.Add(-time.Minute).Format(http.TimeFormat), want: 0},
+		"Invalid": {value: "soon", want: 0},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := retryAfter(tc.value, now); got != tc.want {
+				t.Errorf("retryAfter(%q): want %s, got %s", tc.value, tc.want, got)
+			}
+		})
+	}
+}
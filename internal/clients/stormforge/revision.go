@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/url"
+	"sort"
+	"time"
+)
+
+// A Revision is a version of the JavaScript definition of a test case.
+// StormForge records a revision whenever a definition is uploaded, whether by
+// the API or the UI.
+type Revision struct {
+	ID        string
+	CreatedAt *time.Time
+
+	// Author is the name of the user that uploaded the revision, or their ID
+	// if the API did not include the user.
+	Author string
+}
+
+type revisionAttributes struct {
+	CreatedAt *time.Time `json:"created_at"`
+}
+
+type userAttributes struct {
+	Name string `json:"name"`
+}
+
+func revisionFrom(o resourceObject, in included) (*Revision, error) {
+	a := revisionAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	r := &Revision{ID: o.ID, CreatedAt: a.CreatedAt, Author: o.related("author")}
+	if u, ok := o.include("author", in); ok {
+		ua := userAttributes{}
+		if err := u.decode(&ua); err != nil {
+			return nil, err
+		}
+		if ua.Name != "" {
+			r.Author = ua.Name
+		}
+	}
+	return r, nil
+}
+
+// ListRevisions returns the revisions of the test case with the supplied ID,
+// newest first.
+func (c *APIClient) ListRevisions(ctx context.Context, testCaseID string) ([]Revision, error) {
+	revs := []Revision{}
+	err := c.collection(ctx, "/test_cases/"+url.PathEscape(testCaseID)+"/revisions", func(o resourceObject, in included) error {
+		r, err := revisionFrom(o, in)
+		if err != nil {
+			return err
+		}
+		revs = append(revs, *r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	SortRevisions(revs)
+	return revs, nil
+}
+
+// SortRevisions sorts the supplied revisions newest first. Revisions without a
+// creation time sort last.
+func SortRevisions(revs []Revision) {
+	sort.SliceStable(revs, func(i, j int) bool {
+		a, b := revs[i].CreatedAt, revs[j].CreatedAt
+		if a == nil || b == nil {
+			return b == nil && a != nil
+		}
+		return a.After(*b)
+	})
+}
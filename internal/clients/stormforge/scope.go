@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Scopes a StormForge JWT may be granted. A token granted a resource's
+// wildcard scope, for example test_cases:*, or the * scope is granted every
+// scope of that resource.
+const (
+	ScopeTestCasesCreate = "test_cases:create"
+	ScopeTestCasesDelete = "test_cases:delete"
+)
+
+const errScopeFmt = "credential lacks permission to %s: its token is not granted the %s scope"
+
+// A ScopeError is returned when the token of an APIClient is not granted the
+// scope an operation requires. The operation is not attempted.
+type ScopeError struct {
+	// Operation that was not attempted, for example "create test cases".
+	Operation string
+
+	// Scope the operation requires.
+	Scope string
+}
+
+func (e *ScopeError) Error() string {
+	return fmt.Sprintf(errScopeFmt, e.Operation, e.Scope)
+}
+
+// tokenClaims are the claims of a JWT that limit what it may be used for, and
+// until when. Tokens carry their scopes either as a space separated scope
+// claim, as in RFC 8693, or as a scopes array. The exp claim is the time the
+// token expires, in seconds since the Unix epoch.
+type tokenClaims struct {
+	Scope  string   `json:"scope"`
+	Scopes []string `json:"scopes"`
+	Exp    *float64 `json:"exp"`
+}
+
+// claims returns the claims of the supplied JWT, and false if they cannot be
+// decoded. The API remains the authority on what a token may do; its claims
+// are only used to fail early and clearly.
+func claims(token string) (tokenClaims, bool) {
+	c := tokenClaims{}
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return c, false
+	}
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(parts[1], "="))
+	if err != nil {
+		return c, false
+	}
+	if err := json.Unmarshal(b, &c); err != nil {
+		return tokenClaims{}, false
+	}
+	return c, true
+}
+
+// scopes returns the scopes granted to the supplied JWT, and false if it does
+// not limit them, or cannot be decoded.
+func scopes(token string) ([]string, bool) {
+	c, ok := claims(token)
+	if !ok {
+		return nil, false
+	}
+	s := append(strings.Fields(c.Scope), c.Scopes...)
+	return s, len(s) > 0
+}
+
+// authorize returns a *ScopeError if the client's token limits its scopes and
+// is not granted the supplied scope, which the supplied operation requires.
+func (c *APIClient) authorize(operation, scope string) error {
+	granted, limited := scopes(c.token)
+	if !limited {
+		return nil
+	}
+	resource := strings.SplitN(scope, ":", 2)[0]
+	for _, s := range granted {
+		if s == scope || s == resource+":*" || s == "*" {
+			return nil
+		}
+	}
+	return &ScopeError{Operation: operation, Scope: scope}
+}
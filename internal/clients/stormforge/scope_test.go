@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+// signed returns a JWT with the supplied claims. Its signature is not valid,
+// which the client never checks.
+func signed(claims string) string {
+	enc := base64.RawURLEncoding.EncodeToString
+	return enc([]byte(`{"alg":"HS256","typ":"JWT"}`)) + "." + enc([]byte(claims)) + ".c2ln"
+}
+
+func TestAuthorize(t *testing.T) {
+	type want struct {
+		err   error
+		calls int
+	}
+
+	cases := map[string]struct {
+		reason string
+		token  string
+		call   func(c *APIClient) error
+		want   want
+	}{
+		"Unlimited": {
+			reason: "A token that does not limit its scopes should be left to the API to authorize.",
+			token:  signed(`{"sub":"jane"}`),
+			call:   func(c *APIClient) error { return c.DeleteTestCase(context.Background(), "a1") },
+			want:   want{calls: 1},
+		},
+		"Opaque": {
+			reason: "A token that is not a JWT should be left to the API to authorize.",
+			token:  "opaque",
+			call:   func(c *APIClient) error { return c.DeleteTestCase(context.Background(), "a1") },
+			want:   want{calls: 1},
+		},
+		"Granted": {
+			reason: "A token granted the scope of an operation should be allowed to attempt it.",
+			token:  signed(`{"scope":"test_runs:create test_cases:delete"}`),
+			call:   func(c *APIClient) error { return c.DeleteTestCase(context.Background(), "a1") },
+			want:   want{calls: 1},
+		},
+		"Wildcard": {
+			reason: "A token granted every scope of a resource should be allowed to attempt any operation on it.",
+			token:  signed(`{"scopes":["test_cases:*"]}`),
+			call:   func(c *APIClient) error { return c.DeleteTestCase(context.Background(), "a1") },
+			want:   want{calls: 1},
+		},
+		"LacksDelete": {
+			reason: "A token lacking the scope of an operation should fail clearly without calling the API.",
+			token:  signed(`{"scope":"test_cases:create"}`),
+			call:   func(c *APIClient) error { return c.DeleteTestCase(context.Background(), "a1") },
+			want:   want{err: &ScopeError{Operation: "delete test cases", Scope: ScopeTestCasesDelete}},
+		},
+		"LacksCreate": {
+			reason: "A token granted only the scopes of other resources should not be allowed to create test cases.",
+			token:  signed(`{"scopes":["test_runs:*"]}`),
+			call: func(c *APIClient) error {
+				_, err := c.CreateTestCase(context.Background(), "acme", "checkout", []byte("definition"))
+				return err
+			},
+			want: want{err: &ScopeError{Operation: "create test cases", Scope: ScopeTestCasesCreate}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			calls := 0
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			t.Cleanup(srv.Close)
+			c := New(tc.token, WithEndpoint(srv.URL), WithHTTPClient(srv.Client()))
+
+			err := tc.call(c)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\n-want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil && !IsForbidden(err) {
+				t.Errorf("\n%s\nIsForbidden(...): want true for %v", tc.reason, err)
+			}
+			if calls != tc.want.calls {
+				t.Errorf("\n%s\nAPI calls: want %d, got %d", tc.reason, tc.want.calls, calls)
+			}
+		})
+	}
+}
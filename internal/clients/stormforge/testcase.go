@@ -0,0 +1,331 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TestCaseCreating is the state of a test case that is still being created.
+// StormForge reports no state for test cases that are created synchronously.
+const TestCaseCreating = "creating"
+
+// A TestCase is a StormForge test case.
+type TestCase struct {
+	ID        string
+	Name      string
+	Scope     string
+	State     string
+	Archived  bool
+	Labels    map[string]string
+	Notes     string
+	ProjectID string
+	CreatedAt *time.Time
+	UpdatedAt *time.Time
+
+	// DefaultDataSourceID is the ID of the data source the test case draws
+	// data from by default, if any.
+	DefaultDataSourceID string
+
+	// DataSourceIDs are the IDs of the data sources attached to the test
+	// case.
+	DataSourceIDs []string
+}
+
+type testCaseAttributes struct {
+	Name      string            `json:"name"`
+	Scope     string            `json:"scope"`
+	State     string            `json:"state"`
+	Archived  bool              `json:"archived"`
+	Labels    map[string]string `json:"labels"`
+	Notes     string            `json:"notes"`
+	ProjectID string            `json:"project_id"`
+	CreatedAt *time.Time        `json:"created_at"`
+	UpdatedAt *time.Time        `json:"updated_at"`
+
+	DefaultDataSourceID string   `json:"default_data_source_id"`
+	DataSourceIDs       []string `json:"data_source_ids"`
+}
+
+// TestCaseOptions configure a created or updated test case.
+type TestCaseOptions struct {
+	// ClientCertificate and ClientKey are the PEM encoded certificate and
+	// private key the test case presents to targets that require mutual TLS.
+	ClientCertificate []byte
+	ClientKey         []byte
+
+	// Labels and Notes of the test case. Neither is changed unless set.
+	Labels map[string]string
+	Notes  *string
+
+	// ProjectID of the project the test case belongs to. It is not changed
+	// unless set; an empty ID removes the test case from its project.
+	ProjectID *string
+
+	// DefaultDataSourceID of the data source the test case draws data from by
+	// default. It is not changed unless set; an empty ID clears the default.
+	DefaultDataSourceID *string
+
+	// DataSourceIDs of the data sources attached to the test case. They are
+	// not changed if nil; an empty slice detaches every data source.
+	DataSourceIDs []string
+}
+
+// A TestCaseOption configures a created or updated test case.
+type TestCaseOption func(o *TestCaseOptions)
+
+// WithClientCertificate attaches the supplied PEM encoded client certificate
+// and private key to a test case.
+func WithClientCertificate(cert, key []byte) TestCaseOption {
+	return func(o *TestCaseOptions) {
+		o.ClientCertificate = cert
+		o.ClientKey = key
+	}
+}
+
+// WithLabels replaces the labels of a test case with the supplied labels.
+func WithLabels(l map[string]string) TestCaseOption {
+	return func(o *TestCaseOptions) {
+		o.Labels = l
+		if o.Labels == nil {
+			o.Labels = map[string]string{}
+		}
+	}
+}
+
+// WithNotes replaces the notes of a test case with the supplied notes.
+func WithNotes(n string) TestCaseOption {
+	return func(o *TestCaseOptions) {
+		o.Notes = &n
+	}
+}
+
+// WithProject moves a test case to the project with the supplied ID.
+func WithProject(id string) TestCaseOption {
+	return func(o *TestCaseOptions) {
+		o.ProjectID = &id
+	}
+}
+
+// WithDefaultDataSource marks the data source with the supplied ID as the
+// default data source of a test case. An empty ID clears the default.
+func WithDefaultDataSource(id string) TestCaseOption {
+	return func(o *TestCaseOptions) {
+		o.DefaultDataSourceID = &id
+	}
+}
+
+// WithDataSources attaches the data sources with the supplied IDs to a test
+// case, replacing those attached to it before. No IDs detaches every data
+// source.
+func WithDataSources(ids ...string) TestCaseOption {
+	return func(o *TestCaseOptions) {
+		o.DataSourceIDs = append([]string{}, ids...)
+	}
+}
+
+// NewTestCaseOptions returns the options configured by the supplied options.
+func NewTestCaseOptions(opts ...TestCaseOption) TestCaseOptions {
+	o := TestCaseOptions{}
+	for _, fn := range opts {
+		fn(&o)
+	}
+	return o
+}
+
+// testCaseForm returns the multipart form of a created or updated test case.
+// The form has no definition if the supplied script is nil.
+func testCaseForm(name string, script []byte, opts ...TestCaseOption) ([]byte, string, error) {
+	o := NewTestCaseOptions(opts...)
+	var files []formFile
+	if script != nil {
+		files = append(files, formFile{field: "test_case[javascript_definition]", filename: name + ".js", content: script})
+	}
+	if o.ClientCertificate != nil {
+		files = append(files,
+			formFile{field: "test_case[client_certificate]", filename: "tls.crt", content: o.ClientCertificate},
+			formFile{field: "test_case[client_key]", filename: "tls.key", content: o.ClientKey},
+		)
+	}
+	fields := url.Values{"test_case[name]": {name}}
+	if o.Notes != nil {
+		fields.Set("test_case[notes]", *o.Notes)
+	}
+	if o.ProjectID != nil {
+		fields.Set("test_case[project_id]", *o.ProjectID)
+	}
+	if o.DefaultDataSourceID != nil {
+		fields.Set("test_case[default_data_source_id]", *o.DefaultDataSourceID)
+	}
+	switch {
+	case o.DataSourceIDs == nil:
+	case len(o.DataSourceIDs) == 0:
+		// An empty value detaches all data sources.
+		fields.Set("test_case[data_source_ids]", "")
+	default:
+		for _, id := range o.DataSourceIDs {
+			fields.Add("test_case[data_source_ids][]", id)
+		}
+	}
+	switch {
+	case o.Labels == nil:
+	case len(o.Labels) == 0:
+		// An empty value clears all labels.
+		fields.Set("test_case[labels]", "")
+	default:
+		for k, v := range o.Labels {
+			fields.Set("test_case[labels]["+k+"]", v)
+		}
+	}
+	return multipartForm(fields, files...)
+}
+
+func testCaseFrom(o resourceObject) (*TestCase, error) {
+	a := testCaseAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	return &TestCase{ID: o.ID, Name: a.Name, Scope: a.Scope, State: a.State, Archived: a.Archived, Labels: a.Labels, Notes: a.Notes, ProjectID: a.ProjectID, CreatedAt: a.CreatedAt, UpdatedAt: a.UpdatedAt, DefaultDataSourceID: a.DefaultDataSourceID, DataSourceIDs: a.DataSourceIDs}, nil
+}
+
+// ListTestCases returns the test cases of the supplied organization, following
+// pagination links until all pages have been read. The test cases are read from
+// the client's cache, if any, while they are fresh, unless the context was
+// returned by Fresh.
+func (c *APIClient) ListTestCases(ctx context.Context, org string) ([]TestCase, error) {
+	key := c.cachePartition() + org
+	if tcs, ok := c.cache.get(key); ok && !IsFresh(ctx) {
+		return tcs, nil
+	}
+
+	tcs := []TestCase{}
+	err := c.collection(WithOrg(ctx, org), "/organisations/"+url.PathEscape(org)+"/test_cases", func(o resourceObject, _ included) error {
+		tc, err := testCaseFrom(o)
+		if err != nil {
+			return err
+		}
+		tcs = append(tcs, *tc)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.cache.set(key, tcs)
+	return tcs, nil
+}
+
+// CreateTestCase creates a test case with the supplied name and JavaScript
+// definition in the supplied organization. It returns a *ScopeError without
+// calling the API if the client's token is not granted ScopeTestCasesCreate.
+func (c *APIClient) CreateTestCase(ctx context.Context, org, name string, script []byte, o ...TestCaseOption) (*TestCase, error) {
+	if err := c.authorize("create test cases", ScopeTestCasesCreate); err != nil {
+		return nil, err
+	}
+	body, ct, err := testCaseForm(name, script, o...)
+	if err != nil {
+		return nil, err
+	}
+	defer c.cache.invalidate(c.cachePartition())
+	d, err := c.resource(WithOrg(ctx, org), http.MethodPost, "/organisations/"+url.PathEscape(org)+"/test_cases", body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return testCaseFrom(d.Data)
+}
+
+// GetTestCase returns the test case with the supplied ID.
+func (c *APIClient) GetTestCase(ctx context.Context, id string) (*TestCase, error) {
+	d, err := c.resource(ctx, http.MethodGet, "/test_cases/"+url.PathEscape(id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return testCaseFrom(d.Data)
+}
+
+// TestCaseURL returns the URL of the test case with the supplied ID in the
+// StormForge API.
+func (c *APIClient) TestCaseURL(id string) string {
+	return c.endpoint + "/test_cases/" + url.PathEscape(id)
+}
+
+// TestCaseAppURL returns the URL of the test case with the supplied ID in the
+// StormForge web UI.
+func TestCaseAppURL(org, id string) string {
+	return AppEndpoint + "/" + url.PathEscape(org) + "/test_cases/" + url.PathEscape(id)
+}
+
+type definitionAttributes struct {
+	JavaScriptDefinition string `json:"javascript_definition"`
+}
+
+// GetDefinition returns the current JavaScript definition of the test case with
+// the supplied ID.
+func (c *APIClient) GetDefinition(ctx context.Context, id string) ([]byte, error) {
+	d, err := c.resource(ctx, http.MethodGet, "/test_cases/"+url.PathEscape(id)+"?fields[test_cases]=javascript_definition", nil, "")
+	if err != nil {
+		return nil, err
+	}
+	a := definitionAttributes{}
+	if err := d.Data.decode(&a); err != nil {
+		return nil, err
+	}
+	return []byte(a.JavaScriptDefinition), nil
+}
+
+// UpdateTestCase updates the name and JavaScript definition of the test case
+// with the supplied ID. The definition is left unchanged if the supplied
+// script is nil.
+func (c *APIClient) UpdateTestCase(ctx context.Context, id, name string, script []byte, o ...TestCaseOption) (*TestCase, error) {
+	body, ct, err := testCaseForm(name, script, o...)
+	if err != nil {
+		return nil, err
+	}
+	defer c.cache.invalidate(c.cachePartition())
+	d, err := c.resource(ctx, http.MethodPatch, "/test_cases/"+url.PathEscape(id), body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return testCaseFrom(d.Data)
+}
+
+// ArchiveTestCase archives the test case with the supplied ID. An archived
+// test case can no longer be run, but its runs and their results are kept.
+func (c *APIClient) ArchiveTestCase(ctx context.Context, id string) error {
+	defer c.cache.invalidate(c.cachePartition())
+	return c.do(ctx, http.MethodPost, "/test_cases/"+url.PathEscape(id)+"/archive", nil, "", nil)
+}
+
+// UnarchiveTestCase restores the archived test case with the supplied ID, so
+// that it can be run again.
+func (c *APIClient) UnarchiveTestCase(ctx context.Context, id string) error {
+	defer c.cache.invalidate(c.cachePartition())
+	return c.do(ctx, http.MethodPost, "/test_cases/"+url.PathEscape(id)+"/unarchive", nil, "", nil)
+}
+
+// DeleteTestCase deletes the test case with the supplied ID. It returns a
+// *ScopeError without calling the API if the client's token is not granted
+// ScopeTestCasesDelete.
+func (c *APIClient) DeleteTestCase(ctx context.Context, id string) error {
+	if err := c.authorize("delete test cases", ScopeTestCasesDelete); err != nil {
+		return err
+	}
+	defer c.cache.invalidate(c.cachePartition())
+	return c.do(ctx, http.MethodDelete, "/test_cases/"+url.PathEscape(id), nil, "", nil)
+}
@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// A TestRun is a single launch of a StormForge test case.
+type TestRun struct {
+	ID         string
+	TestCaseID string
+	Title      string
+	State      string
+	StartedAt  *time.Time
+	EndedAt    *time.Time
+
+	// Summary of the results of the run. It is only reported once the run
+	// has finished.
+	Summary *RunSummary
+}
+
+// A RunSummary summarizes the results of a finished test run.
+type RunSummary struct {
+	Requests   int64
+	Errors     int64
+	LatencyP50 time.Duration
+	LatencyP95 time.Duration
+	LatencyP99 time.Duration
+}
+
+// Metrics a threshold of a test run may apply to. Latencies are in
+// milliseconds and the error rate is a percentage of all requests. A run
+// fails once any latency or the error rate exceeds its threshold, or once the
+// Apdex score, computed with the Apdex threshold T, falls below its threshold.
+const (
+	ThresholdLatencyP50 = "latency_p50"
+	ThresholdLatencyP95 = "latency_p95"
+	ThresholdLatencyP99 = "latency_p99"
+	ThresholdErrorRate  = "error_rate"
+	ThresholdApdexT     = "apdex_t"
+	ThresholdApdexScore = "apdex_score"
+)
+
+// Artifacts of a finished test run that may be downloaded.
+const (
+	// ArtifactMetrics are the raw metrics of every request sent during a run.
+	ArtifactMetrics = "metrics"
+
+	// ArtifactLogs are the logs of the load generators of a run.
+	ArtifactLogs = "logs"
+)
+
+// RunOptions configure a launched test run.
+type RunOptions struct {
+	Title string
+	Notes string
+
+	// Thresholds of the run by metric.
+	Thresholds map[string]float64
+
+	// ArrivalPhases replace those of the test case's definition for the run,
+	// if any are supplied.
+	ArrivalPhases []ArrivalPhase
+
+	// Target replaces the target URL of the test case's definition for the
+	// run, if set.
+	Target string
+
+	// Headers sent with every request of the run.
+	Headers map[string]string
+}
+
+// An ArrivalPhase is a period of a run during which clients arrive at a rate
+// that changes linearly from Rate to TargetRate.
+type ArrivalPhase struct {
+	Duration time.Duration
+
+	// Rate and TargetRate are the numbers of clients arriving per second at
+	// the start and the end of the phase.
+	Rate       float64
+	TargetRate float64
+
+	// MaxClients limits the number of clients active at once, if set.
+	MaxClients *int32
+}
+
+type testRunAttributes struct {
+	Title     string     `json:"title"`
+	State     string     `json:"state"`
+	StartedAt *time.Time `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at"`
+
+	Summary *runSummaryAttributes `json:"summary"`
+}
+
+// runSummaryAttributes are the results of a run. Latencies are reported in
+// milliseconds.
+type runSummaryAttributes struct {
+	RequestCount int64   `json:"request_count"`
+	ErrorCount   int64   `json:"error_count"`
+	LatencyP50   float64 `json:"latency_p50"`
+	LatencyP95   float64 `json:"latency_p95"`
+	LatencyP99   float64 `json:"latency_p99"`
+}
+
+func (a *runSummaryAttributes) summary() *RunSummary {
+	if a == nil {
+		return nil
+	}
+	ms := func(v float64) time.Duration { return time.Duration(v * float64(time.Millisecond)) }
+	return &RunSummary{
+		Requests:   a.RequestCount,
+		Errors:     a.ErrorCount,
+		LatencyP50: ms(a.LatencyP50),
+		LatencyP95: ms(a.LatencyP95),
+		LatencyP99: ms(a.LatencyP99),
+	}
+}
+
+// testRunFrom returns the test run of the supplied resource object. The test
+// case ID is used unless the object relates the run to a test case.
+func testRunFrom(o resourceObject, testCaseID string) (*TestRun, error) {
+	a := testRunAttributes{}
+	if err := o.decode(&a); err != nil {
+		return nil, err
+	}
+	if id := o.related("test_case"); id != "" {
+		testCaseID = id
+	}
+	return &TestRun{ID: o.ID, TestCaseID: testCaseID, Title: a.Title, State: a.State, StartedAt: a.StartedAt, EndedAt: a.EndedAt, Summary: a.Summary.summary()}, nil
+}
+
+// LaunchTestRun launches a run of the test case with the supplied ID.
+func (c *APIClient) LaunchTestRun(ctx context.Context, testCaseID string, ro RunOptions) (*TestRun, error) {
+	fields := url.Values{}
+	if ro.Title != "" {
+		fields.Set("test_run[title]", ro.Title)
+	}
+	if ro.Notes != "" {
+		fields.Set("test_run[notes]", ro.Notes)
+	}
+	for m, v := range ro.Thresholds {
+		fields.Set("test_run[thresholds]["+m+"]", strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	for i, p := range ro.ArrivalPhases {
+		k := "test_run[arrival_phases][" + strconv.Itoa(i) + "]"
+		fields.Set(k+"[duration]", strconv.FormatInt(int64(p.Duration/time.Second), 10))
+		fields.Set(k+"[rate]", strconv.FormatFloat(p.Rate, 'f', -1, 64))
+		fields.Set(k+"[target_rate]", strconv.FormatFloat(p.TargetRate, 'f', -1, 64))
+		if p.MaxClients != nil {
+			fields.Set(k+"[max_clients]", strconv.Itoa(int(*p.MaxClients)))
+		}
+	}
+	if ro.Target != "" {
+		fields.Set("test_run[target]", ro.Target)
+	}
+	for k, v := range ro.Headers {
+		fields.Set("test_run[headers]["+k+"]", v)
+	}
+	body, ct, err := multipartForm(fields)
+	if err != nil {
+		return nil, err
+	}
+	d, err := c.resource(ctx, http.MethodPost, "/test_cases/"+url.PathEscape(testCaseID)+"/test_runs", body, ct)
+	if err != nil {
+		return nil, err
+	}
+	return testRunFrom(d.Data, testCaseID)
+}
+
+// GetTestRun returns the test run with the supplied ID.
+func (c *APIClient) GetTestRun(ctx context.Context, id string) (*TestRun, error) {
+	d, err := c.resource(ctx, http.MethodGet, "/test_runs/"+url.PathEscape(id), nil, "")
+	if err != nil {
+		return nil, err
+	}
+	return testRunFrom(d.Data, "")
+}
+
+// AbortTestRun aborts the test run with the supplied ID.
+func (c *APIClient) AbortTestRun(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "/test_runs/"+url.PathEscape(id)+"/abort", nil, "", nil)
+}
+
+// ListTestRuns returns the runs of the test case with the supplied ID.
+func (c *APIClient) ListTestRuns(ctx context.Context, testCaseID string) ([]TestRun, error) {
+	runs := []TestRun{}
+	err := c.collection(ctx, "/test_cases/"+url.PathEscape(testCaseID)+"/test_runs", func(o resourceObject, _ included) error {
+		r, err := testRunFrom(o, testCaseID)
+		if err != nil {
+			return err
+		}
+		runs = append(runs, *r)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return runs, nil
+}
+
+// GetTestRunArtifact writes the raw content of the supplied artifact of the
+// test run with the supplied ID to the supplied writer, without buffering it.
+func (c *APIClient) GetTestRunArtifact(ctx context.Context, id, artifact string, w io.Writer) error {
+	return c.download(ctx, "/test_runs/"+url.PathEscape(id)+"/exports/"+url.PathEscape(artifact), w)
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stormforge
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestNewTransport(t *testing.T) {
+	api := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer api.Close()
+	ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: api.Certificate().Raw})
+
+	proxied := ""
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	cases := map[string]struct {
+		reason   string
+		proxy    string
+		caBundle []byte
+		endpoint string
+		wantErr  error
+		want     string
+	}{
+		"CABundle": {
+			reason:   "Servers with certificates issued by the supplied CA bundle should be trusted.",
+			caBundle: ca,
+			endpoint: api.URL,
+		},
+		"InvalidCABundle": {
+			reason:   "A CA bundle without PEM encoded certificates should be rejected.",
+			caBundle: []byte("not a certificate"),
+			wantErr:  errors.New(errParseCABundle),
+		},
+		"Proxy": {
+			reason:   "Requests should be sent through the supplied proxy.",
+			proxy:    proxy.URL,
+			endpoint: "http://api.stormforge.example",
+			want:     "http://api.stormforge.example/user",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			proxied = ""
+			tr, err := NewTransport(tc.proxy, tc.caBundle)
+			if diff := cmp.Diff(tc.wantErr, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nNewTransport(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			c := New(token, WithEndpoint(tc.endpoint), WithHTTPClient(&http.Client{Transport: tr}))
+			if err := c.Ping(context.Background()); err != nil {
+				t.Errorf("\n%s\nc.Ping(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, proxied); diff != "" {
+				t.Errorf("\n%s\nproxied request: -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
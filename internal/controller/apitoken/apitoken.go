@@ -0,0 +1,265 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package apitoken contains a controller that issues StormForge API tokens.
+package apitoken
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "API token"
+
+const (
+	errImmutableFmt          = "spec.forProvider.org is immutable: the API token was created in %q, not %q; delete and recreate the APIToken instead"
+	errExpiresAtImmutableFmt = "spec.forProvider.expiresAt is immutable: the API token was created to expire %s, not %s; delete and recreate the APIToken instead"
+)
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.APITokenKind)
+
+// Setup adds a controller that reconciles APIToken managed resources. The
+// supplied options configure the StormForge client used for each APIToken.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.APITokenGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.APITokenGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		// The external name of an APIToken is the ID of its token.
+		managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.APIToken{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied APIToken.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.APIToken); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{client: sf}), nil
+}
+
+// An ExternalClient observes, then either creates, updates, or revokes an API
+// token.
+type external struct {
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.APIToken)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	if o := cr.Status.AtProvider.Org; o != "" && o != cr.Spec.ForProvider.Org {
+		return managed.ExternalObservation{}, errors.Errorf(errImmutableFmt, o, cr.Spec.ForProvider.Org)
+	}
+
+	// Unlike other resources, an existing token is never adopted by name;
+	// its secret value could not be written to the connection secret.
+	id := meta.GetExternalName(cr)
+	if id == "" {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	t, err := c.client.GetAPIToken(ctx, cr.Spec.ForProvider.Org, id)
+	if stormforge.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	cr.Status.AtProvider = observation(t)
+	if want := cr.Spec.ForProvider.ExpiresAt; !sameExpiry(want, t.ExpiresAt) {
+		return managed.ExternalObservation{}, errors.Errorf(errExpiresAtImmutableFmt, expiry(timeOrNil(t.ExpiresAt)), expiry(want))
+	}
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: t.Name == cr.Spec.ForProvider.Name && sameSet(t.Scopes, scopes(cr.Spec.ForProvider.Scopes)),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.APIToken)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	p := cr.Spec.ForProvider
+	want := stormforge.APIToken{Name: p.Name, Scopes: scopes(p.Scopes)}
+	if p.ExpiresAt != nil {
+		want.ExpiresAt = &p.ExpiresAt.Time
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	t, err := c.client.CreateAPIToken(ctx, p.Org, want)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+
+	meta.SetExternalName(cr, t.ID)
+	cr.Status.AtProvider = observation(t)
+
+	return managed.ExternalCreation{
+		ExternalNameAssigned: true,
+		ConnectionDetails:    managed.ConnectionDetails{xpv1.ResourceCredentialsSecretTokenKey: []byte(t.Token)},
+	}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.APIToken)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	p := cr.Spec.ForProvider
+	t, err := c.client.UpdateAPIToken(ctx, p.Org, meta.GetExternalName(cr), stormforge.APIToken{Name: p.Name, Scopes: scopes(p.Scopes)})
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+
+	cr.Status.AtProvider = observation(t)
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.APIToken)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	err := c.client.DeleteAPIToken(ctx, cr.Spec.ForProvider.Org, meta.GetExternalName(cr))
+	return errors.Wrapf(resource.Ignore(stormforge.IsNotFound, err), errs.DeleteFmt, externalKind)
+}
+
+// observation returns the observed state of the supplied token.
+func observation(t *stormforge.APIToken) v1alpha1.APITokenObservation {
+	return v1alpha1.APITokenObservation{
+		ID:         t.ID,
+		Org:        t.Scope,
+		CreatedAt:  timeOrNil(t.CreatedAt),
+		ExpiresAt:  timeOrNil(t.ExpiresAt),
+		LastUsedAt: timeOrNil(t.LastUsedAt),
+	}
+}
+
+// sameExpiry returns true if the supplied desired and observed expiry times
+// are the same to the second, the precision at which the desired time is
+// stored.
+func sameExpiry(want *metav1.Time, got *time.Time) bool {
+	if want == nil || got == nil {
+		return want == nil && got == nil
+	}
+	return want.Time.Truncate(time.Second).Equal(got.Truncate(time.Second))
+}
+
+// expiry describes when a token that expires at the supplied time expires.
+func expiry(t *metav1.Time) string {
+	if t == nil {
+		return "never"
+	}
+	return "at " + t.UTC().Format(time.RFC3339)
+}
+
+func timeOrNil(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	mt := metav1.NewTime(*t)
+	return &mt
+}
+
+// scopes returns the supplied scopes as the StormForge API expects them.
+func scopes(in []v1alpha1.APITokenScope) []string {
+	out := make([]string, len(in))
+	for i := range in {
+		out[i] = string(in[i])
+	}
+	return out
+}
+
+// sameSet returns true if the supplied slices contain the same strings,
+// regardless of order.
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := append([]string(nil), a...)
+	sb := append([]string(nil), b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
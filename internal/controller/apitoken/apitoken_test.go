@@ -0,0 +1,258 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package apitoken
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func apiToken(id string, o ...func(cr *v1alpha1.APIToken)) *v1alpha1.APIToken {
+	cr := &v1alpha1.APIToken{Spec: v1alpha1.APITokenSpec{ForProvider: v1alpha1.APITokenParameters{
+		Org:    "acme",
+		Name:   "ci",
+		Scopes: []v1alpha1.APITokenScope{v1alpha1.APITokenScopeRead, v1alpha1.APITokenScopeLaunch},
+	}}}
+	meta.SetExternalName(cr, id)
+	for _, fn := range o {
+		fn(cr)
+	}
+	return cr
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	ci := map[string]stormforge.APIToken{"k1": {ID: "k1", Name: "ci", Scope: "acme", Scopes: []string{"launch", "read"}}}
+	expires := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	precise := expires.Add(250 * time.Millisecond)
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.APITokenObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.APIToken
+		want   want
+	}{
+		"NotCreated": {
+			reason: "A token without an external name should be reported as not existing, even if a token of the same name exists.",
+			client: &fake.Client{APITokens: ci},
+			cr:     apiToken(""),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"NotFound": {
+			reason: "A token that has been revoked should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     apiToken("k1"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"GetError": {
+			reason: "Errors getting the token should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     apiToken("k1"),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+		"UpToDate": {
+			reason: "A token with the desired name and scopes, in any order, should be up to date.",
+			client: &fake.Client{APITokens: ci},
+			cr:     apiToken("k1"),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.APITokenObservation{ID: "k1", Org: "acme"},
+			},
+		},
+		"ScopesChanged": {
+			reason: "A token whose scopes differ from the desired scopes should not be up to date.",
+			client: &fake.Client{APITokens: ci},
+			cr: apiToken("k1", func(cr *v1alpha1.APIToken) {
+				cr.Spec.ForProvider.Scopes = []v1alpha1.APITokenScope{v1alpha1.APITokenScopeRead}
+			}),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				status: v1alpha1.APITokenObservation{ID: "k1", Org: "acme"},
+			},
+		},
+		"ExpiresAtChanged": {
+			reason: "Changing the expiry of a created token should be rejected.",
+			client: &fake.Client{APITokens: map[string]stormforge.APIToken{"k1": {ID: "k1", Name: "ci", Scope: "acme", Scopes: []string{"launch", "read"}, ExpiresAt: &expires}}},
+			cr: apiToken("k1", func(cr *v1alpha1.APIToken) {
+				cr.Spec.ForProvider.ExpiresAt = &metav1.Time{Time: expires.Add(24 * time.Hour)}
+			}),
+			want: want{
+				err:    errors.Errorf(errExpiresAtImmutableFmt, "at 2030-01-01T00:00:00Z", "at 2030-01-02T00:00:00Z"),
+				status: v1alpha1.APITokenObservation{ID: "k1", Org: "acme", ExpiresAt: &metav1.Time{Time: expires}},
+			},
+		},
+		"ExpiresAtAdded": {
+			reason: "Setting an expiry on a created token that never expires should be rejected.",
+			client: &fake.Client{APITokens: ci},
+			cr: apiToken("k1", func(cr *v1alpha1.APIToken) {
+				cr.Spec.ForProvider.ExpiresAt = &metav1.Time{Time: expires}
+			}),
+			want: want{
+				err:    errors.Errorf(errExpiresAtImmutableFmt, "never", "at 2030-01-01T00:00:00Z"),
+				status: v1alpha1.APITokenObservation{ID: "k1", Org: "acme"},
+			},
+		},
+		"ExpiresAtUnchanged": {
+			reason: "A token that expires at the desired time, to the second, should be up to date.",
+			client: &fake.Client{APITokens: map[string]stormforge.APIToken{"k1": {ID: "k1", Name: "ci", Scope: "acme", Scopes: []string{"launch", "read"}, ExpiresAt: &precise}}},
+			cr: apiToken("k1", func(cr *v1alpha1.APIToken) {
+				cr.Spec.ForProvider.ExpiresAt = &metav1.Time{Time: expires}
+			}),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.APITokenObservation{ID: "k1", Org: "acme", ExpiresAt: &metav1.Time{Time: precise}},
+			},
+		},
+		"OrgChanged": {
+			reason: "Changing the organization of a created token should be rejected.",
+			client: &fake.Client{APITokens: ci},
+			cr: apiToken("k1", func(cr *v1alpha1.APIToken) {
+				cr.Spec.ForProvider.Org = "initech"
+				cr.Status.AtProvider.Org = "acme"
+			}),
+			want: want{
+				err:    errors.Errorf(errImmutableFmt, "acme", "initech"),
+				status: v1alpha1.APITokenObservation{Org: "acme"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		c            managed.ExternalCreation
+		externalName string
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   want
+	}{
+		"Created": {
+			reason: "The secret value of a created token should be returned as a connection detail.",
+			client: &fake.Client{},
+			want: want{
+				c: managed.ExternalCreation{
+					ExternalNameAssigned: true,
+					ConnectionDetails:    managed.ConnectionDetails{xpv1.ResourceCredentialsSecretTokenKey: []byte("token-1")},
+				},
+				externalName: "1",
+			},
+		},
+		"CreateError": {
+			reason: "Errors creating the token should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   want{err: errors.Wrapf(errBoom, errs.CreateFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := apiToken("")
+			e := external{client: tc.client}
+			got, err := e.Create(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.c, got); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(cr)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   error
+	}{
+		"Revoked": {
+			reason: "An existing token should be revoked.",
+			client: &fake.Client{APITokens: map[string]stormforge.APIToken{"k1": {ID: "k1", Scope: "acme"}}},
+		},
+		"NotFound": {
+			reason: "A token that has already been revoked should be considered deleted.",
+			client: &fake.Client{},
+		},
+		"DeleteError": {
+			reason: "Errors revoking the token should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   errors.Wrapf(errBoom, errs.DeleteFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			err := e.Delete(context.Background(), apiToken("k1"))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if _, ok := tc.client.APITokens["k1"]; ok {
+				t.Errorf("\n%s\ne.Delete(...): token was not revoked\n", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package application contains a controller that manages StormForge Optimize
+// applications.
+package application
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "application"
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.ApplicationKind)
+
+// Setup adds a controller that reconciles Application managed resources. The
+// supplied options configure the StormForge client used for each Application.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.ApplicationGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	// The external name of an Application is the name of its application,
+	// which defaults to the name of the Application.
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ApplicationGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.Application{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied Application.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Application); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{client: sf}), nil
+}
+
+// An ExternalClient observes, then either puts or deletes an application.
+type external struct {
+	// A client used to connect to the StormForge Optimize API.
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Application)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	a, err := c.client.GetApplication(ctx, meta.GetExternalName(cr))
+	if stormforge.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: upToDate(desired(cr), *a),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Application)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	_, err := c.client.PutApplication(ctx, desired(cr))
+	return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Application)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	_, err := c.client.PutApplication(ctx, desired(cr))
+	return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Application)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	err := c.client.DeleteApplication(ctx, meta.GetExternalName(cr))
+	return errors.Wrapf(resource.Ignore(stormforge.IsNotFound, err), errs.DeleteFmt, externalKind)
+}
+
+// desired returns the application described by the supplied Application.
+func desired(cr *v1alpha1.Application) stormforge.Application {
+	p := cr.Spec.ForProvider
+	a := stormforge.Application{Name: meta.GetExternalName(cr)}
+	for _, r := range p.Resources {
+		a.Resources = append(a.Resources, stormforge.ApplicationResource{Namespace: r.Namespace, Selector: r.Selector, Workloads: r.Workloads})
+	}
+	for _, s := range p.Scenarios {
+		a.Scenarios = append(a.Scenarios, stormforge.Scenario{Name: s.Name, TestCase: s.TestCase})
+	}
+	return a
+}
+
+// upToDate returns true if the supplied application has the resources and
+// scenarios we want.
+func upToDate(want, got stormforge.Application) bool {
+	if len(want.Resources) != len(got.Resources) || len(want.Scenarios) != len(got.Scenarios) {
+		return false
+	}
+	for i := range want.Resources {
+		w, g := want.Resources[i], got.Resources[i]
+		if w.Namespace != g.Namespace || w.Selector != g.Selector || !sameList(w.Workloads, g.Workloads) {
+			return false
+		}
+	}
+	for i := range want.Scenarios {
+		if want.Scenarios[i] != got.Scenarios[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameList returns true if the supplied slices contain the same strings in the
+// same order.
+func sameList(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package application
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func application(o ...func(cr *v1alpha1.Application)) *v1alpha1.Application {
+	cr := &v1alpha1.Application{Spec: v1alpha1.ApplicationSpec{ForProvider: v1alpha1.ApplicationParameters{
+		Resources: []v1alpha1.ApplicationResource{{Namespace: "shop", Selector: "tier=backend"}},
+		Scenarios: []v1alpha1.Scenario{{Name: "black-friday", TestCase: "acme/checkout"}},
+	}}}
+	meta.SetExternalName(cr, "shop")
+	for _, fn := range o {
+		fn(cr)
+	}
+	return cr
+}
+
+// shop is the application described by application().
+func shop() stormforge.Application {
+	return stormforge.Application{
+		Name:      "shop",
+		Resources: []stormforge.ApplicationResource{{Namespace: "shop", Selector: "tier=backend"}},
+		Scenarios: []stormforge.Scenario{{Name: "black-friday", TestCase: "acme/checkout"}},
+	}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.Application
+		want   managed.ExternalObservation
+		err    error
+	}{
+		"NotFound": {
+			reason: "An application that does not exist should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     application(),
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"GetError": {
+			reason: "Errors getting the application should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     application(),
+			err:    errors.Wrapf(errBoom, errs.ObserveFmt, externalKind),
+		},
+		"UpToDate": {
+			reason: "An application with the desired resources and scenarios should be up to date.",
+			client: &fake.Client{Applications: map[string]stormforge.Application{"shop": shop()}},
+			cr:     application(),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"WorkloadAdded": {
+			reason: "An application whose resources differ from the desired resources should not be up to date.",
+			client: &fake.Client{Applications: map[string]stormforge.Application{"shop": shop()}},
+			cr: application(func(cr *v1alpha1.Application) {
+				cr.Spec.ForProvider.Resources[0].Workloads = []string{"deployment/checkout"}
+			}),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+		"ScenarioRemoved": {
+			reason: "An application whose scenarios differ from the desired scenarios should not be up to date.",
+			client: &fake.Client{Applications: map[string]stormforge.Application{"shop": shop()}},
+			cr: application(func(cr *v1alpha1.Application) {
+				cr.Spec.ForProvider.Scenarios = nil
+			}),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   map[string]stormforge.Application
+		err    error
+	}{
+		"Created": {
+			reason: "The desired application should be put.",
+			client: &fake.Client{},
+			want:   map[string]stormforge.Application{"shop": shop()},
+		},
+		"PutError": {
+			reason: "Errors putting the application should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			err:    errors.Wrapf(errBoom, errs.CreateFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			_, err := e.Create(context.Background(), application())
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, tc.client.Applications); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want applications, +got applications:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   error
+	}{
+		"Deleted": {
+			reason: "An existing application should be deleted.",
+			client: &fake.Client{Applications: map[string]stormforge.Application{"shop": shop()}},
+		},
+		"NotFound": {
+			reason: "An application that no longer exists should be considered deleted.",
+			client: &fake.Client{},
+		},
+		"DeleteError": {
+			reason: "Errors deleting the application should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   errors.Wrapf(errBoom, errs.DeleteFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			err := e.Delete(context.Background(), application())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if _, ok := tc.client.Applications["shop"]; ok {
+				t.Errorf("\n%s\ne.Delete(...): application was not deleted\n", tc.reason)
+			}
+		})
+	}
+}
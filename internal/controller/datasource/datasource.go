@@ -0,0 +1,281 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package datasource contains a controller that uploads StormForge data
+// sources.
+package datasource
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "data source"
+
+const (
+	errNoSource     = "data source has no source"
+	errGetConfigMap = "cannot get ConfigMap"
+	errGetSecret    = "cannot get Secret"
+	errNoKeyFmt     = "key %q not found"
+	errImmutableFmt = "spec.forProvider.org is immutable: the data source was uploaded to %q, not %q; delete and recreate the DataSource instead"
+)
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.DataSourceKind)
+
+// Setup adds a controller that reconciles DataSource managed resources. The
+// supplied options configure the StormForge client used for each DataSource.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.DataSourceGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.DataSourceGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:   mgr.GetClient(),
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		// The external name of a DataSource is the ID of its data source.
+		managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.DataSource{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube   client.Client
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied DataSource.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.DataSource); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{kube: c.kube, client: sf}), nil
+}
+
+// An ExternalClient observes, then either uploads or deletes a data source.
+type external struct {
+	// A client used to read the content of a data source.
+	kube client.Client
+
+	// A client used to connect to the StormForge API.
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.DataSource)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	if o := cr.Status.AtProvider.Org; o != "" && o != cr.Spec.ForProvider.Org {
+		return managed.ExternalObservation{}, errors.Errorf(errImmutableFmt, o, cr.Spec.ForProvider.Org)
+	}
+
+	ds, err := c.find(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+	if ds == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// A data source that already exists in the organization is adopted
+	// rather than duplicated.
+	adopted := false
+	if meta.GetExternalName(cr) == "" {
+		meta.SetExternalName(cr, ds.ID)
+		adopted = true
+	}
+
+	o := &cr.Status.AtProvider
+	o.ID, o.Org, o.Name = ds.ID, ds.Scope, ds.Name
+	cr.SetConditions(xpv1.Available())
+
+	b, err := c.content(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	// StormForge does not return the content of a data source, so the content
+	// is compared with the content last uploaded.
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        ds.Name == cr.Spec.ForProvider.Name && o.Checksum == checksum(b),
+		ResourceLateInitialized: adopted,
+	}, nil
+}
+
+// find returns the data source of the supplied managed resource, or nil if it
+// does not exist. The data source is identified by its external name if one is
+// set, and otherwise by its name.
+func (c *external) find(ctx context.Context, cr *v1alpha1.DataSource) (*stormforge.DataSource, error) {
+	org := cr.Spec.ForProvider.Org
+	if id := meta.GetExternalName(cr); id != "" {
+		ds, err := c.client.GetDataSource(ctx, org, id)
+		if stormforge.IsNotFound(err) {
+			return nil, nil
+		}
+		return ds, err
+	}
+
+	dss, err := c.client.ListDataSources(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	for i := range dss {
+		if dss[i].Name == cr.Spec.ForProvider.Name {
+			return &dss[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.DataSource)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	p := cr.Spec.ForProvider
+	b, err := c.content(ctx, p)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	ds, err := c.client.CreateDataSource(ctx, p.Org, p.Name, b)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+
+	meta.SetExternalName(cr, ds.ID)
+	cr.Status.AtProvider = v1alpha1.DataSourceObservation{ID: ds.ID, Org: ds.Scope, Name: ds.Name, Checksum: checksum(b)}
+
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.DataSource)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	p := cr.Spec.ForProvider
+	b, err := c.content(ctx, p)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+
+	ds, err := c.client.UpdateDataSource(ctx, p.Org, meta.GetExternalName(cr), p.Name, b)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+
+	cr.Status.AtProvider = v1alpha1.DataSourceObservation{ID: ds.ID, Org: ds.Scope, Name: ds.Name, Checksum: checksum(b)}
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.DataSource)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	err := c.client.DeleteDataSource(ctx, cr.Spec.ForProvider.Org, meta.GetExternalName(cr))
+	return errors.Wrapf(resource.Ignore(stormforge.IsNotFound, err), errs.DeleteFmt, externalKind)
+}
+
+// content returns the content of the supplied data source.
+func (c *external) content(ctx context.Context, p v1alpha1.DataSourceParameters) ([]byte, error) {
+	switch {
+	case p.ConfigMapRef != nil:
+		cm := &corev1.ConfigMap{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Namespace: p.ConfigMapRef.Namespace, Name: p.ConfigMapRef.Name}, cm); err != nil {
+			return nil, errors.Wrap(err, errGetConfigMap)
+		}
+		if v, ok := cm.Data[p.ConfigMapRef.Key]; ok {
+			return []byte(v), nil
+		}
+		if v, ok := cm.BinaryData[p.ConfigMapRef.Key]; ok {
+			return v, nil
+		}
+		return nil, errors.Errorf(errNoKeyFmt, p.ConfigMapRef.Key)
+	case p.SecretRef != nil:
+		s := &corev1.Secret{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Namespace: p.SecretRef.Namespace, Name: p.SecretRef.Name}, s); err != nil {
+			return nil, errors.Wrap(err, errGetSecret)
+		}
+		if v, ok := s.Data[p.SecretRef.Key]; ok {
+			return v, nil
+		}
+		return nil, errors.Errorf(errNoKeyFmt, p.SecretRef.Key)
+	}
+	return nil, errors.New(errNoSource)
+}
+
+// checksum returns the SHA-256 checksum of the supplied content, as recorded in
+// status.atProvider.checksum.
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
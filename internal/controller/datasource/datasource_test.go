@@ -0,0 +1,336 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datasource
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+const content = "user,password\n"
+
+func dataSource(id string, o ...func(cr *v1alpha1.DataSource)) *v1alpha1.DataSource {
+	cr := &v1alpha1.DataSource{Spec: v1alpha1.DataSourceSpec{ForProvider: v1alpha1.DataSourceParameters{
+		Org:          "acme",
+		Name:         "users.csv",
+		ConfigMapRef: &v1alpha1.ConfigMapKeySelector{Name: "fixtures", Namespace: "default", Key: "users.csv"},
+	}}}
+	meta.SetExternalName(cr, id)
+	for _, fn := range o {
+		fn(cr)
+	}
+	return cr
+}
+
+func withChecksum(sum string) func(cr *v1alpha1.DataSource) {
+	return func(cr *v1alpha1.DataSource) {
+		cr.Status.AtProvider.Checksum = sum
+	}
+}
+
+// configMap returns a client that serves a ConfigMap with the supplied content.
+func configMap(content string) client.Client {
+	return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.ConfigMap).Data = map[string]string{"users.csv": content}
+		return nil
+	})}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	users := map[string]stormforge.DataSource{"f1": {ID: "f1", Name: "users.csv", Scope: "acme"}}
+
+	type want struct {
+		o            managed.ExternalObservation
+		externalName string
+		status       v1alpha1.DataSourceObservation
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		client *fake.Client
+		cr     *v1alpha1.DataSource
+		want   want
+	}{
+		"NotUploaded": {
+			reason: "A data source that does not exist by name should be reported as not existing.",
+			kube:   configMap(content),
+			client: &fake.Client{},
+			cr:     dataSource(""),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"NotFound": {
+			reason: "A data source that does not exist by ID should be reported as not existing.",
+			kube:   configMap(content),
+			client: &fake.Client{},
+			cr:     dataSource("f1"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}, externalName: "f1"},
+		},
+		"GetError": {
+			reason: "Errors getting the data source should be wrapped.",
+			kube:   configMap(content),
+			client: &fake.Client{Err: errBoom},
+			cr:     dataSource("f1"),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind), externalName: "f1"},
+		},
+		"Adopted": {
+			reason: "A data source that already exists by name should be adopted by recording its ID as the external name.",
+			kube:   configMap(content),
+			client: &fake.Client{DataSources: users},
+			cr:     dataSource(""),
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false, ResourceLateInitialized: true},
+				externalName: "f1",
+				status:       v1alpha1.DataSourceObservation{ID: "f1", Org: "acme", Name: "users.csv"},
+			},
+		},
+		"UpToDate": {
+			reason: "A data source whose content was last uploaded should be up to date.",
+			kube:   configMap(content),
+			client: &fake.Client{DataSources: users},
+			cr:     dataSource("f1", withChecksum(checksum([]byte(content)))),
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				externalName: "f1",
+				status:       v1alpha1.DataSourceObservation{ID: "f1", Org: "acme", Name: "users.csv", Checksum: checksum([]byte(content))},
+			},
+		},
+		"ContentChanged": {
+			reason: "A data source whose content differs from the content last uploaded should not be up to date.",
+			kube:   configMap("user,password\nalice,secret\n"),
+			client: &fake.Client{DataSources: users},
+			cr:     dataSource("f1", withChecksum(checksum([]byte(content)))),
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				externalName: "f1",
+				status:       v1alpha1.DataSourceObservation{ID: "f1", Org: "acme", Name: "users.csv", Checksum: checksum([]byte(content))},
+			},
+		},
+		"Renamed": {
+			reason: "A data source whose name differs from the desired name should not be up to date.",
+			kube:   configMap(content),
+			client: &fake.Client{DataSources: map[string]stormforge.DataSource{"f1": {ID: "f1", Name: "people.csv", Scope: "acme"}}},
+			cr:     dataSource("f1", withChecksum(checksum([]byte(content)))),
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				externalName: "f1",
+				status:       v1alpha1.DataSourceObservation{ID: "f1", Org: "acme", Name: "people.csv", Checksum: checksum([]byte(content))},
+			},
+		},
+		"ReadContentError": {
+			reason: "Errors reading the content of the data source should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errors.New("boom"))},
+			client: &fake.Client{DataSources: users},
+			cr:     dataSource("f1"),
+			want: want{
+				err:          errors.Wrapf(errors.Wrap(errors.New("boom"), errGetConfigMap), errs.ObserveFmt, externalKind),
+				externalName: "f1",
+				status:       v1alpha1.DataSourceObservation{ID: "f1", Org: "acme", Name: "users.csv"},
+			},
+		},
+		"OrgChanged": {
+			reason: "Changing the organization of an uploaded data source should be rejected.",
+			kube:   configMap(content),
+			client: &fake.Client{DataSources: users},
+			cr: dataSource("f1", func(cr *v1alpha1.DataSource) {
+				cr.Spec.ForProvider.Org = "initech"
+				cr.Status.AtProvider.Org = "acme"
+			}),
+			want: want{
+				err:          errors.Errorf(errImmutableFmt, "acme", "initech"),
+				externalName: "f1",
+				status:       v1alpha1.DataSourceObservation{Org: "acme"},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: tc.kube, client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.cr)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		externalName string
+		content      map[string][]byte
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		cr     *v1alpha1.DataSource
+		want   want
+	}{
+		"Uploaded": {
+			reason: "The content of the data source should be uploaded and its ID recorded as the external name.",
+			kube:   configMap(content),
+			cr:     dataSource(""),
+			want:   want{externalName: "1", content: map[string][]byte{"1": []byte(content)}},
+		},
+		"Secret": {
+			reason: "The content of a data source may be read from a Secret.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{"users.csv": []byte(content)}
+				return nil
+			})},
+			cr: dataSource("", func(cr *v1alpha1.DataSource) {
+				cr.Spec.ForProvider.ConfigMapRef = nil
+				cr.Spec.ForProvider.SecretRef = &xpv1.SecretKeySelector{
+					SecretReference: xpv1.SecretReference{Name: "fixtures", Namespace: "default"},
+					Key:             "users.csv",
+				}
+			}),
+			want: want{externalName: "1", content: map[string][]byte{"1": []byte(content)}},
+		},
+		"NoSource": {
+			reason: "A data source without a source cannot be uploaded.",
+			kube:   configMap(content),
+			cr:     dataSource("", func(cr *v1alpha1.DataSource) { cr.Spec.ForProvider.ConfigMapRef = nil }),
+			want:   want{err: errors.Wrapf(errors.New(errNoSource), errs.CreateFmt, externalKind)},
+		},
+		"MissingKey": {
+			reason: "A data source whose key is missing from its ConfigMap cannot be uploaded.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				return nil
+			})},
+			cr:   dataSource(""),
+			want: want{err: errors.Wrapf(errors.Errorf(errNoKeyFmt, "users.csv"), errs.CreateFmt, externalKind)},
+		},
+		"GetConfigMapError": {
+			reason: "Errors getting the ConfigMap of the data source should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			cr:     dataSource(""),
+			want:   want{err: errors.Wrapf(errors.Wrap(errBoom, errGetConfigMap), errs.CreateFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fc := &fake.Client{}
+			e := external{kube: tc.kube, client: fc}
+			got, err := e.Create(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.cr)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.content, fc.DataSourceContent); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want content, +got content:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err == nil && !got.ExternalNameAssigned {
+				t.Errorf("\n%s\ne.Create(...): want ExternalNameAssigned", tc.reason)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	fc := &fake.Client{
+		DataSources:       map[string]stormforge.DataSource{"f1": {ID: "f1", Name: "people.csv", Scope: "acme"}},
+		DataSourceContent: map[string][]byte{"f1": []byte("user\n")},
+	}
+	cr := dataSource("f1")
+	e := external{kube: configMap(content), client: fc}
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): unexpected error: %s", err)
+	}
+
+	if diff := cmp.Diff(stormforge.DataSource{ID: "f1", Name: "users.csv", Scope: "acme"}, fc.DataSources["f1"]); diff != "" {
+		t.Errorf("e.Update(...): -want data source, +got data source:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(content, string(fc.DataSourceContent["f1"])); diff != "" {
+		t.Errorf("e.Update(...): -want content, +got content:\n%s\n", diff)
+	}
+	want := v1alpha1.DataSourceObservation{ID: "f1", Org: "acme", Name: "users.csv", Checksum: checksum([]byte(content))}
+	if diff := cmp.Diff(want, cr.Status.AtProvider); diff != "" {
+		t.Errorf("e.Update(...): -want status, +got status:\n%s\n", diff)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   error
+	}{
+		"Deleted": {
+			reason: "The data source should be deleted.",
+			client: &fake.Client{DataSources: map[string]stormforge.DataSource{"f1": {ID: "f1", Name: "users.csv", Scope: "acme"}}},
+		},
+		"NotFound": {
+			reason: "A data source that no longer exists should not be an error.",
+			client: &fake.Client{},
+		},
+		"DeleteError": {
+			reason: "Errors deleting the data source should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   errors.Wrapf(errBoom, errs.DeleteFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			err := e.Delete(context.Background(), dataSource("f1"))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if _, ok := tc.client.DataSources["f1"]; ok && tc.want == nil {
+				t.Errorf("\n%s\ne.Delete(...): data source was not deleted", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package experiment contains a controller that manages StormForge Optimize
+// experiments.
+package experiment
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "experiment"
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.ExperimentKind)
+
+// Setup adds a controller that reconciles Experiment managed resources. The
+// supplied options configure the StormForge client used for each Experiment.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.ExperimentGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	// The external name of an Experiment is the name of its experiment, which
+	// defaults to the name of the Experiment.
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ExperimentGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.Experiment{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied Experiment.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Experiment); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{client: sf}), nil
+}
+
+// An ExternalClient observes, then either puts or deletes an experiment.
+type external struct {
+	// A client used to connect to the StormForge Optimize API.
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Experiment)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	e, err := c.client.GetExperiment(ctx, meta.GetExternalName(cr))
+	if stormforge.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	cr.Status.AtProvider.Observations = e.Observations
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: upToDate(desired(cr), *e),
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Experiment)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	_, err := c.client.PutExperiment(ctx, desired(cr))
+	return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Experiment)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	e, err := c.client.PutExperiment(ctx, desired(cr))
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+
+	cr.Status.AtProvider.Observations = e.Observations
+
+	return managed.ExternalUpdate{}, nil
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Experiment)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	err := c.client.DeleteExperiment(ctx, meta.GetExternalName(cr))
+	return errors.Wrapf(resource.Ignore(stormforge.IsNotFound, err), errs.DeleteFmt, externalKind)
+}
+
+// desired returns the experiment described by the supplied Experiment.
+func desired(cr *v1alpha1.Experiment) stormforge.Experiment {
+	p := cr.Spec.ForProvider
+	e := stormforge.Experiment{
+		Name:       meta.GetExternalName(cr),
+		Parameters: make([]stormforge.Parameter, 0, len(p.Parameters)),
+		Metrics:    make([]stormforge.Metric, 0, len(p.Metrics)),
+	}
+	if p.TrialBudget != nil {
+		e.Budget = *p.TrialBudget
+	}
+	for _, pp := range p.Parameters {
+		sp := stormforge.Parameter{Name: pp.Name, Type: string(pp.Type)}
+		if sp.Type == "" {
+			sp.Type = stormforge.ParameterInt
+		}
+		if sp.Type == stormforge.ParameterCategorical {
+			sp.Values = pp.Values
+		} else {
+			sp.Min, sp.Max = pp.Min, pp.Max
+		}
+		e.Parameters = append(e.Parameters, sp)
+	}
+	for _, m := range p.Metrics {
+		e.Metrics = append(e.Metrics, stormforge.Metric{
+			Name:     m.Name,
+			Minimize: m.Minimize,
+			// Metrics are optimized unless they say otherwise.
+			Optimize: m.Optimize == nil || *m.Optimize,
+		})
+	}
+	return e
+}
+
+// upToDate returns true if the supplied experiment has the parameters, metrics
+// and budget we want.
+func upToDate(want, got stormforge.Experiment) bool {
+	if want.Budget != got.Budget || len(want.Parameters) != len(got.Parameters) || len(want.Metrics) != len(got.Metrics) {
+		return false
+	}
+	for i := range want.Parameters {
+		w, g := want.Parameters[i], got.Parameters[i]
+		if w.Name != g.Name || w.Type != g.Type || w.Min != g.Min || w.Max != g.Max || !sameList(w.Values, g.Values) {
+			return false
+		}
+	}
+	for i := range want.Metrics {
+		if want.Metrics[i] != got.Metrics[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sameList returns true if the supplied slices contain the same strings in the
+// same order.
+func sameList(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
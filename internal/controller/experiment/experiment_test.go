@@ -0,0 +1,252 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package experiment
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func experiment(o ...func(cr *v1alpha1.Experiment)) *v1alpha1.Experiment {
+	budget := int64(40)
+	cr := &v1alpha1.Experiment{Spec: v1alpha1.ExperimentSpec{ForProvider: v1alpha1.ExperimentParameters{
+		Parameters: []v1alpha1.Parameter{
+			{Name: "cpu", Type: v1alpha1.ParameterInt, Min: "100", Max: "2000"},
+			{Name: "gc", Type: v1alpha1.ParameterCategorical, Values: []string{"serial", "g1"}},
+		},
+		Metrics:     []v1alpha1.Metric{{Name: "cost", Minimize: true}},
+		TrialBudget: &budget,
+	}}}
+	meta.SetExternalName(cr, "checkout")
+	for _, fn := range o {
+		fn(cr)
+	}
+	return cr
+}
+
+// checkout is the experiment described by experiment().
+func checkout() stormforge.Experiment {
+	return stormforge.Experiment{
+		Name: "checkout",
+		Parameters: []stormforge.Parameter{
+			{Name: "cpu", Type: stormforge.ParameterInt, Min: "100", Max: "2000"},
+			{Name: "gc", Type: stormforge.ParameterCategorical, Values: []string{"serial", "g1"}},
+		},
+		Metrics: []stormforge.Metric{{Name: "cost", Minimize: true, Optimize: true}},
+		Budget:  40,
+	}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	observed := checkout()
+	observed.Observations = 7
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.ExperimentObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.Experiment
+		want   want
+	}{
+		"NotFound": {
+			reason: "An experiment that does not exist should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     experiment(),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"GetError": {
+			reason: "Errors getting the experiment should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     experiment(),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+		"UpToDate": {
+			reason: "An experiment with the desired parameters, metrics and budget should be up to date.",
+			client: &fake.Client{Experiments: map[string]stormforge.Experiment{"checkout": observed}},
+			cr:     experiment(),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.ExperimentObservation{Observations: 7},
+			},
+		},
+		"BudgetChanged": {
+			reason: "An experiment whose budget differs from the desired budget should not be up to date.",
+			client: &fake.Client{Experiments: map[string]stormforge.Experiment{"checkout": observed}},
+			cr: experiment(func(cr *v1alpha1.Experiment) {
+				budget := int64(80)
+				cr.Spec.ForProvider.TrialBudget = &budget
+			}),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				status: v1alpha1.ExperimentObservation{Observations: 7},
+			},
+		},
+		"ParameterChanged": {
+			reason: "An experiment whose parameters differ from the desired parameters should not be up to date.",
+			client: &fake.Client{Experiments: map[string]stormforge.Experiment{"checkout": observed}},
+			cr: experiment(func(cr *v1alpha1.Experiment) {
+				cr.Spec.ForProvider.Parameters[0].Max = "4000"
+			}),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				status: v1alpha1.ExperimentObservation{Observations: 7},
+			},
+		},
+		"MetricNotOptimized": {
+			reason: "An experiment that optimizes a metric that should only be measured should not be up to date.",
+			client: &fake.Client{Experiments: map[string]stormforge.Experiment{"checkout": observed}},
+			cr: experiment(func(cr *v1alpha1.Experiment) {
+				optimize := false
+				cr.Spec.ForProvider.Metrics[0].Optimize = &optimize
+			}),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				status: v1alpha1.ExperimentObservation{Observations: 7},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		experiments map[string]stormforge.Experiment
+		err         error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.Experiment
+		want   want
+	}{
+		"Created": {
+			reason: "The desired experiment should be put.",
+			client: &fake.Client{},
+			cr:     experiment(),
+			want:   want{experiments: map[string]stormforge.Experiment{"checkout": checkout()}},
+		},
+		"DefaultType": {
+			reason: "Parameters without a type should be put as integer parameters.",
+			client: &fake.Client{},
+			cr: experiment(func(cr *v1alpha1.Experiment) {
+				cr.Spec.ForProvider.Parameters = cr.Spec.ForProvider.Parameters[:1]
+				cr.Spec.ForProvider.Parameters[0].Type = ""
+			}),
+			want: want{experiments: map[string]stormforge.Experiment{"checkout": func() stormforge.Experiment {
+				e := checkout()
+				e.Parameters = e.Parameters[:1]
+				return e
+			}()}},
+		},
+		"PutError": {
+			reason: "Errors putting the experiment should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     experiment(),
+			want:   want{err: errors.Wrapf(errBoom, errs.CreateFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			_, err := e.Create(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.experiments, tc.client.Experiments); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want experiments, +got experiments:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   error
+	}{
+		"Deleted": {
+			reason: "An existing experiment should be deleted.",
+			client: &fake.Client{Experiments: map[string]stormforge.Experiment{"checkout": checkout()}},
+		},
+		"NotFound": {
+			reason: "An experiment that no longer exists should be considered deleted.",
+			client: &fake.Client{},
+		},
+		"DeleteError": {
+			reason: "Errors deleting the experiment should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   errors.Wrapf(errBoom, errs.DeleteFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			err := e.Delete(context.Background(), experiment())
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if _, ok := tc.client.Experiments["checkout"]; ok {
+				t.Errorf("\n%s\ne.Delete(...): experiment was not deleted\n", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ipallowlist contains a controller that observes the IP ranges of
+// StormForge load generators.
+package ipallowlist
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "IP ranges"
+
+const (
+	errObserveOnly = "IP ranges are published by StormForge and cannot be created using Crossplane"
+	errNoRangesFmt = "no IP ranges are published for region %q"
+)
+
+// Keys of the connection details of an IPAllowlist.
+const (
+	connectionKey    = "cidrs"
+	connectionPrefix = connectionKey + "."
+)
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.IPAllowlistKind)
+
+// Setup adds a controller that reconciles IPAllowlist managed resources. The
+// supplied options configure the StormForge client used for each
+// IPAllowlist.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.IPAllowlistGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.IPAllowlistGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.IPAllowlist{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied IPAllowlist.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.IPAllowlist); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{client: sf}), nil
+}
+
+// An ExternalClient observes the IP ranges of load generators. They are never
+// created, updated, or deleted.
+type external struct {
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.IPAllowlist)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	// IP ranges are never deleted, so an IPAllowlist may be finalized
+	// immediately.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	ranges, err := c.client.ListIPRanges(ctx)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+	o, err := observation(ranges, cr.Spec.ForProvider.Regions)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	cr.Status.AtProvider = o
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:    true,
+		ResourceUpToDate:  true,
+		ConnectionDetails: connectionDetails(o),
+	}, nil
+}
+
+// observation returns the supplied IP ranges of the supplied regions, or of
+// every region if none are supplied, ordered by region and CIDR.
+func observation(ranges []stormforge.IPRange, regions []string) (v1alpha1.IPAllowlistObservation, error) {
+	byRegion := map[string][]string{}
+	for _, r := range ranges {
+		byRegion[r.Region] = append(byRegion[r.Region], r.CIDR)
+	}
+	regions = append([]string{}, regions...)
+	if len(regions) == 0 {
+		for r := range byRegion {
+			regions = append(regions, r)
+		}
+	}
+	sort.Strings(regions)
+
+	o := v1alpha1.IPAllowlistObservation{CIDRs: []string{}, Regions: []v1alpha1.IPAllowlistRegion{}}
+	for _, r := range regions {
+		cidrs, ok := byRegion[r]
+		if !ok {
+			return v1alpha1.IPAllowlistObservation{}, errors.Errorf(errNoRangesFmt, r)
+		}
+		sort.Strings(cidrs)
+		o.Regions = append(o.Regions, v1alpha1.IPAllowlistRegion{Name: r, CIDRs: cidrs})
+		o.CIDRs = append(o.CIDRs, cidrs...)
+	}
+	sort.Strings(o.CIDRs)
+	return o, nil
+}
+
+// connectionDetails returns the CIDRs of the supplied observation, one per
+// line, under the key "cidrs" and those of each region under the key
+// "cidrs.<region>".
+func connectionDetails(o v1alpha1.IPAllowlistObservation) managed.ConnectionDetails {
+	cd := managed.ConnectionDetails{connectionKey: lines(o.CIDRs)}
+	for _, r := range o.Regions {
+		cd[connectionPrefix+r.Name] = lines(r.CIDRs)
+	}
+	return cd
+}
+
+func lines(s []string) []byte {
+	if len(s) == 0 {
+		return []byte{}
+	}
+	return []byte(strings.Join(s, "\n") + "\n")
+}
+
+// Create returns an error; IP ranges are published by StormForge.
+func (c *external) Create(_ context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if _, ok := mg.(*v1alpha1.IPAllowlist); !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+	return managed.ExternalCreation{}, errors.New(errObserveOnly)
+}
+
+// Update does nothing; IP ranges cannot be changed.
+func (c *external) Update(_ context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if _, ok := mg.(*v1alpha1.IPAllowlist); !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete does nothing; IP ranges are never deleted.
+func (c *external) Delete(_ context.Context, mg resource.Managed) error {
+	if _, ok := mg.(*v1alpha1.IPAllowlist); !ok {
+		return errors.New(errNotMyType)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ipallowlist
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func allowlist(regions ...string) *v1alpha1.IPAllowlist {
+	return &v1alpha1.IPAllowlist{Spec: v1alpha1.IPAllowlistSpec{ForProvider: v1alpha1.IPAllowlistParameters{Regions: regions}}}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	now := metav1.Now()
+	deleted := allowlist()
+	deleted.SetDeletionTimestamp(&now)
+
+	ranges := []stormforge.IPRange{
+		{Region: "us-east-1", CIDR: "198.51.100.0/24"},
+		{Region: "eu-central-1", CIDR: "203.0.113.128/25"},
+		{Region: "eu-central-1", CIDR: "203.0.113.0/25"},
+	}
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.IPAllowlistObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.IPAllowlist
+		want   want
+	}{
+		"AllRegions": {
+			reason: "The ranges of every region should be observed and published if no regions are configured.",
+			client: &fake.Client{IPRanges: ranges},
+			cr:     allowlist(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{
+					"cidrs":              []byte("198.51.100.0/24\n203.0.113.0/25\n203.0.113.128/25\n"),
+					"cidrs.eu-central-1": []byte("203.0.113.0/25\n203.0.113.128/25\n"),
+					"cidrs.us-east-1":    []byte("198.51.100.0/24\n"),
+				}},
+				status: v1alpha1.IPAllowlistObservation{
+					CIDRs: []string{"198.51.100.0/24", "203.0.113.0/25", "203.0.113.128/25"},
+					Regions: []v1alpha1.IPAllowlistRegion{
+						{Name: "eu-central-1", CIDRs: []string{"203.0.113.0/25", "203.0.113.128/25"}},
+						{Name: "us-east-1", CIDRs: []string{"198.51.100.0/24"}},
+					},
+				},
+			},
+		},
+		"SomeRegions": {
+			reason: "Only the ranges of the configured regions should be observed.",
+			client: &fake.Client{IPRanges: ranges},
+			cr:     allowlist("us-east-1"),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{
+					"cidrs":           []byte("198.51.100.0/24\n"),
+					"cidrs.us-east-1": []byte("198.51.100.0/24\n"),
+				}},
+				status: v1alpha1.IPAllowlistObservation{
+					CIDRs:   []string{"198.51.100.0/24"},
+					Regions: []v1alpha1.IPAllowlistRegion{{Name: "us-east-1", CIDRs: []string{"198.51.100.0/24"}}},
+				},
+			},
+		},
+		"UnknownRegion": {
+			reason: "A region without published ranges should be an error rather than an empty allowlist.",
+			client: &fake.Client{IPRanges: ranges},
+			cr:     allowlist("ap-south-1"),
+			want:   want{err: errors.Wrapf(errors.Errorf(errNoRangesFmt, "ap-south-1"), errs.ObserveFmt, externalKind)},
+		},
+		"ListError": {
+			reason: "Errors listing the ranges should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     allowlist(),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+		"Deleted": {
+			reason: "A deleted IPAllowlist should be reported as not existing so that it is finalized.",
+			client: &fake.Client{IPRanges: ranges},
+			cr:     deleted,
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
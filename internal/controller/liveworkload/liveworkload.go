@@ -0,0 +1,187 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package liveworkload contains a controller that configures workloads
+// optimized by StormForge Optimize Live.
+package liveworkload
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "workload configuration"
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.LiveWorkloadKind)
+
+// Setup adds a controller that reconciles LiveWorkload managed resources. The
+// supplied options configure the StormForge client used for each
+// LiveWorkload.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.LiveWorkloadGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.LiveWorkloadGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.LiveWorkload{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied LiveWorkload.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.LiveWorkload); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{client: sf}), nil
+}
+
+// An ExternalClient observes, then either puts or resets the configuration of
+// a workload.
+type external struct {
+	// A client used to connect to the StormForge Optimize API.
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.LiveWorkload)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	cfg, err := c.client.GetWorkloadConfig(ctx, workload(cr.Spec.ForProvider.WorkloadReference))
+	if stormforge.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	cr.Status.AtProvider.ApplyPolicy = v1alpha1.ApplyPolicy(cfg.ApplyPolicy)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:   true,
+		ResourceUpToDate: cfg.ApplyPolicy == desired(cr).ApplyPolicy,
+	}, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.LiveWorkload)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	_, err := c.client.PutWorkloadConfig(ctx, desired(cr))
+	return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.LiveWorkload)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	cfg, err := c.client.PutWorkloadConfig(ctx, desired(cr))
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+
+	cr.Status.AtProvider.ApplyPolicy = v1alpha1.ApplyPolicy(cfg.ApplyPolicy)
+
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete resets the configuration of the workload to the defaults of its
+// cluster; the workload itself is not affected.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.LiveWorkload)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	err := c.client.DeleteWorkloadConfig(ctx, workload(cr.Spec.ForProvider.WorkloadReference))
+	return errors.Wrapf(resource.Ignore(stormforge.IsNotFound, err), errs.DeleteFmt, externalKind)
+}
+
+// desired returns the workload configuration described by the supplied
+// LiveWorkload.
+func desired(cr *v1alpha1.LiveWorkload) stormforge.WorkloadConfig {
+	cfg := stormforge.WorkloadConfig{
+		Workload:    workload(cr.Spec.ForProvider.WorkloadReference),
+		ApplyPolicy: string(cr.Spec.ForProvider.ApplyPolicy),
+	}
+	if cfg.ApplyPolicy == "" {
+		cfg.ApplyPolicy = stormforge.ApplyPolicyRecommendOnly
+	}
+	return cfg
+}
+
+// workload returns the StormForge workload identified by the supplied
+// reference.
+func workload(ref v1alpha1.WorkloadReference) stormforge.Workload {
+	w := stormforge.Workload{Cluster: ref.Cluster, Namespace: ref.Namespace, Kind: ref.Kind, Name: ref.Name}
+	if w.Kind == "" {
+		w.Kind = "Deployment"
+	}
+	return w
+}
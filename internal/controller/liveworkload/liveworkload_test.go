@@ -0,0 +1,204 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package liveworkload
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+var checkout = stormforge.Workload{Cluster: "prod", Namespace: "shop", Kind: "Deployment", Name: "checkout"}
+
+func liveWorkload(p v1alpha1.ApplyPolicy) *v1alpha1.LiveWorkload {
+	return &v1alpha1.LiveWorkload{Spec: v1alpha1.LiveWorkloadSpec{ForProvider: v1alpha1.LiveWorkloadParameters{
+		WorkloadReference: v1alpha1.WorkloadReference{Cluster: "prod", Namespace: "shop", Name: "checkout"},
+		ApplyPolicy:       p,
+	}}}
+}
+
+func configs(policy string) map[stormforge.Workload]stormforge.WorkloadConfig {
+	return map[stormforge.Workload]stormforge.WorkloadConfig{checkout: {Workload: checkout, ApplyPolicy: policy}}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.LiveWorkloadObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.LiveWorkload
+		want   want
+	}{
+		"NotFound": {
+			reason: "A workload StormForge does not know about should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     liveWorkload(v1alpha1.ApplyPolicyAutoApply),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"GetError": {
+			reason: "Errors getting the workload configuration should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     liveWorkload(v1alpha1.ApplyPolicyAutoApply),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+		"UpToDate": {
+			reason: "A workload with the desired apply policy should be up to date.",
+			client: &fake.Client{WorkloadConfigs: configs(stormforge.ApplyPolicyAutoApply)},
+			cr:     liveWorkload(v1alpha1.ApplyPolicyAutoApply),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.LiveWorkloadObservation{ApplyPolicy: v1alpha1.ApplyPolicyAutoApply},
+			},
+		},
+		"DefaultPolicy": {
+			reason: "A workload that only generates recommendations should be up to date when no apply policy is specified.",
+			client: &fake.Client{WorkloadConfigs: configs(stormforge.ApplyPolicyRecommendOnly)},
+			cr:     liveWorkload(""),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.LiveWorkloadObservation{ApplyPolicy: v1alpha1.ApplyPolicyRecommendOnly},
+			},
+		},
+		"PolicyChanged": {
+			reason: "A workload whose apply policy differs from the desired policy should not be up to date.",
+			client: &fake.Client{WorkloadConfigs: configs(stormforge.ApplyPolicyOff)},
+			cr:     liveWorkload(v1alpha1.ApplyPolicyAutoApply),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				status: v1alpha1.LiveWorkloadObservation{ApplyPolicy: v1alpha1.ApplyPolicyOff},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		configs map[stormforge.Workload]stormforge.WorkloadConfig
+		status  v1alpha1.LiveWorkloadObservation
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   want
+	}{
+		"Updated": {
+			reason: "The desired apply policy should be put.",
+			client: &fake.Client{WorkloadConfigs: configs(stormforge.ApplyPolicyOff)},
+			want: want{
+				configs: configs(stormforge.ApplyPolicyAutoApply),
+				status:  v1alpha1.LiveWorkloadObservation{ApplyPolicy: v1alpha1.ApplyPolicyAutoApply},
+			},
+		},
+		"PutError": {
+			reason: "Errors putting the workload configuration should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   want{err: errors.Wrapf(errBoom, errs.UpdateFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := liveWorkload(v1alpha1.ApplyPolicyAutoApply)
+			e := external{client: tc.client}
+			_, err := e.Update(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.configs, tc.client.WorkloadConfigs); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want configs, +got configs:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   error
+	}{
+		"Reset": {
+			reason: "The configuration of the workload should be reset.",
+			client: &fake.Client{WorkloadConfigs: configs(stormforge.ApplyPolicyAutoApply)},
+		},
+		"NotFound": {
+			reason: "A workload StormForge no longer knows about should be considered reset.",
+			client: &fake.Client{},
+		},
+		"DeleteError": {
+			reason: "Errors resetting the workload configuration should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   errors.Wrapf(errBoom, errs.DeleteFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			err := e.Delete(context.Background(), liveWorkload(v1alpha1.ApplyPolicyAutoApply))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if _, ok := tc.client.WorkloadConfigs[checkout]; ok {
+				t.Errorf("\n%s\ne.Delete(...): workload configuration was not reset\n", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package notificationchannel contains a controller that manages StormForge
+// notification channels.
+package notificationchannel
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "notification channel"
+
+const (
+	errNoChannelType = "one of email, slack or webhook must be set"
+	errNoWebhookURL  = "one of url or urlSecretRef must be set"
+	errGetSecret     = "cannot get Secret"
+	errNoKeyFmt      = "key %q not found"
+	errImmutableFmt  = "spec.forProvider.org is immutable: the notification channel was created in %q, not %q; delete and recreate the NotificationChannel instead"
+)
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.NotificationChannelKind)
+
+// events are the StormForge names of the lifecycle events of a test run.
+var events = map[v1alpha1.NotificationEvent]string{
+	v1alpha1.NotificationTestRunStarted:  "test_run.started",
+	v1alpha1.NotificationTestRunFinished: "test_run.finished",
+	v1alpha1.NotificationTestRunFailed:   "test_run.failed",
+	v1alpha1.NotificationTestRunAborted:  "test_run.aborted",
+}
+
+// Setup adds a controller that reconciles NotificationChannel managed
+// resources. The supplied options configure the StormForge client used for
+// each NotificationChannel.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.NotificationChannelGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.NotificationChannelGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:   mgr.GetClient(),
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		// The external name of a NotificationChannel is the ID of its channel.
+		managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.NotificationChannel{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube   client.Client
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied NotificationChannel.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.NotificationChannel); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{kube: c.kube, client: sf}), nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes a
+// notification channel.
+type external struct {
+	// A client used to read the Secrets of a notification channel.
+	kube client.Client
+
+	// A client used to connect to the StormForge API.
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.NotificationChannel)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	if o := cr.Status.AtProvider.Org; o != "" && o != cr.Spec.ForProvider.Org {
+		return managed.ExternalObservation{}, errors.Errorf(errImmutableFmt, o, cr.Spec.ForProvider.Org)
+	}
+
+	nc, err := c.find(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+	if nc == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// A channel that already exists in the organization is adopted rather
+	// than duplicated.
+	adopted := false
+	if meta.GetExternalName(cr) == "" {
+		meta.SetExternalName(cr, nc.ID)
+		adopted = true
+	}
+
+	cr.Status.AtProvider = v1alpha1.NotificationChannelObservation{ID: nc.ID, Org: nc.Scope, Type: nc.Type}
+	cr.SetConditions(xpv1.Available())
+
+	want, err := c.desired(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        upToDate(want, *nc),
+		ResourceLateInitialized: adopted,
+	}, nil
+}
+
+// find returns the notification channel of the supplied managed resource, or
+// nil if it does not exist. The channel is identified by its external name if
+// one is set, and otherwise by its name.
+func (c *external) find(ctx context.Context, cr *v1alpha1.NotificationChannel) (*stormforge.NotificationChannel, error) {
+	org := cr.Spec.ForProvider.Org
+	if id := meta.GetExternalName(cr); id != "" {
+		nc, err := c.client.GetNotificationChannel(ctx, org, id)
+		if stormforge.IsNotFound(err) {
+			return nil, nil
+		}
+		return nc, err
+	}
+
+	ncs, err := c.client.ListNotificationChannels(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ncs {
+		if ncs[i].Name == cr.Spec.ForProvider.Name {
+			return &ncs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.NotificationChannel)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	want, err := c.desired(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	nc, err := c.client.CreateNotificationChannel(ctx, cr.Spec.ForProvider.Org, want)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+
+	meta.SetExternalName(cr, nc.ID)
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.NotificationChannel)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	want, err := c.desired(ctx, cr.Spec.ForProvider)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+
+	_, err = c.client.UpdateNotificationChannel(ctx, cr.Spec.ForProvider.Org, meta.GetExternalName(cr), want)
+	return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.NotificationChannel)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	err := c.client.DeleteNotificationChannel(ctx, cr.Spec.ForProvider.Org, meta.GetExternalName(cr))
+	return errors.Wrapf(resource.Ignore(stormforge.IsNotFound, err), errs.DeleteFmt, externalKind)
+}
+
+// desired returns the notification channel described by the supplied
+// parameters, reading any URLs stored in Secrets.
+func (c *external) desired(ctx context.Context, p v1alpha1.NotificationChannelParameters) (stormforge.NotificationChannel, error) {
+	nc := stormforge.NotificationChannel{Name: p.Name, Events: make([]string, 0, len(p.Events))}
+	for _, e := range p.Events {
+		nc.Events = append(nc.Events, events[e])
+	}
+
+	var err error
+	switch {
+	case p.Email != nil:
+		nc.Type = stormforge.NotificationChannelEmail
+		nc.Recipients = p.Email.Recipients
+	case p.Slack != nil:
+		nc.Type = stormforge.NotificationChannelSlack
+		nc.SlackChannel = p.Slack.Channel
+		nc.URL, err = c.secretKey(ctx, &p.Slack.WebhookURLSecretRef)
+	case p.Webhook != nil && p.Webhook.URLSecretRef != nil:
+		nc.Type = stormforge.NotificationChannelWebhook
+		nc.URL, err = c.secretKey(ctx, p.Webhook.URLSecretRef)
+	case p.Webhook != nil && p.Webhook.URL != "":
+		nc.Type = stormforge.NotificationChannelWebhook
+		nc.URL = p.Webhook.URL
+	case p.Webhook != nil:
+		err = errors.New(errNoWebhookURL)
+	default:
+		err = errors.New(errNoChannelType)
+	}
+	return nc, err
+}
+
+// secretKey returns the value of the referenced Secret key, stripped of any
+// surrounding whitespace.
+func (c *external) secretKey(ctx context.Context, ref *xpv1.SecretKeySelector) (string, error) {
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return "", errors.Wrap(err, errGetSecret)
+	}
+	v, ok := s.Data[ref.Key]
+	if !ok {
+		return "", errors.Errorf(errNoKeyFmt, ref.Key)
+	}
+	return strings.TrimSpace(string(v)), nil
+}
+
+// upToDate returns true if the supplied notification channel is configured as
+// desired. The order of events and recipients is not significant.
+func upToDate(want, got stormforge.NotificationChannel) bool {
+	return want.Name == got.Name &&
+		want.Type == got.Type &&
+		want.URL == got.URL &&
+		want.SlackChannel == got.SlackChannel &&
+		sameSet(want.Events, got.Events) &&
+		sameSet(want.Recipients, got.Recipients)
+}
+
+// sameSet returns true if the supplied slices contain the same strings,
+// regardless of order.
+func sameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sa := append([]string(nil), a...)
+	sb := append([]string(nil), b...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	for i := range sa {
+		if sa[i] != sb[i] {
+			return false
+		}
+	}
+	return true
+}
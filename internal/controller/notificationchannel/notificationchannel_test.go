@@ -0,0 +1,327 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notificationchannel
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+const hook = "https://hooks.slack.com/services/T0/B0/x"
+
+func channel(id string, o ...func(cr *v1alpha1.NotificationChannel)) *v1alpha1.NotificationChannel {
+	cr := &v1alpha1.NotificationChannel{Spec: v1alpha1.NotificationChannelSpec{ForProvider: v1alpha1.NotificationChannelParameters{
+		Org:    "acme",
+		Name:   "load-tests",
+		Events: []v1alpha1.NotificationEvent{v1alpha1.NotificationTestRunFinished, v1alpha1.NotificationTestRunFailed},
+		Slack: &v1alpha1.SlackNotification{
+			WebhookURLSecretRef: xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Name: "slack", Namespace: "default"}, Key: "url"},
+			Channel:             "#load",
+		},
+	}}}
+	meta.SetExternalName(cr, id)
+	for _, fn := range o {
+		fn(cr)
+	}
+	return cr
+}
+
+// slack returns the remote channel described by channel.
+func slack() stormforge.NotificationChannel {
+	return stormforge.NotificationChannel{
+		ID:           "n1",
+		Name:         "load-tests",
+		Scope:        "acme",
+		Type:         stormforge.NotificationChannelSlack,
+		Events:       []string{"test_run.failed", "test_run.finished"},
+		URL:          hook,
+		SlackChannel: "#load",
+	}
+}
+
+// secret returns a client that serves a Secret with the supplied URL.
+func secret(url string) client.Client {
+	return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.Secret).Data = map[string][]byte{"url": []byte(url + "\n")}
+		return nil
+	})}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	withURL := func(url string) stormforge.NotificationChannel {
+		nc := slack()
+		nc.URL = url
+		return nc
+	}
+
+	type want struct {
+		o            managed.ExternalObservation
+		externalName string
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		client *fake.Client
+		cr     *v1alpha1.NotificationChannel
+		want   want
+	}{
+		"NotCreated": {
+			reason: "A channel that does not exist by name should be reported as not existing.",
+			kube:   secret(hook),
+			client: &fake.Client{},
+			cr:     channel(""),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"NotFound": {
+			reason: "A channel that does not exist by ID should be reported as not existing.",
+			kube:   secret(hook),
+			client: &fake.Client{},
+			cr:     channel("n1"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}, externalName: "n1"},
+		},
+		"GetError": {
+			reason: "Errors getting the channel should be wrapped.",
+			kube:   secret(hook),
+			client: &fake.Client{Err: errBoom},
+			cr:     channel("n1"),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind), externalName: "n1"},
+		},
+		"Adopted": {
+			reason: "A channel that already exists by name should be adopted by recording its ID as the external name.",
+			kube:   secret(hook),
+			client: &fake.Client{NotificationChannels: map[string]stormforge.NotificationChannel{"n1": slack()}},
+			cr:     channel(""),
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: "n1",
+			},
+		},
+		"UpToDate": {
+			reason: "A channel configured as desired should be up to date regardless of the order of its events.",
+			kube:   secret(hook),
+			client: &fake.Client{NotificationChannels: map[string]stormforge.NotificationChannel{"n1": slack()}},
+			cr:     channel("n1"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, externalName: "n1"},
+		},
+		"URLChanged": {
+			reason: "A channel whose URL differs from the URL in its Secret should not be up to date.",
+			kube:   secret(hook),
+			client: &fake.Client{NotificationChannels: map[string]stormforge.NotificationChannel{"n1": withURL("https://hooks.slack.com/services/T0/B0/y")}},
+			cr:     channel("n1"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, externalName: "n1"},
+		},
+		"EventsChanged": {
+			reason: "A channel notified of different events should not be up to date.",
+			kube:   secret(hook),
+			client: &fake.Client{NotificationChannels: map[string]stormforge.NotificationChannel{"n1": slack()}},
+			cr: channel("n1", func(cr *v1alpha1.NotificationChannel) {
+				cr.Spec.ForProvider.Events = []v1alpha1.NotificationEvent{v1alpha1.NotificationTestRunFailed}
+			}),
+			want: want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, externalName: "n1"},
+		},
+		"TypeChanged": {
+			reason: "A channel changed from Slack to email should not be up to date.",
+			kube:   secret(hook),
+			client: &fake.Client{NotificationChannels: map[string]stormforge.NotificationChannel{"n1": slack()}},
+			cr: channel("n1", func(cr *v1alpha1.NotificationChannel) {
+				cr.Spec.ForProvider.Slack = nil
+				cr.Spec.ForProvider.Email = &v1alpha1.EmailNotification{Recipients: []string{"sre@example.org"}}
+			}),
+			want: want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, externalName: "n1"},
+		},
+		"OrgChanged": {
+			reason: "Changing the organization of a created channel should be rejected.",
+			kube:   secret(hook),
+			client: &fake.Client{NotificationChannels: map[string]stormforge.NotificationChannel{"n1": slack()}},
+			cr: channel("n1", func(cr *v1alpha1.NotificationChannel) {
+				cr.Spec.ForProvider.Org = "initech"
+				cr.Status.AtProvider.Org = "acme"
+			}),
+			want: want{err: errors.Errorf(errImmutableFmt, "acme", "initech"), externalName: "n1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: tc.kube, client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.cr)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type want struct {
+		channels map[string]stormforge.NotificationChannel
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		cr     *v1alpha1.NotificationChannel
+		want   want
+	}{
+		"Slack": {
+			reason: "A Slack channel should be created with the webhook URL read from its Secret.",
+			kube:   secret(hook),
+			cr:     channel(""),
+			want: want{channels: map[string]stormforge.NotificationChannel{"1": {
+				ID: "1", Name: "load-tests", Scope: "acme", Type: stormforge.NotificationChannelSlack,
+				Events: []string{"test_run.finished", "test_run.failed"}, URL: hook, SlackChannel: "#load",
+			}}},
+		},
+		"Email": {
+			reason: "An email channel should be created with its recipients.",
+			cr: channel("", func(cr *v1alpha1.NotificationChannel) {
+				cr.Spec.ForProvider.Slack = nil
+				cr.Spec.ForProvider.Email = &v1alpha1.EmailNotification{Recipients: []string{"sre@example.org"}}
+			}),
+			want: want{channels: map[string]stormforge.NotificationChannel{"1": {
+				ID: "1", Name: "load-tests", Scope: "acme", Type: stormforge.NotificationChannelEmail,
+				Events: []string{"test_run.finished", "test_run.failed"}, Recipients: []string{"sre@example.org"},
+			}}},
+		},
+		"Webhook": {
+			reason: "A webhook channel should be created with its URL.",
+			cr: channel("", func(cr *v1alpha1.NotificationChannel) {
+				cr.Spec.ForProvider.Slack = nil
+				cr.Spec.ForProvider.Webhook = &v1alpha1.WebhookNotification{URL: "https://ci.example.org/hooks/stormforge"}
+			}),
+			want: want{channels: map[string]stormforge.NotificationChannel{"1": {
+				ID: "1", Name: "load-tests", Scope: "acme", Type: stormforge.NotificationChannelWebhook,
+				Events: []string{"test_run.finished", "test_run.failed"}, URL: "https://ci.example.org/hooks/stormforge",
+			}}},
+		},
+		"NoWebhookURL": {
+			reason: "A webhook channel without a URL cannot be created.",
+			cr: channel("", func(cr *v1alpha1.NotificationChannel) {
+				cr.Spec.ForProvider.Slack = nil
+				cr.Spec.ForProvider.Webhook = &v1alpha1.WebhookNotification{}
+			}),
+			want: want{err: errors.Wrapf(errors.New(errNoWebhookURL), errs.CreateFmt, externalKind)},
+		},
+		"NoType": {
+			reason: "A channel that is neither email, Slack nor webhook cannot be created.",
+			cr:     channel("", func(cr *v1alpha1.NotificationChannel) { cr.Spec.ForProvider.Slack = nil }),
+			want:   want{err: errors.Wrapf(errors.New(errNoChannelType), errs.CreateFmt, externalKind)},
+		},
+		"GetSecretError": {
+			reason: "Errors getting the Secret of a Slack channel should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			cr:     channel(""),
+			want:   want{err: errors.Wrapf(errors.Wrap(errBoom, errGetSecret), errs.CreateFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fc := &fake.Client{}
+			e := external{kube: tc.kube, client: fc}
+			_, err := e.Create(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.channels, fc.NotificationChannels); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want channels, +got channels:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	old := slack()
+	old.URL = "https://hooks.slack.com/services/T0/B0/y"
+	fc := &fake.Client{NotificationChannels: map[string]stormforge.NotificationChannel{"n1": old}}
+
+	e := external{kube: secret(hook), client: fc}
+	if _, err := e.Update(context.Background(), channel("n1")); err != nil {
+		t.Fatalf("e.Update(...): unexpected error: %s", err)
+	}
+
+	want := slack()
+	want.Events = []string{"test_run.finished", "test_run.failed"}
+	if diff := cmp.Diff(want, fc.NotificationChannels["n1"]); diff != "" {
+		t.Errorf("e.Update(...): -want channel, +got channel:\n%s\n", diff)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   error
+	}{
+		"Deleted": {
+			reason: "The channel should be deleted.",
+			client: &fake.Client{NotificationChannels: map[string]stormforge.NotificationChannel{"n1": slack()}},
+		},
+		"NotFound": {
+			reason: "A channel that no longer exists should not be an error.",
+			client: &fake.Client{},
+		},
+		"DeleteError": {
+			reason: "Errors deleting the channel should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   errors.Wrapf(errBoom, errs.DeleteFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			err := e.Delete(context.Background(), channel("n1"))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if _, ok := tc.client.NotificationChannels["n1"]; ok && tc.want == nil {
+				t.Errorf("\n%s\ne.Delete(...): channel was not deleted", tc.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package organization
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func organization(id string) *v1alpha1.Organization {
+	cr := &v1alpha1.Organization{}
+	meta.SetExternalName(cr, id)
+	return cr
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	runs, hour := int64(2), time.Hour
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.OrganizationObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.Organization
+		want   want
+	}{
+		"Exists": {
+			reason: "The plan, members and limits of an existing organization should be reported.",
+			client: &fake.Client{Organizations: map[string]stormforge.Organization{
+				"acme": {ID: "acme", Name: "ACME", Plan: "enterprise", MemberCount: 12, Limits: stormforge.OrganizationLimits{ConcurrentTestRuns: &runs, TestDuration: &hour}},
+			}},
+			cr: organization("acme"),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.OrganizationObservation{
+					ID: "acme", Name: "ACME", Plan: "enterprise", MemberCount: 12,
+					Limits: v1alpha1.OrganizationLimits{ConcurrentTestRuns: &runs, TestDuration: &metav1.Duration{Duration: hour}},
+				},
+			},
+		},
+		"DoesNotExist": {
+			reason: "An organization that does not exist should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     organization("acme"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"GetError": {
+			reason: "Errors getting the organization should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     organization("acme"),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	e := external{client: &fake.Client{}}
+	_, err := e.Create(context.Background(), organization("acme"))
+	if diff := cmp.Diff(errors.New(errObserveOnly), err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Create(...): -want error, +got error:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package project contains a controller that manages StormForge projects.
+package project
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "project"
+
+const errImmutableFmt = "spec.forProvider.org is immutable: the project was created in %q, not %q; delete and recreate the Project instead"
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.ProjectKind)
+
+// Setup adds a controller that reconciles Project managed resources. The
+// supplied options configure the StormForge client used for each Project.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.ProjectGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ProjectGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		// The external name of a Project is the ID of its project.
+		managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.Project{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied Project.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Project); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{client: sf}), nil
+}
+
+// An ExternalClient observes, then either creates, updates, or deletes a
+// project.
+type external struct {
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Project)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	if o := cr.Status.AtProvider.Org; o != "" && o != cr.Spec.ForProvider.Org {
+		return managed.ExternalObservation{}, errors.Errorf(errImmutableFmt, o, cr.Spec.ForProvider.Org)
+	}
+
+	p, err := c.find(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+	if p == nil {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// A project that already exists in the organization is adopted rather
+	// than duplicated.
+	adopted := false
+	if meta.GetExternalName(cr) == "" {
+		meta.SetExternalName(cr, p.ID)
+		adopted = true
+	}
+
+	cr.Status.AtProvider = v1alpha1.ProjectObservation{ID: p.ID, Org: p.Scope}
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceUpToDate:        p.Name == cr.Spec.ForProvider.Name && p.Description == cr.Spec.ForProvider.Description,
+		ResourceLateInitialized: adopted,
+	}, nil
+}
+
+// find returns the project of the supplied managed resource, or nil if it does
+// not exist. The project is identified by its external name if one is set,
+// and otherwise by its name.
+func (c *external) find(ctx context.Context, cr *v1alpha1.Project) (*stormforge.Project, error) {
+	org := cr.Spec.ForProvider.Org
+	if id := meta.GetExternalName(cr); id != "" {
+		p, err := c.client.GetProject(ctx, org, id)
+		if stormforge.IsNotFound(err) {
+			return nil, nil
+		}
+		return p, err
+	}
+
+	ps, err := c.client.ListProjects(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ps {
+		if ps[i].Name == cr.Spec.ForProvider.Name {
+			return &ps[i], nil
+		}
+	}
+	return nil, nil
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.Project)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	p := cr.Spec.ForProvider
+	cr.SetConditions(xpv1.Creating())
+	created, err := c.client.CreateProject(ctx, p.Org, stormforge.Project{Name: p.Name, Description: p.Description})
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+
+	meta.SetExternalName(cr, created.ID)
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.Project)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	p := cr.Spec.ForProvider
+	_, err := c.client.UpdateProject(ctx, p.Org, meta.GetExternalName(cr), stormforge.Project{Name: p.Name, Description: p.Description})
+	return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+}
+
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.Project)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Deleting())
+	err := c.client.DeleteProject(ctx, cr.Spec.ForProvider.Org, meta.GetExternalName(cr))
+	return errors.Wrapf(resource.Ignore(stormforge.IsNotFound, err), errs.DeleteFmt, externalKind)
+}
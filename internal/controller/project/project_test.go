@@ -0,0 +1,192 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package project
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func project(id string, o ...func(cr *v1alpha1.Project)) *v1alpha1.Project {
+	cr := &v1alpha1.Project{Spec: v1alpha1.ProjectSpec{ForProvider: v1alpha1.ProjectParameters{
+		Org:         "acme",
+		Name:        "payments",
+		Description: "Load tests of the payments team.",
+	}}}
+	meta.SetExternalName(cr, id)
+	for _, fn := range o {
+		fn(cr)
+	}
+	return cr
+}
+
+// payments returns the remote project described by project.
+func payments() stormforge.Project {
+	return stormforge.Project{ID: "p1", Scope: "acme", Name: "payments", Description: "Load tests of the payments team."}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	type want struct {
+		o            managed.ExternalObservation
+		externalName string
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.Project
+		want   want
+	}{
+		"NotCreated": {
+			reason: "A project that does not exist by name should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     project(""),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"NotFound": {
+			reason: "A project that does not exist by ID should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     project("p1"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}, externalName: "p1"},
+		},
+		"GetError": {
+			reason: "Errors getting the project should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     project("p1"),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind), externalName: "p1"},
+		},
+		"Adopted": {
+			reason: "A project that already exists by name should be adopted by recording its ID as the external name.",
+			client: &fake.Client{Projects: map[string]stormforge.Project{"p1": payments()}},
+			cr:     project(""),
+			want: want{
+				o:            managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				externalName: "p1",
+			},
+		},
+		"UpToDate": {
+			reason: "A project with the desired name and description should be up to date.",
+			client: &fake.Client{Projects: map[string]stormforge.Project{"p1": payments()}},
+			cr:     project("p1"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, externalName: "p1"},
+		},
+		"DescriptionChanged": {
+			reason: "A project whose description differs should not be up to date.",
+			client: &fake.Client{Projects: map[string]stormforge.Project{"p1": payments()}},
+			cr:     project("p1", func(cr *v1alpha1.Project) { cr.Spec.ForProvider.Description = "" }),
+			want:   want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false}, externalName: "p1"},
+		},
+		"OrgChanged": {
+			reason: "Changing the organization of a created project should be rejected.",
+			client: &fake.Client{Projects: map[string]stormforge.Project{"p1": payments()}},
+			cr: project("p1", func(cr *v1alpha1.Project) {
+				cr.Spec.ForProvider.Org = "initech"
+				cr.Status.AtProvider.Org = "acme"
+			}),
+			want: want{err: errors.Errorf(errImmutableFmt, "acme", "initech"), externalName: "p1"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.cr)); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	fc := &fake.Client{}
+	e := external{client: fc}
+	cr := project("")
+	got, err := e.Create(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Create(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(managed.ExternalCreation{ExternalNameAssigned: true}, got); diff != "" {
+		t.Errorf("e.Create(...): -want, +got:\n%s\n", diff)
+	}
+	want := map[string]stormforge.Project{"1": {ID: "1", Scope: "acme", Name: "payments", Description: "Load tests of the payments team."}}
+	if diff := cmp.Diff(want, fc.Projects); diff != "" {
+		t.Errorf("e.Create(...): -want projects, +got projects:\n%s\n", diff)
+	}
+	if diff := cmp.Diff("1", meta.GetExternalName(cr)); diff != "" {
+		t.Errorf("e.Create(...): -want external name, +got external name:\n%s\n", diff)
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   error
+	}{
+		"Deleted": {
+			reason: "The project should be deleted.",
+			client: &fake.Client{Projects: map[string]stormforge.Project{"p1": payments()}},
+		},
+		"NotFound": {
+			reason: "A project that no longer exists should not be an error.",
+			client: &fake.Client{},
+		},
+		"DeleteError": {
+			reason: "Errors deleting the project should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   errors.Wrapf(errBoom, errs.DeleteFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			err := e.Delete(context.Background(), project("p1"))
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if _, ok := tc.client.Projects["p1"]; ok && tc.want == nil {
+				t.Errorf("\n%s\ne.Delete(...): project was not deleted", tc.reason)
+			}
+		})
+	}
+}
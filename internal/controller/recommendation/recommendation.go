@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package recommendation contains a controller that observes StormForge
+// resource recommendations.
+package recommendation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "recommendation"
+
+const errObserveOnly = "recommendations cannot be created using Crossplane; StormForge has no recommendation for the workload yet"
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.RecommendationKind)
+
+// Setup adds a controller that reconciles Recommendation managed resources.
+// The supplied options configure the StormForge client used for each
+// Recommendation.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.RecommendationGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.RecommendationGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.Recommendation{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied Recommendation.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.Recommendation); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{client: sf}), nil
+}
+
+// An ExternalClient observes a recommendation. Recommendations are never
+// created, updated, or deleted.
+type external struct {
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.Recommendation)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	// A Recommendation is never deleted, so it may be finalized immediately.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	r, err := c.client.GetRecommendation(ctx, workload(cr.Spec.ForProvider.WorkloadReference))
+	if stormforge.IsNotFound(err) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	cr.Status.AtProvider = observation(r)
+	cr.SetConditions(xpv1.Available())
+
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+// workload returns the StormForge workload identified by the supplied
+// reference.
+func workload(ref v1alpha1.WorkloadReference) stormforge.Workload {
+	w := stormforge.Workload{Cluster: ref.Cluster, Namespace: ref.Namespace, Kind: ref.Kind, Name: ref.Name}
+	if w.Kind == "" {
+		w.Kind = "Deployment"
+	}
+	return w
+}
+
+// observation returns the observed state of the supplied recommendation.
+func observation(r *stormforge.Recommendation) v1alpha1.RecommendationObservation {
+	o := v1alpha1.RecommendationObservation{}
+	if r.GeneratedAt != nil {
+		t := metav1.NewTime(*r.GeneratedAt)
+		o.GeneratedAt = &t
+	}
+	for _, ct := range r.Containers {
+		o.Containers = append(o.Containers, v1alpha1.ContainerRecommendation{
+			Name:     ct.Name,
+			Requests: v1alpha1.RecommendedResources{CPU: ct.Requests.CPU, Memory: ct.Requests.Memory},
+			Limits:   v1alpha1.RecommendedResources{CPU: ct.Limits.CPU, Memory: ct.Limits.Memory},
+		})
+	}
+	return o
+}
+
+// Create returns an error; recommendations are generated by StormForge, not
+// by Crossplane.
+func (c *external) Create(_ context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if _, ok := mg.(*v1alpha1.Recommendation); !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+	return managed.ExternalCreation{}, errors.New(errObserveOnly)
+}
+
+// Update does nothing; a Recommendation has no configurable fields.
+func (c *external) Update(_ context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if _, ok := mg.(*v1alpha1.Recommendation); !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete does nothing; recommendations are never deleted.
+func (c *external) Delete(_ context.Context, mg resource.Managed) error {
+	if _, ok := mg.(*v1alpha1.Recommendation); !ok {
+		return errors.New(errNotMyType)
+	}
+	return nil
+}
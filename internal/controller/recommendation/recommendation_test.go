@@ -0,0 +1,116 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recommendation
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+var checkout = stormforge.Workload{Cluster: "prod", Namespace: "shop", Kind: "Deployment", Name: "checkout"}
+
+func recommendation() *v1alpha1.Recommendation {
+	return &v1alpha1.Recommendation{Spec: v1alpha1.RecommendationSpec{ForProvider: v1alpha1.RecommendationParameters{
+		WorkloadReference: v1alpha1.WorkloadReference{Cluster: "prod", Namespace: "shop", Name: "checkout"},
+	}}}
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	generated := time.Date(2020, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.RecommendationObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		want   want
+	}{
+		"Exists": {
+			reason: "The resources recommended for each container of the workload should be reported.",
+			client: &fake.Client{Recommendations: map[stormforge.Workload]stormforge.Recommendation{checkout: {
+				Workload:    checkout,
+				GeneratedAt: &generated,
+				Containers: []stormforge.ContainerRecommendation{
+					{Name: "app", Requests: stormforge.Resources{CPU: "250m", Memory: "512Mi"}, Limits: stormforge.Resources{Memory: "1Gi"}},
+				},
+			}}},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.RecommendationObservation{
+					GeneratedAt: &metav1.Time{Time: generated},
+					Containers: []v1alpha1.ContainerRecommendation{
+						{Name: "app", Requests: v1alpha1.RecommendedResources{CPU: "250m", Memory: "512Mi"}, Limits: v1alpha1.RecommendedResources{Memory: "1Gi"}},
+					},
+				},
+			},
+		},
+		"DoesNotExist": {
+			reason: "A workload without a recommendation should be reported as not existing.",
+			client: &fake.Client{},
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"GetError": {
+			reason: "Errors getting the recommendation should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := recommendation()
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	e := external{client: &fake.Client{}}
+	_, err := e.Create(context.Background(), recommendation())
+	if diff := cmp.Diff(errors.New(errObserveOnly), err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Create(...): -want error, +got error:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,384 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resultexport contains a controller that archives the artifacts of
+// StormForge test runs to object storage.
+package resultexport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/blob"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "result export"
+
+const (
+	errNoTestRun      = "one of testRun, testRunRef or testRunSelector must be set"
+	errGetTestRun     = "cannot get TestRun"
+	errGetCredentials = "cannot get credentials Secret"
+	errNoDestination  = "one of s3, gcs or azureBlob must be set"
+	errGetArtifactFmt = "cannot get %s of test run"
+	errUploadFmt      = "cannot upload %s of test run"
+	errSpoolFmt       = "cannot spool %s of test run"
+)
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.ResultExportKind)
+
+// artifacts are the StormForge artifacts of a test run by artifact.
+var artifacts = map[v1alpha1.ResultArtifact]string{
+	v1alpha1.ResultArtifactMetrics: stormforge.ArtifactMetrics,
+	v1alpha1.ResultArtifactLogs:    stormforge.ArtifactLogs,
+}
+
+// Setup adds a controller that reconciles ResultExport managed resources. The
+// supplied options configure the StormForge client used for each
+// ResultExport.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.ResultExportGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.ResultExportGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:   mgr.GetClient(),
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+		}),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.ResultExport{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube   client.Client
+	client *clients.Connector
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied ResultExport.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.ResultExport); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{kube: c.kube, client: sf}), nil
+}
+
+// An ExternalClient observes, then either exports or exports again the
+// artifacts of a test run.
+type external struct {
+	// A client used to read the TestRun and the credentials of an export.
+	kube client.Client
+
+	// A client used to connect to the StormForge API.
+	client stormforge.Client
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.ResultExport)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	// Exported artifacts are archives, so they are left in object storage.
+	if meta.WasDeleted(cr) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	run, err := c.testRun(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	// Artifacts are only complete once the run has ended, so the export is
+	// reported as being created until then.
+	if !ended(run) {
+		cr.SetConditions(xpv1.Creating())
+		return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+	}
+
+	o := cr.Status.AtProvider
+	ids := runIDs(run)
+	if o.TestRunID != strings.Join(ids, ",") || len(o.Artifacts) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	b, err := c.bucket(ctx, cr)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	cr.SetConditions(xpv1.Available())
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: upToDate(cr, b, ids)}, nil
+}
+
+// testRun returns the TestRun whose artifacts the supplied export exports.
+func (c *external) testRun(ctx context.Context, cr *v1alpha1.ResultExport) (*v1alpha1.TestRun, error) {
+	if cr.Spec.ForProvider.TestRun == "" {
+		return nil, errors.New(errNoTestRun)
+	}
+	run := &v1alpha1.TestRun{}
+	err := c.kube.Get(ctx, types.NamespacedName{Name: cr.Spec.ForProvider.TestRun}, run)
+	return run, errors.Wrap(err, errGetTestRun)
+}
+
+// ended returns true if the supplied run has been launched and has ended.
+func ended(run *v1alpha1.TestRun) bool {
+	if len(runIDs(run)) == 0 {
+		return false
+	}
+	switch run.Status.AtProvider.Phase {
+	case v1alpha1.TestRunSucceeded, v1alpha1.TestRunFailed, v1alpha1.TestRunAborted:
+		return true
+	default:
+		return false
+	}
+}
+
+// runIDs returns the IDs of the runs of the supplied TestRun: one per
+// environment it was launched against, if any, otherwise its only run.
+func runIDs(run *v1alpha1.TestRun) []string {
+	o := run.Status.AtProvider
+	if len(o.Environments) == 0 {
+		if o.ID == "" {
+			return nil
+		}
+		return []string{o.ID}
+	}
+	ids := make([]string, len(o.Environments))
+	for i, e := range o.Environments {
+		ids[i] = e.ID
+	}
+	return ids
+}
+
+// desired returns the artifacts the supplied export exports.
+func desired(cr *v1alpha1.ResultExport) []v1alpha1.ResultArtifact {
+	if a := cr.Spec.ForProvider.Artifacts; len(a) > 0 {
+		return a
+	}
+	return []v1alpha1.ResultArtifact{v1alpha1.ResultArtifactMetrics, v1alpha1.ResultArtifactLogs}
+}
+
+// key returns the key the supplied artifact of the supplied run is uploaded
+// under.
+func key(cr *v1alpha1.ResultExport, runID string, a v1alpha1.ResultArtifact) string {
+	return cr.Spec.ForProvider.Prefix + runID + "/" + artifacts[a]
+}
+
+// upToDate returns false if the desired artifacts of the supplied runs of the
+// supplied export have not all been exported to the supplied bucket, or if
+// other artifacts have.
+func upToDate(cr *v1alpha1.ResultExport, b blob.Bucket, runIDs []string) bool {
+	want := desired(cr)
+	got := cr.Status.AtProvider.Artifacts
+	if len(want)*len(runIDs) != len(got) {
+		return false
+	}
+	i := 0
+	for _, id := range runIDs {
+		for _, a := range want {
+			if got[i].Name != a || got[i].URL != b.URL(key(cr, id, a)) {
+				return false
+			}
+			i++
+		}
+	}
+	return true
+}
+
+// bucket returns the bucket the artifacts of the supplied export are uploaded
+// to, authenticated with the credentials read from its Secret.
+func (c *external) bucket(ctx context.Context, cr *v1alpha1.ResultExport) (blob.Bucket, error) {
+	p := cr.Spec.ForProvider
+	s := &corev1.Secret{}
+	ref := p.CredentialsSecretRef
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, errors.Wrap(err, errGetCredentials)
+	}
+	v := func(k string) string { return strings.TrimSpace(string(s.Data[k])) }
+	creds := blob.Credentials{
+		AccessKeyID:     v(v1alpha1.ResultExportAccessKeyIDKey),
+		SecretAccessKey: v(v1alpha1.ResultExportSecretAccessKeyKey),
+		SessionToken:    v(v1alpha1.ResultExportSessionTokenKey),
+	}
+
+	switch {
+	case p.S3 != nil:
+		o := []blob.Option{}
+		if p.S3.Endpoint != "" {
+			o = append(o, blob.WithEndpoint(p.S3.Endpoint))
+		}
+		return blob.NewS3(p.S3.Bucket, p.S3.Region, creds, o...), nil
+	case p.GCS != nil:
+		return blob.NewGCS(p.GCS.Bucket, creds), nil
+	case p.AzureBlob != nil:
+		o := []blob.Option{}
+		if p.AzureBlob.Endpoint != "" {
+			o = append(o, blob.WithEndpoint(p.AzureBlob.Endpoint))
+		}
+		return blob.NewAzureBlob(p.AzureBlob.Account, p.AzureBlob.Container, v(v1alpha1.ResultExportSASTokenKey), o...), nil
+	default:
+		return nil, errors.New(errNoDestination)
+	}
+}
+
+// export uploads the desired artifacts of the runs of the supplied export to
+// its bucket, and records them in its status.
+func (c *external) export(ctx context.Context, cr *v1alpha1.ResultExport) error {
+	run, err := c.testRun(ctx, cr)
+	if err != nil {
+		return err
+	}
+	b, err := c.bucket(ctx, cr)
+	if err != nil {
+		return err
+	}
+	ids := runIDs(run)
+	exported := make([]v1alpha1.ExportedArtifact, 0, len(ids)*len(desired(cr)))
+	for _, id := range ids {
+		for _, a := range desired(cr) {
+			e, err := c.exportArtifact(ctx, cr, b, id, a)
+			if err != nil {
+				return err
+			}
+			exported = append(exported, e)
+		}
+	}
+	now := metav1.Now()
+	cr.Status.AtProvider = v1alpha1.ResultExportObservation{TestRunID: strings.Join(ids, ","), Artifacts: exported, ExportedAt: &now}
+	return nil
+}
+
+// exportArtifact uploads the supplied artifact of the supplied run to the
+// supplied bucket, unless the export already records an upload of the same
+// content to the same URL. The artifact is spooled to a temporary file rather
+// than held in memory, so that it can be hashed and then streamed.
+func (c *external) exportArtifact(ctx context.Context, cr *v1alpha1.ResultExport, b blob.Bucket, runID string, a v1alpha1.ResultArtifact) (v1alpha1.ExportedArtifact, error) {
+	f, err := ioutil.TempFile("", "artifact-")
+	if err != nil {
+		return v1alpha1.ExportedArtifact{}, errors.Wrapf(err, errSpoolFmt, artifacts[a])
+	}
+	defer os.Remove(f.Name()) //nolint:errcheck
+	defer f.Close()           //nolint:errcheck
+
+	h := sha256.New()
+	if err := c.client.GetTestRunArtifact(ctx, runID, artifacts[a], io.MultiWriter(f, h)); err != nil {
+		return v1alpha1.ExportedArtifact{}, errors.Wrapf(err, errGetArtifactFmt, artifacts[a])
+	}
+	size, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return v1alpha1.ExportedArtifact{}, errors.Wrapf(err, errSpoolFmt, artifacts[a])
+	}
+
+	k := key(cr, runID, a)
+	e := v1alpha1.ExportedArtifact{Name: a, URL: b.URL(k), Size: size, Digest: "sha256:" + hex.EncodeToString(h.Sum(nil))}
+	if wasExported(cr, e) {
+		return e, nil
+	}
+	if err := b.Put(ctx, k, f); err != nil {
+		return v1alpha1.ExportedArtifact{}, errors.Wrapf(err, errUploadFmt, artifacts[a])
+	}
+	return e, nil
+}
+
+// wasExported returns true if the supplied export records the supplied
+// artifact as exported, with the same URL and digest.
+func wasExported(cr *v1alpha1.ResultExport, e v1alpha1.ExportedArtifact) bool {
+	for _, got := range cr.Status.AtProvider.Artifacts {
+		if got == e {
+			return true
+		}
+	}
+	return false
+}
+
+// Create exports the artifacts of the run. The external name of a
+// ResultExport is not used.
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.ResultExport)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	cr.SetConditions(xpv1.Creating())
+	return managed.ExternalCreation{}, errors.Wrapf(c.export(ctx, cr), errs.CreateFmt, externalKind)
+}
+
+// Update exports the artifacts of the run again, for example to a different
+// bucket. Artifacts already exported unchanged are not uploaded again.
+func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	cr, ok := mg.(*v1alpha1.ResultExport)
+	if !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+
+	return managed.ExternalUpdate{}, errors.Wrapf(c.export(ctx, cr), errs.UpdateFmt, externalKind)
+}
+
+// Delete does nothing; exported artifacts are left in object storage.
+func (c *external) Delete(_ context.Context, mg resource.Managed) error {
+	if _, ok := mg.(*v1alpha1.ResultExport); !ok {
+		return errors.New(errNotMyType)
+	}
+	return nil
+}
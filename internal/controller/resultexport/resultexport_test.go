@@ -0,0 +1,273 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package resultexport
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func export(o ...func(cr *v1alpha1.ResultExport)) *v1alpha1.ResultExport {
+	cr := &v1alpha1.ResultExport{Spec: v1alpha1.ResultExportSpec{ForProvider: v1alpha1.ResultExportParameters{
+		TestRun:              "nightly",
+		Prefix:               "load/",
+		S3:                   &v1alpha1.S3Destination{Bucket: "results", Region: "eu-west-1"},
+		CredentialsSecretRef: xpv1.SecretReference{Namespace: "default", Name: "results"},
+	}}}
+	for _, fn := range o {
+		fn(cr)
+	}
+	return cr
+}
+
+// exported records the metrics and logs of run r1 as exported to the bucket
+// of export.
+func exported(cr *v1alpha1.ResultExport) {
+	cr.Status.AtProvider = v1alpha1.ResultExportObservation{TestRunID: "r1", Artifacts: []v1alpha1.ExportedArtifact{
+		{Name: v1alpha1.ResultArtifactMetrics, URL: "s3://results/load/r1/metrics", Size: 8},
+		{Name: v1alpha1.ResultArtifactLogs, URL: "s3://results/load/r1/logs", Size: 8},
+	}}
+}
+
+// kube returns a client that serves a TestRun in the supplied phase and the
+// credentials Secret.
+func kube(phase v1alpha1.TestRunPhase) client.Client {
+	return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		switch o := obj.(type) {
+		case *v1alpha1.TestRun:
+			o.Status.AtProvider = v1alpha1.TestRunObservation{ID: "r1", Phase: phase}
+		case *corev1.Secret:
+			o.Data = map[string][]byte{
+				v1alpha1.ResultExportAccessKeyIDKey:     []byte("AKID"),
+				v1alpha1.ResultExportSecretAccessKeyKey: []byte("secret\n"),
+			}
+		}
+		return nil
+	})}
+}
+
+// digest returns the digest of the supplied artifact content.
+func digest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := errors.New("boom")
+	now := metav1.Now()
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		cr     *v1alpha1.ResultExport
+		want   managed.ExternalObservation
+		err    error
+	}{
+		"RunActive": {
+			reason: "An export of a run that is still active should wait for the run to end.",
+			kube:   kube(v1alpha1.TestRunRunning),
+			cr:     export(),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"NotExported": {
+			reason: "The artifacts of an ended run that have not been exported should be reported as not existing.",
+			kube:   kube(v1alpha1.TestRunSucceeded),
+			cr:     export(),
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"Exported": {
+			reason: "Artifacts exported to the desired bucket should be up to date.",
+			kube:   kube(v1alpha1.TestRunFailed),
+			cr:     export(exported),
+			want:   managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+		},
+		"BucketChanged": {
+			reason: "Artifacts exported to another bucket should not be up to date.",
+			kube:   kube(v1alpha1.TestRunSucceeded),
+			cr: export(exported, func(cr *v1alpha1.ResultExport) {
+				cr.Spec.ForProvider.S3 = nil
+				cr.Spec.ForProvider.GCS = &v1alpha1.GCSDestination{Bucket: "results"}
+			}),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+		"ArtifactsChanged": {
+			reason: "An export of fewer artifacts than were exported should not be up to date.",
+			kube:   kube(v1alpha1.TestRunSucceeded),
+			cr: export(exported, func(cr *v1alpha1.ResultExport) {
+				cr.Spec.ForProvider.Artifacts = []v1alpha1.ResultArtifact{v1alpha1.ResultArtifactMetrics}
+			}),
+			want: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+		},
+		"Deleted": {
+			reason: "A deleted export should be reported as not existing, leaving its artifacts in place.",
+			kube:   kube(v1alpha1.TestRunSucceeded),
+			cr:     export(exported, func(cr *v1alpha1.ResultExport) { cr.SetDeletionTimestamp(&now) }),
+			want:   managed.ExternalObservation{ResourceExists: false},
+		},
+		"GetTestRunError": {
+			reason: "Errors getting the TestRun should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			cr:     export(),
+			err:    errors.Wrapf(errors.Wrap(errBoom, errGetTestRun), errs.ObserveFmt, externalKind),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: tc.kube, client: &fake.Client{}}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	uploaded := map[string]string{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		uploaded[r.URL.Path] = string(b)
+	}))
+	defer srv.Close()
+
+	fc := &fake.Client{Artifacts: map[string]map[string][]byte{"r1": {
+		stormforge.ArtifactMetrics: []byte("metrics\n"),
+		stormforge.ArtifactLogs:    []byte("logs\n"),
+	}}}
+	withEndpoint := func(cr *v1alpha1.ResultExport) { cr.Spec.ForProvider.S3.Endpoint = srv.URL }
+
+	t.Run("Exported", func(t *testing.T) {
+		cr := export(withEndpoint)
+		e := external{kube: kube(v1alpha1.TestRunSucceeded), client: fc}
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %s", err)
+		}
+		want := map[string]string{"/results/load/r1/metrics": "metrics\n", "/results/load/r1/logs": "logs\n"}
+		if diff := cmp.Diff(want, uploaded); diff != "" {
+			t.Errorf("e.Create(...): -want uploads, +got uploads:\n%s\n", diff)
+		}
+		wantStatus := v1alpha1.ResultExportObservation{TestRunID: "r1", Artifacts: []v1alpha1.ExportedArtifact{
+			{Name: v1alpha1.ResultArtifactMetrics, URL: "s3://results/load/r1/metrics", Size: 8, Digest: digest("metrics\n")},
+			{Name: v1alpha1.ResultArtifactLogs, URL: "s3://results/load/r1/logs", Size: 5, Digest: digest("logs\n")},
+		}}
+		if diff := cmp.Diff(wantStatus, cr.Status.AtProvider, cmpopts.IgnoreFields(v1alpha1.ResultExportObservation{}, "ExportedAt")); diff != "" {
+			t.Errorf("e.Create(...): -want status, +got status:\n%s\n", diff)
+		}
+	})
+
+	t.Run("Unchanged", func(t *testing.T) {
+		for k := range uploaded {
+			delete(uploaded, k)
+		}
+		cr := export(withEndpoint, func(cr *v1alpha1.ResultExport) {
+			cr.Status.AtProvider = v1alpha1.ResultExportObservation{TestRunID: "r1", Artifacts: []v1alpha1.ExportedArtifact{
+				{Name: v1alpha1.ResultArtifactMetrics, URL: "s3://results/load/r1/metrics", Size: 8, Digest: digest("metrics\n")},
+				{Name: v1alpha1.ResultArtifactLogs, URL: "s3://results/load/r1/logs", Size: 8, Digest: digest("started\n")},
+			}}
+		})
+		e := external{kube: kube(v1alpha1.TestRunSucceeded), client: fc}
+		if _, err := e.Update(context.Background(), cr); err != nil {
+			t.Fatalf("e.Update(...): unexpected error: %s", err)
+		}
+		want := map[string]string{"/results/load/r1/logs": "logs\n"}
+		if diff := cmp.Diff(want, uploaded); diff != "" {
+			t.Errorf("e.Update(...): only artifacts whose digest changed should be uploaded again: -want uploads, +got uploads:\n%s\n", diff)
+		}
+		if got := cr.Status.AtProvider.Artifacts[1].Digest; got != digest("logs\n") {
+			t.Errorf("e.Update(...): want the digest of the uploaded logs, got %q", got)
+		}
+	})
+
+	t.Run("Environments", func(t *testing.T) {
+		for k := range uploaded {
+			delete(uploaded, k)
+		}
+		fc := &fake.Client{Artifacts: map[string]map[string][]byte{
+			"r1": {stormforge.ArtifactMetrics: []byte("staging\n")},
+			"r2": {stormforge.ArtifactMetrics: []byte("production\n")},
+		}}
+		kc := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			switch o := obj.(type) {
+			case *v1alpha1.TestRun:
+				o.Status.AtProvider = v1alpha1.TestRunObservation{Phase: v1alpha1.TestRunSucceeded, Environments: []v1alpha1.EnvironmentObservation{
+					{Name: "staging", ID: "r1", Phase: v1alpha1.TestRunSucceeded},
+					{Name: "production", ID: "r2", Phase: v1alpha1.TestRunSucceeded},
+				}}
+			case *corev1.Secret:
+				o.Data = map[string][]byte{v1alpha1.ResultExportAccessKeyIDKey: []byte("AKID")}
+			}
+			return nil
+		})}
+		cr := export(withEndpoint, func(cr *v1alpha1.ResultExport) {
+			cr.Spec.ForProvider.Artifacts = []v1alpha1.ResultArtifact{v1alpha1.ResultArtifactMetrics}
+		})
+		e := external{kube: kc, client: fc}
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %s", err)
+		}
+		want := map[string]string{"/results/load/r1/metrics": "staging\n", "/results/load/r2/metrics": "production\n"}
+		if diff := cmp.Diff(want, uploaded); diff != "" {
+			t.Errorf("e.Create(...): the artifacts of the run of each environment should be exported: -want uploads, +got uploads:\n%s\n", diff)
+		}
+		if got := cr.Status.AtProvider.TestRunID; got != "r1,r2" {
+			t.Errorf("e.Create(...): want TestRunID %q, got %q", "r1,r2", got)
+		}
+	})
+
+	t.Run("NoDestination", func(t *testing.T) {
+		cr := export(func(cr *v1alpha1.ResultExport) { cr.Spec.ForProvider.S3 = nil })
+		e := external{kube: kube(v1alpha1.TestRunSucceeded), client: fc}
+		_, err := e.Create(context.Background(), cr)
+		want := errors.Wrapf(errors.New(errNoDestination), errs.CreateFmt, externalKind)
+		if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+			t.Errorf("e.Create(...): -want error, +got error:\n%s\n", diff)
+		}
+	})
+
+	t.Run("MissingArtifact", func(t *testing.T) {
+		cr := export(withEndpoint)
+		e := external{kube: kube(v1alpha1.TestRunSucceeded), client: &fake.Client{}}
+		if _, err := e.Create(context.Background(), cr); err == nil {
+			t.Errorf("e.Create(...): want error for a run without artifacts")
+		}
+	})
+}
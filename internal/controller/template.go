@@ -22,18 +22,53 @@ import (
 
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
 
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/controller/apitoken"
+	"github.com/luebken/provider-stormforge/internal/controller/application"
 	"github.com/luebken/provider-stormforge/internal/controller/config"
+	"github.com/luebken/provider-stormforge/internal/controller/datasource"
+	"github.com/luebken/provider-stormforge/internal/controller/experiment"
+	"github.com/luebken/provider-stormforge/internal/controller/ipallowlist"
+	"github.com/luebken/provider-stormforge/internal/controller/liveworkload"
+	"github.com/luebken/provider-stormforge/internal/controller/notificationchannel"
+	"github.com/luebken/provider-stormforge/internal/controller/organization"
+	"github.com/luebken/provider-stormforge/internal/controller/project"
+	"github.com/luebken/provider-stormforge/internal/controller/recommendation"
+	"github.com/luebken/provider-stormforge/internal/controller/resultexport"
 	testcase "github.com/luebken/provider-stormforge/internal/controller/testcase"
+	"github.com/luebken/provider-stormforge/internal/controller/testrun"
+	"github.com/luebken/provider-stormforge/internal/controller/testrunschedule"
+	"github.com/luebken/provider-stormforge/internal/controller/trial"
 )
 
 // Setup creates all Template controllers with the supplied logger and adds them to
-// the supplied manager.
-func Setup(mgr ctrl.Manager, l logging.Logger, wl workqueue.RateLimiter) error {
-	for _, setup := range []func(ctrl.Manager, logging.Logger, workqueue.RateLimiter) error{
-		config.Setup,
-		testcase.Setup,
+// the supplied manager. The supplied TestCase options configure how TestCases
+// are reconciled, and the supplied options configure the StormForge clients
+// used by the managed resource controllers.
+func Setup(mgr ctrl.Manager, l logging.Logger, wl workqueue.RateLimiter, to testcase.Options, co ...stormforge.Option) error {
+	if err := config.Setup(mgr, l, wl); err != nil {
+		return err
+	}
+	if err := testcase.Setup(mgr, l, wl, to, co...); err != nil {
+		return err
+	}
+	for _, setup := range []func(ctrl.Manager, logging.Logger, workqueue.RateLimiter, ...stormforge.Option) error{
+		testrun.Setup,
+		testrunschedule.Setup,
+		organization.Setup,
+		datasource.Setup,
+		notificationchannel.Setup,
+		apitoken.Setup,
+		project.Setup,
+		resultexport.Setup,
+		ipallowlist.Setup,
+		experiment.Setup,
+		trial.Setup,
+		application.Setup,
+		recommendation.Setup,
+		liveworkload.Setup,
 	} {
-		if err := setup(mgr, l, wl); err != nil {
+		if err := setup(mgr, l, wl, co...); err != nil {
 			return err
 		}
 	}
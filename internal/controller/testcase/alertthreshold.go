@@ -0,0 +1,171 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/pkg/errors"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+const (
+	errDuplicateAlertThresholdFmt = "alert threshold %q is set more than once"
+	errListAlertThresholds        = "cannot list alert thresholds"
+	errCreateAlertThresholdFmt    = "cannot create alert threshold %q"
+	errUpdateAlertThresholdFmt    = "cannot update alert threshold %q"
+	errDeleteAlertThresholdFmt    = "cannot delete alert threshold %q"
+)
+
+// alertMetrics are the StormForge metrics of each alert metric.
+var alertMetrics = map[v1alpha1.AlertMetric]string{
+	v1alpha1.AlertMetricLatencyP50: "latency_p50",
+	v1alpha1.AlertMetricLatencyP95: "latency_p95",
+	v1alpha1.AlertMetricLatencyP99: "latency_p99",
+	v1alpha1.AlertMetricErrorRate:  "error_rate",
+	v1alpha1.AlertMetricApdex:      "apdex",
+}
+
+// alertThresholds returns the desired alert thresholds of the supplied test
+// case in StormForge by name.
+func alertThresholds(p v1alpha1.TestCaseParameters) (map[string]stormforge.AlertThreshold, error) {
+	ts := make(map[string]stormforge.AlertThreshold, len(p.AlertThresholds))
+	for _, t := range p.AlertThresholds {
+		if _, ok := ts[t.Name]; ok {
+			return nil, errors.Errorf(errDuplicateAlertThresholdFmt, t.Name)
+		}
+		op := "gt"
+		if t.Operator == v1alpha1.AlertBelow {
+			op = "lt"
+		}
+		ts[t.Name] = stormforge.AlertThreshold{Name: t.Name, Metric: alertMetrics[t.Metric], Operator: op, Value: t.Value}
+	}
+	return ts, nil
+}
+
+// managesAlertThresholds returns true if the alert thresholds of the supplied
+// test case are reconciled: if it sets any, or had any when last observed, so
+// that removing the last of them removes it from StormForge too. Test cases
+// that never set any are left alone, which saves listing them on every poll.
+func managesAlertThresholds(cr *v1alpha1.TestCase) bool {
+	return len(cr.Spec.ForProvider.AlertThresholds) > 0 || len(cr.Status.AtProvider.AlertThresholds) > 0
+}
+
+// alertThresholdsUpToDate returns false if the alert thresholds of the test
+// case with the supplied ID differ from those of the supplied test case. The
+// thresholds are recorded in its status.
+func (c *external) alertThresholdsUpToDate(ctx context.Context, cr *v1alpha1.TestCase, id string) (bool, error) {
+	if !managesAlertThresholds(cr) {
+		return true, nil
+	}
+	want, err := alertThresholds(cr.Spec.ForProvider)
+	if err != nil {
+		return false, err
+	}
+	have, err := c.client.ListAlertThresholds(ctx, id)
+	if err != nil {
+		return false, errors.Wrap(err, errListAlertThresholds)
+	}
+	observeAlertThresholds(cr, have)
+	if len(have) != len(want) {
+		return false, nil
+	}
+	for _, t := range have {
+		if w, ok := want[t.Name]; !ok || !sameAlertThreshold(w, t) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// syncAlertThresholds creates, updates and deletes the alert thresholds of the
+// test case with the supplied ID to match those of the supplied test case,
+// and records them in its status.
+func (c *external) syncAlertThresholds(ctx context.Context, cr *v1alpha1.TestCase, id string) error {
+	if !managesAlertThresholds(cr) {
+		return nil
+	}
+	want, err := alertThresholds(cr.Spec.ForProvider)
+	if err != nil {
+		return err
+	}
+	have, err := c.client.ListAlertThresholds(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, errListAlertThresholds)
+	}
+
+	synced := make([]stormforge.AlertThreshold, 0, len(want))
+	for _, t := range have {
+		w, ok := want[t.Name]
+		switch {
+		case !ok:
+			if err := c.client.DeleteAlertThreshold(ctx, id, t.ID); err != nil {
+				return errors.Wrapf(err, errDeleteAlertThresholdFmt, t.Name)
+			}
+			continue
+		case !sameAlertThreshold(w, t):
+			u, err := c.client.UpdateAlertThreshold(ctx, id, t.ID, w)
+			if err != nil {
+				return errors.Wrapf(err, errUpdateAlertThresholdFmt, t.Name)
+			}
+			t = *u
+		}
+		// Any other threshold of the same name is a duplicate to delete.
+		delete(want, t.Name)
+		synced = append(synced, t)
+	}
+	for _, t := range cr.Spec.ForProvider.AlertThresholds {
+		w, ok := want[t.Name]
+		if !ok {
+			continue
+		}
+		created, err := c.client.CreateAlertThreshold(ctx, id, w)
+		if err != nil {
+			return errors.Wrapf(err, errCreateAlertThresholdFmt, t.Name)
+		}
+		synced = append(synced, *created)
+	}
+	observeAlertThresholds(cr, synced)
+	return nil
+}
+
+// observeAlertThresholds records the supplied alert thresholds in the status
+// of the supplied test case.
+func observeAlertThresholds(cr *v1alpha1.TestCase, ts []stormforge.AlertThreshold) {
+	cr.Status.AtProvider.AlertThresholds = nil
+	for _, t := range ts {
+		cr.Status.AtProvider.AlertThresholds = append(cr.Status.AtProvider.AlertThresholds, v1alpha1.TestCaseAlertThresholdObservation{Name: t.Name, ID: t.ID})
+	}
+}
+
+// sameAlertThreshold returns true if the supplied alert thresholds alert on
+// the same condition. Values are compared numerically, so that for example
+// 0.5 and 0.50 are the same.
+func sameAlertThreshold(a, b stormforge.AlertThreshold) bool {
+	if a.Metric != b.Metric || a.Operator != b.Operator {
+		return false
+	}
+	x, errA := strconv.ParseFloat(a.Value, 64)
+	y, errB := strconv.ParseFloat(b.Value, 64)
+	if errA != nil || errB != nil {
+		return a.Value == b.Value
+	}
+	return x == y
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+)
+
+func TestAlertThresholds(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	slow := v1alpha1.AlertThreshold{Name: "slow", Metric: v1alpha1.AlertMetricLatencyP95, Operator: v1alpha1.AlertAbove, Value: "250"}
+	failing := v1alpha1.AlertThreshold{Name: "failing", Metric: v1alpha1.AlertMetricErrorRate, Operator: v1alpha1.AlertAbove, Value: "0.5"}
+	withThresholds := func(observed ...string) *v1alpha1.TestCase {
+		cr := testCase("acme", "checkout")
+		cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+		for _, n := range observed {
+			cr.Status.AtProvider.AlertThresholds = append(cr.Status.AtProvider.AlertThresholds, v1alpha1.TestCaseAlertThresholdObservation{Name: n})
+		}
+		return cr
+	}
+	remote := func(ts ...stormforge.AlertThreshold) *fake.Client {
+		return &fake.Client{
+			TestCases:       map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+			Scripts:         map[string][]byte{"1": []byte(script)},
+			AlertThresholds: map[string][]stormforge.AlertThreshold{"1": ts},
+		}
+	}
+
+	type want struct {
+		drift bool
+		have  []stormforge.AlertThreshold
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.TestCase
+		spec   []v1alpha1.AlertThreshold
+		want   want
+	}{
+		"Add": {
+			reason: "Alert thresholds missing from StormForge should be created.",
+			client: remote(stormforge.AlertThreshold{ID: "t1", Name: "slow", Metric: "latency_p95", Operator: "gt", Value: "250"}),
+			cr:     withThresholds(),
+			spec:   []v1alpha1.AlertThreshold{slow, failing},
+			want: want{drift: true, have: []stormforge.AlertThreshold{
+				{ID: "t1", Name: "slow", Metric: "latency_p95", Operator: "gt", Value: "250"},
+				{ID: "1", Name: "failing", Metric: "error_rate", Operator: "gt", Value: "0.5"},
+			}},
+		},
+		"Update": {
+			reason: "Alert thresholds that differ from the desired ones should be updated.",
+			client: remote(stormforge.AlertThreshold{ID: "t1", Name: "slow", Metric: "latency_p95", Operator: "gt", Value: "500"}),
+			cr:     withThresholds(),
+			spec:   []v1alpha1.AlertThreshold{slow},
+			want: want{drift: true, have: []stormforge.AlertThreshold{
+				{ID: "t1", Name: "slow", Metric: "latency_p95", Operator: "gt", Value: "250"},
+			}},
+		},
+		"Remove": {
+			reason: "Alert thresholds that are no longer desired should be deleted, including the last of them.",
+			client: remote(stormforge.AlertThreshold{ID: "t1", Name: "slow", Metric: "latency_p95", Operator: "gt", Value: "250"}),
+			cr:     withThresholds("slow"),
+			want:   want{drift: true, have: []stormforge.AlertThreshold{}},
+		},
+		"Unchanged": {
+			reason: "Alert thresholds whose values are numerically the same should be up to date.",
+			client: remote(stormforge.AlertThreshold{ID: "t1", Name: "failing", Metric: "error_rate", Operator: "gt", Value: "0.50"}),
+			cr:     withThresholds(),
+			spec:   []v1alpha1.AlertThreshold{failing},
+			want: want{have: []stormforge.AlertThreshold{
+				{ID: "t1", Name: "failing", Metric: "error_rate", Operator: "gt", Value: "0.50"},
+			}},
+		},
+		"Unmanaged": {
+			reason: "The alert thresholds of a test case that never set any should be left alone.",
+			client: remote(stormforge.AlertThreshold{ID: "t1", Name: "slow", Metric: "latency_p95", Operator: "gt", Value: "250"}),
+			cr:     withThresholds(),
+			want: want{have: []stormforge.AlertThreshold{
+				{ID: "t1", Name: "slow", Metric: "latency_p95", Operator: "gt", Value: "250"},
+			}},
+		},
+		"Duplicate": {
+			reason: "Alert thresholds may not share a name.",
+			client: remote(),
+			cr:     withThresholds(),
+			spec:   []v1alpha1.AlertThreshold{slow, slow},
+			want:   want{err: errors.Errorf(errDuplicateAlertThresholdFmt, "slow")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.cr.Spec.ForProvider.AlertThresholds = tc.spec
+			e := external{client: tc.client, record: event.NewNopRecorder()}
+			remote := tc.client.TestCases["1"]
+			d, err := e.diff(context.Background(), tc.cr, &remote)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.diff(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			drift := false
+			for _, f := range d {
+				drift = drift || f == "alertThresholds"
+			}
+			if drift != tc.want.drift {
+				t.Errorf("\n%s\ne.diff(...): want alert threshold drift %t, got %v", tc.reason, tc.want.drift, d)
+			}
+			if _, err := e.Update(context.Background(), withExternalName(tc.cr, "1")); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.have, tc.client.AlertThresholds["1"]); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want alert thresholds, +got:\n%s\n", tc.reason, diff)
+			}
+			d, err = e.diff(context.Background(), tc.cr, &remote)
+			if err != nil {
+				t.Fatalf("\n%s\ne.diff(...): unexpected error: %s", tc.reason, err)
+			}
+			for _, f := range d {
+				if f == "alertThresholds" {
+					t.Errorf("\n%s\ne.diff(...): alert thresholds should be up to date after e.Update(...)", tc.reason)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+const (
+	errGetCloneSource         = "cannot get TestCase to clone"
+	errCloneSourceNotReadyFmt = "TestCase %q to clone has not been created in StormForge yet"
+	errCloneSourceNotFoundFmt = "test case %q to clone does not exist"
+	errGetCloneSourceTestCase = "cannot get test case to clone"
+)
+
+// cloning returns true if the supplied test case is cloned from another one
+// rather than defined by a script or scenario.
+func cloning(p v1alpha1.TestCaseParameters) bool {
+	return p.CloneFrom != "" && p.Script == nil && p.Scenario == nil
+}
+
+// cloneSource returns the TestCase the supplied test case is cloned from. The
+// TestCase must have been created in StormForge.
+func (c *external) cloneSource(ctx context.Context, cr *v1alpha1.TestCase) (*v1alpha1.TestCase, error) {
+	src := &v1alpha1.TestCase{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.Spec.ForProvider.CloneFrom}, src); err != nil {
+		return nil, errors.Wrap(err, errGetCloneSource)
+	}
+	if src.Status.AtProvider.ID == "" {
+		return nil, errors.Errorf(errCloneSourceNotReadyFmt, src.GetName())
+	}
+	return src, nil
+}
+
+// cloneDefinition returns the definition of the supplied cloned test case.
+// Until the test case is created it is the definition of the test case it is
+// cloned from; afterwards it is its own, so that later changes to the source
+// are not promoted again.
+func (c *external) cloneDefinition(ctx context.Context, cr *v1alpha1.TestCase) ([]byte, error) {
+	id := cr.Status.AtProvider.ID
+	if id == "" {
+		src, err := c.cloneSource(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+		id = src.Status.AtProvider.ID
+		ctx = stormforge.WithOrg(ctx, src.Spec.ForProvider.Org)
+	}
+	b, err := c.client.GetDefinition(ctx, id)
+	return b, errors.Wrap(err, errGetDefinition)
+}
+
+// cloneMetadata returns the options copying the labels and notes of the test
+// case the supplied one is cloned from, unless the supplied test case sets
+// its own.
+func (c *external) cloneMetadata(ctx context.Context, cr *v1alpha1.TestCase) ([]stormforge.TestCaseOption, error) {
+	src, err := c.cloneSource(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	tc, err := c.getTestCase(stormforge.WithOrg(ctx, src.Spec.ForProvider.Org), src.Spec.ForProvider.Org, src.Status.AtProvider.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetCloneSourceTestCase)
+	}
+	if tc == nil {
+		return nil, errors.Errorf(errCloneSourceNotFoundFmt, src.Status.AtProvider.ID)
+	}
+	var opts []stormforge.TestCaseOption
+	if cr.Spec.ForProvider.Labels == nil {
+		opts = append(opts, stormforge.WithLabels(tc.Labels))
+	}
+	if cr.Spec.ForProvider.Notes == "" {
+		opts = append(opts, stormforge.WithNotes(tc.Notes))
+	}
+	return opts, nil
+}
+
+// observeClone records the source and target of the supplied cloned test case
+// in its status the first time it is observed. Nothing is recorded if the
+// TestCase it was cloned from has been deleted since.
+func (c *external) observeClone(ctx context.Context, cr *v1alpha1.TestCase, tc *stormforge.TestCase) error {
+	if !cloning(cr.Spec.ForProvider) || cr.Status.AtProvider.Clone != nil {
+		return nil
+	}
+	src := &v1alpha1.TestCase{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.Spec.ForProvider.CloneFrom}, src); err != nil {
+		return errors.Wrap(resource.IgnoreNotFound(err), errGetCloneSource)
+	}
+	cr.Status.AtProvider.Clone = &v1alpha1.TestCaseClone{
+		Source:          src.GetName(),
+		SourceID:        src.Status.AtProvider.ID,
+		SourceOrg:       src.Status.AtProvider.Org,
+		SourceProjectID: src.Status.AtProvider.ProjectID,
+		TargetOrg:       tc.Scope,
+		TargetProjectID: tc.ProjectID,
+	}
+	return nil
+}
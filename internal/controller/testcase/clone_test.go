@@ -0,0 +1,158 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func TestClone(t *testing.T) {
+	script := []byte("definition.session(\"checkout\", function(session) {});\n")
+
+	// staging is the TestCase promoted to production.
+	staging := func(id string) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			src := obj.(*v1alpha1.TestCase)
+			src.SetName("checkout-staging")
+			src.Spec.ForProvider.Org = "staging"
+			src.Status.AtProvider.ID = id
+			src.Status.AtProvider.Org = "staging"
+			src.Status.AtProvider.ProjectID = "p1"
+			return nil
+		}
+	}
+	source := func() *fake.Client {
+		return &fake.Client{
+			TestCases: map[string]stormforge.TestCase{
+				"s1": {ID: "s1", Name: "checkout", Scope: "staging", ProjectID: "p1", Labels: map[string]string{"team": "payments"}, Notes: "Checkout flow"},
+			},
+			Scripts: map[string][]byte{"s1": script},
+		}
+	}
+	production := func() *v1alpha1.TestCase {
+		cr := testCase("production", "checkout")
+		cr.Spec.ForProvider.CloneFrom = "checkout-staging"
+		return cr
+	}
+
+	t.Run("Promoted", func(t *testing.T) {
+		fc := source()
+		e := external{kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, staging("s1"))}, client: fc, record: event.NewNopRecorder()}
+		cr := production()
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %s", err)
+		}
+		want := stormforge.TestCase{ID: "1", Name: "checkout", Scope: "production", Labels: map[string]string{"team": "payments"}, Notes: "Checkout flow"}
+		if diff := cmp.Diff(want, fc.TestCases["1"]); diff != "" {
+			t.Errorf("e.Create(...): -want test case, +got test case:\n%s\n", diff)
+		}
+		if diff := cmp.Diff(script, fc.Scripts["1"]); diff != "" {
+			t.Errorf("e.Create(...): -want script, +got script:\n%s\n", diff)
+		}
+
+		tc := fc.TestCases["1"]
+		if err := e.observeClone(context.Background(), cr, &tc); err != nil {
+			t.Fatalf("e.observeClone(...): unexpected error: %s", err)
+		}
+		wantClone := &v1alpha1.TestCaseClone{Source: "checkout-staging", SourceID: "s1", SourceOrg: "staging", SourceProjectID: "p1", TargetOrg: "production"}
+		if diff := cmp.Diff(wantClone, cr.Status.AtProvider.Clone); diff != "" {
+			t.Errorf("e.observeClone(...): -want clone, +got clone:\n%s\n", diff)
+		}
+	})
+
+	t.Run("SourceChangedAfterCreate", func(t *testing.T) {
+		fc := source()
+		e := external{kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, staging("s1"))}, client: fc, record: event.NewNopRecorder()}
+		cr := production()
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %s", err)
+		}
+		fc.Scripts["s1"] = []byte("definition.session(\"search\", function(session) {});\n")
+		got, err := e.cloneDefinition(context.Background(), cr)
+		if err != nil {
+			t.Fatalf("e.cloneDefinition(...): unexpected error: %s", err)
+		}
+		if diff := cmp.Diff(script, got); diff != "" {
+			t.Errorf("e.cloneDefinition(...): -want the definition of the clone, +got:\n%s\n", diff)
+		}
+	})
+
+	t.Run("OwnMetadata", func(t *testing.T) {
+		fc := source()
+		e := external{kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, staging("s1"))}, client: fc, record: event.NewNopRecorder()}
+		cr := production()
+		cr.Spec.ForProvider.Labels = map[string]string{"team": "sre"}
+		cr.Spec.ForProvider.Notes = "Production checkout"
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %s", err)
+		}
+		tc := fc.TestCases["1"]
+		if diff := cmp.Diff(cr.Spec.ForProvider.Labels, tc.Labels); diff != "" {
+			t.Errorf("e.Create(...): -want labels, +got labels:\n%s\n", diff)
+		}
+		if diff := cmp.Diff(cr.Spec.ForProvider.Notes, tc.Notes); diff != "" {
+			t.Errorf("e.Create(...): -want notes, +got notes:\n%s\n", diff)
+		}
+	})
+
+	t.Run("SourceNotReady", func(t *testing.T) {
+		fc := source()
+		e := external{kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, staging(""))}, client: fc, record: event.NewNopRecorder()}
+		want := errors.Wrapf(errors.Errorf(errCloneSourceNotReadyFmt, "checkout-staging"), errs.CreateFmt, externalKind)
+		_, err := e.Create(context.Background(), production())
+		if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+			t.Errorf("e.Create(...): -want error, +got error:\n%s\n", diff)
+		}
+		if len(fc.TestCases) != 1 {
+			t.Errorf("e.Create(...): want no test case created, got %d test cases", len(fc.TestCases))
+		}
+	})
+
+	t.Run("SourceDeleted", func(t *testing.T) {
+		e := external{kube: &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "checkout-staging"))}, client: source(), record: event.NewNopRecorder()}
+		cr := production()
+		if err := e.observeClone(context.Background(), cr, &stormforge.TestCase{ID: "1", Scope: "production"}); err != nil {
+			t.Fatalf("e.observeClone(...): unexpected error: %s", err)
+		}
+		if cr.Status.AtProvider.Clone != nil {
+			t.Errorf("e.observeClone(...): want no clone recorded, got %+v", cr.Status.AtProvider.Clone)
+		}
+	})
+
+	t.Run("ScriptTakesPrecedence", func(t *testing.T) {
+		cr := production()
+		cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{}
+		if cloning(cr.Spec.ForProvider) {
+			t.Errorf("cloning(...): want a TestCase with a script not to be cloned")
+		}
+	})
+}
@@ -0,0 +1,263 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+const (
+	errNoDataSource          = "data source has no source"
+	errReadDataSourceFmt     = "cannot read data source %q"
+	errListDataSources       = "cannot list data sources"
+	errUploadDataSourceFmt   = "cannot upload data source %q"
+	errDefaultDataSourceFmt  = "default data source %q is not one of the data sources of the test case"
+	errGetDataSourceFmt      = "cannot get DataSource %q"
+	errDataSourceNotReadyFmt = "waiting for DataSource %q to be ready"
+	errDataSourceOrgFmt      = "DataSource %q belongs to organization %q, not %q"
+)
+
+// dataSource returns the content of the supplied data source.
+func (c *external) dataSource(ctx context.Context, ds v1alpha1.TestCaseDataSource) ([]byte, error) {
+	switch {
+	case ds.ConfigMapRef != nil:
+		return c.configMapKey(ctx, ds.ConfigMapRef)
+	case ds.SecretRef != nil:
+		return c.secretKey(ctx, ds.SecretRef)
+	}
+	return nil, errors.New(errNoDataSource)
+}
+
+// dataSourcesUpToDate returns false if the content of any data source of the
+// supplied test case differs from the content last uploaded.
+func (c *external) dataSourcesUpToDate(ctx context.Context, cr *v1alpha1.TestCase) (bool, error) {
+	want, last := cr.Spec.ForProvider.DataSources, lastDataSources(cr)
+	if len(want) != len(last) {
+		return false, nil
+	}
+	for _, ds := range want {
+		b, err := c.dataSource(ctx, ds)
+		if err != nil {
+			return false, errors.Wrapf(err, errReadDataSourceFmt, ds.Name)
+		}
+		if o, ok := last[ds.Name]; !ok || o.Checksum != checksum(b) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// syncDataSources uploads each data source of the supplied test case whose
+// content differs from the content last uploaded, and records the uploaded
+// data sources in its status. Data sources that already exist in the
+// organization are updated rather than duplicated. Data sources removed from
+// the test case are left in place, as other test cases may use them.
+func (c *external) syncDataSources(ctx context.Context, cr *v1alpha1.TestCase) error {
+	last := lastDataSources(cr)
+	var existing map[string]string
+
+	obs := make([]v1alpha1.TestCaseDataSourceObservation, 0, len(cr.Spec.ForProvider.DataSources))
+	for _, ds := range cr.Spec.ForProvider.DataSources {
+		b, err := c.dataSource(ctx, ds)
+		if err != nil {
+			return errors.Wrapf(err, errReadDataSourceFmt, ds.Name)
+		}
+		sum := checksum(b)
+
+		o, ok := last[ds.Name]
+		if ok && o.Checksum == sum {
+			obs = append(obs, o)
+			continue
+		}
+		if !ok {
+			if existing == nil {
+				if existing, err = c.existingDataSources(ctx, cr.Spec.ForProvider.Org); err != nil {
+					return err
+				}
+			}
+			o.ID = existing[ds.Name]
+		}
+
+		var up *stormforge.DataSource
+		if o.ID == "" {
+			up, err = c.client.CreateDataSource(ctx, cr.Spec.ForProvider.Org, ds.Name, b)
+		} else {
+			up, err = c.client.UpdateDataSource(ctx, cr.Spec.ForProvider.Org, o.ID, ds.Name, b)
+		}
+		if err != nil {
+			return errors.Wrapf(err, errUploadDataSourceFmt, ds.Name)
+		}
+		obs = append(obs, v1alpha1.TestCaseDataSourceObservation{Name: ds.Name, ID: up.ID, Checksum: sum})
+	}
+
+	cr.Status.AtProvider.DataSources = nil
+	if len(obs) > 0 {
+		cr.Status.AtProvider.DataSources = obs
+	}
+	return nil
+}
+
+// defaultDataSource returns the ID of the default data source of the supplied
+// test case, or an empty ID if it has none. The default must be one of its
+// data sources; its ID is empty until it has been uploaded.
+func defaultDataSource(cr *v1alpha1.TestCase) (string, error) {
+	p := cr.Spec.ForProvider
+	if p.DefaultDataSource == "" {
+		return "", nil
+	}
+	if !declared(p, p.DefaultDataSource) {
+		return "", errors.Errorf(errDefaultDataSourceFmt, p.DefaultDataSource)
+	}
+	return lastDataSources(cr)[p.DefaultDataSource].ID, nil
+}
+
+// declared returns true if the supplied parameters declare a data source with
+// the supplied name.
+func declared(p v1alpha1.TestCaseParameters, name string) bool {
+	for _, ds := range p.DataSources {
+		if ds.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// dataSourceName returns the name of the data source with the supplied ID
+// last uploaded for the supplied test case, or the ID if it was not.
+func dataSourceName(cr *v1alpha1.TestCase, id string) string {
+	for _, o := range cr.Status.AtProvider.DataSources {
+		if o.ID == id {
+			return o.Name
+		}
+	}
+	return id
+}
+
+// referencedDataSources returns the IDs of the DataSources referenced by the
+// supplied test case, or an error if any of them does not exist yet, is not
+// Ready, or belongs to another organization.
+func (c *external) referencedDataSources(ctx context.Context, cr *v1alpha1.TestCase) ([]string, error) {
+	ids := make([]string, 0, len(cr.Spec.ForProvider.DataSourceRefs))
+	for _, ref := range cr.Spec.ForProvider.DataSourceRefs {
+		ds := &v1alpha1.DataSource{}
+		err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, ds)
+		if kerrors.IsNotFound(err) {
+			return nil, errors.Errorf(errDataSourceNotReadyFmt, ref.Name)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, errGetDataSourceFmt, ref.Name)
+		}
+		if org := ds.Spec.ForProvider.Org; org != cr.Spec.ForProvider.Org {
+			return nil, errors.Errorf(errDataSourceOrgFmt, ref.Name, org, cr.Spec.ForProvider.Org)
+		}
+		if ds.GetCondition(xpv1.TypeReady).Status != corev1.ConditionTrue || meta.GetExternalName(ds) == "" {
+			return nil, errors.Errorf(errDataSourceNotReadyFmt, ref.Name)
+		}
+		ids = append(ids, meta.GetExternalName(ds))
+	}
+	return ids, nil
+}
+
+// dataSourceRefs returns the options attaching the DataSources referenced by
+// the supplied test case to it, or no options if it references none. The
+// DataSources must be Ready.
+func (c *external) dataSourceRefs(ctx context.Context, cr *v1alpha1.TestCase) ([]stormforge.TestCaseOption, error) {
+	ids, err := c.referencedDataSources(ctx, cr)
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	return []stormforge.TestCaseOption{stormforge.WithDataSources(ids...)}, nil
+}
+
+// sameIDs returns true if the supplied IDs are the same, in any order.
+func sameIDs(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	n := make(map[string]int, len(a))
+	for _, id := range a {
+		n[id]++
+	}
+	for _, id := range b {
+		if n[id] == 0 {
+			return false
+		}
+		n[id]--
+	}
+	return true
+}
+
+// dependentTestCases returns a function that maps a DataSource to the
+// TestCases that reference it, so that those waiting for it are reconciled
+// as soon as it becomes Ready. TestCases that cannot be listed are left to
+// their next poll.
+func dependentTestCases(kube client.Reader) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		l := &v1alpha1.TestCaseList{}
+		if err := kube.List(context.Background(), l); err != nil {
+			return nil
+		}
+		reqs := []reconcile.Request{}
+		for _, tc := range l.Items {
+			for _, ref := range tc.Spec.ForProvider.DataSourceRefs {
+				if ref.Name == o.GetName() {
+					reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: tc.GetName()}})
+					break
+				}
+			}
+		}
+		return reqs
+	}
+}
+
+// existingDataSources returns the IDs of the data sources of the supplied
+// organization by name.
+func (c *external) existingDataSources(ctx context.Context, org string) (map[string]string, error) {
+	dss, err := c.client.ListDataSources(ctx, org)
+	if err != nil {
+		return nil, errors.Wrap(err, errListDataSources)
+	}
+	ids := make(map[string]string, len(dss))
+	for _, ds := range dss {
+		ids[ds.Name] = ds.ID
+	}
+	return ids, nil
+}
+
+// lastDataSources returns the data sources last uploaded for the supplied test
+// case by name.
+func lastDataSources(cr *v1alpha1.TestCase) map[string]v1alpha1.TestCaseDataSourceObservation {
+	last := make(map[string]v1alpha1.TestCaseDataSourceObservation, len(cr.Status.AtProvider.DataSources))
+	for _, o := range cr.Status.AtProvider.DataSources {
+		last[o.Name] = o
+	}
+	return last
+}
@@ -0,0 +1,456 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func TestSyncDataSources(t *testing.T) {
+	errBoom := errors.New("boom")
+	users := "user,password\nalice,secret\n"
+
+	kube := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.ConfigMap).Data = map[string]string{"users.csv": users}
+		return nil
+	})}
+	withUsers := func(last ...v1alpha1.TestCaseDataSourceObservation) *v1alpha1.TestCase {
+		cr := testCase("acme", "checkout")
+		cr.Spec.ForProvider.DataSources = []v1alpha1.TestCaseDataSource{{
+			Name:         "users.csv",
+			ConfigMapRef: &v1alpha1.ConfigMapKeySelector{Namespace: "default", Name: "fixtures", Key: "users.csv"},
+		}}
+		cr.Status.AtProvider.DataSources = last
+		return cr
+	}
+
+	type want struct {
+		obs     []v1alpha1.TestCaseDataSourceObservation
+		content map[string][]byte
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		client *fake.Client
+		cr     *v1alpha1.TestCase
+		want   want
+	}{
+		"Create": {
+			reason: "A data source that does not exist should be uploaded.",
+			kube:   kube,
+			client: &fake.Client{},
+			cr:     withUsers(),
+			want: want{
+				obs:     []v1alpha1.TestCaseDataSourceObservation{{Name: "users.csv", ID: "1", Checksum: checksum([]byte(users))}},
+				content: map[string][]byte{"1": []byte(users)},
+			},
+		},
+		"Adopt": {
+			reason: "A data source that exists in the organization should be updated rather than duplicated.",
+			kube:   kube,
+			client: &fake.Client{DataSources: map[string]stormforge.DataSource{"f1": {ID: "f1", Name: "users.csv", Scope: "acme"}}},
+			cr:     withUsers(),
+			want: want{
+				obs:     []v1alpha1.TestCaseDataSourceObservation{{Name: "users.csv", ID: "f1", Checksum: checksum([]byte(users))}},
+				content: map[string][]byte{"f1": []byte(users)},
+			},
+		},
+		"Unchanged": {
+			reason: "A data source whose content has not changed should not be uploaded again.",
+			kube:   kube,
+			client: &fake.Client{},
+			cr:     withUsers(v1alpha1.TestCaseDataSourceObservation{Name: "users.csv", ID: "f1", Checksum: checksum([]byte(users))}),
+			want: want{
+				obs: []v1alpha1.TestCaseDataSourceObservation{{Name: "users.csv", ID: "f1", Checksum: checksum([]byte(users))}},
+			},
+		},
+		"ReadError": {
+			reason: "Errors reading a data source should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			client: &fake.Client{},
+			cr:     withUsers(),
+			want:   want{err: errors.Wrapf(errors.Wrap(errBoom, errGetConfigMap), errReadDataSourceFmt, "users.csv")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: tc.kube, client: tc.client, record: event.NewNopRecorder()}
+			err := e.syncDataSources(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.syncDataSources(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.obs, tc.cr.Status.AtProvider.DataSources); diff != "" {
+				t.Errorf("\n%s\ne.syncDataSources(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.content, tc.client.DataSourceContent, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.syncDataSources(...): -want uploads, +got uploads:\n%s\n", tc.reason, diff)
+			}
+			upToDate, err := e.dataSourcesUpToDate(context.Background(), tc.cr)
+			if err != nil || !upToDate {
+				t.Errorf("\n%s\ne.dataSourcesUpToDate(...): want up to date after sync, got %t, %v", tc.reason, upToDate, err)
+			}
+		})
+	}
+}
+
+func TestDefaultDataSource(t *testing.T) {
+	users, orders := "user,password\nalice,secret\n", "order\n42\n"
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	kube := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.ConfigMap).Data = map[string]string{"users.csv": users, "orders.csv": orders}
+		return nil
+	})}
+	fixture := func(name string) v1alpha1.TestCaseDataSource {
+		return v1alpha1.TestCaseDataSource{Name: name, ConfigMapRef: &v1alpha1.ConfigMapKeySelector{Namespace: "default", Name: "fixtures", Key: name}}
+	}
+	withFixtures := func(def string) *v1alpha1.TestCase {
+		cr := testCase("acme", "checkout")
+		cr.Spec.ForProvider.DataSources = []v1alpha1.TestCaseDataSource{fixture("users.csv"), fixture("orders.csv")}
+		cr.Spec.ForProvider.DefaultDataSource = def
+		cr.Status.AtProvider.DataSources = []v1alpha1.TestCaseDataSourceObservation{
+			{Name: "users.csv", ID: "d1", Checksum: checksum([]byte(users))},
+			{Name: "orders.csv", ID: "d2", Checksum: checksum([]byte(orders))},
+		}
+		return cr
+	}
+	remote := func(def string) *fake.Client {
+		return &fake.Client{
+			TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme", DefaultDataSourceID: def}},
+			Scripts:   map[string][]byte{"1": []byte(script)},
+		}
+	}
+
+	type want struct {
+		drift bool
+		def   string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.TestCase
+		want   want
+	}{
+		"Set": {
+			reason: "A test case without a default data source should have the desired one marked default.",
+			client: remote(""),
+			cr:     withFixtures("orders.csv"),
+			want:   want{drift: true, def: "d2"},
+		},
+		"Change": {
+			reason: "A test case whose default data source differs from the desired one should have it changed.",
+			client: remote("d1"),
+			cr:     withFixtures("orders.csv"),
+			want:   want{drift: true, def: "d2"},
+		},
+		"Unchanged": {
+			reason: "A test case whose default data source is the desired one should be up to date.",
+			client: remote("d2"),
+			cr:     withFixtures("orders.csv"),
+			want:   want{def: "d2"},
+		},
+		"NoneAllowed": {
+			reason: "A test case without data sources cannot have a default, so a default set outside of Kubernetes should be cleared.",
+			client: remote("d9"),
+			cr:     testCase("acme", "checkout"),
+			want:   want{drift: true, def: ""},
+		},
+		"NotDeclared": {
+			reason: "A default data source that is not one of the data sources of the test case is invalid.",
+			client: remote(""),
+			cr:     withFixtures("missing.csv"),
+			want:   want{err: errors.Errorf(errDefaultDataSourceFmt, "missing.csv")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			tc.cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+			e := external{kube: kube, client: tc.client, record: event.NewNopRecorder()}
+			remote := tc.client.TestCases["1"]
+			d, err := e.diff(context.Background(), tc.cr, &remote)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.diff(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.err != nil {
+				return
+			}
+			drift := false
+			for _, f := range d {
+				drift = drift || f == "defaultDataSource"
+			}
+			if drift != tc.want.drift {
+				t.Errorf("\n%s\ne.diff(...): want default data source drift %t, got %v", tc.reason, tc.want.drift, d)
+			}
+			if _, err := e.Update(context.Background(), withExternalName(tc.cr, "1")); err != nil {
+				t.Fatalf("\n%s\ne.Update(...): unexpected error: %s", tc.reason, err)
+			}
+			if got := tc.client.TestCases["1"].DefaultDataSourceID; got != tc.want.def {
+				t.Errorf("\n%s\ne.Update(...): want default data source %q, got %q", tc.reason, tc.want.def, got)
+			}
+		})
+	}
+}
+
+func TestLateInitializeDefaultDataSource(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		remote string
+		want   string
+	}{
+		"Adopted": {
+			reason: "A default data source set in StormForge should be adopted if it is one of the data sources of the test case.",
+			remote: "d1",
+			want:   "users.csv",
+		},
+		"Foreign": {
+			reason: "A default data source that is not one of the data sources of the test case should not be adopted.",
+			remote: "d9",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testCase("acme", "checkout")
+			cr.Spec.ForProvider.DataSources = []v1alpha1.TestCaseDataSource{{Name: "users.csv"}}
+			cr.Status.AtProvider.DataSources = []v1alpha1.TestCaseDataSourceObservation{{Name: "users.csv", ID: "d1"}}
+			li := lateInitialize(cr, &stormforge.TestCase{DefaultDataSourceID: tc.remote})
+			if got := cr.Spec.ForProvider.DefaultDataSource; got != tc.want || li != (tc.want != "") {
+				t.Errorf("\n%s\nlateInitialize(...): want default data source %q, got %q (late initialized %t)", tc.reason, tc.want, got, li)
+			}
+		})
+	}
+}
+
+func TestCreateAwaitsDataSources(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	errBoom := errors.New("boom")
+	dataSource := func(org string, ready bool) func(obj client.Object) error {
+		return func(obj client.Object) error {
+			ds := obj.(*v1alpha1.DataSource)
+			ds.Spec.ForProvider.Org = org
+			if ready {
+				meta.SetExternalName(ds, "d1")
+				ds.SetConditions(xpv1.Available())
+				return nil
+			}
+			ds.SetConditions(xpv1.Creating())
+			return nil
+		}
+	}
+
+	type want struct {
+		created bool
+		message string
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		want   want
+	}{
+		"NotFound": {
+			reason: "A test case referencing a DataSource that does not exist yet should wait for it.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(kerrors.NewNotFound(schema.GroupResource{}, "users"))},
+			want: want{
+				message: fmt.Sprintf(errDataSourceNotReadyFmt, "users"),
+				err:     errors.Wrapf(errors.Errorf(errDataSourceNotReadyFmt, "users"), errs.CreateFmt, externalKind),
+			},
+		},
+		"NotReady": {
+			reason: "A test case referencing a DataSource that is not Ready should wait for it.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil, dataSource("acme", false))},
+			want: want{
+				message: fmt.Sprintf(errDataSourceNotReadyFmt, "users"),
+				err:     errors.Wrapf(errors.Errorf(errDataSourceNotReadyFmt, "users"), errs.CreateFmt, externalKind),
+			},
+		},
+		"OtherOrg": {
+			reason: "A test case referencing a DataSource of another organization should not be created.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil, dataSource("other", true))},
+			want: want{
+				message: fmt.Sprintf(errDataSourceOrgFmt, "users", "other", "acme"),
+				err:     errors.Wrapf(errors.Errorf(errDataSourceOrgFmt, "users", "other", "acme"), errs.CreateFmt, externalKind),
+			},
+		},
+		"GetError": {
+			reason: "Errors getting a referenced DataSource should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			want: want{
+				message: fmt.Sprintf(errGetDataSourceFmt, "users") + ": boom",
+				err:     errors.Wrapf(errors.Wrapf(errBoom, errGetDataSourceFmt, "users"), errs.CreateFmt, externalKind),
+			},
+		},
+		"Ready": {
+			reason: "A test case whose referenced DataSources are Ready should be created.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(nil, dataSource("acme", true))},
+			want:   want{created: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testCase("acme", "checkout")
+			cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+			cr.Spec.ForProvider.DataSourceRefs = []xpv1.Reference{{Name: "users"}}
+			sf := &fake.Client{}
+			e := external{kube: tc.kube, client: sf, record: event.NewNopRecorder()}
+			_, err := e.Create(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if created := len(sf.TestCases) > 0; created != tc.want.created {
+				t.Errorf("\n%s\ne.Create(...): want created %t, got %t", tc.reason, tc.want.created, created)
+			}
+			if tc.want.err == nil {
+				if diff := cmp.Diff([]string{"d1"}, sf.TestCases["1"].DataSourceIDs); diff != "" {
+					t.Errorf("\n%s\ne.Create(...): -want attached data sources, +got:\n%s\n", tc.reason, diff)
+				}
+				return
+			}
+			want := xpv1.Creating().WithMessage(tc.want.message)
+			if diff := cmp.Diff(want, cr.GetCondition(xpv1.TypeReady), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDataSourceRefsDiff(t *testing.T) {
+	kube := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		ds := obj.(*v1alpha1.DataSource)
+		ds.Spec.ForProvider.Org = "acme"
+		meta.SetExternalName(ds, "id-"+key.Name)
+		ds.SetConditions(xpv1.Available())
+		return nil
+	}}
+	withRefs := func(refs ...string) *v1alpha1.TestCase {
+		cr := testCase("acme", "checkout")
+		for _, r := range refs {
+			cr.Spec.ForProvider.DataSourceRefs = append(cr.Spec.ForProvider.DataSourceRefs, xpv1.Reference{Name: r})
+		}
+		return cr
+	}
+
+	cases := map[string]struct {
+		reason   string
+		cr       *v1alpha1.TestCase
+		attached []string
+		want     bool
+	}{
+		"Attached": {
+			reason:   "A test case with its referenced data sources attached should not differ.",
+			cr:       withRefs("users", "orders"),
+			attached: []string{"id-orders", "id-users"},
+		},
+		"Missing": {
+			reason:   "A test case missing a referenced data source should differ.",
+			cr:       withRefs("users", "orders"),
+			attached: []string{"id-users"},
+			want:     true,
+		},
+		"Extra": {
+			reason:   "A test case with data sources attached that it does not reference should differ.",
+			cr:       withRefs("users"),
+			attached: []string{"id-users", "id-orders"},
+			want:     true,
+		},
+		"NoRefs": {
+			reason:   "The data sources attached to a test case that references none should be left alone.",
+			cr:       withRefs(),
+			attached: []string{"id-users"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: kube, client: &fake.Client{}, record: event.NewNopRecorder()}
+			d, err := e.diff(context.Background(), tc.cr, &stormforge.TestCase{ID: "1", Name: "checkout", Scope: "acme", DataSourceIDs: tc.attached})
+			if err != nil {
+				t.Fatalf("\n%s\ne.diff(...): unexpected error: %s", tc.reason, err)
+			}
+			got := false
+			for _, f := range d {
+				got = got || f == "dataSourceRefs"
+			}
+			if got != tc.want {
+				t.Errorf("\n%s\ne.diff(...): want dataSourceRefs to differ %t, got %v", tc.reason, tc.want, d)
+			}
+		})
+	}
+}
+
+func TestDependentTestCases(t *testing.T) {
+	withRefs := func(name string, refs ...string) v1alpha1.TestCase {
+		cr := testCase("acme", name)
+		cr.SetName(name)
+		for _, r := range refs {
+			cr.Spec.ForProvider.DataSourceRefs = append(cr.Spec.ForProvider.DataSourceRefs, xpv1.Reference{Name: r})
+		}
+		return *cr
+	}
+	kube := &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		obj.(*v1alpha1.TestCaseList).Items = []v1alpha1.TestCase{
+			withRefs("checkout", "users", "orders"),
+			withRefs("search", "queries"),
+			withRefs("login"),
+			withRefs("cart", "users"),
+		}
+		return nil
+	})}
+
+	ds := &v1alpha1.DataSource{}
+	ds.SetName("users")
+	got := dependentTestCases(kube)(ds)
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "checkout"}},
+		{NamespacedName: types.NamespacedName{Name: "cart"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("dependentTestCases(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+const (
+	errNoEnvSource = "valueFrom has no source"
+	errReadEnvFmt  = "cannot read value of environment variable %q"
+)
+
+// withEnv returns the supplied definition preceded by the declaration of a
+// global env object holding the environment variables of the supplied test
+// case. The definition is returned unchanged if the test case has none.
+func (c *external) withEnv(ctx context.Context, cr *v1alpha1.TestCase, definition []byte) ([]byte, error) {
+	if len(cr.Spec.ForProvider.Env) == 0 {
+		return definition, nil
+	}
+	b := &bytes.Buffer{}
+	b.WriteString("// Environment variables of the test case.\nconst env = Object.freeze({\n")
+	for _, v := range cr.Spec.ForProvider.Env {
+		val, err := c.envValue(ctx, v)
+		if err != nil {
+			return nil, errors.Wrapf(err, errReadEnvFmt, v.Name)
+		}
+		// JSON strings are valid JavaScript string literals.
+		lit, _ := json.Marshal(val)
+		fmt.Fprintf(b, "  %s: %s,\n", v.Name, lit)
+	}
+	b.WriteString("});\n\n")
+	b.Write(definition)
+	return b.Bytes(), nil
+}
+
+// envValue returns the value of the supplied environment variable.
+func (c *external) envValue(ctx context.Context, v v1alpha1.EnvVar) (string, error) {
+	src := v.ValueFrom
+	switch {
+	case src == nil:
+		return v.Value, nil
+	case src.SecretKeyRef != nil:
+		b, err := c.secretKey(ctx, src.SecretKeyRef)
+		return string(b), err
+	case src.ConfigMapKeyRef != nil:
+		b, err := c.configMapKey(ctx, src.ConfigMapKeyRef)
+		return string(b), err
+	}
+	return "", errors.New(errNoEnvSource)
+}
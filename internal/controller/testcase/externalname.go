@@ -0,0 +1,173 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+// claimIndex indexes TestCases by the external names of the test cases they
+// claim, so that the claimant of a test case can be found without listing
+// every TestCase.
+const claimIndex = "testCaseClaims"
+
+const (
+	errIndexClaims        = "cannot index TestCases by the test cases they claim"
+	errListTestCases      = "cannot list TestCases"
+	errUpdateExternalName = "cannot update external name of TestCase"
+	errClaimedFmt         = "test case %s is already managed by TestCase %q"
+)
+
+// An externalNameInitializer sets the external name of a TestCase to the ID of
+// its test case once it is known, and to its org and name, as "org/name",
+// until then. It refuses to initialize a TestCase whose test case is already
+// claimed by another TestCase, so that two TestCases never manage, and fight
+// over, one test case.
+type externalNameInitializer struct {
+	kube client.Client
+}
+
+// Initialize the external name of the supplied TestCase. The external name is
+// left unset if the org of the TestCase is not yet known, for example because
+// its org reference has not been resolved. A deleted TestCase is left as is,
+// so that losing its claim never blocks its finalization.
+func (i *externalNameInitializer) Initialize(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.TestCase)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+	if meta.WasDeleted(cr) {
+		return nil
+	}
+	en := claim(cr)
+	if en == "" {
+		return nil
+	}
+	owner, err := i.claimant(ctx, cr, en)
+	if err != nil {
+		return err
+	}
+	if owner != "" {
+		return errors.Errorf(errClaimedFmt, en, owner)
+	}
+	if meta.GetExternalName(cr) == en {
+		return nil
+	}
+	meta.SetExternalName(cr, en)
+	return errors.Wrap(i.kube.Update(ctx, cr), errUpdateExternalName)
+}
+
+// claim returns the external name the supplied TestCase claims its test case
+// by: the ID of its test case if it is bound to or has observed one, and its
+// org and name otherwise.
+func claim(cr *v1alpha1.TestCase) string {
+	if id := externalName(cr); id != "" {
+		return id
+	}
+	if id := cr.Status.AtProvider.ID; id != "" {
+		return id
+	}
+	if p := cr.Spec.ForProvider; p.Org != "" {
+		return p.Org + "/" + p.Name
+	}
+	return ""
+}
+
+// claimant returns the name of the TestCase, other than the supplied one,
+// that claims the test case identified by the supplied external name, if any.
+// A TestCase bound to the ID of a test case takes precedence over one that
+// only claims it by its org and name; otherwise the oldest TestCase wins.
+// TestCases that may only observe their test case claim nothing.
+func (i *externalNameInitializer) claimant(ctx context.Context, cr *v1alpha1.TestCase, en string) (string, error) {
+	if policiesOf(cr).observeOnly() {
+		return "", nil
+	}
+	l := &v1alpha1.TestCaseList{}
+	if err := i.kube.List(ctx, l, client.MatchingFields{claimIndex: en}); err != nil {
+		return "", errors.Wrap(err, errListTestCases)
+	}
+	for j := range l.Items {
+		o := &l.Items[j]
+		if o.GetName() == cr.GetName() || policiesOf(o).observeOnly() || !claims(o, en) {
+			continue
+		}
+		bound, otherBound := !isOrgName(en), externalName(o) != ""
+		if (otherBound && !bound) || (otherBound == bound && older(o, cr)) {
+			return o.GetName(), nil
+		}
+	}
+	return "", nil
+}
+
+// claims returns true if the supplied TestCase claims the test case
+// identified by the supplied external name.
+func claims(cr *v1alpha1.TestCase, en string) bool {
+	for _, k := range claimKeys(cr) {
+		if k == en {
+			return true
+		}
+	}
+	return false
+}
+
+// claimKeys returns the external names of the test cases the supplied
+// TestCase claims: its external name and, once it has observed its test case,
+// the ID and org and name it observed.
+func claimKeys(o client.Object) []string {
+	cr, ok := o.(*v1alpha1.TestCase)
+	if !ok {
+		return nil
+	}
+	keys := []string{}
+	if en := meta.GetExternalName(cr); en != "" {
+		keys = append(keys, en)
+	}
+	if s := cr.Status.AtProvider; s.ID != "" {
+		keys = append(keys, s.ID, s.Org+"/"+s.Name)
+	}
+	return keys
+}
+
+// indexClaims indexes TestCases by the test cases they claim.
+func indexClaims(ctx context.Context, i client.FieldIndexer) error {
+	return errors.Wrap(i.IndexField(ctx, &v1alpha1.TestCase{}, claimIndex, claimKeys), errIndexClaims)
+}
+
+// isOrgName returns true if the supplied external name identifies a test case
+// by its org and name rather than its ID.
+func isOrgName(en string) bool {
+	return strings.Contains(en, "/")
+}
+
+// older returns true if a was created before b. TestCases created at the same
+// time are ordered by name.
+func older(a, b *v1alpha1.TestCase) bool {
+	ta, tb := a.GetCreationTimestamp(), b.GetCreationTimestamp()
+	if !ta.Equal(&tb) {
+		return ta.Before(&tb)
+	}
+	return a.GetName() < b.GetName()
+}
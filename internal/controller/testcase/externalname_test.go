@@ -0,0 +1,212 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+// named returns a test case for the supplied org and name with the supplied
+// managed resource name, created at the supplied time.
+func named(mrName, org, name string, created time.Time) *v1alpha1.TestCase {
+	cr := testCase(org, name)
+	cr.SetName(mrName)
+	cr.SetCreationTimestamp(metav1.NewTime(created))
+	return cr
+}
+
+func TestExternalNameInitializer(t *testing.T) {
+	errBoom := errors.New("boom")
+	earlier := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	later := earlier.Add(time.Minute)
+
+	bound := func(cr *v1alpha1.TestCase, id string) *v1alpha1.TestCase {
+		meta.SetExternalName(cr, id)
+		cr.Status.AtProvider.ID, cr.Status.AtProvider.Org, cr.Status.AtProvider.Name = id, cr.Spec.ForProvider.Org, cr.Spec.ForProvider.Name
+		return cr
+	}
+
+	type want struct {
+		externalName string
+		updated      bool
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason    string
+		cr        *v1alpha1.TestCase
+		others    []v1alpha1.TestCase
+		listErr   error
+		updateErr error
+		want      want
+	}{
+		"OrgName": {
+			reason: "A TestCase that is not bound to a test case should claim it by its org and name.",
+			cr:     named("checkout", "acme", "checkout", later),
+			want:   want{externalName: "acme/checkout", updated: true},
+		},
+		"ObservedID": {
+			reason: "A TestCase that has observed its test case should be bound to its ID.",
+			cr: func() *v1alpha1.TestCase {
+				cr := withExternalName(named("checkout", "acme", "checkout", later), "acme/checkout")
+				cr.Status.AtProvider.ID = "1"
+				return cr
+			}(),
+			want: want{externalName: "1", updated: true},
+		},
+		"LegacyName": {
+			reason: "An external name equal to the name of the TestCase should be replaced by its org and name.",
+			cr:     withExternalName(named("checkout", "acme", "checkout", later), "checkout"),
+			want:   want{externalName: "acme/checkout", updated: true},
+		},
+		"AlreadyBound": {
+			reason: "A TestCase that is already bound to an ID should not be updated.",
+			cr:     bound(named("checkout", "acme", "checkout", later), "1"),
+			want:   want{externalName: "1"},
+		},
+		"NoOrg": {
+			reason: "A TestCase whose org is not yet known should be left uninitialized.",
+			cr:     named("checkout", "", "checkout", later),
+		},
+		"ClaimedByBound": {
+			reason: "A test case bound to another TestCase should not be claimed by its org and name.",
+			cr:     named("checkout-copy", "acme", "checkout", earlier),
+			others: []v1alpha1.TestCase{*bound(named("checkout", "acme", "checkout", later), "1")},
+			want:   want{err: errors.Errorf(errClaimedFmt, "acme/checkout", "checkout")},
+		},
+		"ClaimedByOlder": {
+			reason: "A test case claimed by an older TestCase should not be claimed again.",
+			cr:     named("checkout-copy", "acme", "checkout", later),
+			others: []v1alpha1.TestCase{*withExternalName(named("checkout", "acme", "checkout", earlier), "acme/checkout")},
+			want:   want{err: errors.Errorf(errClaimedFmt, "acme/checkout", "checkout")},
+		},
+		"SameIDOlder": {
+			reason: "A test case bound to an older TestCase should not be bound to another.",
+			cr:     withExternalName(named("checkout-copy", "acme", "checkout", later), "1"),
+			others: []v1alpha1.TestCase{*bound(named("checkout", "acme", "checkout", earlier), "1")},
+			want:   want{externalName: "1", err: errors.Errorf(errClaimedFmt, "1", "checkout")},
+		},
+		"ClaimedByYounger": {
+			reason: "A claim by a younger TestCase should not prevent an older one from claiming its test case.",
+			cr:     named("checkout", "acme", "checkout", earlier),
+			others: []v1alpha1.TestCase{*withExternalName(named("checkout-copy", "acme", "checkout", later), "acme/checkout")},
+			want:   want{externalName: "acme/checkout", updated: true},
+		},
+		"OtherTestCase": {
+			reason: "TestCases that claim other test cases should not prevent a claim.",
+			cr:     named("checkout", "acme", "checkout", later),
+			others: []v1alpha1.TestCase{*bound(named("search", "acme", "search", earlier), "2")},
+			want:   want{externalName: "acme/checkout", updated: true},
+		},
+		"ObserveOnly": {
+			reason: "A TestCase that may only observe its test case should not prevent another from claiming it.",
+			cr:     named("checkout", "acme", "checkout", later),
+			others: []v1alpha1.TestCase{func() v1alpha1.TestCase {
+				cr := bound(named("checkout-observer", "acme", "checkout", earlier), "1")
+				cr.Spec.ManagementPolicies = []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve}
+				return *cr
+			}()},
+			want: want{externalName: "acme/checkout", updated: true},
+		},
+		"DeletedContested": {
+			reason: "A deleted TestCase that lost its claim should be left as is so that it can be finalized.",
+			cr: func() *v1alpha1.TestCase {
+				cr := withExternalName(named("checkout-copy", "acme", "checkout", later), "1")
+				now := metav1.Now()
+				cr.SetDeletionTimestamp(&now)
+				return cr
+			}(),
+			others: []v1alpha1.TestCase{*bound(named("checkout", "acme", "checkout", earlier), "1")},
+			want:   want{externalName: "1"},
+		},
+		"ObserveOnlyNotListed": {
+			reason: "A TestCase that may only observe its test case claims nothing, so TestCases should not be listed.",
+			cr: func() *v1alpha1.TestCase {
+				cr := named("checkout", "acme", "checkout", later)
+				cr.Spec.ManagementPolicies = []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve}
+				return cr
+			}(),
+			listErr: errors.New("TestCases should not be listed"),
+			want:    want{externalName: "acme/checkout", updated: true},
+		},
+		"ListError": {
+			reason:  "Errors listing TestCases should be wrapped.",
+			cr:      named("checkout", "acme", "checkout", later),
+			listErr: errBoom,
+			want:    want{err: errors.Wrap(errBoom, errListTestCases)},
+		},
+		"UpdateError": {
+			reason:    "Errors updating the external name should be wrapped.",
+			cr:        named("checkout", "acme", "checkout", later),
+			updateErr: errBoom,
+			want:      want{externalName: "acme/checkout", updated: true, err: errors.Wrap(errBoom, errUpdateExternalName)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			updated := false
+			kube := &test.MockClient{
+				MockList: func(_ context.Context, obj client.ObjectList, opts ...client.ListOption) error {
+					if tc.listErr != nil {
+						return tc.listErr
+					}
+					lo := &client.ListOptions{}
+					lo.ApplyOptions(opts)
+					en, ok := lo.FieldSelector.RequiresExactMatch(claimIndex)
+					if !ok {
+						t.Errorf("\n%s\ni.Initialize(...): want TestCases listed by the %s index", tc.reason, claimIndex)
+					}
+					l := obj.(*v1alpha1.TestCaseList)
+					for _, o := range append([]v1alpha1.TestCase{*tc.cr.DeepCopy()}, tc.others...) {
+						if claims(&o, en) {
+							l.Items = append(l.Items, o)
+						}
+					}
+					return nil
+				},
+				MockUpdate: test.NewMockUpdateFn(tc.updateErr, func(_ client.Object) error {
+					updated = true
+					return nil
+				}),
+			}
+			i := &externalNameInitializer{kube: kube}
+			err := i.Initialize(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ni.Initialize(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.cr)); diff != "" {
+				t.Errorf("\n%s\ni.Initialize(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+			if updated != tc.want.updated {
+				t.Errorf("\n%s\ni.Initialize(...): want updated %t, got %t", tc.reason, tc.want.updated, updated)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"time"
+
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+// Replication lag in the StormForge API may briefly hide a test case from a
+// get of its ID while the list of its organization already shows it, for
+// example just after it was created.
+const (
+	lagRetries = 3
+	lagWait    = time.Second
+)
+
+// getTestCase returns the test case with the supplied ID, or nil if it does
+// not exist. A test case that cannot be got but is listed in the supplied
+// organization is got again a few times to wait out replication lag, and the
+// listed test case is returned if it still cannot be got, rather than
+// concluding that it is absent and creating it again.
+func (c *external) getTestCase(ctx context.Context, org, id string) (*stormforge.TestCase, error) {
+	tc, err := c.client.GetTestCase(ctx, id)
+	if !stormforge.IsNotFound(err) {
+		return tc, err
+	}
+	tcs, err := c.client.ListTestCases(ctx, org)
+	if err != nil {
+		return nil, err
+	}
+	var listed *stormforge.TestCase
+	for i := range tcs {
+		if tcs[i].ID == id {
+			listed = &tcs[i]
+		}
+	}
+	if listed == nil {
+		return nil, nil
+	}
+	for i := 0; i < lagRetries; i++ {
+		if err := c.pause(ctx, lagWait); err != nil {
+			return nil, err
+		}
+		tc, err := c.client.GetTestCase(ctx, id)
+		if !stormforge.IsNotFound(err) {
+			return tc, err
+		}
+	}
+	return listed, nil
+}
+
+// pause waits for the supplied duration, or until the supplied context is
+// done.
+func (c *external) pause(ctx context.Context, d time.Duration) error {
+	if c.wait != nil {
+		return c.wait(ctx, d)
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+)
+
+// laggingClient fails the first gets of test cases with a 404, like an API
+// that has not replicated them yet.
+type laggingClient struct {
+	*fake.Client
+	lag  int
+	gets int
+}
+
+func (c *laggingClient) GetTestCase(ctx context.Context, id string) (*stormforge.TestCase, error) {
+	c.gets++
+	if c.gets <= c.lag {
+		return nil, &stormforge.APIError{StatusCode: http.StatusNotFound}
+	}
+	return c.Client.GetTestCase(ctx, id)
+}
+
+func TestGetTestCaseLag(t *testing.T) {
+	checkout := stormforge.TestCase{ID: "1", Name: "checkout", Scope: "acme"}
+
+	type want struct {
+		tc     *stormforge.TestCase
+		gets   int
+		pauses int
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *laggingClient
+		want   want
+	}{
+		"Consistent": {
+			reason: "A test case that can be got should be returned without listing its organization.",
+			client: &laggingClient{Client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": checkout}}},
+			want:   want{tc: &checkout, gets: 1},
+		},
+		"Lagging": {
+			reason: "A test case that cannot be got but is listed should be got again until it can be.",
+			client: &laggingClient{Client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": checkout}}, lag: 2},
+			want:   want{tc: &checkout, gets: 3, pauses: 2},
+		},
+		"StillLagging": {
+			reason: "A test case that is listed but still cannot be got after retrying should be returned as listed rather than found absent.",
+			client: &laggingClient{Client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": checkout}}, lag: 1 + lagRetries},
+			want:   want{tc: &checkout, gets: 1 + lagRetries, pauses: lagRetries},
+		},
+		"Absent": {
+			reason: "A test case that neither can be got nor is listed should be found absent without retrying.",
+			client: &laggingClient{Client: &fake.Client{}},
+			want:   want{gets: 1},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			pauses := 0
+			e := external{client: tc.client, record: event.NewNopRecorder(), wait: func(context.Context, time.Duration) error {
+				pauses++
+				return nil
+			}}
+			got, err := e.getTestCase(context.Background(), "acme", "1")
+			if err != nil {
+				t.Fatalf("\n%s\ne.getTestCase(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.tc, got); diff != "" {
+				t.Errorf("\n%s\ne.getTestCase(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if tc.client.gets != tc.want.gets || pauses != tc.want.pauses {
+				t.Errorf("\n%s\ne.getTestCase(...): want %d gets and %d pauses, got %d and %d", tc.reason, tc.want.gets, tc.want.pauses, tc.client.gets, pauses)
+			}
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+)
+
+const errGetLastRun = "cannot get latest run of test case"
+
+// lastRun returns a summary of the supplied latest run of the supplied test
+// case, or nil if there is none or the test case does not observe it. The
+// results of a run that has ended are fetched unless they were listed with it
+// or were already reported, so they are fetched at most once per run.
+func (c *external) lastRun(ctx context.Context, cr *v1alpha1.TestCase, r *stormforge.TestRun) (*v1alpha1.TestCaseLastRun, error) {
+	if !cr.Spec.ForProvider.ObserveLastRun || r == nil {
+		return nil, nil
+	}
+	lr := &v1alpha1.TestCaseLastRun{
+		ID:        r.ID,
+		Phase:     runphase.Of(r.State),
+		StartedAt: metaTime(r.StartedAt),
+		EndedAt:   metaTime(r.EndedAt),
+		Result:    runphase.Result(r.Summary),
+	}
+	if lr.Result != nil || runphase.Active(lr.Phase) {
+		return lr, nil
+	}
+	if last := cr.Status.AtProvider.LastRun; last != nil && last.ID == r.ID && last.Result != nil {
+		lr.Result = last.Result
+		return lr, nil
+	}
+	full, err := c.client.GetTestRun(ctx, r.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetLastRun)
+	}
+	lr.Result = runphase.Result(full.Summary)
+	return lr, nil
+}
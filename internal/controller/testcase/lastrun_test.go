@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+)
+
+func TestLastRun(t *testing.T) {
+	started := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	ended := started.Add(10 * time.Minute)
+	summary := &stormforge.RunSummary{Requests: 1200, Errors: 3, LatencyP50: 12 * time.Millisecond, LatencyP95: 80 * time.Millisecond, LatencyP99: 250 * time.Millisecond}
+	result := runphase.Result(&stormforge.RunSummary{
+		Requests: 1200, Errors: 3, LatencyP50: 12 * time.Millisecond, LatencyP95: 80 * time.Millisecond, LatencyP99: 250 * time.Millisecond,
+	})
+	done := stormforge.TestRun{ID: "r1", TestCaseID: "1", State: "done", StartedAt: &started, EndedAt: &ended}
+	withSummary := done
+	withSummary.Summary = summary
+	ms, me := metav1.NewTime(started), metav1.NewTime(ended)
+	errBoom := errors.New("boom")
+
+	observing := func(last *v1alpha1.TestCaseLastRun) *v1alpha1.TestCase {
+		cr := testCase("acme", "checkout")
+		cr.Spec.ForProvider.ObserveLastRun = true
+		cr.Status.AtProvider.LastRun = last
+		return cr
+	}
+
+	type want struct {
+		lr  *v1alpha1.TestCaseLastRun
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.TestCase
+		run    *stormforge.TestRun
+		want   want
+	}{
+		"OptedOut": {
+			reason: "A test case that does not observe its latest run should not report it.",
+			client: &fake.Client{},
+			cr:     testCase("acme", "checkout"),
+			run:    &done,
+		},
+		"NoRuns": {
+			reason: "A test case without runs should not report a latest run.",
+			client: &fake.Client{},
+			cr:     observing(nil),
+		},
+		"Active": {
+			reason: "The results of a run that is still active should not be fetched.",
+			client: &fake.Client{},
+			cr:     observing(nil),
+			run:    &stormforge.TestRun{ID: "r2", TestCaseID: "1", State: "running", StartedAt: &started},
+			want:   want{lr: &v1alpha1.TestCaseLastRun{ID: "r2", Phase: v1alpha1.TestRunRunning, StartedAt: &ms}},
+		},
+		"Listed": {
+			reason: "The results of a run listed with them should not be fetched again.",
+			client: &fake.Client{},
+			cr:     observing(nil),
+			run:    &withSummary,
+			want:   want{lr: &v1alpha1.TestCaseLastRun{ID: "r1", Phase: v1alpha1.TestRunSucceeded, StartedAt: &ms, EndedAt: &me, Result: result}},
+		},
+		"Fetched": {
+			reason: "The results of a run that has ended should be fetched.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{"r1": withSummary}},
+			cr:     observing(&v1alpha1.TestCaseLastRun{ID: "r1", Phase: v1alpha1.TestRunRunning, StartedAt: &ms}),
+			run:    &done,
+			want:   want{lr: &v1alpha1.TestCaseLastRun{ID: "r1", Phase: v1alpha1.TestRunSucceeded, StartedAt: &ms, EndedAt: &me, Result: result}},
+		},
+		"Cached": {
+			reason: "The results of a run that were already reported should not be fetched again.",
+			client: &fake.Client{Err: errBoom},
+			cr:     observing(&v1alpha1.TestCaseLastRun{ID: "r1", Phase: v1alpha1.TestRunSucceeded, Result: result}),
+			run:    &done,
+			want:   want{lr: &v1alpha1.TestCaseLastRun{ID: "r1", Phase: v1alpha1.TestRunSucceeded, StartedAt: &ms, EndedAt: &me, Result: result}},
+		},
+		"GetError": {
+			reason: "Errors fetching the results of a run should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     observing(nil),
+			run:    &done,
+			want:   want{err: errors.Wrap(errBoom, errGetLastRun)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client, record: event.NewNopRecorder()}
+			got, err := e.lastRun(context.Background(), tc.cr, tc.run)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.lastRun(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.lr, got); diff != "" {
+				t.Errorf("\n%s\ne.lastRun(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+const (
+	errCreateNotAllowed = "test case does not exist and the management policies of the TestCase do not allow creating it"
+	errNotAllowedFmt    = "the management policies of the TestCase do not allow the %s action"
+)
+
+// managementPolicies are the management actions allowed for a TestCase.
+type managementPolicies map[v1alpha1.ManagementAction]bool
+
+// policiesOf returns the management policies of the supplied TestCase. Every
+// action is allowed if none are specified.
+func policiesOf(cr *v1alpha1.TestCase) managementPolicies {
+	p := managementPolicies{}
+	for _, a := range cr.Spec.ManagementPolicies {
+		p[a] = true
+	}
+	if len(p) == 0 {
+		p[v1alpha1.ManagementActionAll] = true
+	}
+	return p
+}
+
+// all returns true if every action is allowed.
+func (p managementPolicies) all() bool {
+	return p[v1alpha1.ManagementActionAll]
+}
+
+// observeOnly returns true if the only allowed action is Observe.
+func (p managementPolicies) observeOnly() bool {
+	return len(p) == 1 && p[v1alpha1.ManagementActionObserve]
+}
+
+// allows returns true if the supplied action is allowed.
+func (p managementPolicies) allows(a v1alpha1.ManagementAction) bool {
+	return p.all() || p[a]
+}
+
+// A policyExternal only takes the actions allowed by the management policies
+// of a TestCase, using the wrapped client.
+type policyExternal struct {
+	client   managed.ExternalClient
+	policies managementPolicies
+}
+
+// Observe the external resource using the wrapped client, adjusting the
+// observation so that the managed reconciler only takes allowed actions. A
+// test case that may not be created is an error, one that may not be updated
+// is reported as up to date, and one that may not be deleted is reported as
+// gone once the TestCase is deleted so that it may be finalized. Late
+// initialized fields are reverted if late initialization is not allowed.
+func (e *policyExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.TestCase)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	desired := cr.Spec.ForProvider.DeepCopy()
+	o, err := e.client.Observe(ctx, mg)
+	if err != nil {
+		return o, err
+	}
+
+	if o.ResourceLateInitialized && !e.policies.allows(v1alpha1.ManagementActionLateInitialize) {
+		cr.Spec.ForProvider = *desired
+		o.ResourceLateInitialized = false
+	}
+
+	switch {
+	case meta.WasDeleted(mg):
+		if !e.policies.allows(v1alpha1.ManagementActionDelete) {
+			o.ResourceExists = false
+		}
+	case !o.ResourceExists:
+		if !e.policies.allows(v1alpha1.ManagementActionCreate) {
+			return managed.ExternalObservation{}, errors.New(errCreateNotAllowed)
+		}
+	case !o.ResourceUpToDate:
+		if !e.policies.allows(v1alpha1.ManagementActionUpdate) {
+			o.ResourceUpToDate = true
+		}
+	}
+	return o, nil
+}
+
+// Create the external resource using the wrapped client, if allowed.
+func (e *policyExternal) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	if !e.policies.allows(v1alpha1.ManagementActionCreate) {
+		return managed.ExternalCreation{}, errors.Errorf(errNotAllowedFmt, v1alpha1.ManagementActionCreate)
+	}
+	return e.client.Create(ctx, mg)
+}
+
+// Update the external resource using the wrapped client, if allowed.
+func (e *policyExternal) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if !e.policies.allows(v1alpha1.ManagementActionUpdate) {
+		return managed.ExternalUpdate{}, errors.Errorf(errNotAllowedFmt, v1alpha1.ManagementActionUpdate)
+	}
+	return e.client.Update(ctx, mg)
+}
+
+// Delete the external resource using the wrapped client, if allowed.
+func (e *policyExternal) Delete(ctx context.Context, mg resource.Managed) error {
+	if !e.policies.allows(v1alpha1.ManagementActionDelete) {
+		return errors.Errorf(errNotAllowedFmt, v1alpha1.ManagementActionDelete)
+	}
+	return e.client.Delete(ctx, mg)
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+func TestPolicyObserve(t *testing.T) {
+	now := metav1.Now()
+
+	type args struct {
+		observation managed.ExternalObservation
+		policies    []v1alpha1.ManagementAction
+		deleted     bool
+	}
+
+	type want struct {
+		o     managed.ExternalObservation
+		notes string
+		err   error
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ObserveOnlyExists": {
+			reason: "An outdated test case should be reported as up to date if it may not be updated.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				policies:    []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve},
+			},
+			want: want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"ObserveOnlyDoesNotExist": {
+			reason: "A missing test case should be an error if it may not be created.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: false},
+				policies:    []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve},
+			},
+			want: want{err: errors.New(errCreateNotAllowed)},
+		},
+		"ObserveOnlyDeleted": {
+			reason: "A test case that may not be deleted should be reported as gone once its TestCase is deleted.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				policies:    []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve},
+				deleted:     true,
+			},
+			want: want{o: managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true}},
+		},
+		"NoLateInitialize": {
+			reason: "Late initialized fields should be reverted if late initialization is not allowed.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				policies:    []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve, v1alpha1.ManagementActionUpdate},
+			},
+			want: want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}},
+		},
+		"LateInitialize": {
+			reason: "Late initialized fields should be kept if late initialization is allowed.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true},
+				policies:    []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve, v1alpha1.ManagementActionLateInitialize},
+			},
+			want: want{o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true, ResourceLateInitialized: true}, notes: "late initialized"},
+		},
+		"Create": {
+			reason: "A missing test case should be reported as missing if it may be created.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: false},
+				policies:    []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve, v1alpha1.ManagementActionCreate},
+			},
+			want: want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testCase("acme", "checkout")
+			cr.Spec.ManagementPolicies = tc.args.policies
+			if tc.args.deleted {
+				cr.SetDeletionTimestamp(&now)
+			}
+			e := &policyExternal{
+				policies: policiesOf(cr),
+				client: &managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+						if tc.args.observation.ResourceLateInitialized {
+							mg.(*v1alpha1.TestCase).Spec.ForProvider.Notes = "late initialized"
+						}
+						return tc.args.observation, nil
+					},
+				},
+			}
+			got, err := e.Observe(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.notes, cr.Spec.ForProvider.Notes); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want notes, +got notes:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestPolicyNotAllowed(t *testing.T) {
+	cr := testCase("acme", "checkout")
+	cr.Spec.ManagementPolicies = []v1alpha1.ManagementAction{v1alpha1.ManagementActionObserve}
+	e := &policyExternal{policies: policiesOf(cr), client: &managed.ExternalClientFns{}}
+
+	_, err := e.Create(context.Background(), cr)
+	if diff := cmp.Diff(errors.Errorf(errNotAllowedFmt, v1alpha1.ManagementActionCreate), err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Create(...): -want error, +got error:\n%s\n", diff)
+	}
+	_, err = e.Update(context.Background(), cr)
+	if diff := cmp.Diff(errors.Errorf(errNotAllowedFmt, v1alpha1.ManagementActionUpdate), err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Update(...): -want error, +got error:\n%s\n", diff)
+	}
+	err = e.Delete(context.Background(), cr)
+	if diff := cmp.Diff(errors.Errorf(errNotAllowedFmt, v1alpha1.ManagementActionDelete), err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Delete(...): -want error, +got error:\n%s\n", diff)
+	}
+}
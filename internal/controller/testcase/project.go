@@ -0,0 +1,51 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+const (
+	errListProjects       = "cannot list projects"
+	errProjectNotFoundFmt = "project %q not found"
+)
+
+// project returns the options adding the supplied test case to its project,
+// and the ID of that project. No options and an empty ID are returned if the
+// test case does not belong to a project.
+func (c *external) project(ctx context.Context, cr *v1alpha1.TestCase) ([]stormforge.TestCaseOption, string, error) {
+	name := cr.Spec.ForProvider.Project
+	if name == "" {
+		return nil, "", nil
+	}
+	ps, err := c.client.ListProjects(ctx, cr.Spec.ForProvider.Org)
+	if err != nil {
+		return nil, "", errors.Wrap(err, errListProjects)
+	}
+	for _, p := range ps {
+		if p.Name == name {
+			return []stormforge.TestCaseOption{stormforge.WithProject(p.ID)}, p.ID, nil
+		}
+	}
+	return nil, "", errors.Errorf(errProjectNotFoundFmt, name)
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+)
+
+func TestProject(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+
+	withProject := func(project string) *v1alpha1.TestCase {
+		cr := testCase("acme", "checkout")
+		cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+		cr.Spec.ForProvider.Project = project
+		return cr
+	}
+	projects := func() map[string]stormforge.Project {
+		return map[string]stormforge.Project{
+			"p1": {ID: "p1", Scope: "acme", Name: "payments"},
+			"p2": {ID: "p2", Scope: "acme", Name: "search"},
+			"p3": {ID: "p3", Scope: "other", Name: "checkout"},
+		}
+	}
+
+	t.Run("Created", func(t *testing.T) {
+		fc := &fake.Client{Projects: projects()}
+		e := external{client: fc, record: event.NewNopRecorder()}
+		cr := withProject("search")
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %s", err)
+		}
+		tc := fc.TestCases["1"]
+		if diff := cmp.Diff("p2", tc.ProjectID); diff != "" {
+			t.Errorf("e.Create(...): -want project ID, +got project ID:\n%s\n", diff)
+		}
+		if d, err := e.diff(context.Background(), cr, &tc); err != nil || len(d) != 0 {
+			t.Errorf("e.diff(...): want no differences after create, got %v, %v", d, err)
+		}
+	})
+
+	t.Run("Moved", func(t *testing.T) {
+		fc := &fake.Client{Projects: projects()}
+		e := external{client: fc, record: event.NewNopRecorder()}
+		cr := withProject("search")
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %s", err)
+		}
+		cr.Spec.ForProvider.Project = "payments"
+		tc := fc.TestCases["1"]
+		if d, err := e.diff(context.Background(), cr, &tc); err != nil || len(d) == 0 {
+			t.Errorf("e.diff(...): want differences once moved to another project, got %v, %v", d, err)
+		}
+		if _, err := e.Update(context.Background(), withExternalName(cr, "1")); err != nil {
+			t.Fatalf("e.Update(...): unexpected error: %s", err)
+		}
+		if diff := cmp.Diff("p1", fc.TestCases["1"].ProjectID); diff != "" {
+			t.Errorf("e.Update(...): -want project ID, +got project ID:\n%s\n", diff)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		e := external{client: &fake.Client{Projects: projects()}, record: event.NewNopRecorder()}
+		want := errors.Errorf(errProjectNotFoundFmt, "checkout")
+		_, _, err := e.project(context.Background(), withProject("checkout"))
+		if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+			t.Errorf("e.project(...): -want error, +got error:\n%s\n", diff)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+const (
+	errParseTarget      = "cannot parse scenario target"
+	errStepFmt          = "cannot render step %d"
+	errUnknownVarFmt    = "variable %q is not extracted by an earlier step"
+	errNoExtractionFmt  = "extraction %q has neither a JSON path nor a header"
+	errTwoExtractionFmt = "extraction %q has both a JSON path and a header"
+)
+
+// reVar matches a reference to a variable in a scenario step.
+var reVar = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// renderScenario returns the StormForge definition of the supplied scenario of
+// the supplied test case.
+func renderScenario(cr *v1alpha1.TestCase) ([]byte, error) {
+	sc := cr.Spec.ForProvider.Scenario
+	t, err := url.Parse(sc.Target)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseTarget)
+	}
+	base := strings.TrimSuffix(t.Path, "/")
+	name := sc.Name
+	if name == "" {
+		name = cr.Spec.ForProvider.Name
+	}
+
+	b := &bytes.Buffer{}
+	fmt.Fprintf(b, "definition.setTarget(%s);\n\n", literal(t.Scheme+"://"+t.Host))
+	fmt.Fprintf(b, "definition.session(%s, function (session) {\n", literal(name))
+	vars := map[string]bool{}
+	for i, st := range sc.Steps {
+		if err := renderStep(b, st, base, vars); err != nil {
+			return nil, errors.Wrapf(err, errStepFmt, i)
+		}
+	}
+	b.WriteString("});\n")
+	return b.Bytes(), nil
+}
+
+// renderStep writes the supplied step to the supplied buffer, and records the
+// variables it extracts.
+func renderStep(b *bytes.Buffer, st v1alpha1.ScenarioStep, base string, vars map[string]bool) error {
+	path := st.URL
+	if u, err := url.Parse(path); err != nil || !u.IsAbs() {
+		path = base + "/" + strings.TrimPrefix(path, "/")
+	}
+	p, err := expr(path, vars)
+	if err != nil {
+		return err
+	}
+
+	opts := &bytes.Buffer{}
+	if st.Name != "" {
+		fmt.Fprintf(opts, "    tag: %s,\n", literal(st.Name))
+	}
+	if len(st.Headers) > 0 {
+		keys := make([]string, 0, len(st.Headers))
+		for k := range st.Headers {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		opts.WriteString("    headers: {\n")
+		for _, k := range keys {
+			v, err := expr(st.Headers[k], vars)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(opts, "      %s: %s,\n", literal(k), v)
+		}
+		opts.WriteString("    },\n")
+	}
+	if st.Body != "" {
+		v, err := expr(st.Body, vars)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(opts, "    payload: %s,\n", v)
+	}
+	if len(st.Extract) > 0 {
+		jsonPaths, headers := &bytes.Buffer{}, &bytes.Buffer{}
+		for _, e := range st.Extract {
+			switch {
+			case e.JSONPath != "" && e.Header != "":
+				return errors.Errorf(errTwoExtractionFmt, e.Name)
+			case e.JSONPath != "":
+				fmt.Fprintf(jsonPaths, "        %s: %s,\n", literal(e.Name), literal(e.JSONPath))
+			case e.Header != "":
+				fmt.Fprintf(headers, "        %s: %s,\n", literal(e.Name), literal(e.Header))
+			default:
+				return errors.Errorf(errNoExtractionFmt, e.Name)
+			}
+		}
+		opts.WriteString("    extraction: {\n")
+		if jsonPaths.Len() > 0 {
+			fmt.Fprintf(opts, "      jsonpath: {\n%s      },\n", jsonPaths)
+		}
+		if headers.Len() > 0 {
+			fmt.Fprintf(opts, "      header: {\n%s      },\n", headers)
+		}
+		opts.WriteString("    },\n")
+	}
+
+	method := strings.ToLower(st.Method)
+	if method == "" {
+		method = "get"
+	}
+	fmt.Fprintf(b, "  session.%s(%s", method, p)
+	if opts.Len() > 0 {
+		fmt.Fprintf(b, ", {\n%s  }", opts)
+	}
+	b.WriteString(");\n")
+
+	// Variables are only available to the steps following the extraction.
+	for _, e := range st.Extract {
+		vars[e.Name] = true
+	}
+	if st.ThinkTime != nil && st.ThinkTime.Duration > 0 {
+		fmt.Fprintf(b, "  session.wait(%s);\n", strconv.FormatFloat(st.ThinkTime.Seconds(), 'f', -1, 64))
+	}
+	return nil
+}
+
+// expr returns a JavaScript expression for the supplied string, replacing
+// references to variables with their values.
+func expr(s string, vars map[string]bool) (string, error) {
+	parts := []string{}
+	last := 0
+	for _, m := range reVar.FindAllStringSubmatchIndex(s, -1) {
+		name := s[m[2]:m[3]]
+		if !vars[name] {
+			return "", errors.Errorf(errUnknownVarFmt, name)
+		}
+		if m[0] > last {
+			parts = append(parts, literal(s[last:m[0]]))
+		}
+		parts = append(parts, fmt.Sprintf("session.getVar(%s)", literal(name)))
+		last = m[1]
+	}
+	if last < len(s) || len(parts) == 0 {
+		parts = append(parts, literal(s[last:]))
+	}
+	return strings.Join(parts, " + "), nil
+}
+
+// literal returns the supplied string as a JavaScript string literal. JSON
+// strings are valid JavaScript string literals.
+func literal(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
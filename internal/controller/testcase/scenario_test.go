@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+func TestRenderScenario(t *testing.T) {
+	type want struct {
+		definition string
+		err        error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		scenario *v1alpha1.Scenario
+		want     want
+	}{
+		"Session": {
+			reason: "Steps should be rendered into a session, with extracted variables available to later steps.",
+			scenario: &v1alpha1.Scenario{
+				Target: "https://shop.example/api/",
+				Steps: []v1alpha1.ScenarioStep{
+					{
+						Name:      "login",
+						Method:    "POST",
+						URL:       "login",
+						Headers:   map[string]string{"Content-Type": "application/json"},
+						Body:      `{"user":"alice"}`,
+						Extract:   []v1alpha1.Extraction{{Name: "token", JSONPath: "$.token"}, {Name: "sid", Header: "X-Session"}},
+						ThinkTime: &metav1.Duration{Duration: 1500 * time.Millisecond},
+					},
+					{
+						URL:     "/cart/${sid}",
+						Headers: map[string]string{"Authorization": "Bearer ${token}"},
+					},
+				},
+			},
+			want: want{definition: `definition.setTarget("https://shop.example");
+
+definition.session("checkout", function (session) {
+  session.post("/api/login", {
+    tag: "login",
+    headers: {
+      "Content-Type": "application/json",
+    },
+    payload: "{\"user\":\"alice\"}",
+    extraction: {
+      jsonpath: {
+        "token": "$.token",
+      },
+      header: {
+        "sid": "X-Session",
+      },
+    },
+  });
+  session.wait(1.5);
+  session.get("/api/cart/" + session.getVar("sid"), {
+    headers: {
+      "Authorization": "Bearer " + session.getVar("token"),
+    },
+  });
+});
+`},
+		},
+		"UnknownVariable": {
+			reason: "Steps may only refer to variables extracted by earlier steps.",
+			scenario: &v1alpha1.Scenario{
+				Target: "https://shop.example",
+				Steps:  []v1alpha1.ScenarioStep{{URL: "/cart/${sid}", Extract: []v1alpha1.Extraction{{Name: "sid", Header: "X-Session"}}}},
+			},
+			want: want{err: errors.Wrapf(errors.Errorf(errUnknownVarFmt, "sid"), errStepFmt, 0)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testCase("acme", "checkout")
+			cr.Spec.ForProvider.Scenario = tc.scenario
+			got, err := renderScenario(cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nrenderScenario(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.definition, string(got)); diff != "" {
+				t.Errorf("\n%s\nrenderScenario(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/fetch"
+	"github.com/luebken/provider-stormforge/internal/har"
+	"github.com/luebken/provider-stormforge/internal/k6"
+)
+
+const (
+	errNoScript      = "test case has no script source"
+	errGetConfigMap  = "cannot get ConfigMap"
+	errGetSecret     = "cannot get Secret"
+	errNoKeyFmt      = "key %q not found"
+	errGetAuthSecret = "cannot get script source auth Secret"
+	errFetchURL      = "cannot fetch script from URL"
+	errFetchGit      = "cannot fetch script from Git repository"
+	errConvertHAR    = "cannot convert HAR file to script"
+	errConvertK6     = "cannot translate k6 script"
+)
+
+// Keys of a script source or target auth Secret.
+const (
+	authKeyToken    = "token"
+	authKeyUsername = "username"
+	authKeyPassword = "password"
+)
+
+// script returns the JavaScript definition of the supplied test case, followed
+// by its traffic model and launch options and preceded by its environment
+// variables and targets.
+func (c *external) script(ctx context.Context, cr *v1alpha1.TestCase) ([]byte, error) {
+	b, err := c.definition(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	tm, err := c.trafficModel(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if b, err = withTrafficModel(tm, b); err != nil {
+		return nil, err
+	}
+	b = withLaunchOptions(cr, b)
+	if b, err = c.withTargets(ctx, cr, b); err != nil {
+		return nil, err
+	}
+	return c.withEnv(ctx, cr, b)
+}
+
+// definition returns the JavaScript definition of the supplied test case as
+// written: read from its script source and translated from its script format,
+// rendered from its scenario, or cloned from another test case.
+func (c *external) definition(ctx context.Context, cr *v1alpha1.TestCase) ([]byte, error) {
+	p := cr.Spec.ForProvider
+	if p.Script == nil && p.Scenario != nil {
+		return renderScenario(cr)
+	}
+	if cloning(p) {
+		return c.cloneDefinition(ctx, cr)
+	}
+	b, err := c.source(ctx, p.Script)
+	if err != nil {
+		return nil, err
+	}
+	if p.ScriptFormat != v1alpha1.ScriptFormatK6 || p.Script.HAR != nil {
+		return b, nil
+	}
+	b, err = k6.Convert(b)
+	return b, errors.Wrap(err, errConvertK6)
+}
+
+// source returns the content of the supplied script source.
+func (c *external) source(ctx context.Context, src *v1alpha1.ScriptSource) ([]byte, error) {
+	switch {
+	case src == nil:
+		return nil, errors.New(errNoScript)
+	case src.Inline != nil:
+		return []byte(*src.Inline), nil
+	case src.ConfigMapRef != nil:
+		return c.configMapKey(ctx, src.ConfigMapRef)
+	case src.SecretRef != nil:
+		return c.secretKey(ctx, src.SecretRef)
+	case src.URL != nil:
+		a, err := c.auth(ctx, src.URL.AuthSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		b, err := c.fetcher().URL(ctx, src.URL.URL, a)
+		return b, errors.Wrap(err, errFetchURL)
+	case src.Git != nil:
+		a, err := c.auth(ctx, src.Git.AuthSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		b, err := c.fetcher().Git(ctx, src.Git.Repository, src.Git.Ref, src.Git.Path, a)
+		return b, errors.Wrap(err, errFetchGit)
+	case src.HAR != nil:
+		b, err := c.configMapKey(ctx, src.HAR)
+		if err != nil {
+			return nil, err
+		}
+		b, err = har.Convert(b)
+		return b, errors.Wrap(err, errConvertHAR)
+	}
+	return nil, errors.New(errNoScript)
+}
+
+// configMapKey returns the value of the referenced ConfigMap key.
+func (c *external) configMapKey(ctx context.Context, ref *v1alpha1.ConfigMapKeySelector) ([]byte, error) {
+	cm := &corev1.ConfigMap{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+		return nil, errors.Wrap(err, errGetConfigMap)
+	}
+	if v, ok := cm.Data[ref.Key]; ok {
+		return []byte(v), nil
+	}
+	if v, ok := cm.BinaryData[ref.Key]; ok {
+		return v, nil
+	}
+	return nil, errors.Errorf(errNoKeyFmt, ref.Key)
+}
+
+// configMapVersion returns the resourceVersion of the ConfigMap the script of
+// the supplied test case is read from, or an empty string if it is not read
+// from a ConfigMap.
+func (c *external) configMapVersion(ctx context.Context, cr *v1alpha1.TestCase) (string, error) {
+	src := cr.Spec.ForProvider.Script
+	if src == nil {
+		return "", nil
+	}
+	ref := src.ConfigMapRef
+	if ref == nil {
+		ref = src.HAR
+	}
+	if ref == nil {
+		return "", nil
+	}
+	cm := &corev1.ConfigMap{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, cm); err != nil {
+		return "", errors.Wrap(err, errGetConfigMap)
+	}
+	return cm.GetResourceVersion(), nil
+}
+
+// secretKey returns the value of the referenced Secret key.
+func (c *external) secretKey(ctx context.Context, ref *xpv1.SecretKeySelector) ([]byte, error) {
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, errors.Wrap(err, errGetSecret)
+	}
+	v, ok := s.Data[ref.Key]
+	if !ok {
+		return nil, errors.Errorf(errNoKeyFmt, ref.Key)
+	}
+	return v, nil
+}
+
+// fetcher returns the Fetcher used to read remote script sources.
+func (c *external) fetcher() *fetch.Fetcher {
+	if c.scripts != nil {
+		return c.scripts
+	}
+	return fetch.New()
+}
+
+// auth returns the credentials stored in the referenced Secret, if any.
+func (c *external) auth(ctx context.Context, ref *xpv1.SecretReference) (fetch.Auth, error) {
+	if ref == nil {
+		return fetch.Auth{}, nil
+	}
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return fetch.Auth{}, errors.Wrap(err, errGetAuthSecret)
+	}
+	return fetch.Auth{
+		Token:    string(s.Data[authKeyToken]),
+		Username: string(s.Data[authKeyUsername]),
+		Password: string(s.Data[authKeyPassword]),
+	}, nil
+}
+
+// malformed returns true if the supplied definition cannot be a working test
+// case definition: if it is blank, is not valid UTF-8, or never configures the
+// definition object through which every StormForge test case is defined.
+func malformed(definition []byte) bool {
+	s := strings.TrimSpace(string(definition))
+	return s == "" || !utf8.ValidString(s) || !strings.Contains(s, "definition.")
+}
+
+// checksum returns the checksum of the supplied definition recorded in
+// status.atProvider.definitionChecksum.
+func checksum(definition []byte) string {
+	sum := sha256.Sum256(definition)
+	return hex.EncodeToString(sum[:])
+}
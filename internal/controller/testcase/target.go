@@ -0,0 +1,112 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+const (
+	errTargetFmt      = "cannot resolve authentication of target %q"
+	errTwoAuthMethods = "target has both a bearer token and basic auth"
+	errHeaderFmt      = "cannot read header %q"
+)
+
+const headerAuthorization = "Authorization"
+
+// A header of a request to a target.
+type header struct {
+	name  string
+	value string
+}
+
+// withTargets returns the supplied definition preceded by the declaration of a
+// global targets object holding the URL and authentication headers of each
+// target of the supplied test case. The definition is returned unchanged if the
+// test case has no targets.
+func (c *external) withTargets(ctx context.Context, cr *v1alpha1.TestCase, definition []byte) ([]byte, error) {
+	if len(cr.Spec.ForProvider.Targets) == 0 {
+		return definition, nil
+	}
+	b := &bytes.Buffer{}
+	b.WriteString("// Targets of the test case.\nconst targets = Object.freeze({\n")
+	for _, t := range cr.Spec.ForProvider.Targets {
+		hs, err := c.headers(ctx, t.Auth)
+		if err != nil {
+			return nil, errors.Wrapf(err, errTargetFmt, t.Name)
+		}
+		fmt.Fprintf(b, "  %s: Object.freeze({\n    url: %s,\n    headers: Object.freeze({", t.Name, literal(t.URL))
+		if len(hs) > 0 {
+			b.WriteString("\n")
+			for _, h := range hs {
+				fmt.Fprintf(b, "      %s: %s,\n", literal(h.name), literal(h.value))
+			}
+			b.WriteString("    ")
+		}
+		b.WriteString("}),\n  }),\n")
+	}
+	b.WriteString("});\n\n")
+	b.Write(definition)
+	return b.Bytes(), nil
+}
+
+// headers returns the headers that authenticate requests to a target.
+func (c *external) headers(ctx context.Context, a *v1alpha1.TargetAuth) ([]header, error) {
+	if a == nil {
+		return nil, nil
+	}
+	hs := []header{}
+	switch {
+	case a.BearerTokenSecretRef != nil && a.BasicAuthSecretRef != nil:
+		return nil, errors.New(errTwoAuthMethods)
+	case a.BearerTokenSecretRef != nil:
+		token, err := c.secretKey(ctx, a.BearerTokenSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		hs = append(hs, header{name: headerAuthorization, value: "Bearer " + string(token)})
+	case a.BasicAuthSecretRef != nil:
+		ref := a.BasicAuthSecretRef
+		s := &corev1.Secret{}
+		if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+			return nil, errors.Wrap(err, errGetSecret)
+		}
+		creds := string(s.Data[authKeyUsername]) + ":" + string(s.Data[authKeyPassword])
+		hs = append(hs, header{name: headerAuthorization, value: "Basic " + base64.StdEncoding.EncodeToString([]byte(creds))})
+	}
+	for _, h := range a.Headers {
+		v := h.Value
+		if h.SecretKeyRef != nil {
+			b, err := c.secretKey(ctx, h.SecretKeyRef)
+			if err != nil {
+				return nil, errors.Wrapf(err, errHeaderFmt, h.Name)
+			}
+			v = string(b)
+		}
+		hs = append(hs, header{name: h.Name, value: v})
+	}
+	return hs, nil
+}
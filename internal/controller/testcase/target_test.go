@@ -0,0 +1,108 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+func TestWithTargets(t *testing.T) {
+	errBoom := errors.New("boom")
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	kube := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.Secret).Data = map[string][]byte{"username": []byte("alice"), "password": []byte("secret"), "key": []byte("k3y")}
+		return nil
+	})}
+	key := &xpv1.SecretKeySelector{SecretReference: xpv1.SecretReference{Namespace: "default", Name: "api"}, Key: "key"}
+
+	type want struct {
+		definition string
+		err        error
+	}
+
+	cases := map[string]struct {
+		reason  string
+		kube    client.Client
+		targets []v1alpha1.Target
+		want    want
+	}{
+		"NoTargets": {
+			reason: "The definition should be unchanged without targets.",
+			want:   want{definition: script},
+		},
+		"Auth": {
+			reason: "Targets should be declared with the headers resolved from their Secrets.",
+			kube:   kube,
+			targets: []v1alpha1.Target{
+				{Name: "web", URL: "https://shop.example"},
+				{Name: "api", URL: "https://api.shop.example", Auth: &v1alpha1.TargetAuth{
+					BasicAuthSecretRef: &xpv1.SecretReference{Namespace: "default", Name: "api"},
+					Headers:            []v1alpha1.TargetHeader{{Name: "X-API-Key", SecretKeyRef: key}, {Name: "X-Client", Value: "load-test"}},
+				}},
+			},
+			want: want{definition: `// Targets of the test case.
+const targets = Object.freeze({
+  web: Object.freeze({
+    url: "https://shop.example",
+    headers: Object.freeze({}),
+  }),
+  api: Object.freeze({
+    url: "https://api.shop.example",
+    headers: Object.freeze({
+      "Authorization": "Basic YWxpY2U6c2VjcmV0",
+      "X-API-Key": "k3y",
+      "X-Client": "load-test",
+    }),
+  }),
+});
+
+` + script},
+		},
+		"GetSecretError": {
+			reason:  "Errors reading a bearer token should be wrapped.",
+			kube:    &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			targets: []v1alpha1.Target{{Name: "api", URL: "https://api.shop.example", Auth: &v1alpha1.TargetAuth{BearerTokenSecretRef: key}}},
+			want:    want{err: errors.Wrapf(errors.Wrap(errBoom, errGetSecret), errTargetFmt, "api")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testCase("acme", "checkout")
+			cr.Spec.ForProvider.Targets = tc.targets
+			e := external{kube: tc.kube}
+			got, err := e.withTargets(context.Background(), cr, []byte(script))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.withTargets(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.definition, string(got)); diff != "" {
+				t.Errorf("\n%s\ne.withTargets(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -18,183 +18,357 @@ package testcase
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"os/exec"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
-	"k8s.io/apimachinery/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/util/workqueue"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
 	"github.com/crossplane/crossplane-runtime/pkg/event"
 	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
 
 	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
-	apisv1alpha1 "github.com/luebken/provider-stormforge/apis/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/fetch"
+	"github.com/luebken/provider-stormforge/internal/pause"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+	"github.com/luebken/provider-stormforge/internal/slo"
 )
 
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "test case"
+
 const (
-	errNotMyType    = "managed resource is not a TestCase custom resource"
-	errTrackPCUsage = "cannot track ProviderConfig usage"
-	errGetPC        = "cannot get ProviderConfig"
-	errGetCreds     = "cannot get credentials"
+	errNoOrg          = "one of org, orgRef or orgSelector must be set"
+	errLaunchOnCreate = "cannot launch run of created test case"
+	errNotFound       = "test case does not exist"
+	errListRuns       = "cannot list runs of test case"
+	errActiveRunsFmt  = "waiting for the active runs of the test case to end before deleting it: %s"
+	errAbortRunFmt    = "cannot abort run %q of test case"
+	errListRevisions  = "cannot list revisions of test case"
+	errGetDefinition  = "cannot get definition of test case"
+	errArchive        = "cannot archive test case"
+	errUnarchive      = "cannot unarchive test case"
+	errProtectedFmt   = "the test case is protected from deletion by the %s annotation; remove the annotation to delete it"
+	errImmutableFmt   = "spec.forProvider.%s is immutable: the test case was created as %q, not %q; delete and recreate the TestCase instead"
+)
 
-	errNewClient = "cannot create new Service"
+// Keys of the connection details of a TestCase.
+const (
+	connectionKeyID     = "id"
+	connectionKeyOrg    = "org"
+	connectionKeyAPIURL = "apiURL"
+	connectionKeyURL    = "url"
 )
 
-type forgeApiResponse struct {
-	ForgeApiResponseData []forgeApiResponseData `json:"data"`
-}
-type forgeApiResponseData struct {
-	Id         string                         `json:"id"`
-	Attributes forgeApiResponseDataAttributes `json:"attributes"`
-}
-type forgeApiResponseDataAttributes struct {
-	Name  string `json:"name"`
-	Scope string `json:"scope"`
-	Org   string
-}
+// DefaultMaxRevisions is the number of revisions of a test case's definition
+// that are reported in its status by default.
+const DefaultMaxRevisions = 5
 
-type forge struct {
-	jwtToken string
-}
+// DefaultMaxStaleness is how long a test case is observed from the provider's
+// caches of StormForge API responses by default before it is read from the
+// API again.
+const DefaultMaxStaleness = 10 * time.Minute
 
-func NewForge(jwtToken string) (forge, error) {
-	result := &forge{
-		jwtToken: jwtToken,
-	}
-	return *result, nil
-}
-func (f *forge) ping() error {
-	//TODO f.jwtToken
-	cmd := exec.Command("forge", "ping")
-	stdout, err := cmd.Output()
+// runsInterval is how often the runs of a test case are listed while its
+// latest run is not active. Runs launched outside of Kubernetes are reported
+// within this interval.
+const runsInterval = 5 * time.Minute
 
-	if err != nil {
-		fmt.Println(err.Error())
-		return err
-	}
-	fmt.Println(string(stdout))
-	return nil
-}
-func (f *forge) exists(org string, name string) (bool, error) {
-	cmd := exec.Command("forge", "--output", "json", "test-case", "list", org)
-	stdout, err := cmd.Output()
-	if err != nil {
-		fmt.Println(err.Error())
-		return false, err
-	}
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.TestCaseKind)
 
-	var r forgeApiResponse
-	err = json.Unmarshal(stdout, &r)
-	if err != nil {
-		fmt.Println(err.Error())
-		return false, err
-	}
+// AnnotationKeyDryRun may be set to "true" on a TestCase to observe it and
+// report the changes that would be made to StormForge without making them.
+const AnnotationKeyDryRun = "stormforge.io/dry-run"
 
-	for _, element := range r.ForgeApiResponseData {
-		if element.Attributes.Name == name {
-			return true, nil
-		}
-	}
-	return false, nil
-}
+// AnnotationKeyDeletionProtection may be set to "true" on a TestCase to
+// prevent its test case from being deleted. Deleting the TestCase is blocked
+// until the annotation is removed, unless its deletion policy is Orphan.
+const AnnotationKeyDeletionProtection = "stormforge.io/deletion-protection"
 
-func (f *forge) create(org string, name string) error {
-	cmd := exec.Command("forge", "test-case", "create", org+"/"+name, "examples/sample/loadtest.mjs") //TODO real test-case
-	stdout, err := cmd.Output()
+// Event reasons for changes planned during a dry run.
+const (
+	reasonPlannedCreate event.Reason = "PlannedCreateExternalResource"
+	reasonPlannedUpdate event.Reason = "PlannedUpdateExternalResource"
+	reasonPlannedDelete event.Reason = "PlannedDeleteExternalResource"
+)
 
-	if err != nil {
-		fmt.Println(err.Error())
-		return err
-	}
-	fmt.Println("create: " + string(stdout))
-	return nil
+// TypeDryRun is the type of the condition that reports the change a dry run
+// of a TestCase would make to its test case.
+const TypeDryRun xpv1.ConditionType = "DryRun"
+
+// Reasons of the DryRun condition. ReasonDryRun is also the reason of the
+// Ready condition of a TestCase whose test case a dry run would create.
+const (
+	ReasonDryRun        xpv1.ConditionReason = "DryRun"
+	ReasonPlannedCreate xpv1.ConditionReason = "PlannedCreate"
+	ReasonPlannedUpdate xpv1.ConditionReason = "PlannedUpdate"
+	ReasonPlannedDelete xpv1.ConditionReason = "PlannedDelete"
+	ReasonNoChanges     xpv1.ConditionReason = "NoChanges"
+)
+
+// Event reasons for changes made to test cases and their runs.
+const (
+	reasonCreated    event.Reason = "CreatedTestCase"
+	reasonUpdated    event.Reason = "UpdatedTestCase"
+	reasonDeleted    event.Reason = "DeletedTestCase"
+	reasonArchived   event.Reason = "ArchivedTestCase"
+	reasonUnarchived event.Reason = "UnarchivedTestCase"
+	reasonMissing    event.Reason = "MissingTestCase"
+	reasonRenamed    event.Reason = "RenamedTestCase"
+	reasonLaunched   event.Reason = "LaunchedTestRun"
+	reasonAbortedRun event.Reason = "AbortedTestRun"
+	reasonRepaired   event.Reason = "RepairedDefinition"
+)
+
+// Options configure how TestCases are reconciled.
+type Options struct {
+	// MaxStaleness is how long a test case may be observed from the
+	// provider's caches of StormForge API responses. Once it was last read
+	// from the API longer ago, it is observed bypassing the caches. Zero
+	// disables the limit.
+	MaxStaleness time.Duration
+
+	// MaxRevisions is the number of the most recent revisions of a test
+	// case's definition that are reported in its status. Zero or less
+	// reports DefaultMaxRevisions.
+	MaxRevisions int
+
+	// ScriptTimeout bounds how long fetching a script from a URL or Git
+	// repository may take. Zero or less uses fetch.DefaultTimeout.
+	ScriptTimeout time.Duration
+
+	// ScriptSchemes are the URL schemes scripts may be fetched from. None
+	// allows fetch.DefaultSchemes.
+	ScriptSchemes []string
+
+	// ScriptHosts are the hosts scripts may be fetched from. None allows any
+	// host.
+	ScriptHosts []string
 }
 
-// Setup adds a controller that reconciles TestCase managed resources.
-func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter) error {
+// Setup adds a controller that reconciles TestCase managed resources. The
+// supplied options configure the StormForge client used for each TestCase.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, to Options, co ...stormforge.Option) error {
 	name := managed.ControllerName(v1alpha1.TestCaseGroupKind)
 
 	o := controller.Options{
 		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
 	}
 
-	r := managed.NewReconciler(mgr,
+	if err := indexClaims(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		return err
+	}
+
+	recorder := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	r := pause.NewReconciler(mgr,
 		resource.ManagedKind(v1alpha1.TestCaseGroupVersionKind),
 		managed.WithExternalConnecter(&connector{
-			kube:  mgr.GetClient(),
-			usage: resource.NewProviderConfigUsageTracker(mgr.GetClient(), &apisv1alpha1.ProviderConfigUsage{}),
+			kube:    mgr.GetClient(),
+			record:  recorder,
+			client:  clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+			options: to,
+			scripts: fetch.New(fetch.WithTimeout(to.ScriptTimeout), fetch.WithAllowedSchemes(to.ScriptSchemes...), fetch.WithAllowedHosts(to.ScriptHosts...)),
 		}),
+		// The external name of a TestCase is the ID of its StormForge test
+		// case, which is only known once it has been created or adopted, so
+		// the name of the managed resource is not used as its external name.
+		managed.WithInitializers(
+			managed.NewDefaultProviderConfig(mgr.GetClient()),
+			&externalNameInitializer{kube: mgr.GetClient()},
+		),
 		managed.WithLogger(l.WithValues("controller", name)),
-		managed.WithRecorder(event.NewAPIRecorder(mgr.GetEventRecorderFor(name))))
+		managed.WithRecorder(recorder))
 
 	return ctrl.NewControllerManagedBy(mgr).
 		Named(name).
 		WithOptions(o).
 		For(&v1alpha1.TestCase{}).
+		Watches(&source.Kind{Type: &v1alpha1.TrafficModel{}}, handler.EnqueueRequestsFromMapFunc(referencingTestCases(mgr.GetClient()))).
+		Watches(&source.Kind{Type: &v1alpha1.DataSource{}}, handler.EnqueueRequestsFromMapFunc(dependentTestCases(mgr.GetClient()))).
 		Complete(r)
 }
 
 // A connector is expected to produce an ExternalClient when its Connect method
 // is called.
 type connector struct {
-	kube  client.Client
-	usage resource.Tracker
+	kube    client.Client
+	record  event.Recorder
+	client  *clients.Connector
+	options Options
+	scripts *fetch.Fetcher
 }
 
-// Connect typically produces an ExternalClient by:
-// 1. Tracking that the managed resource is using a ProviderConfig.
-// 2. Getting the managed resource's ProviderConfig.
-// 3. Getting the credentials specified by the ProviderConfig.
-// 4. Using the credentials to form a client.
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied TestCase.
 func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
-
-	fmt.Printf("MDL Connect\n")
-
 	cr, ok := mg.(*v1alpha1.TestCase)
 	if !ok {
 		return nil, errors.New(errNotMyType)
 	}
 
-	if err := c.usage.Track(ctx, mg); err != nil {
-		return nil, errors.Wrap(err, errTrackPCUsage)
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
 	}
 
-	pc := &apisv1alpha1.ProviderConfig{}
-	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.GetProviderConfigReference().Name}, pc); err != nil {
-		return nil, errors.Wrap(err, errGetPC)
+	ext := &external{kube: c.kube, client: sf, record: c.record, scripts: c.scripts, maxStaleness: c.options.MaxStaleness, maxRevisions: c.options.MaxRevisions}
+	var e managed.ExternalClient = ext
+	if p := policiesOf(cr); !p.all() {
+		e = &policyExternal{client: e, policies: p}
 	}
+	if mg.GetAnnotations()[AnnotationKeyDryRun] == "true" {
+		e = &dryRunExternal{client: e, record: c.record, drift: ext.drifted}
+	}
+
+	return clients.WithAPIHealth(e), nil
+}
 
-	cd := pc.Spec.Credentials
-	data, err := resource.CommonCredentialExtractor(ctx, cd.Source, c.kube, cd.CommonCredentialSelectors)
+// A dryRunExternal observes the external resource using the wrapped client,
+// but only reports the changes it would make instead of making them.
+type dryRunExternal struct {
+	client managed.ExternalClient
+	record event.Recorder
+
+	// drift returns the fields the wrapped client last observed to differ
+	// from those of the managed resource, if known.
+	drift func() []string
+}
+
+// Observe the external resource and report the change that would be made, if
+// any, in the DryRun condition. An event is recorded when the planned change
+// changes. A test case that would be created is reported as unavailable
+// because of the dry run. The returned observation always reports the
+// external resource as up to date so that the managed reconciler never
+// attempts to create or update it. A deleted managed resource is reported as
+// having no external resource so that it may be finalized without deleting
+// anything in StormForge.
+func (d *dryRunExternal) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	o, err := d.client.Observe(ctx, mg)
 	if err != nil {
-		return nil, errors.Wrap(err, errGetCreds)
+		return o, err
+	}
+
+	switch {
+	case meta.WasDeleted(mg):
+		if o.ResourceExists && mg.GetDeletionPolicy() != xpv1.DeletionOrphan {
+			d.plan(mg, reasonPlannedDelete, ReasonPlannedDelete, "Dry run: would delete external resource")
+		}
+		o.ResourceExists = false
+		return o, nil
+	case !o.ResourceExists:
+		msg := "Dry run: would create external resource"
+		d.plan(mg, reasonPlannedCreate, ReasonPlannedCreate, msg)
+		c := xpv1.Unavailable()
+		c.Reason, c.Message = ReasonDryRun, msg
+		mg.SetConditions(c)
+	case !o.ResourceUpToDate:
+		msg := "Dry run: would update external resource"
+		if d.drift != nil && len(d.drift()) > 0 {
+			msg += " to change its " + strings.Join(d.drift(), ", ")
+		}
+		d.plan(mg, reasonPlannedUpdate, ReasonPlannedUpdate, msg)
+	default:
+		mg.SetConditions(dryRunCondition(ReasonNoChanges, "Dry run: no changes to make"))
 	}
 
-	fmt.Printf("MDL pc.Spec.Credentials.data: %+v\n", string(data))
+	o.ResourceExists = true
+	o.ResourceUpToDate = true
+	return o, nil
+}
 
-	forge, err := NewForge(string(data))
-	if err != nil {
-		return nil, errors.Wrap(err, errNewClient)
+// plan reports the supplied planned change in the DryRun condition of the
+// supplied managed resource, and records an event unless the condition
+// already reported it.
+func (d *dryRunExternal) plan(mg resource.Managed, er event.Reason, cr xpv1.ConditionReason, msg string) {
+	if c := mg.GetCondition(TypeDryRun); c.Reason != cr || c.Message != msg {
+		d.record.Event(mg, event.Normal(er, msg))
 	}
-	forge.ping()
+	mg.SetConditions(dryRunCondition(cr, msg))
+}
+
+// dryRunCondition returns a DryRun condition with the supplied reason and
+// message.
+func dryRunCondition(r xpv1.ConditionReason, msg string) xpv1.Condition {
+	return xpv1.Condition{
+		Type:               TypeDryRun,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             r,
+		Message:            msg,
+	}
+}
+
+// Create does nothing during a dry run.
+func (d *dryRunExternal) Create(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+	return managed.ExternalCreation{}, nil
+}
 
-	return &external{forge: forge}, nil
+// Update does nothing during a dry run.
+func (d *dryRunExternal) Update(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete does nothing during a dry run.
+func (d *dryRunExternal) Delete(_ context.Context, _ resource.Managed) error {
+	return nil
 }
 
 // An ExternalClient observes, then either creates, updates, or deletes an
 // external resource to ensure it reflects the managed resource's desired state.
 type external struct {
-	// A 'client' used to connect to the external resource API. In practice this
-	// would be something like an AWS SDK client.
-	forge forge
+	// A client used to read the sources of test case definitions.
+	kube client.Client
+
+	// A client used to connect to the StormForge API.
+	client stormforge.Client
+
+	// A recorder of the changes made to test cases and their runs.
+	record event.Recorder
+
+	// A fetcher of remote script sources, shared by all TestCases so that
+	// unchanged scripts are not downloaded again. A Fetcher with the default
+	// options is used if it is nil.
+	scripts *fetch.Fetcher
+
+	// wait pauses for the supplied duration, or until the supplied context
+	// is done. Pauses use a timer if it is nil.
+	wait func(ctx context.Context, d time.Duration) error
+
+	// How long a test case may be observed from the client's caches. Zero
+	// disables the limit.
+	maxStaleness time.Duration
+
+	// The number of revisions reported in status. Zero or less reports
+	// DefaultMaxRevisions.
+	maxRevisions int
+
+	// The fields of the test case that the last observation found to differ
+	// from those of the managed resource.
+	drift []string
+}
+
+// drifted returns the fields of the test case that the last observation found
+// to differ from those of the managed resource.
+func (c *external) drifted() []string {
+	return c.drift
 }
 
 func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
@@ -202,71 +376,732 @@ func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.Ex
 	if !ok {
 		return managed.ExternalObservation{}, errors.New(errNotMyType)
 	}
+	ctx = stormforge.WithOrg(ctx, testCase.Spec.ForProvider.Org)
 
-	exists, _ := c.forge.exists(testCase.Spec.ForProvider.Org, testCase.Spec.ForProvider.Name)
+	if testCase.Spec.ForProvider.Org == "" {
+		return managed.ExternalObservation{}, errors.New(errNoOrg)
+	}
 
-	// These fmt statements should be removed in the real implementation.
-	fmt.Printf("MDL Observing: %+v\n", testCase)
-	fmt.Printf("MDL Observing TestCase Exists: %+v\n", exists)
+	if err := immutable(testCase); err != nil {
+		return managed.ExternalObservation{}, err
+	}
 
-	return managed.ExternalObservation{
+	now := time.Now()
+	fresh := stale(testCase.Status.AtProvider.FetchedAt, c.maxStaleness, now)
+	if fresh {
+		ctx = stormforge.Fresh(ctx)
+	}
+
+	tc, err := c.find(ctx, testCase)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+	if tc != nil && fresh {
+		testCase.Status.AtProvider.FetchedAt = &metav1.Time{Time: now}
+	}
+
+	if tc == nil {
+		if id := testCase.Status.AtProvider.ID; id != "" && !meta.WasDeleted(testCase) {
+			c.record.Event(testCase, event.Warning(reasonMissing, errors.Errorf("test case %s no longer exists in StormForge and will be created again", id)))
+		}
 		// Return false when the external resource does not exist. This lets
 		// the managed resource reconciler know that it needs to call Create to
 		// (re)create the resource, or that it has successfully been deleted.
-		ResourceExists: exists,
+		return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}}, nil
+	}
+
+	if tc.Archived && meta.WasDeleted(testCase) && testCase.Spec.ForProvider.DeletionBehavior == v1alpha1.DeletionBehaviorArchive {
+		// The test case was archived rather than deleted when the managed
+		// resource was deleted, so it no longer exists as far as Crossplane
+		// is concerned.
+		return managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true, ConnectionDetails: managed.ConnectionDetails{}}, nil
+	}
+
+	if tc.State == stormforge.TestCaseCreating {
+		// The test case is being created asynchronously. It cannot be
+		// observed or updated until it has been, but it must not be created
+		// again.
+		testCase.SetConditions(xpv1.Creating())
+		return managed.ExternalObservation{
+			ResourceExists:    true,
+			ResourceUpToDate:  true,
+			ConnectionDetails: c.connectionDetails(tc),
+		}, nil
+	}
+	testCase.SetConditions(xpv1.Available())
+
+	// The remote name is compared with the name last observed, so that only
+	// renames made in StormForge are reported, not a rename of the TestCase
+	// that is yet to be applied.
+	if o := testCase.Status.AtProvider; o.Name != "" && tc.Name != o.Name && tc.Name != testCase.Spec.ForProvider.Name {
+		c.record.Event(testCase, event.Warning(reasonRenamed, errors.Errorf("test case %s is named %q in StormForge, but its TestCase names it %q", tc.ID, tc.Name, testCase.Spec.ForProvider.Name)))
+	}
+
+	if err := c.observe(ctx, testCase, tc); err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+	if err := c.observeClone(ctx, testCase, tc); err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+
+	lateInitialized := lateInitialize(testCase, tc)
+
+	drift, err := c.diff(ctx, testCase, tc)
+	if err != nil {
+		return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+	}
+	c.drift = drift
+	if len(drift) == 0 {
+		// The definition is in sync, so it was synced from the current
+		// version of its ConfigMap, if any.
+		v, err := c.configMapVersion(ctx, testCase)
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+		}
+		testCase.Status.AtProvider.ScriptConfigMapVersion = v
+	}
+
+	return managed.ExternalObservation{
+		ResourceExists:          true,
+		ResourceLateInitialized: lateInitialized,
 
 		// Return false when the external resource exists, but it not up to date
 		// with the desired managed resource state. This lets the managed
 		// resource reconciler know that it needs to call Update.
-		ResourceUpToDate: true,
+		ResourceUpToDate: len(drift) == 0,
 
 		// Return any details that may be required to connect to the external
 		// resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
+		ConnectionDetails: c.connectionDetails(tc),
 	}, nil
 }
 
+// find returns the test case of the supplied managed resource, or nil if it
+// does not exist. The test case is read by the ID in its external name, or
+// else the ID it was last observed with, so that a test case renamed in
+// StormForge is still found. Otherwise the test cases of its org are listed to
+// find it by its name, ignoring archived test cases. A test case that belongs
+// to another org does not exist.
+func (c *external) find(ctx context.Context, cr *v1alpha1.TestCase) (*stormforge.TestCase, error) {
+	id := externalName(cr)
+	if id == "" {
+		id = cr.Status.AtProvider.ID
+	}
+	if id != "" {
+		tc, err := c.getTestCase(ctx, cr.Spec.ForProvider.Org, id)
+		if tc == nil || err != nil || tc.Scope != cr.Spec.ForProvider.Org {
+			return nil, err
+		}
+		return tc, nil
+	}
+	tcs, err := c.client.ListTestCases(ctx, cr.Spec.ForProvider.Org)
+	if err != nil {
+		return nil, err
+	}
+	for i := range tcs {
+		if tcs[i].Name == cr.Spec.ForProvider.Name && (!tcs[i].Archived || cr.Spec.ForProvider.Archived) {
+			return &tcs[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// externalName returns the ID of the StormForge test case the supplied managed
+// resource is bound to, if any. An external name of the form "org/name" only
+// claims a test case that is yet to be created or adopted. An external name
+// equal to the name of the managed resource is ignored; it was set by
+// Crossplane's default initializer before TestCases used IDs as external
+// names.
+func externalName(cr *v1alpha1.TestCase) string {
+	if en := meta.GetExternalName(cr); en != cr.GetName() && !isOrgName(en) {
+		return en
+	}
+	return ""
+}
+
+// immutable returns an error if the org of the supplied test case differs
+// from the org it was created in. Changing it would orphan the existing test
+// case and create a new one. A test case that has been observed is found by
+// its ID rather than its name, so it may be renamed.
+func immutable(cr *v1alpha1.TestCase) error {
+	p, o := cr.Spec.ForProvider, cr.Status.AtProvider
+	if o.Org != "" && p.Org != o.Org {
+		return errors.Errorf(errImmutableFmt, "org", o.Org, p.Org)
+	}
+	return nil
+}
+
+// metadataDiff returns which of the name, labels and notes of the supplied
+// test case differ from those of the supplied remote test case.
+func metadataDiff(cr *v1alpha1.TestCase, tc *stormforge.TestCase) []string {
+	p := cr.Spec.ForProvider
+	var d []string
+	if p.Name != tc.Name {
+		d = append(d, "name")
+	}
+	if !labelsEqual(p.Labels, tc.Labels) {
+		d = append(d, "labels")
+	}
+	if p.Notes != tc.Notes {
+		d = append(d, "notes")
+	}
+	return d
+}
+
+// labelsEqual returns true if the supplied labels are equal.
+func labelsEqual(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if bv, ok := b[k]; !ok || bv != v {
+			return false
+		}
+	}
+	return true
+}
+
+// lateInitialize sets the labels, notes and default data source of the
+// supplied test case to those of the supplied remote test case if they are
+// unset, and returns true if it changed any of them. A remote default data
+// source is only adopted if it is one of the data sources of the test case.
+// The sizing and region of its launch options are part of its definition
+// rather than of the remote test case, so they are not late initialized.
+func lateInitialize(cr *v1alpha1.TestCase, tc *stormforge.TestCase) bool {
+	p := &cr.Spec.ForProvider
+	li := false
+	if p.Labels == nil && len(tc.Labels) > 0 {
+		p.Labels = make(map[string]string, len(tc.Labels))
+		for k, v := range tc.Labels {
+			p.Labels[k] = v
+		}
+		li = true
+	}
+	if p.Notes == "" && tc.Notes != "" {
+		p.Notes = tc.Notes
+		li = true
+	}
+	if p.DefaultDataSource == "" && tc.DefaultDataSourceID != "" {
+		if n := dataSourceName(cr, tc.DefaultDataSourceID); declared(*p, n) {
+			p.DefaultDataSource = n
+			li = true
+		}
+	}
+	return li
+}
+
+// metadata returns the options setting the labels and notes of the supplied
+// test case.
+func metadata(cr *v1alpha1.TestCase) []stormforge.TestCaseOption {
+	return []stormforge.TestCaseOption{
+		stormforge.WithLabels(cr.Spec.ForProvider.Labels),
+		stormforge.WithNotes(cr.Spec.ForProvider.Notes),
+	}
+}
+
+// observe records the observed state of the supplied test case, including its
+// latest run and most recent revisions, in the status of the supplied managed
+// resource. Revisions are only listed when the test case was updated since it
+// was last observed, and runs only when they are due to be listed again.
+func (c *external) observe(ctx context.Context, cr *v1alpha1.TestCase, tc *stormforge.TestCase) error {
+	o := &cr.Status.AtProvider
+	same := o.ID == tc.ID
+	if !same || !observedUpdate(o.UpdatedAt, tc.UpdatedAt) {
+		revs, err := c.client.ListRevisions(ctx, tc.ID)
+		if err != nil {
+			return errors.Wrap(err, errListRevisions)
+		}
+		o.Revisions = revisions(revs, c.maxRevisions)
+	}
+	if !same || runsDue(o, time.Now()) {
+		runs, err := c.client.ListTestRuns(ctx, tc.ID)
+		if err != nil {
+			return errors.Wrap(err, errListRuns)
+		}
+		r := latest(runs)
+		lr, err := c.lastRun(ctx, cr, r)
+		if err != nil {
+			return err
+		}
+		o.LastRunID, o.LastRunState, o.LastRun = "", "", lr
+		if r != nil {
+			o.LastRunID, o.LastRunState = r.ID, r.State
+		}
+		now := metav1.Now()
+		o.RunsObservedAt = &now
+	}
+	o.ID = tc.ID
+	o.Org = tc.Scope
+	o.Name = tc.Name
+	o.ProjectID = tc.ProjectID
+	o.DefaultDataSource = ""
+	if tc.DefaultDataSourceID != "" {
+		o.DefaultDataSource = dataSourceName(cr, tc.DefaultDataSourceID)
+	}
+	o.CreatedAt = metaTime(tc.CreatedAt)
+	o.UpdatedAt = metaTime(tc.UpdatedAt)
+	o.Archived = tc.Archived
+	return nil
+}
+
+// observedUpdate returns true if the supplied update time was already
+// observed. Observed times are stored with a precision of a second.
+func observedUpdate(observed *metav1.Time, t *time.Time) bool {
+	if observed == nil || t == nil {
+		return false
+	}
+	a, b := observed.Rfc3339Copy(), metaTime(t).Rfc3339Copy()
+	return a.Equal(&b)
+}
+
+// stale returns true if a test case last read from the StormForge API at the
+// supplied time must be read from the API again rather than from the client's
+// caches at the supplied time now. A test case never read from the API is
+// stale, unless the supplied max staleness is zero.
+func stale(fetched *metav1.Time, max time.Duration, now time.Time) bool {
+	if max <= 0 {
+		return false
+	}
+	return fetched == nil || now.Sub(fetched.Time) >= max
+}
+
+// runsDue returns true if the runs of the supplied test case observation are
+// due to be listed at the supplied time: if they were never listed, if its
+// latest run is active, or if they were last listed runsInterval ago.
+func runsDue(o *v1alpha1.TestCaseObservation, now time.Time) bool {
+	if o.RunsObservedAt == nil {
+		return true
+	}
+	if o.LastRunID != "" && runphase.Active(runphase.Of(o.LastRunState)) {
+		return true
+	}
+	return now.Sub(o.RunsObservedAt.Time) >= runsInterval
+}
+
+// revisions returns up to max of the most recent of the supplied revisions,
+// which must be sorted newest first, so the oldest are pruned once there are
+// more than max. Zero or less returns up to DefaultMaxRevisions.
+func revisions(revs []stormforge.Revision, max int) []v1alpha1.TestCaseRevision {
+	if len(revs) == 0 {
+		return nil
+	}
+	if max <= 0 {
+		max = DefaultMaxRevisions
+	}
+	if len(revs) > max {
+		revs = revs[:max]
+	}
+	out := make([]v1alpha1.TestCaseRevision, len(revs))
+	for i, r := range revs {
+		out[i] = v1alpha1.TestCaseRevision{ID: r.ID, CreatedAt: metaTime(r.CreatedAt), Author: r.Author}
+	}
+	return out
+}
+
+// latest returns the most recently started of the supplied runs. Runs that have
+// not started yet are more recent than any that have.
+func latest(runs []stormforge.TestRun) *stormforge.TestRun {
+	var l *stormforge.TestRun
+	for i := range runs {
+		r := &runs[i]
+		switch {
+		case l == nil:
+			l = r
+		case l.StartedAt == nil:
+		case r.StartedAt == nil || r.StartedAt.After(*l.StartedAt):
+			l = r
+		}
+	}
+	return l
+}
+
+// metaTime returns the supplied time as a Kubernetes time, if any.
+func metaTime(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	mt := metav1.NewTime(*t)
+	return &mt
+}
+
+// diff returns the fields of the supplied test case that differ from those of
+// the supplied remote test case: its name, labels, notes and project, its
+// client certificate or data sources if their content differs from the one
+// last uploaded, its default data source, the data sources attached to it if
+// it references any, and its definition if it differs from the remote
+// definition. The definition includes the cluster options of its launch
+// options, so changing them also makes the test case outdated. The remote
+// definition is compared rather than the checksum recorded when it was
+// uploaded, so that definitions edited outside of Kubernetes are corrected.
+// The definition also differs if the ConfigMap it is read from changed since
+// it was last synced. A deleted test case never differs, nor does the
+// definition of a test case without a script source or scenario.
+func (c *external) diff(ctx context.Context, cr *v1alpha1.TestCase, tc *stormforge.TestCase) ([]string, error) {
+	if meta.WasDeleted(cr) {
+		return nil, nil
+	}
+	d := metadataDiff(cr, tc)
+	if cr.Spec.ForProvider.Archived != tc.Archived {
+		d = append(d, "archived")
+	}
+	_, id, err := c.project(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if id != "" && id != tc.ProjectID {
+		d = append(d, "project")
+	}
+	ok, err := c.dataSourcesUpToDate(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		d = append(d, "dataSources")
+	}
+	if len(cr.Spec.ForProvider.DataSourceRefs) > 0 {
+		ids, err := c.referencedDataSources(ctx, cr)
+		if err != nil {
+			return nil, err
+		}
+		if !sameIDs(ids, tc.DataSourceIDs) {
+			d = append(d, "dataSourceRefs")
+		}
+	}
+	dsID, err := defaultDataSource(cr)
+	if err != nil {
+		return nil, err
+	}
+	if dsID != tc.DefaultDataSourceID {
+		d = append(d, "defaultDataSource")
+	}
+	ok, err = c.alertThresholdsUpToDate(ctx, cr, tc.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		d = append(d, "alertThresholds")
+	}
+	_, certSum, err := c.clientCertificate(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	if certSum != cr.Status.AtProvider.ClientCertificateChecksum {
+		d = append(d, "clientCertificate")
+	}
+	if cr.Spec.ForProvider.Script == nil && cr.Spec.ForProvider.Scenario == nil {
+		return d, nil
+	}
+	script, err := c.script(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := c.client.GetDefinition(ctx, tc.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errGetDefinition)
+	}
+	v, err := c.configMapVersion(ctx, cr)
+	if err != nil {
+		return nil, err
+	}
+	synced := cr.Status.AtProvider.ScriptConfigMapVersion
+	if checksum(script) != checksum(remote) || (synced != "" && synced != v) {
+		d = append(d, "definition")
+	}
+	return d, nil
+}
+
 func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
 	cr, ok := mg.(*v1alpha1.TestCase)
 	if !ok {
 		return managed.ExternalCreation{}, errors.New(errNotMyType)
 	}
+	ctx = stormforge.WithOrg(ctx, cr.Spec.ForProvider.Org)
 
-	fmt.Printf("MDL Creating: %+v\n", cr)
-	err := c.forge.create(cr.Spec.ForProvider.Org, cr.Spec.ForProvider.Name)
+	refOpts, err := c.dataSourceRefs(ctx, cr)
+	if err != nil {
+		cr.SetConditions(xpv1.Creating().WithMessage(err.Error()))
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	if _, err := alertThresholds(cr.Spec.ForProvider); err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	script, err := c.script(ctx, cr)
 	if err != nil {
-		return managed.ExternalCreation{}, err
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	opts, certSum, err := c.clientCertificate(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	popts, _, err := c.project(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	opts = append(append(opts, popts...), refOpts...)
+	if err := c.syncDataSources(ctx, cr); err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	dsID, err := defaultDataSource(cr)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	if dsID != "" {
+		opts = append(opts, stormforge.WithDefaultDataSource(dsID))
+	}
+	opts = append(opts, metadata(cr)...)
+	if cloning(cr.Spec.ForProvider) {
+		copts, err := c.cloneMetadata(ctx, cr)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+		}
+		opts = append(opts, copts...)
+	}
+	tc, err := c.client.CreateTestCase(ctx, cr.Spec.ForProvider.Org, cr.Spec.ForProvider.Name, script, opts...)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	cr.Status.AtProvider.DefinitionChecksum = checksum(script)
+	cr.Status.AtProvider.ClientCertificateChecksum = certSum
+	cr.Status.AtProvider.ID = tc.ID
+	cr.Status.AtProvider.Org = tc.Scope
+	cr.Status.AtProvider.Name = tc.Name
+	meta.SetExternalName(cr, tc.ID)
+	c.record.Event(cr, event.Normal(reasonCreated, fmt.Sprintf("Created test case %s", tc.ID)))
+	if err := c.syncAlertThresholds(ctx, cr, tc.ID); err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+
+	if l := cr.Spec.ForProvider.Launch; l != nil && l.OnCreate {
+		t, err := slo.Thresholds(ctx, c.kube, cr.Spec.ForProvider.SLORefs...)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errLaunchOnCreate)
+		}
+		r, err := c.client.LaunchTestRun(ctx, tc.ID, stormforge.RunOptions{Title: l.Title, Notes: l.Notes, Thresholds: t})
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrap(err, errLaunchOnCreate)
+		}
+		cr.Status.AtProvider.LastRunID, cr.Status.AtProvider.LastRunState = r.ID, r.State
+		c.record.Event(cr, event.Normal(reasonLaunched, fmt.Sprintf("Launched run %s of test case %s", r.ID, tc.ID)))
 	}
 
 	return managed.ExternalCreation{
+		ExternalNameAssigned: true,
+
 		// Optionally return any details that may be required to connect to the
 		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
+		ConnectionDetails: c.connectionDetails(tc),
 	}, nil
 }
 
+// connectionDetails returns the ID and org of the supplied test case, and its
+// URLs in the StormForge API and web UI.
+func (c *external) connectionDetails(tc *stormforge.TestCase) managed.ConnectionDetails {
+	return managed.ConnectionDetails{
+		connectionKeyID:     []byte(tc.ID),
+		connectionKeyOrg:    []byte(tc.Scope),
+		connectionKeyAPIURL: []byte(c.client.TestCaseURL(tc.ID)),
+		connectionKeyURL:    []byte(stormforge.TestCaseAppURL(tc.Scope, tc.ID)),
+	}
+}
+
 func (c *external) Update(ctx context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
 	cr, ok := mg.(*v1alpha1.TestCase)
 	if !ok {
 		return managed.ExternalUpdate{}, errors.New(errNotMyType)
 	}
+	ctx = stormforge.WithOrg(ctx, cr.Spec.ForProvider.Org)
 
-	fmt.Printf("MDL Updating: %+v\n", cr)
+	tc, err := c.find(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	if tc == nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(errors.New(errNotFound), errs.UpdateFmt, externalKind)
+	}
+	refOpts, err := c.dataSourceRefs(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	script, err := c.script(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	opts, certSum, err := c.clientCertificate(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	popts, _, err := c.project(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	opts = append(append(opts, popts...), refOpts...)
+	if err := c.syncDataSources(ctx, cr); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	if err := c.syncAlertThresholds(ctx, cr, tc.ID); err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	dsID, err := defaultDataSource(cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	if dsID != tc.DefaultDataSourceID {
+		opts = append(opts, stormforge.WithDefaultDataSource(dsID))
+	}
+	upload, repair, err := c.changed(ctx, tc.ID, script)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	version, err := c.configMapVersion(ctx, cr)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	if !cr.Spec.ForProvider.Archived {
+		if err := c.unarchive(ctx, cr, tc); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+		}
+	}
+	archived := tc.Archived
+	tc, err = c.client.UpdateTestCase(ctx, tc.ID, cr.Spec.ForProvider.Name, upload, append(opts, metadata(cr)...)...)
+	if err != nil {
+		return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+	}
+	cr.Status.AtProvider.DefinitionChecksum = checksum(script)
+	cr.Status.AtProvider.ScriptConfigMapVersion = version
+	cr.Status.AtProvider.ClientCertificateChecksum = certSum
+	cr.Status.AtProvider.Name = tc.Name
+	cr.Status.AtProvider.UpdatedAt = metaTime(tc.UpdatedAt)
+	msg := fmt.Sprintf("Updated test case %s", tc.ID)
+	if upload != nil {
+		msg = fmt.Sprintf("Updated test case %s and uploaded a new revision of its definition", tc.ID)
+	}
+	c.record.Event(cr, event.Normal(reasonUpdated, msg))
+	if cr.Spec.ForProvider.Archived {
+		tc.Archived = archived
+		if err := c.archive(ctx, cr, tc); err != nil {
+			return managed.ExternalUpdate{}, errors.Wrapf(err, errs.UpdateFmt, externalKind)
+		}
+	}
+	if repair {
+		c.record.Event(cr, event.Warning(reasonRepaired, errors.Errorf("re-uploaded the definition of test case %s, which was empty or malformed in StormForge", tc.ID)))
+	}
 
 	return managed.ExternalUpdate{
 		// Optionally return any details that may be required to connect to the
 		// external resource. These will be stored as the connection secret.
-		ConnectionDetails: managed.ConnectionDetails{},
+		ConnectionDetails: c.connectionDetails(tc),
 	}, nil
 }
 
+// changed returns the supplied script if it differs from the remote definition
+// of the test case with the supplied ID, and nil otherwise. Uploading an
+// unchanged definition would needlessly record a new revision of it. It also
+// returns true if uploading the script repairs a remote definition that was
+// corrupted in StormForge, i.e. one that is empty or malformed while the
+// script is not. A script that is itself empty is uploaded as is.
+func (c *external) changed(ctx context.Context, id string, script []byte) ([]byte, bool, error) {
+	remote, err := c.client.GetDefinition(ctx, id)
+	if err != nil {
+		return nil, false, errors.Wrap(err, errGetDefinition)
+	}
+	if checksum(script) == checksum(remote) {
+		return nil, false, nil
+	}
+	return script, malformed(remote) && !malformed(script), nil
+}
+
+// Delete deletes the test case of the supplied managed resource. A test case
+// that no longer exists, for example because it was deleted outside of
+// Kubernetes, is not an error; the next observation reports it as gone. A
+// test case protected from deletion is not deleted, and the Deleting condition
+// explains why. The test case is archived instead of deleted if the deletion
+// behavior of the managed resource is Archive.
 func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
 	cr, ok := mg.(*v1alpha1.TestCase)
 	if !ok {
 		return errors.New(errNotMyType)
 	}
+	ctx = stormforge.WithOrg(ctx, cr.Spec.ForProvider.Org)
 
-	fmt.Printf("MDL Deleting: %+v", cr)
+	if cr.GetAnnotations()[AnnotationKeyDeletionProtection] == "true" {
+		msg := fmt.Sprintf(errProtectedFmt, AnnotationKeyDeletionProtection)
+		cr.SetConditions(xpv1.Deleting().WithMessage(msg))
+		return errors.Wrapf(errors.New(msg), errs.DeleteFmt, externalKind)
+	}
 
+	cr.SetConditions(xpv1.Deleting())
+	tc, err := c.find(ctx, cr)
+	if err != nil || tc == nil {
+		return errors.Wrapf(err, errs.DeleteFmt, externalKind)
+	}
+	if err := c.endRuns(ctx, cr, tc); err != nil {
+		return errors.Wrapf(err, errs.DeleteFmt, externalKind)
+	}
+	if cr.Spec.ForProvider.DeletionBehavior == v1alpha1.DeletionBehaviorArchive {
+		return errors.Wrapf(c.archive(ctx, cr, tc), errs.DeleteFmt, externalKind)
+	}
+	if err := c.client.DeleteTestCase(ctx, tc.ID); resource.Ignore(stormforge.IsNotFound, err) != nil {
+		return errors.Wrapf(err, errs.DeleteFmt, externalKind)
+	}
+	c.record.Event(cr, event.Normal(reasonDeleted, fmt.Sprintf("Deleted test case %s", tc.ID)))
+	return nil
+}
+
+// archive archives the supplied test case of the supplied managed resource,
+// unless it has already been archived.
+func (c *external) archive(ctx context.Context, cr *v1alpha1.TestCase, tc *stormforge.TestCase) error {
+	if tc.Archived {
+		return nil
+	}
+	if err := c.client.ArchiveTestCase(ctx, tc.ID); resource.Ignore(stormforge.IsNotFound, err) != nil {
+		return errors.Wrap(err, errArchive)
+	}
+	c.record.Event(cr, event.Normal(reasonArchived, fmt.Sprintf("Archived test case %s", tc.ID)))
+	return nil
+}
+
+// unarchive restores the supplied archived test case of the supplied managed
+// resource, unless it is not archived.
+func (c *external) unarchive(ctx context.Context, cr *v1alpha1.TestCase, tc *stormforge.TestCase) error {
+	if !tc.Archived {
+		return nil
+	}
+	if err := c.client.UnarchiveTestCase(ctx, tc.ID); err != nil {
+		return errors.Wrap(err, errUnarchive)
+	}
+	c.record.Event(cr, event.Normal(reasonUnarchived, fmt.Sprintf("Unarchived test case %s", tc.ID)))
+	return nil
+}
+
+// endRuns handles the active runs of the supplied test case before it is
+// deleted, according to the active runs policy of the supplied managed
+// resource. Active runs are aborted if the policy is Abort. Otherwise an error
+// is returned, and the Deleting condition explains that deletion is blocked
+// until the runs have ended.
+func (c *external) endRuns(ctx context.Context, cr *v1alpha1.TestCase, tc *stormforge.TestCase) error {
+	runs, err := c.client.ListTestRuns(ctx, tc.ID)
+	if err != nil {
+		return errors.Wrap(err, errListRuns)
+	}
+	var ids []string
+	for _, r := range runs {
+		if runphase.Active(runphase.Of(r.State)) {
+			ids = append(ids, r.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	if cr.Spec.ForProvider.ActiveRunsPolicy != v1alpha1.ActiveRunsAbort {
+		msg := fmt.Sprintf(errActiveRunsFmt, strings.Join(ids, ", "))
+		cr.SetConditions(xpv1.Deleting().WithMessage(msg))
+		return errors.New(msg)
+	}
+	for _, id := range ids {
+		if err := c.client.AbortTestRun(ctx, id); resource.Ignore(stormforge.IsNotFound, err) != nil {
+			return errors.Wrapf(err, errAbortRunFmt, id)
+		}
+		c.record.Event(cr, event.Normal(reasonAbortedRun, fmt.Sprintf("Aborted run %s of test case %s before deleting it", id, tc.ID)))
+	}
 	return nil
 }
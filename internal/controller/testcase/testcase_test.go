@@ -18,13 +18,39 @@ package testcase
 
 import (
 	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
 	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
 	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	xpfake "github.com/crossplane/crossplane-runtime/pkg/resource/fake"
 	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	apisv1alpha1 "github.com/luebken/provider-stormforge/apis/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/credentials"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/har"
 )
 
 // Unlike many Kubernetes projects Crossplane does not use third party testing
@@ -35,9 +61,49 @@ import (
 // https://github.com/golang/go/wiki/TestComments
 // https://github.com/crossplane/crossplane/blob/master/CONTRIBUTING.md#contributing-code
 
+func testCase(org, name string) *v1alpha1.TestCase {
+	return &v1alpha1.TestCase{
+		Spec: v1alpha1.TestCaseSpec{
+			ForProvider: v1alpha1.TestCaseParameters{Org: org, Name: name},
+		},
+	}
+}
+
+// withExternalName sets the external name of the supplied test case.
+func withExternalName(cr *v1alpha1.TestCase, en string) *v1alpha1.TestCase {
+	meta.SetExternalName(cr, en)
+	return cr
+}
+
 func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	script := "definition.session(\"checkout\", function(session) {});\n"
+
+	fromConfigMap := func(sum string) *v1alpha1.TestCase {
+		cr := testCase("acme", "checkout")
+		cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{
+			ConfigMapRef: &v1alpha1.ConfigMapKeySelector{Namespace: "default", Name: "scripts", Key: "checkout.js"},
+		}
+		cr.Status.AtProvider.DefinitionChecksum = sum
+		return cr
+	}
+	configMap := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.ConfigMap).Data = map[string]string{"checkout.js": script}
+		return nil
+	})}
+	existing := map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}
+	details := func(id string) managed.ConnectionDetails {
+		return managed.ConnectionDetails{
+			"id":     []byte(id),
+			"org":    []byte("acme"),
+			"apiURL": []byte("https://api.stormforger.com/test_cases/" + id),
+			"url":    []byte("https://app.stormforger.com/acme/test_cases/" + id),
+		}
+	}
+
 	type fields struct {
-		service interface{}
+		kube   client.Client
+		client *fake.Client
 	}
 
 	type args struct {
@@ -56,14 +122,273 @@ func TestObserve(t *testing.T) {
 		args   args
 		want   want
 	}{
-		// TODO: Add test cases.
+		"Exists": {
+			reason: "A test case with the desired name in the desired org should be reported as existing.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "acme"},
+			}}},
+			args: args{ctx: context.Background(), mg: testCase("acme", "checkout")},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"Creating": {
+			reason: "A test case that is still being created should be reported as existing and up to date so that it is neither created again nor updated.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "acme", State: stormforge.TestCaseCreating},
+			}}},
+			args: args{ctx: context.Background(), mg: testCase("acme", "checkout")},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"ObserveError": {
+			reason: "Errors listing test cases should be returned rather than reported as a missing test case.",
+			fields: fields{client: &fake.Client{Err: errBoom}},
+			args:   args{ctx: context.Background(), mg: testCase("acme", "checkout")},
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+		"ScriptUnchanged": {
+			reason: "A test case whose script matches its remote definition should be up to date.",
+			fields: fields{kube: configMap, client: &fake.Client{TestCases: existing, Scripts: map[string][]byte{"1": []byte(script)}}},
+			args:   args{ctx: context.Background(), mg: fromConfigMap(checksum([]byte(script)))},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"ScriptChanged": {
+			reason: "A test case whose referenced script changed since it was uploaded should not be up to date.",
+			fields: fields{kube: configMap, client: &fake.Client{TestCases: existing, Scripts: map[string][]byte{"1": []byte("old")}}},
+			args:   args{ctx: context.Background(), mg: fromConfigMap(checksum([]byte("old")))},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"LaunchOptionsChanged": {
+			reason: "A test case whose launch options are not part of its remote definition should not be up to date.",
+			fields: fields{kube: configMap, client: &fake.Client{TestCases: existing, Scripts: map[string][]byte{"1": []byte(script)}}},
+			args: args{ctx: context.Background(), mg: func() resource.Managed {
+				cr := fromConfigMap(checksum([]byte(script)))
+				cr.Spec.ForProvider.Launch = &v1alpha1.LaunchOptions{Region: "eu-central-1"}
+				return cr
+			}()},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"LaunchOnCreateOnly": {
+			reason: "Launch options that only launch a run on creation do not affect the definition, so should not make a test case outdated.",
+			fields: fields{kube: configMap, client: &fake.Client{TestCases: existing, Scripts: map[string][]byte{"1": []byte(script)}}},
+			args: args{ctx: context.Background(), mg: func() resource.Managed {
+				cr := fromConfigMap(checksum([]byte(script)))
+				cr.Spec.ForProvider.Launch = &v1alpha1.LaunchOptions{OnCreate: true, Title: "smoke"}
+				return cr
+			}()},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"DefinitionDrifted": {
+			reason: "A test case whose remote definition was edited outside of Kubernetes should not be up to date.",
+			fields: fields{kube: configMap, client: &fake.Client{TestCases: existing, Scripts: map[string][]byte{"1": []byte("edited in the UI")}}},
+			args:   args{ctx: context.Background(), mg: fromConfigMap(checksum([]byte(script)))},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"ArchivedOutOfBand": {
+			reason: "A test case archived outside of Kubernetes while its TestCase says it is active should not be up to date.",
+			fields: fields{kube: configMap, client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme", Archived: true}},
+				Scripts:   map[string][]byte{"1": []byte(script)},
+			}},
+			args: args{ctx: context.Background(), mg: withExternalName(fromConfigMap(checksum([]byte(script))), "1")},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"DefinitionEmptied": {
+			reason: "A test case whose remote definition is empty while its script is not should not be up to date, so that it is repaired.",
+			fields: fields{kube: configMap, client: &fake.Client{TestCases: existing, Scripts: map[string][]byte{"1": {}}}},
+			args:   args{ctx: context.Background(), mg: fromConfigMap(checksum([]byte(script)))},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"GetScriptError": {
+			reason: "Errors reading the script source should be wrapped.",
+			fields: fields{
+				kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+				client: &fake.Client{TestCases: existing},
+			},
+			args: args{ctx: context.Background(), mg: fromConfigMap("")},
+			want: want{err: errors.Wrapf(errors.Wrap(errBoom, errGetConfigMap), errs.ObserveFmt, externalKind)},
+		},
+		"LabelsDrifted": {
+			reason: "A test case whose remote labels differ from the desired labels should not be up to date.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "acme", Labels: map[string]string{"team": "payments"}},
+			}}},
+			args: args{ctx: context.Background(), mg: func() resource.Managed {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Labels = map[string]string{"team": "checkout"}
+				return cr
+			}()},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"NotesDrifted": {
+			reason: "A test case whose remote notes differ from the desired notes should not be up to date.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "acme", Notes: "edited in the UI"},
+			}}},
+			args: args{ctx: context.Background(), mg: func() resource.Managed {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Notes = "owned by the checkout team"
+				return cr
+			}()},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"LateInitialized": {
+			reason: "Omitted labels and notes should be late initialized from the remote test case.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "acme", Labels: map[string]string{"team": "payments"}, Notes: "edited in the UI"},
+			}}},
+			args: args{ctx: context.Background(), mg: testCase("acme", "checkout")},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:          true,
+				ResourceUpToDate:        true,
+				ResourceLateInitialized: true,
+				ConnectionDetails:       details("1"),
+			}},
+		},
+		"NoOrg": {
+			reason: "A test case cannot be observed until its org is known, for example because its reference has not been resolved.",
+			fields: fields{client: &fake.Client{TestCases: existing}},
+			args:   args{ctx: context.Background(), mg: testCase("", "checkout")},
+			want:   want{err: errors.New(errNoOrg)},
+		},
+		"OrgChanged": {
+			reason: "Changing the org of a created test case should return an error rather than orphan it.",
+			fields: fields{client: &fake.Client{TestCases: existing}},
+			args: args{ctx: context.Background(), mg: func() resource.Managed {
+				cr := testCase("other", "checkout")
+				cr.Status.AtProvider = v1alpha1.TestCaseObservation{ID: "1", Org: "acme", Name: "checkout"}
+				return cr
+			}()},
+			want: want{err: errors.Errorf(errImmutableFmt, "org", "acme", "other")},
+		},
+		"ExternalName": {
+			reason: "A test case should be found by the ID in its external name, and renamed if its name differs.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "acme"},
+				"2": {ID: "2", Name: "legacy-checkout", Scope: "acme"},
+			}}},
+			args: args{ctx: context.Background(), mg: withExternalName(testCase("acme", "checkout"), "2")},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("2"),
+			}},
+		},
+		"RenamedRemotely": {
+			reason: "A test case renamed in StormForge should be found by the ID it was observed with, and renamed back, rather than created again.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout-renamed", Scope: "acme"},
+			}}},
+			args: args{ctx: context.Background(), mg: func() resource.Managed {
+				cr := withExternalName(testCase("acme", "checkout"), "acme/checkout")
+				cr.Status.AtProvider = v1alpha1.TestCaseObservation{ID: "1", Org: "acme", Name: "checkout"}
+				return cr
+			}()},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  false,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"ExternalNameDoesNotExist": {
+			reason: "A test case whose external name is not the ID of any test case should not be reported as existing, even if its name is taken.",
+			fields: fields{client: &fake.Client{TestCases: existing}},
+			args:   args{ctx: context.Background(), mg: withExternalName(testCase("acme", "checkout"), "2")},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    false,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			}},
+		},
+		"ExternalNameInOtherOrg": {
+			reason: "A test case whose external name is the ID of a test case of another org should not be reported as existing.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{"2": {ID: "2", Name: "checkout", Scope: "other"}}}},
+			args:   args{ctx: context.Background(), mg: withExternalName(testCase("acme", "checkout"), "2")},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    false,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			}},
+		},
+		"ExternalNameGetError": {
+			reason: "Errors getting a test case by its external name should be returned rather than reported as a missing test case.",
+			fields: fields{client: &fake.Client{Err: errBoom}},
+			args:   args{ctx: context.Background(), mg: withExternalName(testCase("acme", "checkout"), "1")},
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+		"DefaultExternalName": {
+			reason: "An external name equal to the name of the managed resource should be ignored in favour of the test case's name.",
+			fields: fields{client: &fake.Client{TestCases: existing}},
+			args: args{ctx: context.Background(), mg: func() resource.Managed {
+				cr := testCase("acme", "checkout")
+				cr.SetName("checkout-tc")
+				return withExternalName(cr, "checkout-tc")
+			}()},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    true,
+				ResourceUpToDate:  true,
+				ConnectionDetails: details("1"),
+			}},
+		},
+		"DoesNotExist": {
+			reason: "A test case that only exists in another org should not be reported as existing.",
+			fields: fields{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "other"},
+			}}},
+			args: args{ctx: context.Background(), mg: testCase("acme", "checkout")},
+			want: want{o: managed.ExternalObservation{
+				ResourceExists:    false,
+				ResourceUpToDate:  true,
+				ConnectionDetails: managed.ConnectionDetails{},
+			}},
+		},
 	}
 
 	for name, tc := range cases {
 		t.Run(name, func(t *testing.T) {
-			e := external{forge: forge{
-				jwtToken: "",
-			}}
+			e := external{kube: tc.fields.kube, client: tc.fields.client, record: event.NewNopRecorder()}
 			got, err := e.Observe(tc.args.ctx, tc.args.mg)
 			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
 				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
@@ -74,3 +399,1468 @@ func TestObserve(t *testing.T) {
 		})
 	}
 }
+
+func TestObserveConditions(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		state  string
+		want   xpv1.Condition
+	}{
+		"Creating": {
+			reason: "A test case that is still being created should not be available yet.",
+			state:  stormforge.TestCaseCreating,
+			want:   xpv1.Creating(),
+		},
+		"Created": {
+			reason: "A test case that has been created should be available.",
+			want:   xpv1.Available(),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testCase("acme", "checkout")
+			e := external{client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "acme", State: tc.state},
+			}}, record: event.NewNopRecorder()}
+			if _, err := e.Observe(context.Background(), cr); err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, cr.GetCondition(xpv1.TypeReady), cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want condition, +got condition:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestObserveStatus(t *testing.T) {
+	created := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	earlier, later := created.Add(time.Hour), created.Add(2*time.Hour)
+
+	fc := &fake.Client{
+		TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme", CreatedAt: &created, UpdatedAt: &created}},
+		Runs: map[string]stormforge.TestRun{
+			"r1": {ID: "r1", TestCaseID: "1", State: "done", StartedAt: &earlier},
+			"r2": {ID: "r2", TestCaseID: "1", State: "running", StartedAt: &later},
+			"r3": {ID: "r3", TestCaseID: "2", State: "launching"},
+		},
+		Revisions: map[string][]stormforge.Revision{"1": {}},
+	}
+	for i := 0; i <= DefaultMaxRevisions; i++ {
+		at := created.Add(time.Duration(i) * time.Minute)
+		fc.Revisions["1"] = append(fc.Revisions["1"], stormforge.Revision{ID: fmt.Sprintf("v%d", i), CreatedAt: &at, Author: "jane"})
+	}
+	cr := testCase("acme", "checkout")
+	e := external{client: fc, record: event.NewNopRecorder()}
+	if _, err := e.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %s", err)
+	}
+
+	mt := metav1.NewTime(created)
+	want := v1alpha1.TestCaseObservation{ID: "1", Org: "acme", Name: "checkout", CreatedAt: &mt, UpdatedAt: &mt, LastRunID: "r2", LastRunState: "running"}
+	for i := DefaultMaxRevisions; i > 0; i-- {
+		at := metav1.NewTime(created.Add(time.Duration(i) * time.Minute))
+		want.Revisions = append(want.Revisions, v1alpha1.TestCaseRevision{ID: fmt.Sprintf("v%d", i), CreatedAt: &at, Author: "jane"})
+	}
+	if diff := cmp.Diff(want, cr.Status.AtProvider, cmpopts.IgnoreFields(v1alpha1.TestCaseObservation{}, "RunsObservedAt")); diff != "" {
+		t.Errorf("e.Observe(...): -want status, +got status:\n%s\n", diff)
+	}
+	if cr.Status.AtProvider.RunsObservedAt == nil {
+		t.Errorf("e.Observe(...): runs should be recorded as observed")
+	}
+
+	// Observing the unchanged test case again must not list its revisions,
+	// nor its runs while its latest run is inactive and was recently listed.
+	fc.Runs["r2"] = stormforge.TestRun{ID: "r2", TestCaseID: "1", State: "done", StartedAt: &later}
+	fc.Runs["r4"] = stormforge.TestRun{ID: "r4", TestCaseID: "1", State: "running", StartedAt: &later}
+	fc.Revisions["1"] = nil
+	cr.Status.AtProvider.LastRunState = "done"
+	prev := cr.Status.AtProvider.DeepCopy()
+	if _, err := e.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %s", err)
+	}
+	if diff := cmp.Diff(*prev, cr.Status.AtProvider); diff != "" {
+		t.Errorf("e.Observe(...): -want cached status, +got status:\n%s\n", diff)
+	}
+}
+
+func TestRevisions(t *testing.T) {
+	revs := func(ids ...string) []stormforge.Revision {
+		out := make([]stormforge.Revision, len(ids))
+		for i, id := range ids {
+			out[i] = stormforge.Revision{ID: id}
+		}
+		return out
+	}
+	observed := func(ids ...string) []v1alpha1.TestCaseRevision {
+		out := make([]v1alpha1.TestCaseRevision, len(ids))
+		for i, id := range ids {
+			out[i] = v1alpha1.TestCaseRevision{ID: id}
+		}
+		return out
+	}
+
+	cases := map[string]struct {
+		reason string
+		revs   []stormforge.Revision
+		max    int
+		want   []v1alpha1.TestCaseRevision
+	}{
+		"None": {
+			reason: "A test case without revisions should report none.",
+			max:    2,
+		},
+		"BelowCap": {
+			reason: "Every revision should be reported while there are fewer than the cap.",
+			revs:   revs("v2", "v1"),
+			max:    3,
+			want:   observed("v2", "v1"),
+		},
+		"AtCap": {
+			reason: "Every revision should be reported when there are as many as the cap.",
+			revs:   revs("v3", "v2", "v1"),
+			max:    3,
+			want:   observed("v3", "v2", "v1"),
+		},
+		"AboveCap": {
+			reason: "The oldest revisions should be pruned once there are more than the cap.",
+			revs:   revs("v5", "v4", "v3", "v2", "v1"),
+			max:    3,
+			want:   observed("v5", "v4", "v3"),
+		},
+		"DefaultCap": {
+			reason: "Revisions should be pruned to the default cap if none is configured.",
+			revs:   revs("v7", "v6", "v5", "v4", "v3", "v2", "v1"),
+			want:   observed("v7", "v6", "v5", "v4", "v3"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := revisions(tc.revs, tc.max)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nrevisions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestRunsDue(t *testing.T) {
+	now := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	recently := metav1.NewTime(now.Add(-time.Minute))
+	long := metav1.NewTime(now.Add(-runsInterval))
+
+	cases := map[string]struct {
+		reason string
+		o      v1alpha1.TestCaseObservation
+		want   bool
+	}{
+		"NeverListed": {
+			reason: "Runs that were never listed should be due.",
+			want:   true,
+		},
+		"RecentlyListed": {
+			reason: "Recently listed runs whose latest run is inactive should not be due.",
+			o:      v1alpha1.TestCaseObservation{LastRunID: "r1", LastRunState: "done", RunsObservedAt: &recently},
+			want:   false,
+		},
+		"NoRuns": {
+			reason: "Recently listed runs of a test case that never ran should not be due.",
+			o:      v1alpha1.TestCaseObservation{RunsObservedAt: &recently},
+			want:   false,
+		},
+		"ActiveRun": {
+			reason: "Runs whose latest run is active should be due.",
+			o:      v1alpha1.TestCaseObservation{LastRunID: "r1", LastRunState: "running", RunsObservedAt: &recently},
+			want:   true,
+		},
+		"IntervalPassed": {
+			reason: "Runs last listed runsInterval ago should be due.",
+			o:      v1alpha1.TestCaseObservation{LastRunID: "r1", LastRunState: "done", RunsObservedAt: &long},
+			want:   true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := runsDue(&tc.o, now); got != tc.want {
+				t.Errorf("\n%s\nrunsDue(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+// freshnessClient records whether test cases were listed bypassing the
+// client's caches.
+type freshnessClient struct {
+	*fake.Client
+	fresh []bool
+}
+
+func (c *freshnessClient) ListTestCases(ctx context.Context, org string) ([]stormforge.TestCase, error) {
+	c.fresh = append(c.fresh, stormforge.IsFresh(ctx))
+	return c.Client.ListTestCases(ctx, org)
+}
+
+func TestObserveStaleness(t *testing.T) {
+	recently := metav1.NewTime(time.Now().Add(-time.Minute))
+	long := metav1.NewTime(time.Now().Add(-time.Hour))
+
+	type want struct {
+		fresh   bool
+		fetched bool
+	}
+
+	cases := map[string]struct {
+		reason       string
+		maxStaleness time.Duration
+		fetchedAt    *metav1.Time
+		want         want
+	}{
+		"NeverFetched": {
+			reason:       "A test case never read from the API should bypass the caches and record when it was read.",
+			maxStaleness: 10 * time.Minute,
+			want:         want{fresh: true, fetched: true},
+		},
+		"RecentlyFetched": {
+			reason:       "A test case recently read from the API should be observed from the caches.",
+			maxStaleness: 10 * time.Minute,
+			fetchedAt:    &recently,
+			want:         want{fresh: false},
+		},
+		"Stale": {
+			reason:       "A test case last read from the API longer ago than the max staleness should bypass the caches and record when it was read.",
+			maxStaleness: 10 * time.Minute,
+			fetchedAt:    &long,
+			want:         want{fresh: true, fetched: true},
+		},
+		"NoLimit": {
+			reason:    "A test case should always be observed from the caches without a max staleness.",
+			fetchedAt: &long,
+			want:      want{fresh: false},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fc := &freshnessClient{Client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}}}
+			cr := testCase("acme", "checkout")
+			cr.Status.AtProvider.FetchedAt = tc.fetchedAt
+			e := external{client: fc, record: event.NewNopRecorder(), maxStaleness: tc.maxStaleness}
+			start := time.Now()
+			if _, err := e.Observe(context.Background(), cr); err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff([]bool{tc.want.fresh}, fc.fresh); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want fresh, +got fresh:\n%s\n", tc.reason, diff)
+			}
+			got := cr.Status.AtProvider.FetchedAt
+			switch {
+			case tc.want.fetched && (got == nil || got.Time.Before(start)):
+				t.Errorf("\n%s\ne.Observe(...): want fetchedAt to be updated, got %v", tc.reason, got)
+			case !tc.want.fetched && got != tc.fetchedAt:
+				t.Errorf("\n%s\ne.Observe(...): want fetchedAt %v, got %v", tc.reason, tc.fetchedAt, got)
+			}
+		})
+	}
+}
+
+func TestLateInitialize(t *testing.T) {
+	type want struct {
+		params v1alpha1.TestCaseParameters
+		li     bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		params v1alpha1.TestCaseParameters
+		remote stormforge.TestCase
+		want   want
+	}{
+		"Unset": {
+			reason: "Unset labels and notes should be set to those of the remote test case.",
+			params: v1alpha1.TestCaseParameters{Name: "checkout"},
+			remote: stormforge.TestCase{Labels: map[string]string{"team": "payments"}, Notes: "nightly"},
+			want: want{
+				params: v1alpha1.TestCaseParameters{Name: "checkout", Labels: map[string]string{"team": "payments"}, Notes: "nightly"},
+				li:     true,
+			},
+		},
+		"Set": {
+			reason: "Labels and notes that are set should not be changed.",
+			params: v1alpha1.TestCaseParameters{Name: "checkout", Labels: map[string]string{}, Notes: "weekly"},
+			remote: stormforge.TestCase{Labels: map[string]string{"team": "payments"}, Notes: "nightly"},
+			want:   want{params: v1alpha1.TestCaseParameters{Name: "checkout", Labels: map[string]string{}, Notes: "weekly"}},
+		},
+		"RemoteUnset": {
+			reason: "Nothing should be late initialized from a remote test case without labels or notes.",
+			params: v1alpha1.TestCaseParameters{Name: "checkout"},
+			want:   want{params: v1alpha1.TestCaseParameters{Name: "checkout"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := &v1alpha1.TestCase{Spec: v1alpha1.TestCaseSpec{ForProvider: tc.params}}
+			li := lateInitialize(cr, &tc.remote)
+			if diff := cmp.Diff(tc.want.li, li); diff != "" {
+				t.Errorf("\n%s\nlateInitialize(...): -want late initialized, +got late initialized:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.params, cr.Spec.ForProvider); diff != "" {
+				t.Errorf("\n%s\nlateInitialize(...): -want parameters, +got parameters:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	script := "definition.session(\"checkout\", function(session) {});\n"
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(script))
+	}))
+	defer srv.Close()
+
+	withScript := func(cr *v1alpha1.TestCase) *v1alpha1.TestCase {
+		cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+		return cr
+	}
+
+	recording := `{"log": {"entries": [{"request": {"method": "GET", "url": "https://shop.example/"}}]}}`
+	converted, _ := har.Convert([]byte(recording))
+
+	type want struct {
+		scripts      map[string][]byte
+		externalName string
+		runs         int
+		thresholds   map[string]float64
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		client *fake.Client
+		mg     resource.Managed
+		want   want
+	}{
+		"Inline": {
+			reason: "The inline script should be uploaded exactly as written.",
+			client: &fake.Client{},
+			mg:     withScript(testCase("acme", "checkout")),
+			want:   want{scripts: map[string][]byte{"1": []byte(script)}, externalName: "1"},
+		},
+		"URL": {
+			reason: "The script served at the URL should be uploaded.",
+			client: &fake.Client{},
+			mg: func() resource.Managed {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{URL: &v1alpha1.URLSource{URL: srv.URL}}
+				return cr
+			}(),
+			want: want{scripts: map[string][]byte{"1": []byte(script)}, externalName: "1"},
+		},
+		"HAR": {
+			reason: "The HAR recording should be converted and uploaded.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*corev1.ConfigMap).Data = map[string]string{"checkout.har": recording}
+				return nil
+			})},
+			client: &fake.Client{},
+			mg: func() resource.Managed {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{HAR: &v1alpha1.ConfigMapKeySelector{Namespace: "default", Name: "recordings", Key: "checkout.har"}}
+				return cr
+			}(),
+			want: want{scripts: map[string][]byte{"1": converted}, externalName: "1"},
+		},
+		"K6Unsupported": {
+			reason: "A k6 script that cannot be translated should not be created.",
+			client: &fake.Client{},
+			mg: func() resource.Managed {
+				cr := testCase("acme", "checkout")
+				k6 := "export const options = { vus: 10 };"
+				cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &k6}
+				cr.Spec.ForProvider.ScriptFormat = v1alpha1.ScriptFormatK6
+				return cr
+			}(),
+			want: want{err: errors.Wrapf(errors.Wrap(errors.New("options.vus configures virtual users, which StormForge does not use; configure load with a constant-arrival-rate or ramping-arrival-rate scenario instead"), errConvertK6), errs.CreateFmt, externalKind)},
+		},
+		"Env": {
+			reason: "Environment variables should be declared ahead of the uploaded script.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*corev1.Secret).Data = map[string][]byte{"key": []byte("s3cr3t")}
+				return nil
+			})},
+			client: &fake.Client{},
+			mg: func() resource.Managed {
+				cr := withScript(testCase("acme", "checkout"))
+				cr.Spec.ForProvider.Env = []v1alpha1.EnvVar{
+					{Name: "REGION", Value: "eu"},
+					{Name: "API_KEY", ValueFrom: &v1alpha1.EnvVarSource{SecretKeyRef: &xpv1.SecretKeySelector{
+						SecretReference: xpv1.SecretReference{Namespace: "default", Name: "api"},
+						Key:             "key",
+					}}},
+				}
+				return cr
+			}(),
+			want: want{scripts: map[string][]byte{"1": []byte("// Environment variables of the test case.\nconst env = Object.freeze({\n  REGION: \"eu\",\n  API_KEY: \"s3cr3t\",\n});\n\n" + script)}, externalName: "1"},
+		},
+		"NoScript": {
+			reason: "A test case without a script source cannot be created.",
+			client: &fake.Client{},
+			mg:     testCase("acme", "checkout"),
+			want:   want{err: errors.Wrapf(errors.New(errNoScript), errs.CreateFmt, externalKind)},
+		},
+		"LaunchOnCreate": {
+			reason: "A run should be launched once the test case is created if requested.",
+			client: &fake.Client{},
+			mg: func() resource.Managed {
+				cr := withScript(testCase("acme", "checkout"))
+				cr.Spec.ForProvider.Launch = &v1alpha1.LaunchOptions{OnCreate: true, Title: "smoke"}
+				return cr
+			}(),
+			want: want{scripts: map[string][]byte{"1": []byte(script)}, externalName: "1", runs: 1},
+		},
+		"LaunchOnCreateWithSLOs": {
+			reason: "The thresholds of the SLOs of the test case should be enforced on the run launched once it is created.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*v1alpha1.SLO).Spec.MaxErrorRate = "0.5"
+				return nil
+			})},
+			client: &fake.Client{},
+			mg: func() resource.Managed {
+				cr := withScript(testCase("acme", "checkout"))
+				cr.Spec.ForProvider.Launch = &v1alpha1.LaunchOptions{OnCreate: true}
+				cr.Spec.ForProvider.SLORefs = []xpv1.Reference{{Name: "checkout"}}
+				return cr
+			}(),
+			want: want{
+				scripts:      map[string][]byte{"1": []byte(script)},
+				externalName: "1",
+				runs:         1,
+				thresholds:   map[string]float64{stormforge.ThresholdErrorRate: 0.5},
+			},
+		},
+		"CreateError": {
+			reason: "Errors creating the test case should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			mg:     withScript(testCase("acme", "checkout")),
+			want:   want{err: errors.Wrapf(errBoom, errs.CreateFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: tc.kube, client: tc.client, record: event.NewNopRecorder()}
+			got, err := e.Create(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(tc.mg)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+			if got.ExternalNameAssigned != (tc.want.externalName != "") {
+				t.Errorf("\n%s\ne.Create(...): want ExternalNameAssigned %t, got %t", tc.reason, tc.want.externalName != "", got.ExternalNameAssigned)
+			}
+			if diff := cmp.Diff(tc.want.scripts, tc.client.Scripts, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want scripts, +got scripts:\n%s\n", tc.reason, diff)
+			}
+			if len(tc.client.Runs) != tc.want.runs {
+				t.Errorf("\n%s\ne.Create(...): want %d runs, got %d", tc.reason, tc.want.runs, len(tc.client.Runs))
+			}
+			for _, o := range tc.client.RunOptions {
+				if diff := cmp.Diff(tc.want.thresholds, o.Thresholds); diff != "" {
+					t.Errorf("\n%s\ne.Create(...): -want thresholds, +got thresholds:\n%s\n", tc.reason, diff)
+				}
+			}
+		})
+	}
+}
+
+func TestMalformed(t *testing.T) {
+	cases := map[string]struct {
+		reason     string
+		definition string
+		want       bool
+	}{
+		"Valid": {
+			reason:     "A definition that configures the definition object should not be malformed.",
+			definition: "definition.session(\"checkout\", function(session) {});\n",
+			want:       false,
+		},
+		"Empty": {
+			reason: "An empty definition should be malformed.",
+			want:   true,
+		},
+		"Blank": {
+			reason:     "A definition of only whitespace should be malformed.",
+			definition: " \n\t",
+			want:       true,
+		},
+		"InvalidUTF8": {
+			reason:     "A definition that is not valid UTF-8 should be malformed.",
+			definition: "definition.session(\"\xff\")",
+			want:       true,
+		},
+		"NoDefinition": {
+			reason:     "A definition that never configures the definition object should be malformed.",
+			definition: "<html>Internal Server Error</html>",
+			want:       true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := malformed([]byte(tc.definition)); got != tc.want {
+				t.Errorf("\n%s\nmalformed(...): want %t, got %t", tc.reason, tc.want, got)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	secret := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		obj.(*corev1.Secret).Data = map[string][]byte{"checkout.js": []byte(script)}
+		return nil
+	})}
+	fromSecret := func() *v1alpha1.TestCase {
+		cr := testCase("acme", "checkout")
+		cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{SecretRef: &xpv1.SecretKeySelector{
+			SecretReference: xpv1.SecretReference{Namespace: "default", Name: "scripts"},
+			Key:             "checkout.js",
+		}}
+		return cr
+	}
+
+	type want struct {
+		scripts  map[string][]byte
+		checksum string
+		name     string
+		archived bool
+		err      error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		client *fake.Client
+		cr     *v1alpha1.TestCase
+		want   want
+	}{
+		"Reupload": {
+			reason: "The script should be uploaded again and its checksum recorded.",
+			kube:   secret,
+			client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+				Scripts:   map[string][]byte{"1": []byte("old")},
+			},
+			cr:   fromSecret(),
+			want: want{scripts: map[string][]byte{"1": []byte(script)}, checksum: checksum([]byte(script))},
+		},
+		"RepairEmpty": {
+			reason: "A definition that is empty in StormForge should be repaired by uploading the script again.",
+			kube:   secret,
+			client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+				Scripts:   map[string][]byte{"1": {}},
+			},
+			cr:   fromSecret(),
+			want: want{scripts: map[string][]byte{"1": []byte(script)}, checksum: checksum([]byte(script))},
+		},
+		"Archive": {
+			reason: "A test case whose TestCase says it is archived should be archived.",
+			kube:   secret,
+			client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+				Scripts:   map[string][]byte{"1": []byte(script)},
+			},
+			cr: func() *v1alpha1.TestCase {
+				cr := fromSecret()
+				cr.Spec.ForProvider.Archived = true
+				return cr
+			}(),
+			want: want{scripts: map[string][]byte{"1": []byte(script)}, checksum: checksum([]byte(script)), archived: true},
+		},
+		"Unarchive": {
+			reason: "A test case archived outside of Kubernetes should be unarchived if its TestCase says it is active.",
+			kube:   secret,
+			client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme", Archived: true}},
+				Scripts:   map[string][]byte{"1": []byte(script)},
+			},
+			cr:   withExternalName(fromSecret(), "1"),
+			want: want{scripts: map[string][]byte{"1": []byte(script)}, checksum: checksum([]byte(script))},
+		},
+		"NotFound": {
+			reason: "A test case that does not exist cannot be updated.",
+			kube:   secret,
+			client: &fake.Client{},
+			cr:     fromSecret(),
+			want:   want{err: errors.Wrapf(errors.New(errNotFound), errs.UpdateFmt, externalKind)},
+		},
+		"Rename": {
+			reason: "A test case bound by its external name should be renamed to the desired name.",
+			kube:   secret,
+			client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "legacy-checkout", Scope: "acme"}},
+				Scripts:   map[string][]byte{"1": []byte(script)},
+			},
+			cr:   withExternalName(fromSecret(), "1"),
+			want: want{scripts: map[string][]byte{"1": []byte(script)}, checksum: checksum([]byte(script)), name: "checkout"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{kube: tc.kube, client: tc.client, record: event.NewNopRecorder()}
+			_, err := e.Update(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.scripts, tc.client.Scripts, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want scripts, +got scripts:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.checksum, tc.cr.Status.AtProvider.DefinitionChecksum); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want checksum, +got checksum:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.name != "" && tc.client.TestCases["1"].Name != tc.want.name {
+				t.Errorf("\n%s\ne.Update(...): want name %q, got %q", tc.reason, tc.want.name, tc.client.TestCases["1"].Name)
+			}
+			if got := tc.client.TestCases["1"].Archived; got != tc.want.archived {
+				t.Errorf("\n%s\ne.Update(...): want archived %t, got %t", tc.reason, tc.want.archived, got)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+
+	running := map[string]stormforge.TestRun{
+		"r1": {ID: "r1", TestCaseID: "1", State: "running"},
+		"r2": {ID: "r2", TestCaseID: "1", State: "done"},
+	}
+	abort := func(cr *v1alpha1.TestCase) *v1alpha1.TestCase {
+		cr.Spec.ForProvider.ActiveRunsPolicy = v1alpha1.ActiveRunsAbort
+		return cr
+	}
+	archive := func(cr *v1alpha1.TestCase) *v1alpha1.TestCase {
+		now := metav1.Now()
+		cr.SetDeletionTimestamp(&now)
+		cr.Spec.ForProvider.DeletionBehavior = v1alpha1.DeletionBehaviorArchive
+		return cr
+	}
+
+	type want struct {
+		testCases map[string]stormforge.TestCase
+		runs      map[string]stormforge.TestRun
+		message   string
+		err       error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.TestCase
+		want   want
+	}{
+		"Deleted": {
+			reason: "The test case bound by the external name should be deleted.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{
+				"1": {ID: "1", Name: "checkout", Scope: "acme"},
+				"2": {ID: "2", Name: "search", Scope: "acme"},
+			}},
+			cr:   withExternalName(testCase("acme", "checkout"), "1"),
+			want: want{testCases: map[string]stormforge.TestCase{"2": {ID: "2", Name: "search", Scope: "acme"}}},
+		},
+		"DeletedByName": {
+			reason: "A test case without an external name should be deleted by its name.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			cr:     testCase("acme", "checkout"),
+			want:   want{testCases: map[string]stormforge.TestCase{}},
+		},
+		"AlreadyDeleted": {
+			reason: "A test case that no longer exists should not be an error.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{}},
+			cr:     withExternalName(testCase("acme", "checkout"), "1"),
+			want:   want{testCases: map[string]stormforge.TestCase{}},
+		},
+		"ActiveRunsWait": {
+			reason: "A test case with active runs should not be deleted until they have ended, and the Deleting condition should explain why.",
+			client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+				Runs:      running,
+			},
+			cr: withExternalName(testCase("acme", "checkout"), "1"),
+			want: want{
+				testCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+				runs:      running,
+				message:   fmt.Sprintf(errActiveRunsFmt, "r1"),
+				err:       errors.Wrapf(errors.Errorf(errActiveRunsFmt, "r1"), errs.DeleteFmt, externalKind),
+			},
+		},
+		"ActiveRunsAbort": {
+			reason: "The active runs of a test case should be aborted before it is deleted if its policy is Abort.",
+			client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+				Runs: map[string]stormforge.TestRun{
+					"r1": {ID: "r1", TestCaseID: "1", State: "running"},
+					"r2": {ID: "r2", TestCaseID: "1", State: "done"},
+				},
+			},
+			cr: abort(withExternalName(testCase("acme", "checkout"), "1")),
+			want: want{
+				testCases: map[string]stormforge.TestCase{},
+				runs: map[string]stormforge.TestRun{
+					"r1": {ID: "r1", TestCaseID: "1", State: "aborted"},
+					"r2": {ID: "r2", TestCaseID: "1", State: "done"},
+				},
+			},
+		},
+		"Archived": {
+			reason: "A test case should be archived rather than deleted if the deletion behavior is Archive.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			cr:     archive(withExternalName(testCase("acme", "checkout"), "1")),
+			want:   want{testCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme", Archived: true}}},
+		},
+		"ArchivedByName": {
+			reason: "A test case found by its name should be archived, and no longer be found once it has been.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			cr:     archive(testCase("acme", "checkout")),
+			want:   want{testCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme", Archived: true}}},
+		},
+		"AlreadyArchived": {
+			reason: "A test case that has already been archived should not be archived again.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme", Archived: true}}},
+			cr:     archive(withExternalName(testCase("acme", "checkout"), "1")),
+			want:   want{testCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme", Archived: true}}},
+		},
+		"Protected": {
+			reason: "A test case protected from deletion should not be deleted, and the Deleting condition should explain why.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			cr: func() *v1alpha1.TestCase {
+				cr := withExternalName(testCase("acme", "checkout"), "1")
+				meta.AddAnnotations(cr, map[string]string{AnnotationKeyDeletionProtection: "true"})
+				return cr
+			}(),
+			want: want{
+				testCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+				message:   fmt.Sprintf(errProtectedFmt, AnnotationKeyDeletionProtection),
+				err:       errors.Wrapf(errors.Errorf(errProtectedFmt, AnnotationKeyDeletionProtection), errs.DeleteFmt, externalKind),
+			},
+		},
+		"ProtectionDisabled": {
+			reason: "A test case whose deletion protection annotation is not true should be deleted.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			cr: func() *v1alpha1.TestCase {
+				cr := withExternalName(testCase("acme", "checkout"), "1")
+				meta.AddAnnotations(cr, map[string]string{AnnotationKeyDeletionProtection: "false"})
+				return cr
+			}(),
+			want: want{testCases: map[string]stormforge.TestCase{}},
+		},
+		"Error": {
+			reason: "Errors deleting the test case should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     testCase("acme", "checkout"),
+			want:   want{err: errors.Wrapf(errBoom, errs.DeleteFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client, record: event.NewNopRecorder()}
+			err := e.Delete(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.testCases, tc.client.TestCases, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want test cases, +got test cases:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.runs, tc.client.Runs, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want runs, +got runs:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.message, tc.cr.GetCondition(xpv1.TypeReady).Message); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want message, +got message:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			o, err := e.Observe(context.Background(), tc.cr)
+			if err != nil {
+				t.Fatalf("\n%s\ne.Observe(...): unexpected error: %s", tc.reason, err)
+			}
+			if o.ResourceExists {
+				t.Errorf("\n%s\ne.Observe(...): want the deleted test case not to exist", tc.reason)
+			}
+		})
+	}
+}
+
+func TestDeletionPolicy(t *testing.T) {
+	now := metav1.Now()
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme(...): %s", err)
+	}
+
+	cases := map[string]struct {
+		reason string
+		policy xpv1.DeletionPolicy
+		want   map[string]stormforge.TestCase
+	}{
+		"Delete": {
+			reason: "Deleting a TestCase with the Delete policy should delete its test case.",
+			policy: xpv1.DeletionDelete,
+			want:   map[string]stormforge.TestCase{},
+		},
+		"Orphan": {
+			reason: "Deleting a TestCase with the Orphan policy should leave its test case in place.",
+			policy: xpv1.DeletionOrphan,
+			want:   map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			fc := &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}}
+			cr := withExternalName(testCase("acme", "checkout"), "1")
+			cr.SetDeletionTimestamp(&now)
+			cr.SetDeletionPolicy(tc.policy)
+			kube := &test.MockClient{
+				MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+					cr.DeepCopyInto(obj.(*v1alpha1.TestCase))
+					return nil
+				}),
+				MockUpdate:       test.NewMockUpdateFn(nil),
+				MockStatusUpdate: test.NewMockStatusUpdateFn(nil),
+			}
+			r := managed.NewReconciler(&xpfake.Manager{Client: kube, Scheme: s},
+				resource.ManagedKind(v1alpha1.TestCaseGroupVersionKind),
+				managed.WithExternalConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+					return &external{client: fc, record: event.NewNopRecorder()}, nil
+				})),
+				managed.WithInitializers(),
+				managed.WithReferenceResolver(managed.ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+				managed.WithConnectionPublishers(),
+				managed.WithFinalizer(resource.FinalizerFns{
+					AddFinalizerFn:    func(_ context.Context, _ resource.Object) error { return nil },
+					RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil },
+				}),
+			)
+			if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.GetName()}}); err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, fc.TestCases, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want test cases, +got test cases:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+// A createError fails to create any test case.
+type createError struct {
+	*fake.Client
+	err error
+}
+
+func (c *createError) CreateTestCase(_ context.Context, _, _ string, _ []byte, _ ...stormforge.TestCaseOption) (*stormforge.TestCase, error) {
+	return nil, c.err
+}
+
+func TestSyncedCondition(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {"
+	s := runtime.NewScheme()
+	if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+		t.Fatalf("AddToScheme(...): %s", err)
+	}
+	rejected := &stormforge.APIError{
+		StatusCode: http.StatusUnprocessableEntity,
+		Message:    "Invalid definition: unexpected end of input (code script_invalid)",
+		Errors:     []stormforge.ErrorObject{{Status: "422", Code: "script_invalid", Title: "Invalid definition", Detail: "unexpected end of input"}},
+	}
+
+	cr := testCase("acme", "checkout")
+	cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+	var got xpv1.Condition
+	kube := &test.MockClient{
+		MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			cr.DeepCopyInto(obj.(*v1alpha1.TestCase))
+			return nil
+		}),
+		MockUpdate: test.NewMockUpdateFn(nil),
+		MockStatusUpdate: test.NewMockStatusUpdateFn(nil, func(obj client.Object) error {
+			got = obj.(*v1alpha1.TestCase).GetCondition(xpv1.TypeSynced)
+			return nil
+		}),
+	}
+	r := managed.NewReconciler(&xpfake.Manager{Client: kube, Scheme: s},
+		resource.ManagedKind(v1alpha1.TestCaseGroupVersionKind),
+		managed.WithExternalConnecter(managed.ExternalConnectorFn(func(_ context.Context, _ resource.Managed) (managed.ExternalClient, error) {
+			return &external{client: &createError{Client: &fake.Client{}, err: rejected}, record: event.NewNopRecorder()}, nil
+		})),
+		managed.WithInitializers(),
+		managed.WithReferenceResolver(managed.ReferenceResolverFn(func(_ context.Context, _ resource.Managed) error { return nil })),
+		managed.WithConnectionPublishers(),
+		managed.WithFinalizer(resource.FinalizerFns{
+			AddFinalizerFn:    func(_ context.Context, _ resource.Object) error { return nil },
+			RemoveFinalizerFn: func(_ context.Context, _ resource.Object) error { return nil },
+		}),
+	)
+	if _, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: cr.GetName()}}); err != nil {
+		t.Fatalf("r.Reconcile(...): unexpected error: %s", err)
+	}
+
+	want := xpv1.ReconcileError(errors.New("create failed: cannot create test case: StormForge API: Invalid (status 422): Invalid definition: unexpected end of input (code script_invalid)"))
+	reason := "A definition rejected by the API should be reported in the Synced condition with the API's message and error code."
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+		t.Errorf("\n%s\nr.Reconcile(...): -want condition, +got condition:\n%s\n", reason, diff)
+	}
+}
+
+// An uploadRecorder records the definitions uploaded by UpdateTestCase.
+type uploadRecorder struct {
+	*fake.Client
+	uploads [][]byte
+}
+
+func (u *uploadRecorder) UpdateTestCase(ctx context.Context, id, name string, script []byte, o ...stormforge.TestCaseOption) (*stormforge.TestCase, error) {
+	u.uploads = append(u.uploads, script)
+	return u.Client.UpdateTestCase(ctx, id, name, script, o...)
+}
+
+func TestUpdateDefinition(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	errBoom := errors.New("boom")
+
+	type want struct {
+		uploads [][]byte
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		remote []byte
+		err    error
+		want   want
+	}{
+		"Changed": {
+			reason: "A definition that differs from the remote definition should be uploaded.",
+			remote: []byte("old"),
+			want:   want{uploads: [][]byte{[]byte(script)}},
+		},
+		"Unchanged": {
+			reason: "A definition equal to the remote definition should not be uploaded again.",
+			remote: []byte(script),
+			want:   want{uploads: [][]byte{nil}},
+		},
+		"GetDefinitionError": {
+			reason: "Errors getting the remote definition should be wrapped.",
+			err:    errBoom,
+			want:   want{err: errors.Wrapf(errors.Wrap(errBoom, errGetDefinition), errs.UpdateFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			u := &uploadRecorder{Client: &fake.Client{
+				TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+				Scripts:   map[string][]byte{"1": tc.remote},
+			}}
+			cr := testCase("acme", "checkout")
+			cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+			e := external{client: u, record: event.NewNopRecorder()}
+			if tc.err != nil {
+				e.client = &definitionError{Client: u.Client, err: tc.err}
+			}
+			_, err := e.Update(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.uploads, u.uploads); diff != "" {
+				t.Errorf("\n%s\ne.Update(...): -want uploads, +got uploads:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestScriptConfigMapChanged(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	version := "1"
+	kube := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+		cm := obj.(*corev1.ConfigMap)
+		cm.SetResourceVersion(version)
+		cm.Data = map[string]string{"checkout.js": script}
+		return nil
+	})}
+	u := &uploadRecorder{Client: &fake.Client{
+		TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}},
+		Scripts:   map[string][]byte{"1": []byte(script)},
+	}}
+	cr := testCase("acme", "checkout")
+	cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{
+		ConfigMapRef: &v1alpha1.ConfigMapKeySelector{Namespace: "default", Name: "scripts", Key: "checkout.js"},
+	}
+	e := external{kube: kube, client: u, record: event.NewNopRecorder()}
+
+	o, err := e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %s", err)
+	}
+	if !o.ResourceUpToDate || cr.Status.AtProvider.ScriptConfigMapVersion != "1" {
+		t.Fatalf("e.Observe(...): a definition in sync should be up to date and record version %q of its ConfigMap, got up to date %t and version %q", "1", o.ResourceUpToDate, cr.Status.AtProvider.ScriptConfigMapVersion)
+	}
+
+	version = "2"
+	o, err = e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %s", err)
+	}
+	if o.ResourceUpToDate {
+		t.Errorf("e.Observe(...): a test case whose ConfigMap changed since it was synced should not be up to date")
+	}
+	if diff := cmp.Diff([]string{"definition"}, e.drift); diff != "" {
+		t.Errorf("e.Observe(...): -want drift, +got drift:\n%s\n", diff)
+	}
+
+	if _, err := e.Update(context.Background(), cr); err != nil {
+		t.Fatalf("e.Update(...): unexpected error: %s", err)
+	}
+	if got := cr.Status.AtProvider.ScriptConfigMapVersion; got != "2" {
+		t.Errorf("e.Update(...): want synced ConfigMap version %q, got %q", "2", got)
+	}
+	if diff := cmp.Diff([][]byte{nil}, u.uploads); diff != "" {
+		t.Errorf("e.Update(...): an unchanged definition should not be uploaded again: -want uploads, +got uploads:\n%s\n", diff)
+	}
+
+	o, err = e.Observe(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %s", err)
+	}
+	if !o.ResourceUpToDate {
+		t.Errorf("e.Observe(...): a test case synced from the current version of its ConfigMap should be up to date")
+	}
+}
+
+// A definitionError fails to get the definition of any test case.
+type definitionError struct {
+	*fake.Client
+	err error
+}
+
+func (d *definitionError) GetDefinition(_ context.Context, _ string) ([]byte, error) {
+	return nil, d.err
+}
+
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestEvents(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	create := func(ctx context.Context, e *external, cr *v1alpha1.TestCase) error {
+		_, err := e.Create(ctx, cr)
+		return err
+	}
+	update := func(ctx context.Context, e *external, cr *v1alpha1.TestCase) error {
+		_, err := e.Update(ctx, cr)
+		return err
+	}
+	observe := func(ctx context.Context, e *external, cr *v1alpha1.TestCase) error {
+		_, err := e.Observe(ctx, cr)
+		return err
+	}
+	remote := func() map[string]stormforge.TestCase {
+		return map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     func() *v1alpha1.TestCase
+		fn     func(ctx context.Context, e *external, cr *v1alpha1.TestCase) error
+		want   []event.Event
+	}{
+		"Created": {
+			reason: "Creating a test case should be recorded.",
+			client: &fake.Client{},
+			cr: func() *v1alpha1.TestCase {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+				return cr
+			},
+			fn:   create,
+			want: []event.Event{event.Normal(reasonCreated, "Created test case 1")},
+		},
+		"CreatedAndLaunched": {
+			reason: "Launching a run on create should be recorded after the test case was created.",
+			client: &fake.Client{},
+			cr: func() *v1alpha1.TestCase {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+				cr.Spec.ForProvider.Launch = &v1alpha1.LaunchOptions{OnCreate: true}
+				return cr
+			},
+			fn: create,
+			want: []event.Event{
+				event.Normal(reasonCreated, "Created test case 1"),
+				event.Normal(reasonLaunched, "Launched run 2 of test case 1"),
+			},
+		},
+		"Updated": {
+			reason: "Updating a test case without a new definition should be recorded.",
+			client: &fake.Client{TestCases: remote(), Scripts: map[string][]byte{"1": []byte(script)}},
+			cr: func() *v1alpha1.TestCase {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+				return cr
+			},
+			fn:   update,
+			want: []event.Event{event.Normal(reasonUpdated, "Updated test case 1")},
+		},
+		"UpdatedDefinition": {
+			reason: "Uploading a new definition should be called out when recording the update.",
+			client: &fake.Client{TestCases: remote(), Scripts: map[string][]byte{"1": []byte("definition.session(\"old\", function(session) {});\n")}},
+			cr: func() *v1alpha1.TestCase {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+				return cr
+			},
+			fn:   update,
+			want: []event.Event{event.Normal(reasonUpdated, "Updated test case 1 and uploaded a new revision of its definition")},
+		},
+		"RepairedDefinition": {
+			reason: "Re-uploading a definition that was emptied in StormForge should be recorded as a repair.",
+			client: &fake.Client{TestCases: remote(), Scripts: map[string][]byte{"1": {}}},
+			cr: func() *v1alpha1.TestCase {
+				cr := testCase("acme", "checkout")
+				cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+				return cr
+			},
+			fn: update,
+			want: []event.Event{
+				event.Normal(reasonUpdated, "Updated test case 1 and uploaded a new revision of its definition"),
+				event.Warning(reasonRepaired, errors.New("re-uploaded the definition of test case 1, which was empty or malformed in StormForge")),
+			},
+		},
+		"Deleted": {
+			reason: "Deleting a test case should be recorded.",
+			client: &fake.Client{TestCases: remote()},
+			cr:     func() *v1alpha1.TestCase { return testCase("acme", "checkout") },
+			fn: func(ctx context.Context, e *external, cr *v1alpha1.TestCase) error {
+				return e.Delete(ctx, cr)
+			},
+			want: []event.Event{event.Normal(reasonDeleted, "Deleted test case 1")},
+		},
+		"Missing": {
+			reason: "A previously observed test case that no longer exists should be recorded as a warning.",
+			client: &fake.Client{},
+			cr: func() *v1alpha1.TestCase {
+				cr := testCase("acme", "checkout")
+				cr.Status.AtProvider.ID = "1"
+				return cr
+			},
+			fn:   observe,
+			want: []event.Event{event.Warning(reasonMissing, errors.New("test case 1 no longer exists in StormForge and will be created again"))},
+		},
+		"RenamedRemotely": {
+			reason: "A test case renamed in StormForge should be recorded as a warning.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout-renamed", Scope: "acme"}}},
+			cr: func() *v1alpha1.TestCase {
+				cr := withExternalName(testCase("acme", "checkout"), "1")
+				cr.Status.AtProvider = v1alpha1.TestCaseObservation{ID: "1", Org: "acme", Name: "checkout"}
+				return cr
+			},
+			fn:   observe,
+			want: []event.Event{event.Warning(reasonRenamed, errors.New(`test case 1 is named "checkout-renamed" in StormForge, but its TestCase names it "checkout"`))},
+		},
+		"RenamedLocally": {
+			reason: "A TestCase renamed by its user should not be recorded as renamed in StormForge.",
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			cr: func() *v1alpha1.TestCase {
+				cr := withExternalName(testCase("acme", "checkout-renamed"), "1")
+				cr.Status.AtProvider = v1alpha1.TestCaseObservation{ID: "1", Org: "acme", Name: "checkout"}
+				return cr
+			},
+			fn: observe,
+		},
+		"NeverCreated": {
+			reason: "A test case that was never created should not be recorded as missing.",
+			client: &fake.Client{},
+			cr:     func() *v1alpha1.TestCase { return testCase("acme", "checkout") },
+			fn:     observe,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			e := &external{client: tc.client, record: r}
+			if err := tc.fn(context.Background(), e, tc.cr()); err != nil {
+				t.Fatalf("\n%s\nunexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want, r.events); diff != "" {
+				t.Errorf("\n%s\n-want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDryRunObserve(t *testing.T) {
+	now := metav1.Now()
+	planned := func(r xpv1.ConditionReason, msg string) *v1alpha1.TestCase {
+		cr := &v1alpha1.TestCase{}
+		cr.SetConditions(dryRunCondition(r, msg))
+		return cr
+	}
+	unavailable := xpv1.Unavailable()
+	unavailable.Reason, unavailable.Message = ReasonDryRun, "Dry run: would create external resource"
+
+	type args struct {
+		observation managed.ExternalObservation
+		drift       []string
+		mg          resource.Managed
+	}
+
+	type want struct {
+		o          managed.ExternalObservation
+		conditions []xpv1.Condition
+		events     []event.Event
+	}
+
+	cases := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"PlannedCreate": {
+			reason: "A missing external resource should be reported as existing but unavailable, and a planned create recorded.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: false},
+				mg:          &v1alpha1.TestCase{},
+			},
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				conditions: []xpv1.Condition{
+					dryRunCondition(ReasonPlannedCreate, "Dry run: would create external resource"),
+					unavailable,
+				},
+				events: []event.Event{event.Normal(reasonPlannedCreate, "Dry run: would create external resource")},
+			},
+		},
+		"PlannedUpdate": {
+			reason: "An outdated external resource should be reported as up to date and a planned update of its changed fields recorded.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				drift:       []string{"name", "definition"},
+				mg:          &v1alpha1.TestCase{},
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				conditions: []xpv1.Condition{dryRunCondition(ReasonPlannedUpdate, "Dry run: would update external resource to change its name, definition")},
+				events:     []event.Event{event.Normal(reasonPlannedUpdate, "Dry run: would update external resource to change its name, definition")},
+			},
+		},
+		"PlanUnchanged": {
+			reason: "A planned change that was already reported should not be recorded again.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				drift:       []string{"notes"},
+				mg:          planned(ReasonPlannedUpdate, "Dry run: would update external resource to change its notes"),
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				conditions: []xpv1.Condition{dryRunCondition(ReasonPlannedUpdate, "Dry run: would update external resource to change its notes")},
+			},
+		},
+		"PlanChanged": {
+			reason: "A planned change that differs from the one already reported should be recorded.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: false},
+				drift:       []string{"labels"},
+				mg:          planned(ReasonPlannedUpdate, "Dry run: would update external resource to change its notes"),
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				conditions: []xpv1.Condition{dryRunCondition(ReasonPlannedUpdate, "Dry run: would update external resource to change its labels")},
+				events:     []event.Event{event.Normal(reasonPlannedUpdate, "Dry run: would update external resource to change its labels")},
+			},
+		},
+		"UpToDate": {
+			reason: "An up to date external resource should report no changes and not record any planned change.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				mg:          &v1alpha1.TestCase{},
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				conditions: []xpv1.Condition{dryRunCondition(ReasonNoChanges, "Dry run: no changes to make")},
+			},
+		},
+		"PlannedDelete": {
+			reason: "A deleted managed resource should be reported as having no external resource and a planned delete recorded.",
+			args: args{
+				observation: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				mg:          &v1alpha1.TestCase{ObjectMeta: metav1.ObjectMeta{DeletionTimestamp: &now}},
+			},
+			want: want{
+				o:          managed.ExternalObservation{ResourceExists: false, ResourceUpToDate: true},
+				conditions: []xpv1.Condition{dryRunCondition(ReasonPlannedDelete, "Dry run: would delete external resource")},
+				events:     []event.Event{event.Normal(reasonPlannedDelete, "Dry run: would delete external resource")},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mutate := func() { t.Errorf("\n%s\nthe wrapped client must not be mutated during a dry run", tc.reason) }
+			r := &recorder{}
+			e := &dryRunExternal{
+				client: managed.ExternalClientFns{
+					ObserveFn: func(_ context.Context, _ resource.Managed) (managed.ExternalObservation, error) {
+						return tc.args.observation, nil
+					},
+					CreateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalCreation, error) {
+						mutate()
+						return managed.ExternalCreation{}, nil
+					},
+					UpdateFn: func(_ context.Context, _ resource.Managed) (managed.ExternalUpdate, error) {
+						mutate()
+						return managed.ExternalUpdate{}, nil
+					},
+					DeleteFn: func(_ context.Context, _ resource.Managed) error {
+						mutate()
+						return nil
+					},
+				},
+				record: r,
+				drift:  func() []string { return tc.args.drift },
+			}
+
+			got, err := e.Observe(context.Background(), tc.args.mg)
+			if err != nil {
+				t.Errorf("\n%s\ne.Observe(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			cr := tc.args.mg.(*v1alpha1.TestCase)
+			if diff := cmp.Diff(tc.want.conditions, cr.Status.Conditions, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime"), cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want conditions, +got conditions:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+
+			// None of these calls may reach the wrapped client.
+			_, _ = e.Create(context.Background(), tc.args.mg)
+			_, _ = e.Update(context.Background(), tc.args.mg)
+			_ = e.Delete(context.Background(), tc.args.mg)
+		})
+	}
+}
+
+func TestConnect(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	cases := map[string]struct {
+		reason string
+		client *clients.Connector
+		mg     resource.Managed
+		want   error
+	}{
+		"NotMyType": {
+			reason: "Connecting to a managed resource of another kind should fail.",
+			mg:     &xpfake.Managed{},
+			want:   errors.New(errNotMyType),
+		},
+		"ConnectError": {
+			reason: "Errors connecting to StormForge should be returned.",
+			client: &clients.Connector{
+				Usage: resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return errBoom }),
+			},
+			mg:   testCase("acme", "checkout"),
+			want: errors.Wrap(errBoom, errs.TrackPCUsage),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			c := &connector{client: tc.client}
+			_, err := c.Connect(context.Background(), tc.mg)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nc.Connect(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestConnectEndpoint(t *testing.T) {
+	sourceToken := xpv1.CredentialsSource("Token")
+	credentials.Register(sourceToken, func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+		return []byte("jwt"), nil
+	})
+	defer credentials.Unregister(sourceToken)
+
+	called := false
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		_, _ = w.Write([]byte(`{"data":[]}`))
+	}))
+	defer srv.Close()
+
+	c := &connector{client: &clients.Connector{
+		Kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			pc := obj.(*apisv1alpha1.ProviderConfig)
+			pc.Spec.Credentials.Source = sourceToken
+			pc.Spec.Endpoint = srv.URL
+			return nil
+		})},
+		Usage:     resource.TrackerFn(func(_ context.Context, _ resource.Managed) error { return nil }),
+		Log:       logging.NewNopLogger(),
+		NewClient: func(token string, o ...stormforge.Option) stormforge.Client { return stormforge.New(token, o...) },
+	}}
+	cr := testCase("acme", "checkout")
+	cr.Spec.ProviderConfigReference = &xpv1.Reference{Name: "example"}
+
+	e, err := c.Connect(context.Background(), cr)
+	if err != nil {
+		t.Fatalf("c.Connect(...): unexpected error: %s", err)
+	}
+	if _, err := e.Observe(context.Background(), cr); err != nil {
+		t.Fatalf("e.Observe(...): unexpected error: %s", err)
+	}
+	if !called {
+		t.Errorf("c.Connect(...): client does not use the ProviderConfig's endpoint %q", srv.URL)
+	}
+}
+
+func TestNotMyType(t *testing.T) {
+	e := &external{}
+	mg := &xpfake.Managed{}
+	want := errors.New(errNotMyType)
+
+	_, err := e.Observe(context.Background(), mg)
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Observe(...): -want error, +got error:\n%s\n", diff)
+	}
+	_, err = e.Create(context.Background(), mg)
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Create(...): -want error, +got error:\n%s\n", diff)
+	}
+	_, err = e.Update(context.Background(), mg)
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Update(...): -want error, +got error:\n%s\n", diff)
+	}
+	err = e.Delete(context.Background(), mg)
+	if diff := cmp.Diff(want, err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Delete(...): -want error, +got error:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"crypto/tls"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+const (
+	errGetClientCertificate     = "cannot get client certificate Secret"
+	errInvalidClientCertificate = "client certificate Secret does not contain a valid certificate and private key"
+)
+
+// clientCertificate returns the options attaching the client certificate of
+// the supplied test case, and the checksum of the certificate and key. No
+// options and an empty checksum are returned if the test case has no client
+// certificate.
+func (c *external) clientCertificate(ctx context.Context, cr *v1alpha1.TestCase) ([]stormforge.TestCaseOption, string, error) {
+	ref := cr.Spec.ForProvider.ClientCertificateSecretRef
+	if ref == nil {
+		return nil, "", nil
+	}
+	s := &corev1.Secret{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}, s); err != nil {
+		return nil, "", errors.Wrap(err, errGetClientCertificate)
+	}
+	cert, key := s.Data[corev1.TLSCertKey], s.Data[corev1.TLSPrivateKeyKey]
+	if _, err := tls.X509KeyPair(cert, key); err != nil {
+		return nil, "", errors.Wrap(err, errInvalidClientCertificate)
+	}
+	return []stormforge.TestCaseOption{stormforge.WithClientCertificate(cert, key)}, checksum(append(append([]byte{}, cert...), key...)), nil
+}
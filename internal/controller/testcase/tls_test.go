@@ -0,0 +1,109 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+)
+
+// keyPair returns a PEM encoded self-signed certificate and its private key.
+func keyPair(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+	k, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "load-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &k.PublicKey, k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	kder, err := x509.MarshalECPrivateKey(k)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: kder})
+}
+
+func TestClientCertificate(t *testing.T) {
+	cert, key := keyPair(t)
+	script := "definition.session(\"checkout\", function(session) {});\n"
+
+	withCert := func(data map[string][]byte) (client.Client, *v1alpha1.TestCase) {
+		kube := &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.(*corev1.Secret).Data = data
+			return nil
+		})}
+		cr := testCase("acme", "checkout")
+		cr.Spec.ForProvider.Script = &v1alpha1.ScriptSource{Inline: &script}
+		cr.Spec.ForProvider.ClientCertificateSecretRef = &xpv1.SecretReference{Namespace: "default", Name: "client-tls"}
+		return kube, cr
+	}
+
+	t.Run("Attached", func(t *testing.T) {
+		kube, cr := withCert(map[string][]byte{corev1.TLSCertKey: cert, corev1.TLSPrivateKeyKey: key})
+		fc := &fake.Client{}
+		e := external{kube: kube, client: fc, record: event.NewNopRecorder()}
+		if _, err := e.Create(context.Background(), cr); err != nil {
+			t.Fatalf("e.Create(...): unexpected error: %s", err)
+		}
+		notes := ""
+		want := map[string]stormforge.TestCaseOptions{"1": {ClientCertificate: cert, ClientKey: key, Labels: map[string]string{}, Notes: &notes}}
+		if diff := cmp.Diff(want, fc.Options); diff != "" {
+			t.Errorf("e.Create(...): -want options, +got options:\n%s\n", diff)
+		}
+		tc := fc.TestCases["1"]
+		if d, err := e.diff(context.Background(), cr, &tc); err != nil || len(d) != 0 {
+			t.Errorf("e.diff(...): want no differences after create, got %v, %v", d, err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		kube, cr := withCert(map[string][]byte{corev1.TLSCertKey: cert})
+		e := external{kube: kube, client: &fake.Client{}, record: event.NewNopRecorder()}
+		if _, err := e.Create(context.Background(), cr); err == nil {
+			t.Errorf("e.Create(...): want error for a Secret without a private key")
+		}
+	})
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+const (
+	errTimeUnit         = "traffic model time unit must be positive"
+	errPhaseDurationFmt = "duration of arrival phase %d must be at least one second"
+	errGetTrafficModel  = "cannot get TrafficModel"
+)
+
+// defaultTimeUnit is the period arrival rates refer to unless configured.
+const defaultTimeUnit = time.Second
+
+// trafficModel returns the traffic model of the supplied test case; that of
+// the TrafficModel it references, if any, or else its own.
+func (c *external) trafficModel(ctx context.Context, cr *v1alpha1.TestCase) (*v1alpha1.TrafficModelSpec, error) {
+	ref := cr.Spec.ForProvider.TrafficModelRef
+	if ref == nil {
+		return cr.Spec.ForProvider.TrafficModel, nil
+	}
+	tm := &v1alpha1.TrafficModel{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: ref.Name}, tm); err != nil {
+		return nil, errors.Wrap(err, errGetTrafficModel)
+	}
+	return &tm.Spec, nil
+}
+
+// referencingTestCases returns a function that maps a TrafficModel to the
+// TestCases that reference it, so that they are reconciled when it changes.
+// TestCases that cannot be listed are left to their next poll.
+func referencingTestCases(kube client.Reader) handler.MapFunc {
+	return func(o client.Object) []reconcile.Request {
+		l := &v1alpha1.TestCaseList{}
+		if err := kube.List(context.Background(), l); err != nil {
+			return nil
+		}
+		reqs := []reconcile.Request{}
+		for _, tc := range l.Items {
+			if ref := tc.Spec.ForProvider.TrafficModelRef; ref != nil && ref.Name == o.GetName() {
+				reqs = append(reqs, reconcile.Request{NamespacedName: types.NamespacedName{Name: tc.GetName()}})
+			}
+		}
+		return reqs
+	}
+}
+
+// withTrafficModel returns the supplied definition followed by the arrival
+// phases of the supplied traffic model. The last arrival phases a definition
+// sets take effect, so the traffic model replaces any the script sets. The
+// definition is returned unchanged if there is no traffic model.
+func withTrafficModel(tm *v1alpha1.TrafficModelSpec, definition []byte) ([]byte, error) {
+	if tm == nil {
+		return definition, nil
+	}
+	unit := defaultTimeUnit
+	if tm.TimeUnit != nil {
+		unit = tm.TimeUnit.Duration
+	}
+	if unit <= 0 {
+		return nil, errors.New(errTimeUnit)
+	}
+
+	b := &bytes.Buffer{}
+	b.Write(definition)
+	b.WriteString("\n// Arrival phases of the traffic model of the test case.\n")
+	b.WriteString("definition.setArrivalPhases([\n")
+	for i, p := range tm.Phases {
+		d := int64(p.Duration.Duration / time.Second)
+		if d < 1 {
+			return nil, errors.Errorf(errPhaseDurationFmt, i)
+		}
+		fmt.Fprintf(b, "  {\n    duration: %d,\n    rate: %s,\n", d, perSecond(p.Rate, unit))
+		if p.TargetRate != nil && *p.TargetRate != p.Rate {
+			fmt.Fprintf(b, "    targetRate: %s,\n", perSecond(*p.TargetRate, unit))
+		}
+		if p.MaxClients != nil {
+			fmt.Fprintf(b, "    maxClients: %d,\n", *p.MaxClients)
+		}
+		b.WriteString("  },\n")
+	}
+	b.WriteString("]);\n")
+	return b.Bytes(), nil
+}
+
+// perSecond returns the supplied rate per time unit as a rate per second.
+func perSecond(rate int32, unit time.Duration) string {
+	return strconv.FormatFloat(float64(rate)/unit.Seconds(), 'f', -1, 64)
+}
+
+// withLaunchOptions returns the supplied definition followed by the cluster
+// options of the launch options of the supplied test case. The definition is
+// returned unchanged if the test case configures neither a sizing nor a
+// region.
+func withLaunchOptions(cr *v1alpha1.TestCase, definition []byte) []byte {
+	l := cr.Spec.ForProvider.Launch
+	if l == nil || (l.Sizing == "" && l.Region == "") {
+		return definition
+	}
+	b := &bytes.Buffer{}
+	b.Write(definition)
+	b.WriteString("\n// Launch options of the test case.\ndefinition.setTestOptions({\n  cluster: {\n")
+	if l.Sizing != "" {
+		fmt.Fprintf(b, "    sizing: %s,\n", literal(l.Sizing))
+	}
+	if l.Region != "" {
+		fmt.Fprintf(b, "    region: %s,\n", literal(l.Region))
+	}
+	b.WriteString("  },\n});\n")
+	return b.Bytes()
+}
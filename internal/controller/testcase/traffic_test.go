@@ -0,0 +1,223 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testcase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+func TestWithTrafficModel(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+	i32 := func(i int32) *int32 { return &i }
+
+	type want struct {
+		definition string
+		err        error
+	}
+
+	cases := map[string]struct {
+		reason string
+		tm     *v1alpha1.TrafficModelSpec
+		want   want
+	}{
+		"NoTrafficModel": {
+			reason: "The definition should be unchanged without a traffic model.",
+			want:   want{definition: script},
+		},
+		"Phases": {
+			reason: "Phases should be compiled into arrival phases with rates per second.",
+			tm: &v1alpha1.TrafficModelSpec{
+				TimeUnit: &metav1.Duration{Duration: time.Minute},
+				Phases: []v1alpha1.ArrivalPhase{
+					{Duration: metav1.Duration{Duration: 2 * time.Minute}, Rate: 30, TargetRate: i32(120)},
+					{Duration: metav1.Duration{Duration: 5 * time.Minute}, Rate: 120, TargetRate: i32(120), MaxClients: i32(500)},
+				},
+			},
+			want: want{definition: script + `
+// Arrival phases of the traffic model of the test case.
+definition.setArrivalPhases([
+  {
+    duration: 120,
+    rate: 0.5,
+    targetRate: 2,
+  },
+  {
+    duration: 300,
+    rate: 2,
+    maxClients: 500,
+  },
+]);
+`},
+		},
+		"ShortPhase": {
+			reason: "Phases shorter than a second cannot be expressed.",
+			tm:     &v1alpha1.TrafficModelSpec{Phases: []v1alpha1.ArrivalPhase{{Duration: metav1.Duration{Duration: time.Millisecond}, Rate: 1}}},
+			want:   want{err: errors.Errorf(errPhaseDurationFmt, 0)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := withTrafficModel(tc.tm, []byte(script))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nwithTrafficModel(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.definition, string(got)); diff != "" {
+				t.Errorf("\n%s\nwithTrafficModel(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestWithLaunchOptions(t *testing.T) {
+	script := "definition.session(\"checkout\", function(session) {});\n"
+
+	cases := map[string]struct {
+		reason string
+		launch *v1alpha1.LaunchOptions
+		want   string
+	}{
+		"NoClusterOptions": {
+			reason: "The definition should be unchanged without a sizing or region.",
+			launch: &v1alpha1.LaunchOptions{OnCreate: true},
+			want:   script,
+		},
+		"ClusterOptions": {
+			reason: "The sizing and region should be set as cluster test options.",
+			launch: &v1alpha1.LaunchOptions{Sizing: "medium", Region: "eu-central-1"},
+			want: script + `
+// Launch options of the test case.
+definition.setTestOptions({
+  cluster: {
+    sizing: "medium",
+    region: "eu-central-1",
+  },
+});
+`,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testCase("acme", "checkout")
+			cr.Spec.ForProvider.Launch = tc.launch
+			got := withLaunchOptions(cr, []byte(script))
+			if diff := cmp.Diff(tc.want, string(got)); diff != "" {
+				t.Errorf("\n%s\nwithLaunchOptions(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestTrafficModel(t *testing.T) {
+	errBoom := errors.New("boom")
+	inline := &v1alpha1.TrafficModelSpec{Phases: []v1alpha1.ArrivalPhase{{Duration: metav1.Duration{Duration: time.Minute}, Rate: 1}}}
+	shared := v1alpha1.TrafficModelSpec{Phases: []v1alpha1.ArrivalPhase{{Duration: metav1.Duration{Duration: time.Hour}, Rate: 10}}}
+
+	type want struct {
+		tm  *v1alpha1.TrafficModelSpec
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		ref    *xpv1.Reference
+		want   want
+	}{
+		"Inline": {
+			reason: "The test case's own traffic model should be used if it references none.",
+			want:   want{tm: inline},
+		},
+		"Referenced": {
+			reason: "The referenced TrafficModel should take precedence over the test case's own.",
+			kube: &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+				obj.(*v1alpha1.TrafficModel).Spec = shared
+				return nil
+			})},
+			ref:  &xpv1.Reference{Name: "peak"},
+			want: want{tm: &shared},
+		},
+		"GetError": {
+			reason: "Errors getting the referenced TrafficModel should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			ref:    &xpv1.Reference{Name: "peak"},
+			want:   want{err: errors.Wrap(errBoom, errGetTrafficModel)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testCase("acme", "checkout")
+			cr.Spec.ForProvider.TrafficModel = inline
+			cr.Spec.ForProvider.TrafficModelRef = tc.ref
+			e := external{kube: tc.kube}
+			got, err := e.trafficModel(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.trafficModel(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.tm, got); diff != "" {
+				t.Errorf("\n%s\ne.trafficModel(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestReferencingTestCases(t *testing.T) {
+	withRef := func(name, model string) v1alpha1.TestCase {
+		cr := testCase("acme", name)
+		cr.SetName(name)
+		if model != "" {
+			cr.Spec.ForProvider.TrafficModelRef = &xpv1.Reference{Name: model}
+		}
+		return *cr
+	}
+	kube := &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		obj.(*v1alpha1.TestCaseList).Items = []v1alpha1.TestCase{
+			withRef("checkout", "peak"),
+			withRef("search", "soak"),
+			withRef("login", ""),
+			withRef("cart", "peak"),
+		}
+		return nil
+	})}
+
+	tm := &v1alpha1.TrafficModel{}
+	tm.SetName("peak")
+	got := referencingTestCases(kube)(tm)
+	want := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Name: "checkout"}},
+		{NamespacedName: types.NamespacedName{Name: "cart"}},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("referencingTestCases(...): -want, +got:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+const (
+	errDurationBounds    = "minDuration must not be greater than maxDuration"
+	errStagesTooShortFmt = "stages last %s, less than the minimum duration of %s"
+	errStagesTooLongFmt  = "stages last %s, more than the maximum duration of %s"
+	errMinDurationFmt    = "run %s has not run for its minimum duration of %s yet"
+	errMaxDurationFmt    = "aborted run %s, which ran for longer than its maximum duration of %s"
+	errAbortMaxDuration  = "cannot abort run that exceeded its maximum duration"
+)
+
+// bounds validates the duration bounds of the supplied TestRun, which must
+// not contradict each other nor the total duration of its stages.
+func bounds(p v1alpha1.TestRunParameters) error {
+	min, max := p.MinDuration, p.MaxDuration
+	if min != nil && max != nil && min.Duration > max.Duration {
+		return errors.New(errDurationBounds)
+	}
+	if len(p.Stages) == 0 {
+		return nil
+	}
+	total := time.Duration(0)
+	for _, s := range p.Stages {
+		total += s.Duration.Duration
+	}
+	if min != nil && total < min.Duration {
+		return errors.Errorf(errStagesTooShortFmt, total, min.Duration)
+	}
+	if max != nil && total > max.Duration {
+		return errors.Errorf(errStagesTooLongFmt, total, max.Duration)
+	}
+	return nil
+}
+
+// elapsed returns how long the run of the supplied observation has generated
+// load at the supplied time, and false if it has not started yet.
+func elapsed(o v1alpha1.TestRunObservation, now time.Time) (time.Duration, bool) {
+	if o.StartedAt == nil {
+		return 0, false
+	}
+	return now.Sub(o.StartedAt.Time), true
+}
+
+// exceeded returns true if the active run of the supplied observation has run
+// for longer than its maximum duration at the supplied time.
+func exceeded(o v1alpha1.TestRunObservation, now time.Time) bool {
+	d, ok := elapsed(o, now)
+	return ok && o.Phase == v1alpha1.TestRunRunning && o.MaxDuration != nil && d > o.MaxDuration.Duration
+}
+
+// premature returns true if the active run of the supplied observation has
+// not yet run for its minimum duration at the supplied time. A run that has
+// not started yet may be aborted without losing any samples.
+func premature(o v1alpha1.TestRunObservation, now time.Time) bool {
+	d, ok := elapsed(o, now)
+	return ok && o.Phase == v1alpha1.TestRunRunning && o.MinDuration != nil && d < o.MinDuration.Duration
+}
+
+// durationBound returns a copy of the supplied duration bound, if any.
+func durationBound(d *metav1.Duration) *metav1.Duration {
+	if d == nil {
+		return nil
+	}
+	return &metav1.Duration{Duration: d.Duration}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+func TestBounds(t *testing.T) {
+	minute := &metav1.Duration{Duration: time.Minute}
+	hour := &metav1.Duration{Duration: time.Hour}
+	stages := []v1alpha1.Stage{
+		{Duration: metav1.Duration{Duration: 10 * time.Minute}, TargetRate: 10},
+		{Duration: metav1.Duration{Duration: 20 * time.Minute}, TargetRate: 10},
+	}
+
+	cases := map[string]struct {
+		reason string
+		p      v1alpha1.TestRunParameters
+		want   error
+	}{
+		"Unbounded": {
+			reason: "A run without duration bounds should be valid.",
+			p:      v1alpha1.TestRunParameters{Stages: stages},
+		},
+		"Bounded": {
+			reason: "A run whose stages last between its bounds should be valid.",
+			p:      v1alpha1.TestRunParameters{MinDuration: minute, MaxDuration: hour, Stages: stages},
+		},
+		"EqualBounds": {
+			reason: "A run whose minimum duration equals its maximum should be valid.",
+			p:      v1alpha1.TestRunParameters{MinDuration: hour, MaxDuration: hour},
+		},
+		"MinGreaterThanMax": {
+			reason: "A run whose minimum duration exceeds its maximum should be invalid.",
+			p:      v1alpha1.TestRunParameters{MinDuration: hour, MaxDuration: minute},
+			want:   errors.New(errDurationBounds),
+		},
+		"StagesTooShort": {
+			reason: "A run whose stages end before its minimum duration should be invalid.",
+			p:      v1alpha1.TestRunParameters{MinDuration: hour, Stages: stages},
+			want:   errors.Errorf(errStagesTooShortFmt, 30*time.Minute, time.Hour),
+		},
+		"StagesTooLong": {
+			reason: "A run whose stages last longer than its maximum duration should be invalid.",
+			p:      v1alpha1.TestRunParameters{MaxDuration: minute, Stages: stages},
+			want:   errors.Errorf(errStagesTooLongFmt, 30*time.Minute, time.Minute),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, bounds(tc.p), test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nbounds(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestExceededAndPremature(t *testing.T) {
+	now := time.Date(2020, 12, 1, 10, 30, 0, 0, time.UTC)
+	started := &metav1.Time{Time: now.Add(-30 * time.Minute)}
+
+	cases := map[string]struct {
+		reason    string
+		o         v1alpha1.TestRunObservation
+		exceeded  bool
+		premature bool
+	}{
+		"WithinBounds": {
+			reason: "A run that has run for between its bounds should neither be aborted nor kept from being aborted.",
+			o: v1alpha1.TestRunObservation{Phase: v1alpha1.TestRunRunning, StartedAt: started,
+				MinDuration: &metav1.Duration{Duration: 10 * time.Minute}, MaxDuration: &metav1.Duration{Duration: time.Hour}},
+		},
+		"AtMaxDuration": {
+			reason: "A run that has run for longer than its maximum duration should be aborted.",
+			o: v1alpha1.TestRunObservation{Phase: v1alpha1.TestRunRunning, StartedAt: started,
+				MaxDuration: &metav1.Duration{Duration: 20 * time.Minute}},
+			exceeded: true,
+		},
+		"AtMinDuration": {
+			reason: "A run that has not run for its minimum duration should not be aborted.",
+			o: v1alpha1.TestRunObservation{Phase: v1alpha1.TestRunRunning, StartedAt: started,
+				MinDuration: &metav1.Duration{Duration: time.Hour}},
+			premature: true,
+		},
+		"NotStarted": {
+			reason: "A run that has not started yet may be aborted, and is not aborted for its duration.",
+			o: v1alpha1.TestRunObservation{Phase: v1alpha1.TestRunPending,
+				MinDuration: &metav1.Duration{Duration: time.Hour}, MaxDuration: &metav1.Duration{Duration: time.Minute}},
+		},
+		"Ended": {
+			reason: "A run that has ended should not be aborted for its duration.",
+			o: v1alpha1.TestRunObservation{Phase: v1alpha1.TestRunSucceeded, StartedAt: started,
+				MinDuration: &metav1.Duration{Duration: time.Hour}, MaxDuration: &metav1.Duration{Duration: time.Minute}},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := exceeded(tc.o, now); got != tc.exceeded {
+				t.Errorf("\n%s\nexceeded(...): want %t, got %t", tc.reason, tc.exceeded, got)
+			}
+			if got := premature(tc.o, now); got != tc.premature {
+				t.Errorf("\n%s\npremature(...): want %t, got %t", tc.reason, tc.premature, got)
+			}
+		})
+	}
+}
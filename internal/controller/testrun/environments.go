@@ -0,0 +1,208 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+)
+
+// maxEnvironments is the most environments a TestRun may be launched
+// against, to bound the load it generates and the runs it tracks.
+const maxEnvironments = 10
+
+const (
+	errTooManyEnvironmentsFmt  = "at most %d environments may be listed, got %d"
+	errDuplicateEnvironmentFmt = "environment %q is listed more than once"
+	errNoEnvironmentNameFmt    = "environment %d must have a name"
+	errNoEnvironmentTargetFmt  = "environment %q must have a target"
+	errLaunchEnvironmentFmt    = "cannot launch run against environment %q"
+)
+
+// environments validates the environments of the supplied TestRun.
+func environments(p v1alpha1.TestRunParameters) error {
+	if n := len(p.Environments); n > maxEnvironments {
+		return errors.Errorf(errTooManyEnvironmentsFmt, maxEnvironments, n)
+	}
+	seen := map[string]bool{}
+	for i, e := range p.Environments {
+		if e.Name == "" {
+			return errors.Errorf(errNoEnvironmentNameFmt, i)
+		}
+		if seen[e.Name] {
+			return errors.Errorf(errDuplicateEnvironmentFmt, e.Name)
+		}
+		seen[e.Name] = true
+		if e.Target == "" {
+			return errors.Errorf(errNoEnvironmentTargetFmt, e.Name)
+		}
+	}
+	return nil
+}
+
+// runIDs returns the IDs of the runs the supplied TestRun launched, which
+// are comma separated in its external name.
+func runIDs(cr *v1alpha1.TestRun) []string {
+	n := meta.GetExternalName(cr)
+	if n == "" {
+		return nil
+	}
+	return strings.Split(n, ",")
+}
+
+// observeEnvironments records the observed state of the supplied runs, one
+// per environment of the supplied TestRun in order, and aggregates them. A
+// nil run no longer exists in StormForge.
+func observeEnvironments(cr *v1alpha1.TestRun, ids []string, rs []*stormforge.TestRun) {
+	o := &cr.Status.AtProvider
+	names := cr.Spec.ForProvider.Environments
+	o.ID, o.State = "", ""
+	o.Environments = make([]v1alpha1.EnvironmentObservation, len(rs))
+	for i, r := range rs {
+		e := &o.Environments[i]
+		e.ID = ids[i]
+		if i < len(names) {
+			e.Name = names[i].Name
+		}
+		if r == nil {
+			e.Phase = v1alpha1.TestRunAborted
+			continue
+		}
+		o.TestCaseID = r.TestCaseID
+		e.State = r.State
+		e.Phase = runphase.Of(r.State)
+		e.StartedAt = metaTime(r.StartedAt)
+		e.EndedAt = metaTime(r.EndedAt)
+		e.Result = runphase.Result(r.Summary)
+	}
+	o.Phase = aggregatePhase(o.Environments)
+	o.StartedAt, o.EndedAt = span(o.Environments, o.Phase)
+	o.Result = aggregateResult(o.Environments)
+}
+
+// aggregatePhase returns the phase of a TestRun whose runs are in the
+// supplied phases. It is active while any of its runs is.
+func aggregatePhase(envs []v1alpha1.EnvironmentObservation) v1alpha1.TestRunPhase {
+	in := map[v1alpha1.TestRunPhase]bool{}
+	for _, e := range envs {
+		in[e.Phase] = true
+	}
+	for _, p := range []v1alpha1.TestRunPhase{
+		v1alpha1.TestRunRunning,
+		v1alpha1.TestRunPending,
+		v1alpha1.TestRunUnknown,
+		v1alpha1.TestRunFailed,
+		v1alpha1.TestRunAborted,
+	} {
+		if in[p] {
+			return p
+		}
+	}
+	return v1alpha1.TestRunSucceeded
+}
+
+// span returns when the first of the supplied runs started, and when the
+// last of them ended once none of them is active.
+func span(envs []v1alpha1.EnvironmentObservation, p v1alpha1.TestRunPhase) (started, ended *metav1.Time) {
+	for _, e := range envs {
+		if e.StartedAt != nil && (started == nil || e.StartedAt.Before(started)) {
+			started = e.StartedAt.DeepCopy()
+		}
+		if e.EndedAt != nil && (ended == nil || ended.Before(e.EndedAt)) {
+			ended = e.EndedAt.DeepCopy()
+		}
+	}
+	if runphase.Active(p) {
+		ended = nil
+	}
+	return started, ended
+}
+
+// aggregateResult returns the result of the supplied runs once all of them
+// have one: the sums of their requests and errors, and their highest
+// latencies.
+func aggregateResult(envs []v1alpha1.EnvironmentObservation) *v1alpha1.TestRunResult {
+	if len(envs) == 0 {
+		return nil
+	}
+	a := &v1alpha1.TestRunResult{}
+	for _, e := range envs {
+		r := e.Result
+		if r == nil {
+			return nil
+		}
+		a.Requests = sum(a.Requests, r.Requests)
+		a.Errors = sum(a.Errors, r.Errors)
+		a.LatencyP50 = longer(a.LatencyP50, r.LatencyP50)
+		a.LatencyP95 = longer(a.LatencyP95, r.LatencyP95)
+		a.LatencyP99 = longer(a.LatencyP99, r.LatencyP99)
+	}
+	return a
+}
+
+// runs returns the observations of the individual runs of the supplied
+// observation: the runs of its environments, if any, otherwise itself.
+func runs(o v1alpha1.TestRunObservation) []v1alpha1.TestRunObservation {
+	if len(o.Environments) == 0 {
+		return []v1alpha1.TestRunObservation{o}
+	}
+	rs := make([]v1alpha1.TestRunObservation, len(o.Environments))
+	for i, e := range o.Environments {
+		rs[i] = v1alpha1.TestRunObservation{
+			ID:          e.ID,
+			TestCaseID:  o.TestCaseID,
+			State:       e.State,
+			Phase:       e.Phase,
+			StartedAt:   e.StartedAt,
+			EndedAt:     e.EndedAt,
+			Result:      e.Result,
+			MinDuration: o.MinDuration,
+			MaxDuration: o.MaxDuration,
+		}
+	}
+	return rs
+}
+
+// sum returns the sum of the supplied counts, ignoring any that are nil.
+func sum(a, b *int64) *int64 {
+	switch {
+	case b == nil:
+		return a
+	case a == nil:
+		s := *b
+		return &s
+	}
+	s := *a + *b
+	return &s
+}
+
+// longer returns the longer of the supplied durations, ignoring any that are
+// nil.
+func longer(a, b *metav1.Duration) *metav1.Duration {
+	if b == nil || (a != nil && a.Duration > b.Duration) {
+		return a
+	}
+	return &metav1.Duration{Duration: b.Duration}
+}
@@ -0,0 +1,164 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+)
+
+func TestEnvironments(t *testing.T) {
+	env := func(name string) v1alpha1.Environment {
+		return v1alpha1.Environment{Name: name, Target: "https://" + name + ".example.com"}
+	}
+	many := make([]v1alpha1.Environment, maxEnvironments+1)
+	for i := range many {
+		many[i] = env(fmt.Sprintf("env-%d", i))
+	}
+
+	cases := map[string]struct {
+		reason string
+		envs   []v1alpha1.Environment
+		want   error
+	}{
+		"None": {
+			reason: "A run without environments is launched against the target of its test case.",
+		},
+		"Valid": {
+			reason: "Environments with unique names and targets are valid.",
+			envs:   []v1alpha1.Environment{env("staging"), env("production")},
+		},
+		"TooMany": {
+			reason: "The number of environments a run is launched against is bounded.",
+			envs:   many,
+			want:   errors.Errorf(errTooManyEnvironmentsFmt, maxEnvironments, maxEnvironments+1),
+		},
+		"NoName": {
+			reason: "An environment must have a name.",
+			envs:   []v1alpha1.Environment{env("staging"), {Target: "https://example.com"}},
+			want:   errors.Errorf(errNoEnvironmentNameFmt, 1),
+		},
+		"Duplicate": {
+			reason: "The runs of environments that share a name could not be told apart.",
+			envs:   []v1alpha1.Environment{env("staging"), env("staging")},
+			want:   errors.Errorf(errDuplicateEnvironmentFmt, "staging"),
+		},
+		"NoTarget": {
+			reason: "An environment must have a target.",
+			envs:   []v1alpha1.Environment{{Name: "staging"}},
+			want:   errors.Errorf(errNoEnvironmentTargetFmt, "staging"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := environments(v1alpha1.TestRunParameters{Environments: tc.envs})
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nenvironments(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAggregatePhase(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		phases []v1alpha1.TestRunPhase
+		want   v1alpha1.TestRunPhase
+	}{
+		"Running": {
+			reason: "A TestRun is running while any of its runs is.",
+			phases: []v1alpha1.TestRunPhase{v1alpha1.TestRunSucceeded, v1alpha1.TestRunRunning, v1alpha1.TestRunPending},
+			want:   v1alpha1.TestRunRunning,
+		},
+		"Pending": {
+			reason: "A TestRun is pending while any of its runs is, and none is running.",
+			phases: []v1alpha1.TestRunPhase{v1alpha1.TestRunFailed, v1alpha1.TestRunPending},
+			want:   v1alpha1.TestRunPending,
+		},
+		"Failed": {
+			reason: "A TestRun has failed if any of its ended runs failed.",
+			phases: []v1alpha1.TestRunPhase{v1alpha1.TestRunAborted, v1alpha1.TestRunFailed, v1alpha1.TestRunSucceeded},
+			want:   v1alpha1.TestRunFailed,
+		},
+		"Aborted": {
+			reason: "A TestRun was aborted if any of its ended runs was, and none failed.",
+			phases: []v1alpha1.TestRunPhase{v1alpha1.TestRunSucceeded, v1alpha1.TestRunAborted},
+			want:   v1alpha1.TestRunAborted,
+		},
+		"Succeeded": {
+			reason: "A TestRun has succeeded once all of its runs have.",
+			phases: []v1alpha1.TestRunPhase{v1alpha1.TestRunSucceeded, v1alpha1.TestRunSucceeded},
+			want:   v1alpha1.TestRunSucceeded,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			envs := make([]v1alpha1.EnvironmentObservation, len(tc.phases))
+			for i, p := range tc.phases {
+				envs[i].Phase = p
+			}
+			if diff := cmp.Diff(tc.want, aggregatePhase(envs)); diff != "" {
+				t.Errorf("\n%s\naggregatePhase(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestAggregateResult(t *testing.T) {
+	result := func(requests, errors int64, p50, p95, p99 int) *v1alpha1.TestRunResult {
+		ms := func(n int) time.Duration { return time.Duration(n) * time.Millisecond }
+		return runphase.Result(&stormforge.RunSummary{Requests: requests, Errors: errors, LatencyP50: ms(p50), LatencyP95: ms(p95), LatencyP99: ms(p99)})
+	}
+	staging := result(1000, 2, 10, 90, 200)
+	production := result(500, 1, 12, 80, 250)
+
+	cases := map[string]struct {
+		reason string
+		envs   []v1alpha1.EnvironmentObservation
+		want   *v1alpha1.TestRunResult
+	}{
+		"Incomplete": {
+			reason: "There is no aggregated result until every run has a result.",
+			envs:   []v1alpha1.EnvironmentObservation{{Result: staging}, {}},
+		},
+		"Complete": {
+			reason: "The aggregated result should sum requests and errors and report the highest latencies.",
+			envs:   []v1alpha1.EnvironmentObservation{{Result: staging}, {Result: production}},
+			want:   result(1500, 3, 12, 90, 250),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(tc.want, aggregateResult(tc.envs)); diff != "" {
+				t.Errorf("\n%s\naggregateResult(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+// DefaultMaxResultTestCases is the number of TestCases whose latest run result
+// is exported by default.
+const DefaultMaxResultTestCases = 100
+
+// resultsTimeout bounds how long a scrape waits for TestResults to be listed.
+const resultsTimeout = 10 * time.Second
+
+var resultLabels = []string{"test_case", "run"}
+
+var (
+	resultRequestsDesc = prometheus.NewDesc(
+		"stormforge_test_run_requests",
+		"Number of requests sent during the latest completed run of a test case.",
+		resultLabels, nil,
+	)
+	resultErrorsDesc = prometheus.NewDesc(
+		"stormforge_test_run_errors",
+		"Number of requests that failed during the latest completed run of a test case.",
+		resultLabels, nil,
+	)
+	resultLatencyDesc = prometheus.NewDesc(
+		"stormforge_test_run_latency_seconds",
+		"Latency quantiles of the requests of the latest completed run of a test case.",
+		append(append([]string{}, resultLabels...), "quantile"), nil,
+	)
+	resultEndedDesc = prometheus.NewDesc(
+		"stormforge_test_run_end_timestamp_seconds",
+		"Time at which the latest completed run of a test case ended.",
+		resultLabels, nil,
+	)
+)
+
+// A ResultCollector exports the results of completed runs recorded as
+// TestResults as Prometheus metrics, labelled by the TestCase and the ID of
+// the run. Only the latest run of each TestCase is exported, and only for the
+// supplied number of TestCases whose runs ended most recently, so the number
+// of series is bounded regardless of how many runs are recorded.
+type ResultCollector struct {
+	kube client.Reader
+	max  int
+}
+
+// NewResultCollector returns a ResultCollector that reads TestResults from
+// the supplied client and exports the latest result of up to max TestCases.
+// A max of zero or less exports DefaultMaxResultTestCases.
+func NewResultCollector(kube client.Reader, max int) *ResultCollector {
+	if max <= 0 {
+		max = DefaultMaxResultTestCases
+	}
+	return &ResultCollector{kube: kube, max: max}
+}
+
+// Describe implements prometheus.Collector.
+func (c *ResultCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- resultRequestsDesc
+	ch <- resultErrorsDesc
+	ch <- resultLatencyDesc
+	ch <- resultEndedDesc
+}
+
+// Collect implements prometheus.Collector.
+func (c *ResultCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), resultsTimeout)
+	defer cancel()
+
+	l := &v1alpha1.TestResultList{}
+	if err := c.kube.List(ctx, l); err != nil {
+		ch <- prometheus.NewInvalidMetric(resultRequestsDesc, err)
+		return
+	}
+	for _, r := range c.latest(l.Items) {
+		lv := []string{r.GetLabels()[v1alpha1.LabelTestCase], r.Spec.TestRunID}
+		if r.Spec.Requests != nil {
+			ch <- prometheus.MustNewConstMetric(resultRequestsDesc, prometheus.GaugeValue, float64(*r.Spec.Requests), lv...)
+		}
+		if r.Spec.Errors != nil {
+			ch <- prometheus.MustNewConstMetric(resultErrorsDesc, prometheus.GaugeValue, float64(*r.Spec.Errors), lv...)
+		}
+		for _, q := range []struct {
+			quantile string
+			latency  *metav1.Duration
+		}{
+			{"0.5", r.Spec.LatencyP50},
+			{"0.95", r.Spec.LatencyP95},
+			{"0.99", r.Spec.LatencyP99},
+		} {
+			if q.latency != nil {
+				ch <- prometheus.MustNewConstMetric(resultLatencyDesc, prometheus.GaugeValue, q.latency.Seconds(), append(lv, q.quantile)...)
+			}
+		}
+		if r.Spec.EndedAt != nil {
+			ch <- prometheus.MustNewConstMetric(resultEndedDesc, prometheus.GaugeValue, float64(r.Spec.EndedAt.Unix()), lv...)
+		}
+	}
+}
+
+// latest returns the latest of the supplied results of each TestCase, for the
+// max TestCases whose runs ended most recently. Results that are not labelled
+// with their TestCase are ignored.
+func (c *ResultCollector) latest(rs []v1alpha1.TestResult) []v1alpha1.TestResult {
+	byTestCase := map[string]v1alpha1.TestResult{}
+	for _, r := range rs {
+		tc := r.GetLabels()[v1alpha1.LabelTestCase]
+		if tc == "" {
+			continue
+		}
+		if l, ok := byTestCase[tc]; !ok || endedBefore(l, r) {
+			byTestCase[tc] = r
+		}
+	}
+	out := make([]v1alpha1.TestResult, 0, len(byTestCase))
+	for _, r := range byTestCase {
+		out = append(out, r)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if endedBefore(out[i], out[j]) != endedBefore(out[j], out[i]) {
+			return endedBefore(out[j], out[i])
+		}
+		return out[i].GetLabels()[v1alpha1.LabelTestCase] < out[j].GetLabels()[v1alpha1.LabelTestCase]
+	})
+	if len(out) > c.max {
+		out = out[:c.max]
+	}
+	return out
+}
+
+// endedBefore returns true if the run of result a ended before that of result
+// b. A run whose end is unknown ended before any whose end is known.
+func endedBefore(a, b v1alpha1.TestResult) bool {
+	switch {
+	case b.Spec.EndedAt == nil:
+		return false
+	case a.Spec.EndedAt == nil:
+		return true
+	}
+	return a.Spec.EndedAt.Before(b.Spec.EndedAt)
+}
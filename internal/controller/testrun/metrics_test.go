@@ -0,0 +1,98 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+)
+
+func TestResultCollector(t *testing.T) {
+	at := func(minutes int) *metav1.Time {
+		t := metav1.NewTime(time.Date(2021, 3, 1, 12, minutes, 0, 0, time.UTC))
+		return &t
+	}
+	result := func(testCase, id string, ended *metav1.Time, requests int64) v1alpha1.TestResult {
+		r := v1alpha1.TestResult{
+			ObjectMeta: metav1.ObjectMeta{Name: testCase + "-" + id},
+			Spec: v1alpha1.TestResultSpec{
+				TestRunID: id,
+				EndedAt:   ended,
+				TestRunResult: *runphase.Result(&stormforge.RunSummary{
+					Requests:   requests,
+					Errors:     requests / 100,
+					LatencyP50: 120 * time.Millisecond,
+					LatencyP95: 300 * time.Millisecond,
+					LatencyP99: 900 * time.Millisecond,
+				}),
+			},
+		}
+		if testCase != "" {
+			r.SetLabels(map[string]string{v1alpha1.LabelTestCase: testCase})
+		}
+		return r
+	}
+	kube := &test.MockClient{MockList: test.NewMockListFn(nil, func(obj client.ObjectList) error {
+		obj.(*v1alpha1.TestResultList).Items = []v1alpha1.TestResult{
+			result("checkout", "r1", at(0), 1000),
+			result("checkout", "r2", at(30), 2000),
+			result("login", "r3", at(10), 500),
+			result("search", "r4", at(5), 800),
+			result("", "r5", at(40), 100),
+		}
+		return nil
+	})}
+
+	// Only the latest run of each of the two test cases whose runs ended
+	// most recently is exported, and results without a test case are not.
+	want := `
+# HELP stormforge_test_run_end_timestamp_seconds Time at which the latest completed run of a test case ended.
+# TYPE stormforge_test_run_end_timestamp_seconds gauge
+stormforge_test_run_end_timestamp_seconds{run="r2",test_case="checkout"} 1.6146018e+09
+stormforge_test_run_end_timestamp_seconds{run="r3",test_case="login"} 1.6146006e+09
+# HELP stormforge_test_run_errors Number of requests that failed during the latest completed run of a test case.
+# TYPE stormforge_test_run_errors gauge
+stormforge_test_run_errors{run="r2",test_case="checkout"} 20
+stormforge_test_run_errors{run="r3",test_case="login"} 5
+# HELP stormforge_test_run_latency_seconds Latency quantiles of the requests of the latest completed run of a test case.
+# TYPE stormforge_test_run_latency_seconds gauge
+stormforge_test_run_latency_seconds{quantile="0.5",run="r2",test_case="checkout"} 0.12
+stormforge_test_run_latency_seconds{quantile="0.95",run="r2",test_case="checkout"} 0.3
+stormforge_test_run_latency_seconds{quantile="0.99",run="r2",test_case="checkout"} 0.9
+stormforge_test_run_latency_seconds{quantile="0.5",run="r3",test_case="login"} 0.12
+stormforge_test_run_latency_seconds{quantile="0.95",run="r3",test_case="login"} 0.3
+stormforge_test_run_latency_seconds{quantile="0.99",run="r3",test_case="login"} 0.9
+# HELP stormforge_test_run_requests Number of requests sent during the latest completed run of a test case.
+# TYPE stormforge_test_run_requests gauge
+stormforge_test_run_requests{run="r2",test_case="checkout"} 2000
+stormforge_test_run_requests{run="r3",test_case="login"} 500
+`
+	if err := testutil.CollectAndCompare(NewResultCollector(kube, 2), strings.NewReader(want)); err != nil {
+		t.Errorf("CollectAndCompare(...): %s", err)
+	}
+}
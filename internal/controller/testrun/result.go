@@ -0,0 +1,119 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+	"github.com/luebken/provider-stormforge/internal/slo"
+)
+
+const (
+	errGetTestResult    = "cannot get TestResult"
+	errCreateTestResult = "cannot create TestResult"
+)
+
+// record records the result of the supplied run of the supplied TestRun in a
+// TestResult named after the TestRun and the ID of the run, unless the run is
+// still active, has no result, or its result has already been recorded. The verdicts of the SLOs
+// of the run's TestCase are omitted if the TestCase no longer exists. A
+// warning event is recorded if the run missed any of its SLOs.
+func (c *external) record(ctx context.Context, cr *v1alpha1.TestRun, o v1alpha1.TestRunObservation) error {
+	if runphase.Active(o.Phase) || o.Result == nil {
+		return nil
+	}
+
+	name := cr.GetName() + "-" + strings.ToLower(o.ID)
+	err := c.kube.Get(ctx, types.NamespacedName{Name: name}, &v1alpha1.TestResult{})
+	if !kerrors.IsNotFound(err) {
+		return errors.Wrap(err, errGetTestResult)
+	}
+
+	refs := []xpv1.Reference{}
+	tc := &v1alpha1.TestCase{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.Spec.ForProvider.TestCase}, tc); resource.IgnoreNotFound(err) != nil {
+		return errors.Wrap(err, errGetTestCase)
+	}
+	refs = append(append(refs, tc.Spec.ForProvider.SLORefs...), cr.Spec.ForProvider.SLORefs...)
+	t, err := slo.Thresholds(ctx, c.kube, refs...)
+	if err != nil {
+		return err
+	}
+
+	rate, _ := slo.ErrorRate(o.Result)
+	tr := &v1alpha1.TestResult{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels(map[string]string{
+			v1alpha1.LabelTestRun:  cr.GetName(),
+			v1alpha1.LabelTestCase: cr.Spec.ForProvider.TestCase,
+		})},
+		Spec: v1alpha1.TestResultSpec{
+			TestRun:       cr.GetName(),
+			TestRunID:     o.ID,
+			TestCaseID:    o.TestCaseID,
+			Phase:         o.Phase,
+			StartedAt:     o.StartedAt,
+			EndedAt:       o.EndedAt,
+			ErrorRate:     strconv.FormatFloat(rate, 'f', 2, 64),
+			TestRunResult: *o.Result,
+			SLOs:          slo.Verdicts(t, o.Result),
+		},
+	}
+	if err := c.kube.Create(ctx, tr); err != nil {
+		return errors.Wrap(resource.Ignore(kerrors.IsAlreadyExists, err), errCreateTestResult)
+	}
+	if m := missed(tr.Spec.SLOs); len(m) > 0 {
+		c.recorder.Event(cr, event.Warning(reasonMissedSLO, errors.Errorf("run %s missed the SLOs for %s", o.ID, strings.Join(m, ", "))))
+	}
+	return nil
+}
+
+// missed returns the metrics of the supplied verdicts that were missed.
+func missed(v []v1alpha1.SLOVerdict) []string {
+	m := []string{}
+	for _, s := range v {
+		if s.Result == v1alpha1.SLOMissed {
+			m = append(m, s.Metric)
+		}
+	}
+	return m
+}
+
+// labels returns the supplied labels, omitting any whose value is not a valid
+// label value, for example a name longer than 63 characters.
+func labels(l map[string]string) map[string]string {
+	valid := map[string]string{}
+	for k, v := range l {
+		if len(validation.IsValidLabelValue(v)) == 0 {
+			valid[k] = v
+		}
+	}
+	return valid
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+)
+
+// results returns a client that serves no TestResults or TestCases and an
+// SLO with a 100ms p95 latency threshold, and appends the TestResults it
+// creates to created.
+func results(created *[]v1alpha1.TestResult) client.Client {
+	return &test.MockClient{
+		MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+			if s, ok := obj.(*v1alpha1.SLO); ok {
+				s.SetName(key.Name)
+				s.Spec.Latency = []v1alpha1.LatencyObjective{{Percentile: v1alpha1.LatencyP95, Max: metav1.Duration{Duration: 100 * time.Millisecond}}}
+				return nil
+			}
+			return kerrors.NewNotFound(schema.GroupResource{}, key.Name)
+		},
+		MockCreate: func(_ context.Context, obj client.Object, _ ...client.CreateOption) error {
+			if created != nil {
+				*created = append(*created, *obj.(*v1alpha1.TestResult))
+			}
+			return nil
+		},
+	}
+}
+
+// A recorder records the events it is asked to record.
+type recorder struct {
+	events []event.Event
+}
+
+func (r *recorder) Event(_ runtime.Object, e event.Event) { r.events = append(r.events, e) }
+
+func (r *recorder) WithAnnotations(_ ...string) event.Recorder { return r }
+
+func TestRecord(t *testing.T) {
+	errBoom := errors.New("boom")
+	ended := metav1.NewTime(time.Date(2020, 12, 1, 10, 10, 0, 0, time.UTC))
+	result := runphase.Result(&stormforge.RunSummary{
+		Requests: 1200, Errors: 3, LatencyP50: 12 * time.Millisecond, LatencyP95: 80 * time.Millisecond, LatencyP99: 250 * time.Millisecond,
+	})
+	slow := result.DeepCopy()
+	slow.LatencyP95 = &metav1.Duration{Duration: 150 * time.Millisecond}
+	finished := func(phase v1alpha1.TestRunPhase, r *v1alpha1.TestRunResult) *v1alpha1.TestRun {
+		cr := testRun("R1")
+		cr.SetName("nightly")
+		cr.Spec.ForProvider.SLORefs = []xpv1.Reference{{Name: "fast"}}
+		cr.Status.AtProvider = v1alpha1.TestRunObservation{ID: "R1", TestCaseID: "1", Phase: phase, EndedAt: &ended, Result: r}
+		return cr
+	}
+
+	type want struct {
+		created []v1alpha1.TestResult
+		events  []event.Event
+		err     error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   func(created *[]v1alpha1.TestResult) client.Client
+		cr     *v1alpha1.TestRun
+		want   want
+	}{
+		"Recorded": {
+			reason: "The result of a finished run should be recorded with the verdicts of its SLOs.",
+			kube:   results,
+			cr:     finished(v1alpha1.TestRunSucceeded, result),
+			want: want{created: []v1alpha1.TestResult{{
+				ObjectMeta: metav1.ObjectMeta{Name: "nightly-r1", Labels: map[string]string{
+					v1alpha1.LabelTestRun:  "nightly",
+					v1alpha1.LabelTestCase: "checkout",
+				}},
+				Spec: v1alpha1.TestResultSpec{
+					TestRun:       "nightly",
+					TestRunID:     "R1",
+					TestCaseID:    "1",
+					Phase:         v1alpha1.TestRunSucceeded,
+					EndedAt:       &ended,
+					ErrorRate:     "0.25",
+					TestRunResult: *result,
+					SLOs: []v1alpha1.SLOVerdict{
+						{Metric: stormforge.ThresholdLatencyP95, Threshold: "100", Actual: "80", Result: v1alpha1.SLOMet},
+					},
+				},
+			}}},
+		},
+		"MissedSLO": {
+			reason: "A run that missed an SLO should be recorded with a warning event.",
+			kube:   results,
+			cr:     finished(v1alpha1.TestRunSucceeded, slow),
+			want: want{
+				created: []v1alpha1.TestResult{{
+					ObjectMeta: metav1.ObjectMeta{Name: "nightly-r1", Labels: map[string]string{
+						v1alpha1.LabelTestRun:  "nightly",
+						v1alpha1.LabelTestCase: "checkout",
+					}},
+					Spec: v1alpha1.TestResultSpec{
+						TestRun:       "nightly",
+						TestRunID:     "R1",
+						TestCaseID:    "1",
+						Phase:         v1alpha1.TestRunSucceeded,
+						EndedAt:       &ended,
+						ErrorRate:     "0.25",
+						TestRunResult: *slow,
+						SLOs: []v1alpha1.SLOVerdict{
+							{Metric: stormforge.ThresholdLatencyP95, Threshold: "100", Actual: "150", Result: v1alpha1.SLOMissed},
+						},
+					},
+				}},
+				events: []event.Event{event.Warning(reasonMissedSLO, errors.New("run R1 missed the SLOs for "+stormforge.ThresholdLatencyP95))},
+			},
+		},
+		"Active": {
+			reason: "The result of a run that is still active should not be recorded.",
+			kube:   results,
+			cr:     finished(v1alpha1.TestRunRunning, result),
+		},
+		"NoResult": {
+			reason: "A run that ended without a result should not be recorded.",
+			kube:   results,
+			cr:     finished(v1alpha1.TestRunAborted, nil),
+		},
+		"AlreadyRecorded": {
+			reason: "A result that has already been recorded should not be recorded again.",
+			kube: func(_ *[]v1alpha1.TestResult) client.Client {
+				return &test.MockClient{MockGet: test.NewMockGetFn(nil), MockCreate: test.NewMockCreateFn(errBoom)}
+			},
+			cr: finished(v1alpha1.TestRunSucceeded, result),
+		},
+		"GetTestResultError": {
+			reason: "Errors getting the TestResult should be wrapped.",
+			kube: func(_ *[]v1alpha1.TestResult) client.Client {
+				return &test.MockClient{MockGet: test.NewMockGetFn(errBoom)}
+			},
+			cr:   finished(v1alpha1.TestRunSucceeded, result),
+			want: want{err: errors.Wrap(errBoom, errGetTestResult)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var created []v1alpha1.TestResult
+			r := &recorder{}
+			e := external{kube: tc.kube(&created), recorder: r}
+			err := e.record(context.Background(), tc.cr, tc.cr.Status.AtProvider)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.record(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.created, created); diff != "" {
+				t.Errorf("\n%s\ne.record(...): -want created, +got created:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events); diff != "" {
+				t.Errorf("\n%s\ne.record(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
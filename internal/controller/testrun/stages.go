@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+const (
+	errStageDurationFmt   = "duration of stage %d must be at least one second"
+	errStageTargetRateFmt = "target rate of stage %d must not be negative"
+	errStageMaxClientsFmt = "max clients of stage %d must not be negative"
+)
+
+// arrivalPhases translates the supplied stages into the arrival phases of a
+// run. Each stage ramps from the target rate of the previous stage, or from
+// zero, to its own. Stages must last at least a second, so that each starts
+// after the one before it, and must not have negative targets.
+func arrivalPhases(s []v1alpha1.Stage) ([]stormforge.ArrivalPhase, error) {
+	if len(s) == 0 {
+		return nil, nil
+	}
+	phases := make([]stormforge.ArrivalPhase, len(s))
+	rate := 0.0
+	for i, st := range s {
+		switch {
+		case st.Duration.Duration < time.Second:
+			return nil, errors.Errorf(errStageDurationFmt, i)
+		case st.TargetRate < 0:
+			return nil, errors.Errorf(errStageTargetRateFmt, i)
+		case st.MaxClients != nil && *st.MaxClients < 0:
+			return nil, errors.Errorf(errStageMaxClientsFmt, i)
+		}
+		phases[i] = stormforge.ArrivalPhase{
+			Duration:   st.Duration.Duration,
+			Rate:       rate,
+			TargetRate: float64(st.TargetRate),
+			MaxClients: st.MaxClients,
+		}
+		rate = float64(st.TargetRate)
+	}
+	return phases, nil
+}
+
+// stages returns the supplied stages along with the time each starts at,
+// relative to the start of the run.
+func stages(s []v1alpha1.Stage) []v1alpha1.StageObservation {
+	if len(s) == 0 {
+		return nil
+	}
+	o := make([]v1alpha1.StageObservation, len(s))
+	start := time.Duration(0)
+	for i, st := range s {
+		o[i] = v1alpha1.StageObservation{Stage: st, Start: metav1.Duration{Duration: start}}
+		start += st.Duration.Duration
+	}
+	return o
+}
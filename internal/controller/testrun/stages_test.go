@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+func TestArrivalPhases(t *testing.T) {
+	maxClients, negative := int32(500), int32(-1)
+
+	type want struct {
+		phases []stormforge.ArrivalPhase
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		stages []v1alpha1.Stage
+		want   want
+	}{
+		"NoStages": {
+			reason: "A run without stages should keep the arrival phases of its test case.",
+		},
+		"Ramp": {
+			reason: "Each stage should ramp from the target rate of the previous stage to its own.",
+			stages: []v1alpha1.Stage{
+				{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 10},
+				{Duration: metav1.Duration{Duration: 5 * time.Minute}, TargetRate: 10, MaxClients: &maxClients},
+				{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 0},
+			},
+			want: want{phases: []stormforge.ArrivalPhase{
+				{Duration: time.Minute, Rate: 0, TargetRate: 10},
+				{Duration: 5 * time.Minute, Rate: 10, TargetRate: 10, MaxClients: &maxClients},
+				{Duration: time.Minute, Rate: 10, TargetRate: 0},
+			}},
+		},
+		"ZeroDuration": {
+			reason: "A stage that does not last would not start after the previous one.",
+			stages: []v1alpha1.Stage{
+				{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 10},
+				{TargetRate: 20},
+			},
+			want: want{err: errors.Errorf(errStageDurationFmt, 1)},
+		},
+		"NegativeDuration": {
+			reason: "A stage must not have a negative duration.",
+			stages: []v1alpha1.Stage{{Duration: metav1.Duration{Duration: -time.Minute}, TargetRate: 10}},
+			want:   want{err: errors.Errorf(errStageDurationFmt, 0)},
+		},
+		"NegativeTargetRate": {
+			reason: "A stage must not have a negative target rate.",
+			stages: []v1alpha1.Stage{{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: -1}},
+			want:   want{err: errors.Errorf(errStageTargetRateFmt, 0)},
+		},
+		"NegativeMaxClients": {
+			reason: "A stage must not limit clients to a negative number.",
+			stages: []v1alpha1.Stage{{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 1, MaxClients: &negative}},
+			want:   want{err: errors.Errorf(errStageMaxClientsFmt, 0)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := arrivalPhases(tc.stages)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\narrivalPhases(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.phases, got); diff != "" {
+				t.Errorf("\n%s\narrivalPhases(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestStages(t *testing.T) {
+	s := []v1alpha1.Stage{
+		{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 10},
+		{Duration: metav1.Duration{Duration: 5 * time.Minute}, TargetRate: 10},
+		{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 0},
+	}
+	want := []v1alpha1.StageObservation{
+		{Stage: s[0], Start: metav1.Duration{}},
+		{Stage: s[1], Start: metav1.Duration{Duration: time.Minute}},
+		{Stage: s[2], Start: metav1.Duration{Duration: 6 * time.Minute}},
+	}
+	if diff := cmp.Diff(want, stages(s)); diff != "" {
+		t.Errorf("stages(...): -want, +got:\n%s\n", diff)
+	}
+}
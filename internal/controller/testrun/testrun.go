@@ -0,0 +1,391 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/pause"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+	"github.com/luebken/provider-stormforge/internal/slo"
+)
+
+// externalKind is the kind of external resource managed by this controller,
+// as used in error messages.
+const externalKind = "test run"
+
+const (
+	errNoTestCase          = "one of testCase, testCaseRef or testCaseSelector must be set"
+	errGetTestCase         = "cannot get TestCase"
+	errTestCaseNotReadyFmt = "TestCase %q has not been created in StormForge yet"
+)
+
+// Event reasons for the lifecycle of a run.
+const (
+	reasonLaunched  event.Reason = "LaunchedTestRun"
+	reasonStarted   event.Reason = "StartedTestRun"
+	reasonSucceeded event.Reason = "SucceededTestRun"
+	reasonFailed    event.Reason = "FailedTestRun"
+	reasonAborted   event.Reason = "AbortedTestRun"
+	reasonMissedSLO event.Reason = "MissedSLO"
+)
+
+var errNotMyType = fmt.Sprintf(errs.NotMyTypeFmt, v1alpha1.TestRunKind)
+
+// Setup adds a controller that reconciles TestRun managed resources. The
+// supplied options configure the StormForge client used for each TestRun.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, co ...stormforge.Option) error {
+	name := managed.ControllerName(v1alpha1.TestRunGroupKind)
+
+	o := controller.Options{
+		RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl),
+	}
+
+	rec := event.NewAPIRecorder(mgr.GetEventRecorderFor(name))
+
+	r := pause.NewReconciler(mgr,
+		resource.ManagedKind(v1alpha1.TestRunGroupVersionKind),
+		managed.WithExternalConnecter(&connector{
+			kube:   mgr.GetClient(),
+			client: clients.NewConnector(mgr.GetClient(), l.WithValues("controller", name), co...),
+			record: rec,
+		}),
+		// The external name of a TestRun is the ID of the run it launched,
+		// or the comma separated IDs of the runs of its environments.
+		managed.WithInitializers(managed.NewDefaultProviderConfig(mgr.GetClient())),
+		managed.WithLogger(l.WithValues("controller", name)),
+		managed.WithRecorder(rec))
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(o).
+		For(&v1alpha1.TestRun{}).
+		Complete(r)
+}
+
+// A connector is expected to produce an ExternalClient when its Connect method
+// is called.
+type connector struct {
+	kube   client.Client
+	client *clients.Connector
+	record event.Recorder
+}
+
+// Connect produces an ExternalClient using a StormForge client for the
+// ProviderConfig of the supplied TestRun.
+func (c *connector) Connect(ctx context.Context, mg resource.Managed) (managed.ExternalClient, error) {
+	if _, ok := mg.(*v1alpha1.TestRun); !ok {
+		return nil, errors.New(errNotMyType)
+	}
+
+	sf, err := c.client.Connect(ctx, mg)
+	if err != nil {
+		return nil, err
+	}
+
+	return clients.WithAPIHealth(&external{kube: c.kube, client: sf, recorder: c.record}), nil
+}
+
+// An ExternalClient observes, then either launches or aborts a test run.
+type external struct {
+	// A client used to read the TestCase a run is launched from, and to
+	// record the result of the run once it has ended.
+	kube client.Client
+
+	// A client used to connect to the StormForge API.
+	client stormforge.Client
+
+	// A recorder of the lifecycle of the run.
+	recorder event.Recorder
+}
+
+func (c *external) Observe(ctx context.Context, mg resource.Managed) (managed.ExternalObservation, error) {
+	cr, ok := mg.(*v1alpha1.TestRun)
+	if !ok {
+		return managed.ExternalObservation{}, errors.New(errNotMyType)
+	}
+
+	ids := runIDs(cr)
+	if len(ids) == 0 {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// A TestRun launched against environments exists as long as any of its
+	// runs does.
+	rs := make([]*stormforge.TestRun, len(ids))
+	found := false
+	for i, id := range ids {
+		r, err := c.client.GetTestRun(ctx, id)
+		if stormforge.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(err, errs.ObserveFmt, externalKind)
+		}
+		rs[i], found = r, true
+	}
+	if !found {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	previous := runs(cr.Status.AtProvider)
+	observe(cr, ids, rs)
+	now := time.Now()
+	for i, r := range runs(cr.Status.AtProvider) {
+		if i >= len(previous) || r.Phase != previous[i].Phase {
+			c.transitioned(cr, r)
+		}
+		if !exceeded(r, now) {
+			continue
+		}
+		if err := c.client.AbortTestRun(ctx, r.ID); resource.Ignore(stormforge.IsNotFound, err) != nil {
+			return managed.ExternalObservation{}, errors.Wrapf(errors.Wrap(err, errAbortMaxDuration), errs.ObserveFmt, externalKind)
+		}
+		c.recorder.Event(cr, event.Warning(reasonAborted, errors.Errorf(errMaxDurationFmt, r.ID, r.MaxDuration.Duration)))
+	}
+	for _, r := range runs(cr.Status.AtProvider) {
+		if err := c.record(ctx, cr, r); err != nil {
+			return managed.ExternalObservation{}, err
+		}
+	}
+	selectResults(cr)
+
+	// A run that is no longer active is left in StormForge when its TestRun
+	// is deleted, so that its results remain available.
+	if meta.WasDeleted(cr) && !runphase.Active(cr.Status.AtProvider.Phase) {
+		return managed.ExternalObservation{ResourceExists: false}, nil
+	}
+
+	// A launched run cannot be changed, so it is always up to date.
+	return managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true}, nil
+}
+
+// observe records the observed state of the supplied runs, with the supplied
+// IDs, in the status of the supplied managed resource. A nil run no longer
+// exists in StormForge.
+func observe(cr *v1alpha1.TestRun, ids []string, rs []*stormforge.TestRun) {
+	o := &cr.Status.AtProvider
+	if len(cr.Spec.ForProvider.Environments) > 0 || len(rs) > 1 {
+		observeEnvironments(cr, ids, rs)
+	} else {
+		r := rs[0]
+		o.ID = r.ID
+		o.TestCaseID = r.TestCaseID
+		o.State = r.State
+		o.Phase = runphase.Of(r.State)
+		o.StartedAt = metaTime(r.StartedAt)
+		o.EndedAt = metaTime(r.EndedAt)
+		o.Result = runphase.Result(r.Summary)
+		o.Environments = nil
+	}
+	o.Stages = stages(cr.Spec.ForProvider.Stages)
+	o.MinDuration = durationBound(cr.Spec.ForProvider.MinDuration)
+	o.MaxDuration = durationBound(cr.Spec.ForProvider.MaxDuration)
+
+	switch o.Phase {
+	case v1alpha1.TestRunSucceeded:
+		cr.SetConditions(xpv1.Available())
+	case v1alpha1.TestRunFailed, v1alpha1.TestRunAborted:
+		cr.SetConditions(xpv1.Unavailable())
+	default:
+		cr.SetConditions(xpv1.Creating())
+	}
+}
+
+// selectResults removes the metrics that the supplied TestRun does not select
+// from the results in its status. Results are recorded in TestResults before
+// they are removed, so TestResults include every metric.
+func selectResults(cr *v1alpha1.TestRun) {
+	o := &cr.Status.AtProvider
+	m := cr.Spec.ForProvider.ResultMetrics
+	o.Result = runphase.Select(o.Result, m)
+	for i := range o.Environments {
+		o.Environments[i].Result = runphase.Select(o.Environments[i].Result, m)
+	}
+}
+
+// transitioned records an event for the supplied run of the supplied TestRun,
+// which entered its phase.
+func (c *external) transitioned(cr *v1alpha1.TestRun, r v1alpha1.TestRunObservation) {
+	id := r.ID
+	switch r.Phase {
+	case v1alpha1.TestRunRunning:
+		c.recorder.Event(cr, event.Normal(reasonStarted, fmt.Sprintf("Run %s started", id)))
+	case v1alpha1.TestRunSucceeded:
+		c.recorder.Event(cr, event.Normal(reasonSucceeded, fmt.Sprintf("Run %s completed", id)))
+	case v1alpha1.TestRunFailed:
+		c.recorder.Event(cr, event.Warning(reasonFailed, errors.Errorf("run %s failed in state %s", id, r.State)))
+	case v1alpha1.TestRunAborted:
+		c.recorder.Event(cr, event.Warning(reasonAborted, errors.Errorf("run %s was aborted", id)))
+	}
+}
+
+// metaTime returns the supplied time as a Kubernetes time, if any.
+func metaTime(t *time.Time) *metav1.Time {
+	if t == nil {
+		return nil
+	}
+	mt := metav1.NewTime(*t)
+	return &mt
+}
+
+func (c *external) Create(ctx context.Context, mg resource.Managed) (managed.ExternalCreation, error) {
+	cr, ok := mg.(*v1alpha1.TestRun)
+	if !ok {
+		return managed.ExternalCreation{}, errors.New(errNotMyType)
+	}
+
+	tc, err := c.testCase(ctx, cr)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	ctx = stormforge.WithOrg(ctx, tc.Spec.ForProvider.Org)
+	p := cr.Spec.ForProvider
+	if err := environments(p); err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	if err := bounds(p); err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	phases, err := arrivalPhases(p.Stages)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	t, err := slo.Thresholds(ctx, c.kube, append(tc.Spec.ForProvider.SLORefs, p.SLORefs...)...)
+	if err != nil {
+		return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+	}
+	ro := stormforge.RunOptions{Title: p.Title, Notes: p.Notes, Thresholds: t, ArrivalPhases: phases}
+	tcID := tc.Status.AtProvider.ID
+
+	if len(p.Environments) == 0 {
+		r, err := c.client.LaunchTestRun(ctx, tcID, ro)
+		if err != nil {
+			return managed.ExternalCreation{}, errors.Wrapf(err, errs.CreateFmt, externalKind)
+		}
+		meta.SetExternalName(cr, r.ID)
+		observe(cr, []string{r.ID}, []*stormforge.TestRun{r})
+		c.recorder.Event(cr, event.Normal(reasonLaunched, fmt.Sprintf("Launched run %s of test case %s", r.ID, tcID)))
+		return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+	}
+
+	ids := make([]string, 0, len(p.Environments))
+	rs := make([]*stormforge.TestRun, 0, len(p.Environments))
+	for _, e := range p.Environments {
+		ro.Target, ro.Headers = e.Target, e.Headers
+		r, err := c.client.LaunchTestRun(ctx, tcID, ro)
+		if err != nil {
+			// The runs launched so far would not be tracked, so they are
+			// aborted before the launch of all runs is retried.
+			c.abort(ctx, ids)
+			return managed.ExternalCreation{}, errors.Wrapf(errors.Wrapf(err, errLaunchEnvironmentFmt, e.Name), errs.CreateFmt, externalKind)
+		}
+		ids, rs = append(ids, r.ID), append(rs, r)
+		c.recorder.Event(cr, event.Normal(reasonLaunched, fmt.Sprintf("Launched run %s of test case %s against environment %s", r.ID, tcID, e.Name)))
+	}
+	meta.SetExternalName(cr, strings.Join(ids, ","))
+	observe(cr, ids, rs)
+
+	return managed.ExternalCreation{ExternalNameAssigned: true}, nil
+}
+
+// abort aborts the runs with the supplied IDs on a best effort basis.
+func (c *external) abort(ctx context.Context, ids []string) {
+	for _, id := range ids {
+		_ = c.client.AbortTestRun(ctx, id)
+	}
+}
+
+// testCase returns the TestCase the supplied run is launched from. The
+// TestCase must have been created in StormForge.
+func (c *external) testCase(ctx context.Context, cr *v1alpha1.TestRun) (*v1alpha1.TestCase, error) {
+	if cr.Spec.ForProvider.TestCase == "" {
+		return nil, errors.New(errNoTestCase)
+	}
+	tc := &v1alpha1.TestCase{}
+	if err := c.kube.Get(ctx, types.NamespacedName{Name: cr.Spec.ForProvider.TestCase}, tc); err != nil {
+		return nil, errors.Wrap(err, errGetTestCase)
+	}
+	if tc.Status.AtProvider.ID == "" {
+		return nil, errors.Errorf(errTestCaseNotReadyFmt, tc.GetName())
+	}
+	return tc, nil
+}
+
+// Update does nothing; a launched run cannot be changed.
+func (c *external) Update(_ context.Context, mg resource.Managed) (managed.ExternalUpdate, error) {
+	if _, ok := mg.(*v1alpha1.TestRun); !ok {
+		return managed.ExternalUpdate{}, errors.New(errNotMyType)
+	}
+	return managed.ExternalUpdate{}, nil
+}
+
+// Delete aborts the runs that are still active. Runs cannot be deleted from
+// StormForge, so a run that has ended is left as is. No run is aborted until
+// every running run has run for its minimum duration, and the Deleting
+// condition explains why.
+func (c *external) Delete(ctx context.Context, mg resource.Managed) error {
+	cr, ok := mg.(*v1alpha1.TestRun)
+	if !ok {
+		return errors.New(errNotMyType)
+	}
+	if !runphase.Active(cr.Status.AtProvider.Phase) {
+		return nil
+	}
+	rs := runs(cr.Status.AtProvider)
+	now := time.Now()
+	for _, r := range rs {
+		if premature(r, now) {
+			msg := fmt.Sprintf(errMinDurationFmt, r.ID, r.MinDuration.Duration)
+			cr.SetConditions(xpv1.Deleting().WithMessage(msg))
+			return errors.Wrapf(errors.New(msg), errs.DeleteFmt, externalKind)
+		}
+	}
+	cr.SetConditions(xpv1.Deleting())
+	for i, id := range runIDs(cr) {
+		if i < len(rs) && rs[i].ID == id && !runphase.Active(rs[i].Phase) {
+			continue
+		}
+		if err := c.client.AbortTestRun(ctx, id); resource.Ignore(stormforge.IsNotFound, err) != nil {
+			return errors.Wrapf(err, errs.DeleteFmt, externalKind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,601 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrun
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+)
+
+func testRun(id string) *v1alpha1.TestRun {
+	cr := &v1alpha1.TestRun{Spec: v1alpha1.TestRunSpec{ForProvider: v1alpha1.TestRunParameters{TestCase: "checkout", Title: "smoke"}}}
+	meta.SetExternalName(cr, id)
+	return cr
+}
+
+func deleted(cr *v1alpha1.TestRun) *v1alpha1.TestRun {
+	now := metav1.Now()
+	cr.SetDeletionTimestamp(&now)
+	return cr
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	started := time.Date(2020, 12, 1, 10, 0, 0, 0, time.UTC)
+	ended := started.Add(10 * time.Minute)
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.TestRunObservation
+		events []event.Event
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Client
+		client *fake.Client
+		cr     *v1alpha1.TestRun
+		want   want
+	}{
+		"NotLaunched": {
+			reason: "A TestRun without an external name has not been launched.",
+			client: &fake.Client{},
+			cr:     testRun(""),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"NotFound": {
+			reason: "A run that does not exist should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     testRun("r1"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"GetError": {
+			reason: "Errors getting the run should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     testRun("r1"),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+		"Running": {
+			reason: "The state of a running run should be reported.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{
+				"r1": {ID: "r1", TestCaseID: "1", State: "running", StartedAt: &started},
+			}},
+			cr: testRun("r1"),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{
+					ID: "r1", TestCaseID: "1", State: "running", Phase: v1alpha1.TestRunRunning,
+					StartedAt: &metav1.Time{Time: started},
+				},
+				events: []event.Event{event.Normal(reasonStarted, "Run r1 started")},
+			},
+		},
+		"StillRunning": {
+			reason: "A run that remains in the same phase should not record an event.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{"r1": {ID: "r1", TestCaseID: "1", State: "finishing"}}},
+			cr: func() *v1alpha1.TestRun {
+				cr := testRun("r1")
+				cr.Status.AtProvider.Phase = v1alpha1.TestRunRunning
+				return cr
+			}(),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{ID: "r1", TestCaseID: "1", State: "finishing", Phase: v1alpha1.TestRunRunning},
+			},
+		},
+		"Failed": {
+			reason: "A run that failed should record a warning event.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{"r1": {ID: "r1", TestCaseID: "1", State: "error"}}},
+			cr:     testRun("r1"),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{ID: "r1", TestCaseID: "1", State: "error", Phase: v1alpha1.TestRunFailed},
+				events: []event.Event{event.Warning(reasonFailed, errors.New("run r1 failed in state error"))},
+			},
+		},
+		"Succeeded": {
+			reason: "The results of a finished run should be reported.",
+			kube:   results(nil),
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{
+				"r1": {ID: "r1", TestCaseID: "1", State: "done", StartedAt: &started, EndedAt: &ended, Summary: &stormforge.RunSummary{
+					Requests: 1200, Errors: 3, LatencyP50: 12 * time.Millisecond, LatencyP95: 80 * time.Millisecond, LatencyP99: 250 * time.Millisecond,
+				}},
+			}},
+			cr: testRun("r1"),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{
+					ID: "r1", TestCaseID: "1", State: "done", Phase: v1alpha1.TestRunSucceeded,
+					StartedAt: &metav1.Time{Time: started},
+					EndedAt:   &metav1.Time{Time: ended},
+					Result: runphase.Select(runphase.Result(&stormforge.RunSummary{
+						Requests: 1200, Errors: 3, LatencyP95: 80 * time.Millisecond,
+					}), nil),
+				},
+				events: []event.Event{event.Normal(reasonSucceeded, "Run r1 completed")},
+			},
+		},
+		"SelectedMetrics": {
+			reason: "Only the result metrics selected by the TestRun should be reported.",
+			kube:   results(nil),
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{
+				"r1": {ID: "r1", TestCaseID: "1", State: "done", StartedAt: &started, EndedAt: &ended, Summary: &stormforge.RunSummary{
+					Requests: 1200, Errors: 3, LatencyP50: 12 * time.Millisecond, LatencyP95: 80 * time.Millisecond, LatencyP99: 250 * time.Millisecond,
+				}},
+			}},
+			cr: func() *v1alpha1.TestRun {
+				cr := testRun("r1")
+				cr.Spec.ForProvider.ResultMetrics = []v1alpha1.ResultMetric{v1alpha1.ResultMetricLatencyP50, v1alpha1.ResultMetricLatencyP99}
+				return cr
+			}(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{
+					ID: "r1", TestCaseID: "1", State: "done", Phase: v1alpha1.TestRunSucceeded,
+					StartedAt: &metav1.Time{Time: started},
+					EndedAt:   &metav1.Time{Time: ended},
+					Result: &v1alpha1.TestRunResult{
+						LatencyP50: &metav1.Duration{Duration: 12 * time.Millisecond},
+						LatencyP99: &metav1.Duration{Duration: 250 * time.Millisecond},
+					},
+				},
+				events: []event.Event{event.Normal(reasonSucceeded, "Run r1 completed")},
+			},
+		},
+		"Stages": {
+			reason: "The stages a run was launched with should be reported along with when they start.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{"r1": {ID: "r1", TestCaseID: "1", State: "launching"}}},
+			cr: func() *v1alpha1.TestRun {
+				cr := testRun("r1")
+				cr.Status.AtProvider.Phase = v1alpha1.TestRunPending
+				cr.Spec.ForProvider.Stages = []v1alpha1.Stage{
+					{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 10},
+					{Duration: metav1.Duration{Duration: 5 * time.Minute}, TargetRate: 10},
+				}
+				return cr
+			}(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{
+					ID: "r1", TestCaseID: "1", State: "launching", Phase: v1alpha1.TestRunPending,
+					Stages: []v1alpha1.StageObservation{
+						{Stage: v1alpha1.Stage{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 10}},
+						{Stage: v1alpha1.Stage{Duration: metav1.Duration{Duration: 5 * time.Minute}, TargetRate: 10}, Start: metav1.Duration{Duration: time.Minute}},
+					},
+				},
+			},
+		},
+		"ExceededMaxDuration": {
+			reason: "A run that has run for longer than its maximum duration should be aborted.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{"r1": {ID: "r1", TestCaseID: "1", State: "running", StartedAt: &started}}},
+			cr: func() *v1alpha1.TestRun {
+				cr := testRun("r1")
+				cr.Status.AtProvider.Phase = v1alpha1.TestRunRunning
+				cr.Spec.ForProvider.MaxDuration = &metav1.Duration{Duration: time.Hour}
+				return cr
+			}(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{
+					ID: "r1", TestCaseID: "1", State: "running", Phase: v1alpha1.TestRunRunning,
+					StartedAt:   &metav1.Time{Time: started},
+					MaxDuration: &metav1.Duration{Duration: time.Hour},
+				},
+				events: []event.Event{event.Warning(reasonAborted, errors.Errorf(errMaxDurationFmt, "r1", time.Hour))},
+			},
+		},
+		"Environments": {
+			reason: "The runs of each environment should be reported and aggregated.",
+			kube:   results(nil),
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{
+				"r1": {ID: "r1", TestCaseID: "1", State: "done", StartedAt: &started, EndedAt: &ended, Summary: &stormforge.RunSummary{Requests: 1200}},
+				"r2": {ID: "r2", TestCaseID: "1", State: "running", StartedAt: &started},
+			}},
+			cr: func() *v1alpha1.TestRun {
+				cr := testRun("r1,r2")
+				cr.Spec.ForProvider.Environments = []v1alpha1.Environment{
+					{Name: "staging", Target: "https://staging.example.com"},
+					{Name: "production", Target: "https://example.com"},
+				}
+				return cr
+			}(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{
+					TestCaseID: "1", Phase: v1alpha1.TestRunRunning,
+					StartedAt: &metav1.Time{Time: started},
+					Environments: []v1alpha1.EnvironmentObservation{
+						{
+							Name: "staging", ID: "r1", State: "done", Phase: v1alpha1.TestRunSucceeded,
+							StartedAt: &metav1.Time{Time: started},
+							EndedAt:   &metav1.Time{Time: ended},
+							Result:    runphase.Select(runphase.Result(&stormforge.RunSummary{Requests: 1200}), nil),
+						},
+						{
+							Name: "production", ID: "r2", State: "running", Phase: v1alpha1.TestRunRunning,
+							StartedAt: &metav1.Time{Time: started},
+						},
+					},
+				},
+				events: []event.Event{
+					event.Normal(reasonSucceeded, "Run r1 completed"),
+					event.Normal(reasonStarted, "Run r2 started"),
+				},
+			},
+		},
+		"EnvironmentRunGone": {
+			reason: "A run of an environment that no longer exists should be reported as aborted.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{"r1": {ID: "r1", TestCaseID: "1", State: "running"}}},
+			cr: func() *v1alpha1.TestRun {
+				cr := testRun("r1,r2")
+				cr.Spec.ForProvider.Environments = []v1alpha1.Environment{
+					{Name: "staging", Target: "https://staging.example.com"},
+					{Name: "production", Target: "https://example.com"},
+				}
+				cr.Status.AtProvider.Phase = v1alpha1.TestRunRunning
+				cr.Status.AtProvider.Environments = []v1alpha1.EnvironmentObservation{
+					{Name: "staging", ID: "r1", State: "running", Phase: v1alpha1.TestRunRunning},
+					{Name: "production", ID: "r2", State: "running", Phase: v1alpha1.TestRunRunning},
+				}
+				return cr
+			}(),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{
+					TestCaseID: "1", Phase: v1alpha1.TestRunRunning,
+					Environments: []v1alpha1.EnvironmentObservation{
+						{Name: "staging", ID: "r1", State: "running", Phase: v1alpha1.TestRunRunning},
+						{Name: "production", ID: "r2", Phase: v1alpha1.TestRunAborted},
+					},
+				},
+				events: []event.Event{event.Warning(reasonAborted, errors.New("run r2 was aborted"))},
+			},
+		},
+		"DeletedWhileRunning": {
+			reason: "A deleted TestRun whose run is still active should be reported as existing so that it is aborted.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{"r1": {ID: "r1", TestCaseID: "1", State: "running"}}},
+			cr:     deleted(testRun("r1")),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TestRunObservation{ID: "r1", TestCaseID: "1", State: "running", Phase: v1alpha1.TestRunRunning},
+				events: []event.Event{event.Normal(reasonStarted, "Run r1 started")},
+			},
+		},
+		"DeletedAfterEnding": {
+			reason: "A deleted TestRun whose run has ended should be reported as not existing so that it is finalized.",
+			client: &fake.Client{Runs: map[string]stormforge.TestRun{"r1": {ID: "r1", TestCaseID: "1", State: "aborted"}}},
+			cr:     deleted(testRun("r1")),
+			want: want{
+				o:      managed.ExternalObservation{ResourceExists: false},
+				status: v1alpha1.TestRunObservation{ID: "r1", TestCaseID: "1", State: "aborted", Phase: v1alpha1.TestRunAborted},
+				events: []event.Event{event.Warning(reasonAborted, errors.New("run r1 was aborted"))},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{}
+			e := external{kube: tc.kube, client: tc.client, recorder: r}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.events, r.events); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want events, +got events:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	testCase := func(id string) client.Client {
+		return &test.MockClient{MockGet: test.NewMockGetFn(nil, func(obj client.Object) error {
+			obj.SetName("checkout")
+			obj.(*v1alpha1.TestCase).Status.AtProvider.ID = id
+			return nil
+		})}
+	}
+
+	withSLOs := &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		switch o := obj.(type) {
+		case *v1alpha1.TestCase:
+			o.SetName(key.Name)
+			o.Status.AtProvider.ID = "1"
+			o.Spec.ForProvider.SLORefs = []xpv1.Reference{{Name: "checkout"}}
+		case *v1alpha1.SLO:
+			o.SetName(key.Name)
+			o.Spec.MaxErrorRate = "1"
+			if key.Name == "strict" {
+				o.Spec.MaxErrorRate = "0.5"
+			}
+		}
+		return nil
+	}}
+
+	type want struct {
+		externalName string
+		runs         map[string]stormforge.TestRun
+		thresholds   map[string]float64
+		phases       []stormforge.ArrivalPhase
+		targets      map[string]string
+		err          error
+	}
+
+	cases := map[string]struct {
+		reason     string
+		kube       client.Client
+		client     *fake.Client
+		slos       []xpv1.Reference
+		stages     []v1alpha1.Stage
+		min, max   *metav1.Duration
+		envs       []v1alpha1.Environment
+		unresolved bool
+		want       want
+	}{
+		"Stages": {
+			reason: "The stages of the run should be launched as its arrival phases.",
+			kube:   testCase("1"),
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			stages: []v1alpha1.Stage{
+				{Duration: metav1.Duration{Duration: time.Minute}, TargetRate: 10},
+				{Duration: metav1.Duration{Duration: 5 * time.Minute}, TargetRate: 10},
+			},
+			want: want{
+				externalName: "1",
+				runs:         map[string]stormforge.TestRun{"1": {ID: "1", TestCaseID: "1", Title: "smoke", State: "launching"}},
+				phases: []stormforge.ArrivalPhase{
+					{Duration: time.Minute, TargetRate: 10},
+					{Duration: 5 * time.Minute, Rate: 10, TargetRate: 10},
+				},
+			},
+		},
+		"InvalidStages": {
+			reason: "A run whose stages are invalid should not be launched.",
+			kube:   testCase("1"),
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			stages: []v1alpha1.Stage{{TargetRate: 10}},
+			want:   want{err: errors.Wrapf(errors.Errorf(errStageDurationFmt, 0), errs.CreateFmt, externalKind)},
+		},
+		"InvalidBounds": {
+			reason: "A run whose stages last longer than its maximum duration should not be launched.",
+			kube:   testCase("1"),
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			stages: []v1alpha1.Stage{{Duration: metav1.Duration{Duration: time.Hour}, TargetRate: 10}},
+			max:    &metav1.Duration{Duration: time.Minute},
+			want:   want{err: errors.Wrapf(errors.Errorf(errStagesTooLongFmt, time.Hour, time.Minute), errs.CreateFmt, externalKind)},
+		},
+		"Environments": {
+			reason: "A run should be launched against each environment and their IDs recorded as the external name.",
+			kube:   testCase("1"),
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			envs: []v1alpha1.Environment{
+				{Name: "staging", Target: "https://staging.example.com"},
+				{Name: "production", Target: "https://example.com", Headers: map[string]string{"X-Env": "production"}},
+			},
+			want: want{
+				externalName: "1,2",
+				runs: map[string]stormforge.TestRun{
+					"1": {ID: "1", TestCaseID: "1", Title: "smoke", State: "launching"},
+					"2": {ID: "2", TestCaseID: "1", Title: "smoke", State: "launching"},
+				},
+				targets: map[string]string{"1": "https://staging.example.com", "2": "https://example.com"},
+			},
+		},
+		"DuplicateEnvironments": {
+			reason: "A run whose environments share a name should not be launched.",
+			kube:   testCase("1"),
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			envs: []v1alpha1.Environment{
+				{Name: "staging", Target: "https://staging.example.com"},
+				{Name: "staging", Target: "https://example.com"},
+			},
+			want: want{err: errors.Wrapf(errors.Errorf(errDuplicateEnvironmentFmt, "staging"), errs.CreateFmt, externalKind)},
+		},
+		"Launched": {
+			reason: "A run of the referenced TestCase should be launched and its ID recorded as the external name.",
+			kube:   testCase("1"),
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			want: want{
+				externalName: "1",
+				runs:         map[string]stormforge.TestRun{"1": {ID: "1", TestCaseID: "1", Title: "smoke", State: "launching"}},
+			},
+		},
+		"SLOs": {
+			reason: "The thresholds of the SLOs of the run and of its TestCase should be enforced on the launched run.",
+			kube:   withSLOs,
+			client: &fake.Client{TestCases: map[string]stormforge.TestCase{"1": {ID: "1", Name: "checkout", Scope: "acme"}}},
+			slos:   []xpv1.Reference{{Name: "strict"}},
+			want: want{
+				externalName: "1",
+				runs:         map[string]stormforge.TestRun{"1": {ID: "1", TestCaseID: "1", Title: "smoke", State: "launching"}},
+				thresholds:   map[string]float64{stormforge.ThresholdErrorRate: 0.5},
+			},
+		},
+		"NoTestCase": {
+			reason:     "A run cannot be launched until its TestCase is known, for example because its reference has not been resolved.",
+			kube:       testCase("1"),
+			client:     &fake.Client{},
+			unresolved: true,
+			want:       want{err: errors.Wrapf(errors.New(errNoTestCase), errs.CreateFmt, externalKind)},
+		},
+		"GetTestCaseError": {
+			reason: "Errors getting the referenced TestCase should be wrapped.",
+			kube:   &test.MockClient{MockGet: test.NewMockGetFn(errBoom)},
+			client: &fake.Client{},
+			want:   want{err: errors.Wrapf(errors.Wrap(errBoom, errGetTestCase), errs.CreateFmt, externalKind)},
+		},
+		"TestCaseNotReady": {
+			reason: "A run of a TestCase that has not been created in StormForge cannot be launched.",
+			kube:   testCase(""),
+			client: &fake.Client{},
+			want:   want{err: errors.Wrapf(errors.Errorf(errTestCaseNotReadyFmt, "checkout"), errs.CreateFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			cr := testRun("")
+			cr.Spec.ForProvider.SLORefs = tc.slos
+			cr.Spec.ForProvider.Stages = tc.stages
+			cr.Spec.ForProvider.MinDuration, cr.Spec.ForProvider.MaxDuration = tc.min, tc.max
+			cr.Spec.ForProvider.Environments = tc.envs
+			if tc.unresolved {
+				cr.Spec.ForProvider.TestCase = ""
+			}
+			e := external{kube: tc.kube, client: tc.client, recorder: event.NewNopRecorder()}
+			_, err := e.Create(context.Background(), cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.externalName, meta.GetExternalName(cr)); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want external name, +got external name:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.runs, tc.client.Runs); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want runs, +got runs:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.thresholds, tc.client.RunOptions["1"].Thresholds); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want thresholds, +got thresholds:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.phases, tc.client.RunOptions["1"].ArrivalPhases); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want arrival phases, +got arrival phases:\n%s\n", tc.reason, diff)
+			}
+			targets := map[string]string{}
+			for id, o := range tc.client.RunOptions {
+				if o.Target != "" {
+					targets[id] = o.Target
+				}
+			}
+			if diff := cmp.Diff(tc.want.targets, targets, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\ne.Create(...): -want targets, +got targets:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		phase  v1alpha1.TestRunPhase
+		min    *metav1.Duration
+		err    error
+		want   string
+	}{
+		"Active": {
+			reason: "An active run should be aborted.",
+			phase:  v1alpha1.TestRunRunning,
+			want:   "aborted",
+		},
+		"BeforeMinDuration": {
+			reason: "A run that has not run for its minimum duration should not be aborted yet.",
+			phase:  v1alpha1.TestRunRunning,
+			min:    &metav1.Duration{Duration: time.Hour},
+			err:    errors.Wrapf(errors.Errorf(errMinDurationFmt, "r1", time.Hour), errs.DeleteFmt, externalKind),
+			want:   "running",
+		},
+		"AfterMinDuration": {
+			reason: "A run that has run for its minimum duration should be aborted.",
+			phase:  v1alpha1.TestRunRunning,
+			min:    &metav1.Duration{Duration: time.Nanosecond},
+			want:   "aborted",
+		},
+		"Ended": {
+			reason: "A run that has ended should be left as is.",
+			phase:  v1alpha1.TestRunSucceeded,
+			want:   "done",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			state := map[v1alpha1.TestRunPhase]string{v1alpha1.TestRunRunning: "running", v1alpha1.TestRunSucceeded: "done"}[tc.phase]
+			fc := &fake.Client{Runs: map[string]stormforge.TestRun{"r1": {ID: "r1", State: state}}}
+			cr := testRun("r1")
+			cr.Status.AtProvider.Phase = tc.phase
+			cr.Status.AtProvider.ID = "r1"
+			cr.Status.AtProvider.StartedAt = &metav1.Time{Time: time.Now().Add(-time.Minute)}
+			cr.Status.AtProvider.MinDuration = tc.min
+			e := external{client: fc, recorder: event.NewNopRecorder()}
+			err := e.Delete(context.Background(), cr)
+			if diff := cmp.Diff(tc.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Delete(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if got := fc.Runs["r1"].State; got != tc.want {
+				t.Errorf("\n%s\ne.Delete(...): want state %q, got %q", tc.reason, tc.want, got)
+			}
+			if tc.phase == v1alpha1.TestRunRunning && cr.GetCondition(xpv1.TypeReady).Reason != xpv1.ReasonDeleting {
+				t.Errorf("\n%s\ne.Delete(...): want Deleting condition", tc.reason)
+			}
+		})
+	}
+}
+
+func TestDeleteEnvironments(t *testing.T) {
+	fc := &fake.Client{Runs: map[string]stormforge.TestRun{
+		"r1": {ID: "r1", State: "done"},
+		"r2": {ID: "r2", State: "running"},
+		"r3": {ID: "r3", State: "queued"},
+	}}
+	cr := testRun("r1,r2,r3")
+	cr.Status.AtProvider.Phase = v1alpha1.TestRunRunning
+	cr.Status.AtProvider.Environments = []v1alpha1.EnvironmentObservation{
+		{Name: "staging", ID: "r1", State: "done", Phase: v1alpha1.TestRunSucceeded},
+		{Name: "production", ID: "r2", State: "running", Phase: v1alpha1.TestRunRunning},
+		{Name: "canary", ID: "r3", State: "queued", Phase: v1alpha1.TestRunPending},
+	}
+	e := external{client: fc, recorder: event.NewNopRecorder()}
+	if err := e.Delete(context.Background(), cr); err != nil {
+		t.Fatalf("e.Delete(...): unexpected error: %s", err)
+	}
+	want := map[string]string{"r1": "done", "r2": "aborted", "r3": "aborted"}
+	got := map[string]string{}
+	for id, r := range fc.Runs {
+		got[id] = r.State
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("e.Delete(...): only the active runs of each environment should be aborted: -want states, +got states:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package testrunschedule launches TestRuns on a recurring schedule.
+package testrunschedule
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/ratelimiter"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/cron"
+)
+
+// LabelKeySchedule is set on the TestRuns launched by a TestRunSchedule to the
+// name of the schedule.
+const LabelKeySchedule = "stormforge.io/test-run-schedule"
+
+const (
+	reconcileTimeout    = 1 * time.Minute
+	defaultHistoryLimit = 3
+)
+
+const (
+	errGetSchedule    = "cannot get TestRunSchedule"
+	errListRuns       = "cannot list TestRuns of schedule"
+	errPruneRun       = "cannot delete TestRun beyond history limit"
+	errParseSchedule  = "cannot parse schedule"
+	errNeverScheduled = "schedule never matches"
+	errLaunchRun      = "cannot create TestRun"
+	errUpdateStatus   = "cannot update TestRunSchedule status"
+)
+
+// Event reasons.
+const (
+	reasonLaunched event.Reason = "LaunchedTestRun"
+	reasonPruned   event.Reason = "DeletedTestRun"
+)
+
+// Setup adds a controller that reconciles TestRunSchedules. TestRunSchedules
+// are not managed resources, so the supplied StormForge client options are
+// unused.
+func Setup(mgr ctrl.Manager, l logging.Logger, rl workqueue.RateLimiter, _ ...stormforge.Option) error {
+	name := "schedule/" + strings.ToLower(v1alpha1.TestRunScheduleGroupKind)
+
+	r := &Reconciler{
+		client: mgr.GetClient(),
+		log:    l.WithValues("controller", name),
+		record: event.NewAPIRecorder(mgr.GetEventRecorderFor(name)),
+		now:    time.Now,
+	}
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named(name).
+		WithOptions(controller.Options{RateLimiter: ratelimiter.NewDefaultManagedRateLimiter(rl)}).
+		For(&v1alpha1.TestRunSchedule{}).
+		Owns(&v1alpha1.TestRun{}).
+		Complete(r)
+}
+
+// A Reconciler launches the TestRuns of a TestRunSchedule when they are due,
+// and deletes those that have ended beyond its history limit.
+type Reconciler struct {
+	client client.Client
+	log    logging.Logger
+	record event.Recorder
+	now    func() time.Time
+}
+
+// Reconcile a TestRunSchedule.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	log := r.log.WithValues("request", req)
+	log.Debug("Reconciling")
+
+	ctx, cancel := context.WithTimeout(ctx, reconcileTimeout)
+	defer cancel()
+
+	s := &v1alpha1.TestRunSchedule{}
+	if err := r.client.Get(ctx, req.NamespacedName, s); err != nil {
+		// There's no need to requeue if the schedule no longer exists.
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetSchedule)
+	}
+	if meta.WasDeleted(s) {
+		// The TestRuns of the schedule are garbage collected with it.
+		return reconcile.Result{}, nil
+	}
+
+	l := &v1alpha1.TestRunList{}
+	if err := r.client.List(ctx, l, client.MatchingLabels{LabelKeySchedule: s.GetName()}); err != nil {
+		return reconcile.Result{}, errors.Wrap(err, errListRuns)
+	}
+	active, ended := partition(s, l.Items)
+
+	if err := r.prune(ctx, s, ended); err != nil {
+		log.Debug(errPruneRun, "error", err)
+		s.Status.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errPruneRun)))
+		return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateStatus)
+	}
+
+	sched, err := cron.Parse(s.Spec.Schedule)
+	if err != nil {
+		// There's no need to requeue until the schedule is fixed, which will
+		// trigger a new reconcile.
+		s.Status.Active = names(active)
+		s.Status.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errParseSchedule)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateStatus)
+	}
+
+	now := r.now().UTC()
+	due, next := schedule(sched, last(s), now)
+	if !due.IsZero() {
+		// Runs that were due while the schedule was suspended are skipped.
+		s.Status.LastScheduleTime = &metav1.Time{Time: due}
+		if !s.Spec.Suspend {
+			run := newRun(s, due)
+			if err := r.client.Create(ctx, run); err != nil && !kerrors.IsAlreadyExists(err) {
+				log.Debug(errLaunchRun, "error", err)
+				s.Status.SetConditions(xpv1.ReconcileError(errors.Wrap(err, errLaunchRun)))
+				return reconcile.Result{Requeue: true}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateStatus)
+			}
+			r.record.Event(s, event.Normal(reasonLaunched, fmt.Sprintf("Created TestRun %q", run.GetName())))
+			active = append(active, *run)
+		}
+	}
+
+	s.Status.Active = names(active)
+	if next.IsZero() {
+		s.Status.SetConditions(xpv1.ReconcileError(errors.New(errNeverScheduled)))
+		return reconcile.Result{}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateStatus)
+	}
+	s.Status.SetConditions(xpv1.ReconcileSuccess())
+	return reconcile.Result{RequeueAfter: next.Sub(now)}, errors.Wrap(r.client.Status().Update(ctx, s), errUpdateStatus)
+}
+
+// prune deletes the oldest of the supplied TestRuns that have ended, so that
+// no more than the history limit of the supplied schedule remain.
+func (r *Reconciler) prune(ctx context.Context, s *v1alpha1.TestRunSchedule, ended []v1alpha1.TestRun) error {
+	limit := defaultHistoryLimit
+	if s.Spec.HistoryLimit != nil {
+		limit = int(*s.Spec.HistoryLimit)
+	}
+	if len(ended) <= limit {
+		return nil
+	}
+	sort.SliceStable(ended, func(i, j int) bool {
+		return ended[i].CreationTimestamp.Before(&ended[j].CreationTimestamp)
+	})
+	for i := range ended[:len(ended)-limit] {
+		run := &ended[i]
+		if err := r.client.Delete(ctx, run); resource.IgnoreNotFound(err) != nil {
+			return err
+		}
+		r.record.Event(s, event.Normal(reasonPruned, fmt.Sprintf("Deleted TestRun %q", run.GetName())))
+	}
+	return nil
+}
+
+// partition the supplied TestRuns controlled by the supplied schedule into
+// those whose runs are active and those whose runs have ended.
+func partition(s *v1alpha1.TestRunSchedule, runs []v1alpha1.TestRun) (active, ended []v1alpha1.TestRun) {
+	for _, run := range runs {
+		if !metav1.IsControlledBy(&run, s) {
+			continue
+		}
+		switch run.Status.AtProvider.Phase {
+		case v1alpha1.TestRunSucceeded, v1alpha1.TestRunFailed, v1alpha1.TestRunAborted:
+			ended = append(ended, run)
+		default:
+			active = append(active, run)
+		}
+	}
+	return active, ended
+}
+
+// last returns the time from which the supplied schedule is next due.
+func last(s *v1alpha1.TestRunSchedule) time.Time {
+	if s.Status.LastScheduleTime != nil {
+		return s.Status.LastScheduleTime.Time
+	}
+	return s.GetCreationTimestamp().Time
+}
+
+// schedule returns the most recent time after last and no later than now at
+// which the supplied schedule was due, if any, and the next time after now at
+// which it will be due. Either may be the zero time.
+func schedule(s *cron.Schedule, last, now time.Time) (due, next time.Time) {
+	for t := s.Next(last.UTC()); !t.IsZero() && !t.After(now); t = s.Next(t) {
+		due = t
+	}
+	return due, s.Next(now)
+}
+
+// newRun returns the TestRun the supplied schedule launches at the supplied
+// time. Its name is derived from the time so that it is launched only once.
+func newRun(s *v1alpha1.TestRunSchedule, at time.Time) *v1alpha1.TestRun {
+	return &v1alpha1.TestRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            fmt.Sprintf("%s-%d", s.GetName(), at.Unix()/60),
+			Labels:          map[string]string{LabelKeySchedule: s.GetName()},
+			OwnerReferences: []metav1.OwnerReference{meta.AsController(meta.TypedReferenceTo(s, v1alpha1.TestRunScheduleGroupVersionKind))},
+		},
+		Spec: *s.Spec.RunTemplate.DeepCopy(),
+	}
+}
+
+func names(runs []v1alpha1.TestRun) []string {
+	if len(runs) == 0 {
+		return nil
+	}
+	n := make([]string, len(runs))
+	for i := range runs {
+		n[i] = runs[i].GetName()
+	}
+	sort.Strings(n)
+	return n
+}
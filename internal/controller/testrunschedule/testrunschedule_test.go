@@ -0,0 +1,227 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package testrunschedule
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/event"
+	"github.com/crossplane/crossplane-runtime/pkg/logging"
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+)
+
+func TestReconcile(t *testing.T) {
+	created := time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC)
+	nightly := created.Add(2 * time.Hour)
+
+	schedule := func(spec string, m ...func(s *v1alpha1.TestRunSchedule)) *v1alpha1.TestRunSchedule {
+		s := &v1alpha1.TestRunSchedule{
+			ObjectMeta: metav1.ObjectMeta{Name: "nightly", UID: "uid", CreationTimestamp: metav1.NewTime(created)},
+			Spec: v1alpha1.TestRunScheduleSpec{
+				Schedule: spec,
+				RunTemplate: v1alpha1.TestRunSpec{
+					ResourceSpec: xpv1.ResourceSpec{ProviderConfigReference: &xpv1.Reference{Name: "example"}},
+					ForProvider:  v1alpha1.TestRunParameters{TestCase: "checkout", Title: "soak"},
+				},
+			},
+		}
+		for _, fn := range m {
+			fn(s)
+		}
+		return s
+	}
+	ended := func(s *v1alpha1.TestRunSchedule, name string, age time.Duration) *v1alpha1.TestRun {
+		r := newRun(s, created)
+		r.SetName(name)
+		r.SetCreationTimestamp(metav1.NewTime(created.Add(-age)))
+		r.Status.AtProvider.Phase = v1alpha1.TestRunSucceeded
+		return r
+	}
+
+	type want struct {
+		result   reconcile.Result
+		runs     []string
+		last     *metav1.Time
+		active   []string
+		synced   xpv1.Condition
+		notFound bool
+	}
+
+	cases := map[string]struct {
+		reason string
+		objs   func() []client.Object
+		now    time.Time
+		want   want
+	}{
+		"NotFound": {
+			reason: "A schedule that no longer exists should not be requeued.",
+			objs:   func() []client.Object { return nil },
+			now:    nightly,
+			want:   want{notFound: true},
+		},
+		"NotDue": {
+			reason: "No run should be launched before the schedule is due.",
+			objs:   func() []client.Object { return []client.Object{schedule("0 2 * * *")} },
+			now:    created.Add(time.Hour),
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Hour},
+				synced: xpv1.ReconcileSuccess(),
+			},
+		},
+		"Due": {
+			reason: "A run should be launched when the schedule is due.",
+			objs:   func() []client.Object { return []client.Object{schedule("0 2 * * *")} },
+			now:    nightly.Add(30 * time.Minute),
+			want: want{
+				result: reconcile.Result{RequeueAfter: 23*time.Hour + 30*time.Minute},
+				runs:   []string{fmt.Sprintf("nightly-%d", nightly.Unix()/60)},
+				last:   &metav1.Time{Time: nightly},
+				active: []string{fmt.Sprintf("nightly-%d", nightly.Unix()/60)},
+				synced: xpv1.ReconcileSuccess(),
+			},
+		},
+		"MissedRuns": {
+			reason: "Only the most recent of several missed runs should be launched.",
+			objs:   func() []client.Object { return []client.Object{schedule("0 2 * * *")} },
+			now:    nightly.AddDate(0, 0, 2),
+			want: want{
+				result: reconcile.Result{RequeueAfter: 24 * time.Hour},
+				runs:   []string{fmt.Sprintf("nightly-%d", nightly.AddDate(0, 0, 2).Unix()/60)},
+				last:   &metav1.Time{Time: nightly.AddDate(0, 0, 2)},
+				active: []string{fmt.Sprintf("nightly-%d", nightly.AddDate(0, 0, 2).Unix()/60)},
+				synced: xpv1.ReconcileSuccess(),
+			},
+		},
+		"Suspended": {
+			reason: "No run should be launched while the schedule is suspended.",
+			objs: func() []client.Object {
+				return []client.Object{schedule("0 2 * * *", func(s *v1alpha1.TestRunSchedule) { s.Spec.Suspend = true })}
+			},
+			now: nightly,
+			want: want{
+				result: reconcile.Result{RequeueAfter: 24 * time.Hour},
+				last:   &metav1.Time{Time: nightly},
+				synced: xpv1.ReconcileSuccess(),
+			},
+		},
+		"Prune": {
+			reason: "TestRuns that have ended beyond the history limit should be deleted, oldest first.",
+			objs: func() []client.Object {
+				var limit int32 = 1
+				s := schedule("0 2 * * *", func(s *v1alpha1.TestRunSchedule) { s.Spec.HistoryLimit = &limit })
+				return []client.Object{s, ended(s, "old", 2*time.Hour), ended(s, "older", 3*time.Hour), ended(s, "newest", time.Hour)}
+			},
+			now: created.Add(time.Hour),
+			want: want{
+				result: reconcile.Result{RequeueAfter: time.Hour},
+				runs:   []string{"newest"},
+				synced: xpv1.ReconcileSuccess(),
+			},
+		},
+		"InvalidSchedule": {
+			reason: "An invalid schedule should be reported and not requeued.",
+			objs:   func() []client.Object { return []client.Object{schedule("every night")} },
+			now:    nightly,
+			want: want{
+				synced: xpv1.ReconcileError(errors.New(errParseSchedule)),
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := runtime.NewScheme()
+			if err := v1alpha1.SchemeBuilder.AddToScheme(s); err != nil {
+				t.Fatal(err)
+			}
+			kube := fake.NewClientBuilder().WithScheme(s).WithObjects(tc.objs()...).Build()
+			r := &Reconciler{client: kube, log: logging.NewNopLogger(), record: event.NewNopRecorder(), now: func() time.Time { return tc.now }}
+
+			got, err := r.Reconcile(context.Background(), reconcile.Request{NamespacedName: types.NamespacedName{Name: "nightly"}})
+			if err != nil {
+				t.Fatalf("\n%s\nr.Reconcile(...): unexpected error: %s", tc.reason, err)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s\n", tc.reason, diff)
+			}
+			if tc.want.notFound {
+				return
+			}
+
+			l := &v1alpha1.TestRunList{}
+			if err := kube.List(context.Background(), l); err != nil {
+				t.Fatal(err)
+			}
+			runs := []string{}
+			for _, run := range l.Items {
+				runs = append(runs, run.GetName())
+				if run.Spec.ForProvider.TestCase != "checkout" || run.GetLabels()[LabelKeySchedule] != "nightly" || len(run.GetOwnerReferences()) != 1 {
+					t.Errorf("\n%s\nr.Reconcile(...): TestRun %q was not created from the schedule's template", tc.reason, run.GetName())
+				}
+			}
+			if diff := cmp.Diff(tc.want.runs, runs, cmpopts.EquateEmpty()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want runs, +got runs:\n%s\n", tc.reason, diff)
+			}
+
+			sched := &v1alpha1.TestRunSchedule{}
+			if err := kube.Get(context.Background(), types.NamespacedName{Name: "nightly"}, sched); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.last, sched.Status.LastScheduleTime, cmpopts.EquateApproxTime(time.Second)); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want last schedule time, +got last schedule time:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.active, sched.Status.Active); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want active, +got active:\n%s\n", tc.reason, diff)
+			}
+			synced := sched.Status.GetCondition(xpv1.TypeSynced)
+			if synced.Status != tc.want.synced.Status || synced.Reason != tc.want.synced.Reason {
+				t.Errorf("\n%s\nr.Reconcile(...): want Synced %s (%s), got %s (%s)", tc.reason, tc.want.synced.Status, tc.want.synced.Reason, synced.Status, synced.Reason)
+			}
+		})
+	}
+}
+
+// Ensure newRun makes the schedule the TestRun's controller.
+func TestNewRun(t *testing.T) {
+	s := &v1alpha1.TestRunSchedule{ObjectMeta: metav1.ObjectMeta{Name: "nightly", UID: "uid"}}
+	r := newRun(s, time.Unix(120, 0))
+	if r.GetName() != "nightly-2" {
+		t.Errorf("newRun(...): want name %q, got %q", "nightly-2", r.GetName())
+	}
+	if !metav1.IsControlledBy(r, s) {
+		t.Errorf("newRun(...): want TestRun controlled by schedule")
+	}
+	if meta.GetExternalName(r) != "" {
+		t.Errorf("newRun(...): want no external name, got %q", meta.GetExternalName(r))
+	}
+}
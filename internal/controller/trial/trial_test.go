@@ -0,0 +1,128 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package trial
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/crossplane/crossplane-runtime/pkg/meta"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/optimize/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge/fake"
+	"github.com/luebken/provider-stormforge/internal/errs"
+)
+
+func trial(number string) *v1alpha1.Trial {
+	cr := &v1alpha1.Trial{Spec: v1alpha1.TrialSpec{ForProvider: v1alpha1.TrialParameters{Experiment: "checkout"}}}
+	meta.SetExternalName(cr, number)
+	return cr
+}
+
+func TestObserve(t *testing.T) {
+	errBoom := &stormforge.APIError{StatusCode: http.StatusServiceUnavailable}
+	start := time.Date(2020, 11, 1, 10, 0, 0, 0, time.UTC)
+
+	type want struct {
+		o      managed.ExternalObservation
+		status v1alpha1.TrialObservation
+		err    error
+	}
+
+	cases := map[string]struct {
+		reason string
+		client *fake.Client
+		cr     *v1alpha1.Trial
+		want   want
+	}{
+		"Exists": {
+			reason: "The assignments and observed values of an existing trial should be reported.",
+			client: &fake.Client{Trials: map[string][]stormforge.Trial{"checkout": {
+				{Number: 2, Status: stormforge.TrialActive},
+				{
+					Number:      3,
+					Status:      stormforge.TrialCompleted,
+					Assignments: []stormforge.Assignment{{Parameter: "cpu", Value: "500"}},
+					Values:      []stormforge.Value{{Metric: "cost", Value: "12.5", Error: "0.5"}},
+					StartTime:   &start,
+				},
+			}}},
+			cr: trial("3"),
+			want: want{
+				o: managed.ExternalObservation{ResourceExists: true, ResourceUpToDate: true},
+				status: v1alpha1.TrialObservation{
+					Number:      3,
+					Status:      stormforge.TrialCompleted,
+					Assignments: []v1alpha1.TrialAssignment{{Parameter: "cpu", Value: "500"}},
+					Values:      []v1alpha1.TrialValue{{Metric: "cost", Value: "12.5", Error: "0.5"}},
+					StartTime:   &metav1.Time{Time: start},
+				},
+			},
+		},
+		"DoesNotExist": {
+			reason: "A trial that does not exist should be reported as not existing.",
+			client: &fake.Client{},
+			cr:     trial("3"),
+			want:   want{o: managed.ExternalObservation{ResourceExists: false}},
+		},
+		"NotANumber": {
+			reason: "An external name that is not a trial number should be rejected.",
+			client: &fake.Client{},
+			cr:     trial("my-trial"),
+			want:   want{err: errors.Errorf(errNumberFmt, "my-trial")},
+		},
+		"GetError": {
+			reason: "Errors getting the trial should be wrapped.",
+			client: &fake.Client{Err: errBoom},
+			cr:     trial("3"),
+			want:   want{err: errors.Wrapf(errBoom, errs.ObserveFmt, externalKind)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			e := external{client: tc.client}
+			got, err := e.Observe(context.Background(), tc.cr)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.o, got); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.status, tc.cr.Status.AtProvider); diff != "" {
+				t.Errorf("\n%s\ne.Observe(...): -want status, +got status:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestCreate(t *testing.T) {
+	e := external{client: &fake.Client{}}
+	_, err := e.Create(context.Background(), trial("3"))
+	if diff := cmp.Diff(errors.New(errObserveOnly), err, test.EquateErrors()); diff != "" {
+		t.Errorf("e.Create(...): -want error, +got error:\n%s\n", diff)
+	}
+}
@@ -0,0 +1,113 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package credentials extracts the credentials referenced by a ProviderConfig.
+package credentials
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+)
+
+// An Extractor extracts credentials from the supplied source.
+type Extractor func(ctx context.Context, source xpv1.CredentialsSource, c client.Client, selector xpv1.CommonCredentialSelectors) ([]byte, error)
+
+const errUnsupportedFmt = "unsupported credentials source %q: must be one of %s"
+
+// common are the sources handled by resource.CommonCredentialExtractor.
+var common = []xpv1.CredentialsSource{
+	xpv1.CredentialsSourceNone,
+	xpv1.CredentialsSourceSecret,
+	xpv1.CredentialsSourceInjectedIdentity,
+	xpv1.CredentialsSourceEnvironment,
+	xpv1.CredentialsSourceFilesystem,
+}
+
+var (
+	mu         sync.RWMutex
+	extractors = map[xpv1.CredentialsSource]Extractor{}
+)
+
+// Register an Extractor for the supplied credentials source. Registering an
+// extractor for a source that already has one replaces it, including the
+// common sources handled by resource.CommonCredentialExtractor.
+func Register(source xpv1.CredentialsSource, e Extractor) {
+	mu.Lock()
+	defer mu.Unlock()
+	extractors[source] = e
+}
+
+// Unregister the Extractor for the supplied credentials source, if any.
+func Unregister(source xpv1.CredentialsSource) {
+	mu.Lock()
+	defer mu.Unlock()
+	delete(extractors, source)
+}
+
+// Supported returns the supported credentials sources, sorted by name: the
+// common sources and those with a registered Extractor.
+func Supported() []xpv1.CredentialsSource {
+	mu.RLock()
+	defer mu.RUnlock()
+	s := append([]xpv1.CredentialsSource{}, common...)
+	for source := range extractors {
+		if !isCommon(source) {
+			s = append(s, source)
+		}
+	}
+	sort.Slice(s, func(i, j int) bool { return s[i] < s[j] })
+	return s
+}
+
+// Extract credentials from the supplied source using the Extractor registered
+// for it, falling back to resource.CommonCredentialExtractor. An error listing
+// the supported sources is returned for a source that is neither common nor
+// registered.
+func Extract(ctx context.Context, source xpv1.CredentialsSource, c client.Client, selector xpv1.CommonCredentialSelectors) ([]byte, error) {
+	mu.RLock()
+	e, ok := extractors[source]
+	mu.RUnlock()
+	if !ok {
+		if !isCommon(source) {
+			names := make([]string, 0, len(common))
+			for _, s := range Supported() {
+				names = append(names, string(s))
+			}
+			return nil, errors.Errorf(errUnsupportedFmt, source, strings.Join(names, ", "))
+		}
+		e = resource.CommonCredentialExtractor
+	}
+	return e(ctx, source, c, selector)
+}
+
+// isCommon returns true if the supplied source is handled by
+// resource.CommonCredentialExtractor.
+func isCommon(source xpv1.CredentialsSource) bool {
+	for _, s := range common {
+		if s == source {
+			return true
+		}
+	}
+	return false
+}
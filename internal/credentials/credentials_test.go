@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentials
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+const sourceVault xpv1.CredentialsSource = "Vault"
+
+func TestExtract(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	type args struct {
+		source   xpv1.CredentialsSource
+		selector xpv1.CommonCredentialSelectors
+	}
+
+	type want struct {
+		data []byte
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason   string
+		register map[xpv1.CredentialsSource]Extractor
+		env      map[string]string
+		args     args
+		want     want
+	}{
+		"CustomExtractor": {
+			reason: "A registered extractor should be used for its source.",
+			register: map[xpv1.CredentialsSource]Extractor{
+				sourceVault: func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+					return []byte("vault-jwt"), nil
+				},
+			},
+			args: args{source: sourceVault},
+			want: want{data: []byte("vault-jwt")},
+		},
+		"CustomExtractorError": {
+			reason: "Errors from a registered extractor should be returned unchanged.",
+			register: map[xpv1.CredentialsSource]Extractor{
+				sourceVault: func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+					return nil, errBoom
+				},
+			},
+			args: args{source: sourceVault},
+			want: want{err: errBoom},
+		},
+		"FallbackToCommon": {
+			reason: "Sources without a registered extractor should use the common extractor.",
+			env:    map[string]string{"STORMFORGE_TEST_JWT": "env-jwt"},
+			args: args{
+				source:   xpv1.CredentialsSourceEnvironment,
+				selector: xpv1.CommonCredentialSelectors{Env: &xpv1.EnvSelector{Name: "STORMFORGE_TEST_JWT"}},
+			},
+			want: want{data: []byte("env-jwt")},
+		},
+		"UnsupportedSource": {
+			reason: "A source that is neither common nor registered should be rejected with the supported sources.",
+			args:   args{source: sourceVault},
+			want:   want{err: errors.Errorf(errUnsupportedFmt, sourceVault, "Environment, Filesystem, InjectedIdentity, None, Secret")},
+		},
+		"UnsupportedSourceWithRegistered": {
+			reason: "The supported sources listed for an unsupported source should include registered sources.",
+			register: map[xpv1.CredentialsSource]Extractor{
+				sourceVault: func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+					return nil, nil
+				},
+			},
+			args: args{source: "Keychain"},
+			want: want{err: errors.Errorf(errUnsupportedFmt, "Keychain", "Environment, Filesystem, InjectedIdentity, None, Secret, Vault")},
+		},
+		"OverrideCommon": {
+			reason: "A registered extractor should take precedence over the common extractor.",
+			register: map[xpv1.CredentialsSource]Extractor{
+				xpv1.CredentialsSourceNone: func(_ context.Context, _ xpv1.CredentialsSource, _ client.Client, _ xpv1.CommonCredentialSelectors) ([]byte, error) {
+					return []byte("anonymous"), nil
+				},
+			},
+			args: args{source: xpv1.CredentialsSourceNone},
+			want: want{data: []byte("anonymous")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			for s, e := range tc.register {
+				Register(s, e)
+				defer Unregister(s)
+			}
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+
+			got, err := Extract(context.Background(), tc.args.source, nil, tc.args.selector)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nExtract(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.data, got); diff != "" {
+				t.Errorf("\n%s\nExtract(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
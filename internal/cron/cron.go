@@ -0,0 +1,225 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cron parses standard five field cron schedules.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errFieldsFmt = "schedule %q must have five fields: minute, hour, day of month, month and day of week"
+	errFieldFmt  = "invalid %s field %q"
+	errRangeFmt  = "%s value %d is out of range [%d, %d]"
+)
+
+// maxYears bounds how far ahead Next searches for a matching time, so that
+// schedules that never match (e.g. February 30th) end the search.
+const maxYears = 5
+
+// descriptors are the supported shorthands for common schedules.
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+type bounds struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minutes = bounds{name: "minute", min: 0, max: 59}
+	hours   = bounds{name: "hour", min: 0, max: 23}
+	doms    = bounds{name: "day of month", min: 1, max: 31}
+	months  = bounds{name: "month", min: 1, max: 12, names: map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dows = bounds{name: "day of week", min: 0, max: 7, names: map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// A Schedule is a parsed cron schedule. Each field is a bit set of the values
+// it matches.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+
+	// domAny and dowAny record whether the day of month and day of week
+	// fields started with a * (or were a ?). If both are restricted a day
+	// matches when either does, as in cron.
+	domAny, dowAny bool
+}
+
+// Parse the supplied cron schedule. It must have five space separated fields
+// (minute, hour, day of month, month and day of week), or be one of the
+// descriptors @yearly, @annually, @monthly, @weekly, @daily, @midnight and
+// @hourly. Fields may be a *, or a comma separated list of values and ranges,
+// each optionally followed by a /step. Months and days of week may be given by
+// their first three letters. Sunday is both 0 and 7.
+func Parse(spec string) (*Schedule, error) {
+	if d, ok := descriptors[strings.ToLower(strings.TrimSpace(spec))]; ok {
+		spec = d
+	}
+	f := strings.Fields(spec)
+	if len(f) != 5 {
+		return nil, errors.Errorf(errFieldsFmt, spec)
+	}
+	s := &Schedule{domAny: unrestricted(f[2]), dowAny: unrestricted(f[4])}
+	var err error
+	for _, p := range []struct {
+		field string
+		b     bounds
+		out   *uint64
+	}{
+		{f[0], minutes, &s.minute},
+		{f[1], hours, &s.hour},
+		{f[2], doms, &s.dom},
+		{f[3], months, &s.month},
+		{f[4], dows, &s.dow},
+	} {
+		if *p.out, err = parseField(p.field, p.b); err != nil {
+			return nil, err
+		}
+	}
+	// Sunday may be written as 7.
+	if s.dow&(1<<7) != 0 {
+		s.dow |= 1
+	}
+	return s, nil
+}
+
+// unrestricted returns true if the supplied day field starts with a *, as
+// cron does. A stepped field such as */2 is therefore combined with the other
+// day field rather than either matching.
+func unrestricted(field string) bool {
+	return strings.HasPrefix(field, "*") || field == "?"
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var set uint64
+	for _, part := range strings.Split(field, ",") {
+		r, step := part, 1
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.Atoi(part[i+1:])
+			if err != nil || n < 1 {
+				return 0, errors.Errorf(errFieldFmt, b.name, field)
+			}
+			r, step = part[:i], n
+		}
+
+		lo, hi := b.min, b.max
+		switch {
+		case r == "*" || r == "?":
+		case strings.Contains(r, "-"):
+			i := strings.Index(r, "-")
+			var err error
+			if lo, err = value(r[:i], b); err != nil {
+				return 0, err
+			}
+			if hi, err = value(r[i+1:], b); err != nil {
+				return 0, err
+			}
+			if lo > hi {
+				return 0, errors.Errorf(errFieldFmt, b.name, field)
+			}
+		default:
+			v, err := value(r, b)
+			if err != nil {
+				return 0, err
+			}
+			lo = v
+			// A single value with a step, e.g. 5/15, runs to the maximum.
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		for v := lo; v <= hi; v += step {
+			set |= 1 << uint(v)
+		}
+	}
+	return set, nil
+}
+
+func value(s string, b bounds) (int, error) {
+	if v, ok := b.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errors.Errorf(errFieldFmt, b.name, s)
+	}
+	if v < b.min || v > b.max {
+		return 0, errors.Errorf(errRangeFmt, b.name, v, b.min, b.max)
+	}
+	return v, nil
+}
+
+// Next returns the first time after the supplied time that matches the
+// schedule, in the location of the supplied time. It returns the zero time if
+// the schedule does not match within the next five years.
+func (s *Schedule) Next(t time.Time) time.Time {
+	loc := t.Location()
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.AddDate(maxYears, 0, 0)
+
+	for t.Before(limit) {
+		if !has(s.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !s.day(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+		if !has(s.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// day returns true if the day of the supplied time matches the schedule.
+func (s *Schedule) day(t time.Time) bool {
+	dom, dow := has(s.dom, t.Day()), has(s.dow, int(t.Weekday()))
+	if s.domAny || s.dowAny {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(set uint64, v int) bool {
+	return set&(1<<uint(v)) != 0
+}
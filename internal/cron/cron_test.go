@@ -0,0 +1,129 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestNext(t *testing.T) {
+	// A Wednesday.
+	from := time.Date(2020, 12, 2, 10, 30, 15, 0, time.UTC)
+
+	type want struct {
+		next time.Time
+		err  error
+	}
+
+	cases := map[string]struct {
+		reason string
+		spec   string
+		want   want
+	}{
+		"EveryMinute": {
+			reason: "A schedule of stars should match the next minute.",
+			spec:   "* * * * *",
+			want:   want{next: time.Date(2020, 12, 2, 10, 31, 0, 0, time.UTC)},
+		},
+		"Nightly": {
+			reason: "A daily schedule whose time has passed today should match tomorrow.",
+			spec:   "0 2 * * *",
+			want:   want{next: time.Date(2020, 12, 3, 2, 0, 0, 0, time.UTC)},
+		},
+		"Descriptor": {
+			reason: "Descriptors should be expanded.",
+			spec:   "@hourly",
+			want:   want{next: time.Date(2020, 12, 2, 11, 0, 0, 0, time.UTC)},
+		},
+		"Step": {
+			reason: "Steps should match every nth value.",
+			spec:   "*/20 * * * *",
+			want:   want{next: time.Date(2020, 12, 2, 10, 40, 0, 0, time.UTC)},
+		},
+		"ListAndRange": {
+			reason: "Lists and ranges should be combined.",
+			spec:   "0 8-9,22 * * *",
+			want:   want{next: time.Date(2020, 12, 2, 22, 0, 0, 0, time.UTC)},
+		},
+		"NamedWeekday": {
+			reason: "Days of the week may be given by name.",
+			spec:   "0 0 * * sat",
+			want:   want{next: time.Date(2020, 12, 5, 0, 0, 0, 0, time.UTC)},
+		},
+		"SundayAsSeven": {
+			reason: "Sunday may be written as 7.",
+			spec:   "0 0 * * 7",
+			want:   want{next: time.Date(2020, 12, 6, 0, 0, 0, 0, time.UTC)},
+		},
+		"DayOfMonthOrWeek": {
+			reason: "If both day fields are restricted either should match.",
+			spec:   "0 0 15 * fri",
+			want:   want{next: time.Date(2020, 12, 4, 0, 0, 0, 0, time.UTC)},
+		},
+		"SteppedDayOfMonth": {
+			reason: "A day of month field starting with * should only narrow the day of week.",
+			spec:   "0 0 */2 * fri",
+			want:   want{next: time.Date(2020, 12, 11, 0, 0, 0, 0, time.UTC)},
+		},
+		"NextYear": {
+			reason: "A schedule should roll over into the next year.",
+			spec:   "0 0 1 jan *",
+			want:   want{next: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		},
+		"Never": {
+			reason: "A schedule that never matches should return the zero time.",
+			spec:   "0 0 30 2 *",
+			want:   want{next: time.Time{}},
+		},
+		"TooFewFields": {
+			reason: "A schedule must have five fields.",
+			spec:   "0 2 * *",
+			want:   want{err: errors.Errorf(errFieldsFmt, "0 2 * *")},
+		},
+		"OutOfRange": {
+			reason: "Values outside the range of their field should be rejected.",
+			spec:   "60 * * * *",
+			want:   want{err: errors.Errorf(errRangeFmt, "minute", 60, 0, 59)},
+		},
+		"InvalidStep": {
+			reason: "Steps must be positive integers.",
+			spec:   "*/0 * * * *",
+			want:   want{err: errors.Errorf(errFieldFmt, "minute", "*/0")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s, err := Parse(tc.spec)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Fatalf("\n%s\nParse(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if err != nil {
+				return
+			}
+			if diff := cmp.Diff(tc.want.next, s.Next(from)); diff != "" {
+				t.Errorf("\n%s\ns.Next(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
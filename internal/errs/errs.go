@@ -0,0 +1,38 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package errs contains the error messages shared by the managed resource
+// controllers, so that conditions read the same regardless of kind.
+package errs
+
+// Messages for failures while connecting to StormForge.
+const (
+	TrackPCUsage = "cannot track ProviderConfig usage"
+	GetPC        = "cannot get ProviderConfig"
+	GetCreds     = "cannot get credentials"
+	NewClient    = "cannot create new StormForge client"
+)
+
+// Format strings for failures of the external client operations. Each takes
+// the kind of the managed or external resource, e.g. "TestCase" or
+// "test case".
+const (
+	NotMyTypeFmt = "managed resource is not a %s custom resource"
+	ObserveFmt   = "cannot observe %s"
+	CreateFmt    = "cannot create %s"
+	UpdateFmt    = "cannot update %s"
+	DeleteFmt    = "cannot delete %s"
+)
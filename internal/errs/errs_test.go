@@ -0,0 +1,73 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errs
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFormats(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		format string
+		kind   string
+		want   string
+	}{
+		"NotMyType": {
+			reason: "The managed resource kind should be named in the message.",
+			format: NotMyTypeFmt,
+			kind:   "TestCase",
+			want:   "managed resource is not a TestCase custom resource",
+		},
+		"Observe": {
+			reason: "The external resource kind should be named in the message.",
+			format: ObserveFmt,
+			kind:   "test case",
+			want:   "cannot observe test case",
+		},
+		"Create": {
+			reason: "The external resource kind should be named in the message.",
+			format: CreateFmt,
+			kind:   "test case",
+			want:   "cannot create test case",
+		},
+		"Update": {
+			reason: "The external resource kind should be named in the message.",
+			format: UpdateFmt,
+			kind:   "test case",
+			want:   "cannot update test case",
+		},
+		"Delete": {
+			reason: "The external resource kind should be named in the message.",
+			format: DeleteFmt,
+			kind:   "test case",
+			want:   "cannot delete test case",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := fmt.Sprintf(tc.format, tc.kind)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nfmt.Sprintf(%q, %q): -want, +got:\n%s\n", tc.reason, tc.format, tc.kind, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,232 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fetch reads files from remote sources such as HTTP servers and Git
+// repositories.
+package fetch
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MaxSize bounds the size of a fetched file.
+const MaxSize = 10 << 20
+
+const (
+	errNewRequest  = "cannot create request"
+	errDoRequest   = "cannot send request"
+	errStatusFmt   = "unexpected status %d fetching %s"
+	errTooLargeFmt = "file is larger than %d bytes"
+	errReadBody    = "cannot read response body"
+	errTempDir     = "cannot create temporary directory"
+	errInvalidRef  = "invalid Git ref"
+	errGitFmt      = "cannot %s: %s"
+	errEmptyPath   = "path within the Git repository is required"
+)
+
+const (
+	defaultGitRef = "HEAD"
+
+	gitFetchActivity = "fetch Git repository"
+	gitLsActivity    = "list Git refs"
+	gitShowActivity  = "read file from Git repository"
+)
+
+// Auth is used to authenticate to a remote source. A token takes precedence
+// over a username and password.
+type Auth struct {
+	Token    string
+	Username string
+	Password string
+}
+
+// header returns the value of the Authorization header for the auth, if any.
+func (a Auth) header() string {
+	switch {
+	case a.Token != "":
+		return "Bearer " + a.Token
+	case a.Username != "" || a.Password != "":
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(a.Username+":"+a.Password))
+	}
+	return ""
+}
+
+// URL returns the file served at the supplied URL.
+func URL(ctx context.Context, hc *http.Client, url string, a Auth) ([]byte, error) {
+	f, err := get(ctx, hc, url, a, nil)
+	return f.b, err
+}
+
+// A file is a fetched file, and the validators of the response it was served
+// with, if any.
+type file struct {
+	b            []byte
+	etag         string
+	lastModified string
+}
+
+// get returns the file served at the supplied URL. If a previously fetched
+// copy is supplied the request is conditional, and the copy is returned if
+// the file was not modified since.
+func get(ctx context.Context, hc *http.Client, url string, a Auth, cached *file) (file, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return file{}, errors.Wrap(err, errNewRequest)
+	}
+	if h := a.header(); h != "" {
+		req.Header.Set("Authorization", h)
+	}
+	if cached != nil && cached.etag != "" {
+		req.Header.Set("If-None-Match", cached.etag)
+	}
+	if cached != nil && cached.lastModified != "" {
+		req.Header.Set("If-Modified-Since", cached.lastModified)
+	}
+	rsp, err := hc.Do(req)
+	if err != nil {
+		return file{}, errors.Wrap(err, errDoRequest)
+	}
+	defer rsp.Body.Close() //nolint:errcheck
+
+	if rsp.StatusCode == http.StatusNotModified && cached != nil {
+		return *cached, nil
+	}
+	if rsp.StatusCode < 200 || rsp.StatusCode > 299 {
+		return file{}, errors.Errorf(errStatusFmt, rsp.StatusCode, url)
+	}
+	b, err := read(rsp.Body)
+	if err != nil {
+		return file{}, err
+	}
+	return file{b: b, etag: rsp.Header.Get("ETag"), lastModified: rsp.Header.Get("Last-Modified")}, nil
+}
+
+// Git returns the file at the supplied path of the supplied ref of a Git
+// repository. The ref defaults to the repository's HEAD. Only the ref is
+// fetched, without history. Git must be installed.
+func Git(ctx context.Context, repo, ref, path string, a Auth) ([]byte, error) {
+	ref, err := gitRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	path = strings.TrimPrefix(path, "/")
+	if path == "" {
+		return nil, errors.New(errEmptyPath)
+	}
+
+	dir, err := ioutil.TempDir("", "provider-stormforge-git-")
+	if err != nil {
+		return nil, errors.Wrap(err, errTempDir)
+	}
+	defer os.RemoveAll(dir) //nolint:errcheck
+
+	env := gitEnv(a)
+	args := []string{"-C", dir}
+	if _, err := git(ctx, gitFetchActivity, env, append(args, "init", "--quiet")...); err != nil {
+		return nil, err
+	}
+	if _, err := git(ctx, gitFetchActivity, env, append(args, "fetch", "--quiet", "--depth=1", "--", repo, ref)...); err != nil {
+		return nil, err
+	}
+	b, err := git(ctx, gitShowActivity, env, append(args, "show", "FETCH_HEAD:"+path)...)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) > MaxSize {
+		return nil, errors.Errorf(errTooLargeFmt, MaxSize)
+	}
+	return b, nil
+}
+
+// revision returns the object name the supplied ref of a Git repository
+// points to, without fetching the repository. It returns an empty name if the
+// ref is not advertised by the repository, for example because it is a
+// commit.
+func revision(ctx context.Context, repo, ref string, a Auth) (string, error) {
+	ref, err := gitRef(ref)
+	if err != nil {
+		return "", err
+	}
+	b, err := git(ctx, gitLsActivity, gitEnv(a), "ls-remote", "--", repo, ref)
+	if err != nil {
+		return "", err
+	}
+	fields := strings.Fields(string(b))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	return fields[0], nil
+}
+
+// gitRef returns the supplied ref, defaulting to the repository's HEAD, or an
+// error if it could be mistaken for an option or a refspec.
+func gitRef(ref string) (string, error) {
+	if ref == "" {
+		ref = defaultGitRef
+	}
+	if strings.HasPrefix(ref, "-") || strings.ContainsAny(ref, " \t\n:") {
+		return "", errors.New(errInvalidRef)
+	}
+	return ref, nil
+}
+
+// gitEnv returns the environment of Git commands authenticated with the
+// supplied auth. The Authorization header is passed as configuration in the
+// environment, which requires Git 2.31 or later, rather than on the command
+// line, where any process on the host could read it.
+func gitEnv(a Auth) []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if h := a.header(); h != "" {
+		env = append(env, "GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: "+h)
+	}
+	return env
+}
+
+func git(ctx context.Context, activity string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, "git", args...) //nolint:gosec
+	cmd.Env = env
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return nil, errors.Errorf(errGitFmt, activity, msg)
+	}
+	return stdout.Bytes(), nil
+}
+
+func read(r io.Reader) ([]byte, error) {
+	b, err := ioutil.ReadAll(io.LimitReader(r, MaxSize+1))
+	if err != nil {
+		return nil, errors.Wrap(err, errReadBody)
+	}
+	if len(b) > MaxSize {
+		return nil, errors.Errorf(errTooLargeFmt, MaxSize)
+	}
+	return b, nil
+}
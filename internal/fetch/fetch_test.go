@@ -0,0 +1,132 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("definition.session();"))
+	}))
+	defer srv.Close()
+
+	type want struct {
+		b   []byte
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		auth   Auth
+		want   want
+	}{
+		"Success": {
+			reason: "The file should be returned when the request is authenticated.",
+			auth:   Auth{Token: "secret"},
+			want:   want{b: []byte("definition.session();")},
+		},
+		"Unauthorized": {
+			reason: "An unsuccessful status should return an error.",
+			auth:   Auth{Username: "alice", Password: "wrong"},
+			want:   want{err: errors.Errorf(errStatusFmt, http.StatusUnauthorized, srv.URL)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, err := URL(context.Background(), srv.Client(), srv.URL, tc.auth)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nURL(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.b, b); diff != "" {
+				t.Errorf("\n%s\nURL(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo, err := ioutil.TempDir("", "fetch-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repo) //nolint:errcheck
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.org"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %s", args, err, out)
+		}
+	}
+	run("init", "--quiet")
+	if err := os.MkdirAll(filepath.Join(repo, "tests"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(repo, "tests", "checkout.js"), []byte("definition.session();"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	run("add", ".")
+	run("commit", "--quiet", "-m", "Add checkout test")
+	run("tag", "v1")
+
+	cases := map[string]struct {
+		reason string
+		ref    string
+		path   string
+		want   []byte
+		err    bool
+	}{
+		"DefaultRef": {reason: "The file should be read from HEAD by default.", path: "tests/checkout.js", want: []byte("definition.session();")},
+		"Tag":        {reason: "The file should be read from the supplied ref.", ref: "v1", path: "/tests/checkout.js", want: []byte("definition.session();")},
+		"NoSuchFile": {reason: "A missing file should return an error.", path: "tests/missing.js", err: true},
+		"OptionRef":  {reason: "A ref that looks like an option should be rejected.", ref: "--upload-pack=touch", path: "tests/checkout.js", err: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			b, err := Git(context.Background(), "file://"+repo, tc.ref, tc.path, Auth{})
+			if (err != nil) != tc.err {
+				t.Fatalf("\n%s\nGit(...): want error %t, got %v", tc.reason, tc.err, err)
+			}
+			if diff := cmp.Diff(tc.want, b); diff != "" {
+				t.Errorf("\n%s\nGit(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetch
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultTimeout bounds how long a Fetcher may take to fetch a file.
+const DefaultTimeout = 30 * time.Second
+
+// DefaultSchemes are the URL schemes a Fetcher fetches files from unless
+// others are allowed.
+var DefaultSchemes = []string{"https", "http"}
+
+// maxFiles bounds the number of fetched files a Fetcher remembers.
+const maxFiles = 256
+
+const (
+	errParseURL      = "cannot parse URL"
+	errSchemeFmt     = "URL scheme %q is not allowed; allowed schemes are %s"
+	errHostFmt       = "host %q is not allowed; allowed hosts are %s"
+	errSchemeMissing = "URL has no scheme"
+)
+
+// A Fetcher fetches files from HTTP servers and Git repositories whose URLs
+// are allowed, bounding how long each fetch may take. It remembers the files
+// it fetched, so that unchanged files are not downloaded again: a file served
+// over HTTP is requested conditionally with the validators of the response it
+// was last served with, and a file of a Git repository is only fetched when
+// the ref it is read from points to a revision it was not yet read from.
+type Fetcher struct {
+	client  *http.Client
+	timeout time.Duration
+	schemes []string
+	hosts   []string
+
+	mu    sync.Mutex
+	files map[string]file
+}
+
+// An Option configures a Fetcher.
+type Option func(*Fetcher)
+
+// WithTimeout bounds how long fetching a file may take. A timeout of zero or
+// less uses DefaultTimeout.
+func WithTimeout(d time.Duration) Option {
+	return func(f *Fetcher) {
+		if d > 0 {
+			f.timeout = d
+		}
+	}
+}
+
+// WithAllowedSchemes sets the URL schemes files may be fetched from. No
+// schemes allows DefaultSchemes.
+func WithAllowedSchemes(s ...string) Option {
+	return func(f *Fetcher) {
+		if len(s) > 0 {
+			f.schemes = s
+		}
+	}
+}
+
+// WithAllowedHosts sets the hosts files may be fetched from. A host starting
+// with "*." allows any subdomain of the rest of it. No hosts allows any host.
+func WithAllowedHosts(h ...string) Option {
+	return func(f *Fetcher) {
+		f.hosts = h
+	}
+}
+
+// New returns a Fetcher configured by the supplied options.
+func New(o ...Option) *Fetcher {
+	f := &Fetcher{timeout: DefaultTimeout, schemes: DefaultSchemes, files: map[string]file{}}
+	for _, fn := range o {
+		fn(f)
+	}
+	f.client = &http.Client{Timeout: f.timeout}
+	return f
+}
+
+// URL returns the file served at the supplied URL.
+func (f *Fetcher) URL(ctx context.Context, u string, a Auth) ([]byte, error) {
+	if err := f.allowed(u); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	key := "url\x00" + u
+	cached, ok := f.get(key)
+	var c *file
+	if ok && (cached.etag != "" || cached.lastModified != "") {
+		c = &cached
+	}
+	fl, err := get(ctx, f.client, u, a, c)
+	if err != nil {
+		return nil, err
+	}
+	f.put(key, fl)
+	return copyOf(fl.b), nil
+}
+
+// Git returns the file at the supplied path of the supplied ref of a Git
+// repository. The ref defaults to the repository's HEAD.
+func (f *Fetcher) Git(ctx context.Context, repo, ref, path string, a Auth) ([]byte, error) {
+	if err := f.allowed(repo); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, f.timeout)
+	defer cancel()
+
+	rev, err := revision(ctx, repo, ref, a)
+	if err != nil {
+		return nil, err
+	}
+	key := "git\x00" + repo + "\x00" + rev + "\x00" + strings.TrimPrefix(path, "/")
+	if fl, ok := f.get(key); ok && rev != "" {
+		return copyOf(fl.b), nil
+	}
+	b, err := Git(ctx, repo, ref, path, a)
+	if err != nil {
+		return nil, err
+	}
+	if rev != "" {
+		f.put(key, file{b: b})
+	}
+	return copyOf(b), nil
+}
+
+// allowed returns an error if files may not be fetched from the supplied URL.
+func (f *Fetcher) allowed(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return errors.Wrap(err, errParseURL)
+	}
+	if u.Scheme == "" {
+		return errors.New(errSchemeMissing)
+	}
+	if !contains(f.schemes, strings.ToLower(u.Scheme)) {
+		return errors.Errorf(errSchemeFmt, u.Scheme, strings.Join(f.schemes, ", "))
+	}
+	if len(f.hosts) == 0 {
+		return nil
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, h := range f.hosts {
+		h = strings.ToLower(h)
+		if h == host || (strings.HasPrefix(h, "*.") && strings.HasSuffix(host, h[1:])) {
+			return nil
+		}
+	}
+	return errors.Errorf(errHostFmt, host, strings.Join(f.hosts, ", "))
+}
+
+func (f *Fetcher) get(key string) (file, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	fl, ok := f.files[key]
+	return fl, ok
+}
+
+// put remembers the supplied file, forgetting an arbitrary other file if the
+// Fetcher remembers too many.
+func (f *Fetcher) put(key string, fl file) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.files[key]; !ok && len(f.files) >= maxFiles {
+		for k := range f.files {
+			delete(f.files, k)
+			break
+		}
+	}
+	f.files[key] = fl
+}
+
+func contains(ss []string, s string) bool {
+	for _, e := range ss {
+		if strings.EqualFold(e, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func copyOf(b []byte) []byte {
+	return append([]byte(nil), b...)
+}
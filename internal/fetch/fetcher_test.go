@@ -0,0 +1,183 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fetch
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestFetcherURL(t *testing.T) {
+	served := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		served++
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte("definition.session();"))
+	}))
+	defer srv.Close()
+
+	f := New(WithAllowedHosts("127.0.0.1"))
+	for i := 0; i < 2; i++ {
+		b, err := f.URL(context.Background(), srv.URL, Auth{})
+		if err != nil {
+			t.Fatalf("f.URL(...): unexpected error: %s", err)
+		}
+		if diff := cmp.Diff([]byte("definition.session();"), b); diff != "" {
+			t.Errorf("f.URL(...): -want, +got:\n%s\n", diff)
+		}
+	}
+	if served != 1 {
+		t.Errorf("f.URL(...): want an unmodified file to be served once, got %d times", served)
+	}
+}
+
+func TestFetcherAllowed(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		o      []Option
+		url    string
+		want   error
+	}{
+		"DefaultSchemes": {
+			reason: "HTTPS URLs should be allowed by default.",
+			url:    "https://example.org/checkout.js",
+		},
+		"SchemeNotAllowed": {
+			reason: "URLs with other schemes should not be allowed.",
+			url:    "file:///etc/passwd",
+			want:   errors.Errorf(errSchemeFmt, "file", "https, http"),
+		},
+		"NoScheme": {
+			reason: "URLs without a scheme should not be allowed.",
+			url:    "example.org/checkout.js",
+			want:   errors.New(errSchemeMissing),
+		},
+		"HostAllowed": {
+			reason: "URLs of an allowed host should be allowed.",
+			o:      []Option{WithAllowedHosts("example.org")},
+			url:    "https://EXAMPLE.org/checkout.js",
+		},
+		"SubdomainAllowed": {
+			reason: "URLs of a subdomain of an allowed wildcard host should be allowed.",
+			o:      []Option{WithAllowedHosts("*.example.org")},
+			url:    "https://git.example.org/tests.git",
+		},
+		"HostNotAllowed": {
+			reason: "URLs of other hosts should not be allowed.",
+			o:      []Option{WithAllowedHosts("*.example.org", "example.com")},
+			url:    "https://example.org.evil.com/checkout.js",
+			want:   errors.Errorf(errHostFmt, "example.org.evil.com", "*.example.org, example.com"),
+		},
+		"HTTPSOnly": {
+			reason: "URLs with a scheme that is no longer allowed should not be allowed.",
+			o:      []Option{WithAllowedSchemes("https")},
+			url:    "http://example.org/checkout.js",
+			want:   errors.Errorf(errSchemeFmt, "http", "https"),
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := New(tc.o...).allowed(tc.url)
+			if diff := cmp.Diff(tc.want, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nallowed(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestFetcherGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo, err := ioutil.TempDir("", "fetcher-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repo) //nolint:errcheck
+
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.org"}, args...)...)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %s", args, err, out)
+		}
+	}
+	commit := func(content string) {
+		if err := ioutil.WriteFile(filepath.Join(repo, "checkout.js"), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		run("add", ".")
+		run("commit", "--quiet", "-m", "Update checkout test")
+	}
+	run("init", "--quiet")
+	commit("definition.session();")
+
+	f := New(WithAllowedSchemes("file"))
+	fetch := func(want string) {
+		t.Helper()
+		b, err := f.Git(context.Background(), "file://"+repo, "", "checkout.js", Auth{Token: "secret"})
+		if err != nil {
+			t.Fatalf("f.Git(...): unexpected error: %s", err)
+		}
+		if diff := cmp.Diff([]byte(want), b); diff != "" {
+			t.Errorf("f.Git(...): -want, +got:\n%s\n", diff)
+		}
+	}
+
+	fetch("definition.session();")
+	if len(f.files) != 1 {
+		t.Errorf("f.Git(...): want the fetched file to be remembered, got %d files", len(f.files))
+	}
+	fetch("definition.session();")
+	if len(f.files) != 1 {
+		t.Errorf("f.Git(...): want an unchanged revision to be read from memory, got %d files", len(f.files))
+	}
+	commit("definition.session(); // v2")
+	fetch("definition.session(); // v2")
+	if len(f.files) != 2 {
+		t.Errorf("f.Git(...): want a new revision to be fetched, got %d files", len(f.files))
+	}
+}
+
+func TestGitEnv(t *testing.T) {
+	env := gitEnv(Auth{Token: "secret"})
+	want := []string{"GIT_CONFIG_COUNT=1", "GIT_CONFIG_KEY_0=http.extraHeader", "GIT_CONFIG_VALUE_0=Authorization: Bearer secret"}
+	if diff := cmp.Diff(want, env[len(env)-len(want):]); diff != "" {
+		t.Errorf("gitEnv(...): -want, +got:\n%s\n", diff)
+	}
+	for _, e := range gitEnv(Auth{}) {
+		if e == "GIT_CONFIG_COUNT=1" {
+			t.Errorf("gitEnv(...): want no header configured without credentials")
+		}
+	}
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package har converts HTTP Archive (HAR) recordings into StormForge test case
+// definitions.
+package har
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errParse          = "cannot parse HAR file"
+	errNoEntries      = "HAR file contains no requests"
+	errParseURLFmt    = "cannot parse URL of request %d"
+	errNotHTTPFmt     = "request %d is not an HTTP request: %s"
+	errUnsupportedFmt = "request %d uses unsupported method %s"
+)
+
+// Defaults of the converted definition. A HAR file records requests, not load,
+// so the arrival phase only exercises the recorded session.
+const (
+	DefaultSession  = "har"
+	DefaultDuration = 5 * 60
+	DefaultRate     = 1.0
+)
+
+// The session methods of a StormForge definition, by HTTP method.
+var methods = map[string]string{
+	"GET":     "get",
+	"POST":    "post",
+	"PUT":     "put",
+	"PATCH":   "patch",
+	"DELETE":  "delete",
+	"HEAD":    "head",
+	"OPTIONS": "options",
+}
+
+// An archive is the subset of a HAR file used for conversion.
+// http://www.softwareishard.com/blog/har-12-spec/
+type archive struct {
+	Log struct {
+		Entries []entry `json:"entries"`
+	} `json:"log"`
+}
+
+type entry struct {
+	Request request `json:"request"`
+}
+
+type request struct {
+	Method   string    `json:"method"`
+	URL      string    `json:"url"`
+	PostData *postData `json:"postData,omitempty"`
+}
+
+type postData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+// Convert the supplied HAR file into a StormForge test case definition. The
+// origin of the first request is the target of the definition; requests to
+// other origins use absolute URLs. The recorded requests form a single session
+// in the order they were recorded.
+func Convert(har []byte) ([]byte, error) {
+	a := &archive{}
+	if err := json.Unmarshal(har, a); err != nil {
+		return nil, errors.Wrap(err, errParse)
+	}
+	if len(a.Log.Entries) == 0 {
+		return nil, errors.New(errNoEntries)
+	}
+
+	b := &bytes.Buffer{}
+	var target string
+	for i, e := range a.Log.Entries {
+		u, err := url.Parse(e.Request.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, errParseURLFmt, i)
+		}
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return nil, errors.Errorf(errNotHTTPFmt, i, e.Request.URL)
+		}
+		m, ok := methods[strings.ToUpper(e.Request.Method)]
+		if !ok {
+			return nil, errors.Errorf(errUnsupportedFmt, i, e.Request.Method)
+		}
+
+		origin := u.Scheme + "://" + u.Host
+		if i == 0 {
+			target = origin
+		}
+		path := u.RequestURI()
+		if origin != target {
+			path = u.String()
+		}
+
+		fmt.Fprintf(b, "  session.%s(%s", m, literal(path))
+		if pd := e.Request.PostData; pd != nil && pd.Text != "" {
+			fmt.Fprintf(b, ", {\n    headers: { \"Content-Type\": %s },\n    payload: %s,\n  }", literal(pd.MimeType), literal(pd.Text))
+		}
+		b.WriteString(");\n")
+	}
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "definition.setTarget(%s);\n\n", literal(target))
+	fmt.Fprintf(out, "definition.setArrivalPhases([\n  {\n    duration: %d,\n    rate: %v,\n  },\n]);\n\n", DefaultDuration, DefaultRate)
+	fmt.Fprintf(out, "definition.session(%s, function (session) {\n", literal(DefaultSession))
+	out.Write(b.Bytes())
+	out.WriteString("});\n")
+	return out.Bytes(), nil
+}
+
+// literal returns the supplied string as a JavaScript string literal. JSON
+// strings are valid JavaScript string literals.
+func literal(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
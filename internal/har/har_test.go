@@ -0,0 +1,93 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package har
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestConvert(t *testing.T) {
+	type want struct {
+		definition string
+		err        error
+	}
+
+	cases := map[string]struct {
+		reason string
+		har    string
+		want   want
+	}{
+		"Session": {
+			reason: "Recorded requests should become a session against the origin of the first request.",
+			har: `{"log": {"entries": [
+				{"request": {"method": "GET", "url": "https://shop.example/?q=1"}},
+				{"request": {"method": "POST", "url": "https://shop.example/cart", "postData": {"mimeType": "application/json", "text": "{\"sku\":\"42\"}"}}},
+				{"request": {"method": "get", "url": "https://cdn.example/app.js"}}
+			]}}`,
+			want: want{definition: `definition.setTarget("https://shop.example");
+
+definition.setArrivalPhases([
+  {
+    duration: 300,
+    rate: 1,
+  },
+]);
+
+definition.session("har", function (session) {
+  session.get("/?q=1");
+  session.post("/cart", {
+    headers: { "Content-Type": "application/json" },
+    payload: "{\"sku\":\"42\"}",
+  });
+  session.get("https://cdn.example/app.js");
+});
+`},
+		},
+		"NoEntries": {
+			reason: "A HAR file without requests cannot be converted.",
+			har:    `{"log": {"entries": []}}`,
+			want:   want{err: errors.New(errNoEntries)},
+		},
+		"UnsupportedMethod": {
+			reason: "Requests with methods a definition cannot express should return an error.",
+			har:    `{"log": {"entries": [{"request": {"method": "CONNECT", "url": "https://shop.example/"}}]}}`,
+			want:   want{err: errors.Errorf(errUnsupportedFmt, 0, "CONNECT")},
+		},
+		"NotHTTP": {
+			reason: "Non-HTTP requests should return an error.",
+			har:    `{"log": {"entries": [{"request": {"method": "GET", "url": "wss://shop.example/live"}}]}}`,
+			want:   want{err: errors.Errorf(errNotHTTPFmt, 0, "wss://shop.example/live")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Convert([]byte(tc.har))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.definition, string(got)); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
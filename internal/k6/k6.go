@@ -0,0 +1,442 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package k6 translates a subset of k6 scripts into StormForge test case
+// definitions.
+//
+// k6 scripts are JavaScript programs, while StormForge definitions describe
+// load declaratively. Only scripts whose load can be described declaratively
+// are translated:
+//
+//   - Load is configured by a single constant-arrival-rate or
+//     ramping-arrival-rate scenario in the exported options. Virtual user
+//     based executors have no StormForge equivalent.
+//   - The default function issues HTTP requests to literal URLs using the
+//     k6/http module, optionally pausing between them using sleep.
+//   - Checks and thresholds are evaluated by k6 itself. StormForge reports the
+//     status and duration of every request instead, so they are kept as
+//     comments of the definition.
+//
+// Anything else is rejected with an error describing how to fix the script.
+package k6
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errNoDefault        = "script has no default function; export one issuing the requests of each iteration"
+	errNoScenario       = "script has no scenario; configure load with a constant-arrival-rate or ramping-arrival-rate scenario in options.scenarios"
+	errManyScenarios    = "script has more than one scenario; StormForge test cases run a single session, so split them into separate test cases"
+	errVUOptionFmt      = "options.%s configures virtual users, which StormForge does not use; configure load with a constant-arrival-rate or ramping-arrival-rate scenario instead"
+	errExecutorFmt      = "scenario %q uses the %s executor; only the constant-arrival-rate and ramping-arrival-rate executors can be translated"
+	errScenarioExecFmt  = "scenario %q executes function %q; only the default function can be translated"
+	errUnsupportedFmt   = "%s is not supported: %s"
+	errParseOptions     = "cannot parse options"
+	errDurationFmt      = "cannot parse duration %q"
+	errRequestFmt       = "cannot translate request %d"
+	errNoRequests       = "default function issues no HTTP requests"
+	errRequestURLFmt    = "URL %q must be an absolute http or https URL"
+	errRequestPayload   = "request body must be a string literal or JSON.stringify of an object literal"
+	errSleepFmt         = "sleep(%s) must sleep for a literal number of seconds"
+	errRequestMethodFmt = "http.request method %q is not supported"
+)
+
+const (
+	executorConstantRate = "constant-arrival-rate"
+	executorRampingRate  = "ramping-arrival-rate"
+
+	defaultTimeUnit = "1s"
+	defaultSession  = "k6"
+)
+
+// Constructs that cannot be translated, and how to work around them.
+var unsupported = []struct {
+	pattern *regexp.Regexp
+	what    string
+	fix     string
+}{
+	{regexp.MustCompile(`from\s+['"]k6/ws['"]`), "k6/ws", "StormForge test cases only issue HTTP requests"},
+	{regexp.MustCompile(`from\s+['"]k6/net/grpc['"]`), "k6/net/grpc", "StormForge test cases only issue HTTP requests"},
+	{regexp.MustCompile(`from\s+['"]k6/(experimental/)?browser['"]`), "k6/browser", "StormForge test cases only issue HTTP requests"},
+	{regexp.MustCompile(`export\s+function\s+setup\b`), "the setup function", "move its requests into the default function"},
+	{regexp.MustCompile(`export\s+function\s+teardown\b`), "the teardown function", "remove it"},
+	{regexp.MustCompile(`\bhttp\.batch\s*\(`), "http.batch", "issue the requests one by one"},
+	{regexp.MustCompile(`\b(for|while)\s*\(`), "looping", "issue each request explicitly"},
+	{regexp.MustCompile(`\bif\s*\(`), "branching", "issue each request explicitly"},
+}
+
+var (
+	reOptions = regexp.MustCompile(`export\s+(const|let|var)\s+options\s*=\s*\{`)
+	reDefault = regexp.MustCompile(`export\s+default\s+function\s*\w*\s*\([^)]*\)\s*\{`)
+	reCall    = regexp.MustCompile(`\b(http\.(get|post|put|patch|del|head|options|request)|sleep|check)\s*\(`)
+)
+
+// The session methods of a StormForge definition, by k6 http function.
+var methods = map[string]string{
+	"get":     "get",
+	"post":    "post",
+	"put":     "put",
+	"patch":   "patch",
+	"del":     "delete",
+	"head":    "head",
+	"options": "options",
+}
+
+type options struct {
+	VUs        *json.RawMessage           `json:"vus,omitempty"`
+	Duration   *json.RawMessage           `json:"duration,omitempty"`
+	Iterations *json.RawMessage           `json:"iterations,omitempty"`
+	Stages     *json.RawMessage           `json:"stages,omitempty"`
+	Scenarios  map[string]scenario        `json:"scenarios,omitempty"`
+	Thresholds map[string]json.RawMessage `json:"thresholds,omitempty"`
+}
+
+type scenario struct {
+	Executor  string  `json:"executor"`
+	Exec      string  `json:"exec,omitempty"`
+	Rate      float64 `json:"rate,omitempty"`
+	StartRate float64 `json:"startRate,omitempty"`
+	TimeUnit  string  `json:"timeUnit,omitempty"`
+	Duration  string  `json:"duration,omitempty"`
+	Stages    []stage `json:"stages,omitempty"`
+}
+
+type stage struct {
+	Duration string  `json:"duration"`
+	Target   float64 `json:"target"`
+}
+
+// A phase of arrivals of a StormForge definition.
+type phase struct {
+	Duration   int
+	Rate       float64
+	TargetRate *float64
+}
+
+// Convert the supplied k6 script into a StormForge test case definition.
+func Convert(script []byte) ([]byte, error) {
+	src := string(script)
+	for _, u := range unsupported {
+		if u.pattern.MatchString(src) {
+			return nil, errors.Errorf(errUnsupportedFmt, u.what, u.fix)
+		}
+	}
+
+	o, err := parseOptions(src)
+	if err != nil {
+		return nil, err
+	}
+	phases, err := arrivals(o)
+	if err != nil {
+		return nil, err
+	}
+
+	loc := reDefault.FindStringIndex(src)
+	if loc == nil {
+		return nil, errors.New(errNoDefault)
+	}
+	body, err := balanced(src, loc[1]-1)
+	if err != nil {
+		return nil, err
+	}
+	target, steps, checks, err := session(body)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &bytes.Buffer{}
+	out.WriteString("// Translated from a k6 script.\n")
+	if len(checks) > 0 || len(o.Thresholds) > 0 {
+		out.WriteString("//\n// The following are evaluated by k6 only and are not enforced by StormForge:\n")
+		for _, c := range checks {
+			fmt.Fprintf(out, "//   check: %s\n", c)
+		}
+		for _, name := range sortedKeys(o.Thresholds) {
+			fmt.Fprintf(out, "//   threshold: %s: %s\n", name, string(o.Thresholds[name]))
+		}
+	}
+	fmt.Fprintf(out, "\ndefinition.setTarget(%s);\n\n", literal(target))
+	out.WriteString("definition.setArrivalPhases([\n")
+	for _, p := range phases {
+		fmt.Fprintf(out, "  {\n    duration: %d,\n    rate: %v,\n", p.Duration, p.Rate)
+		if p.TargetRate != nil {
+			fmt.Fprintf(out, "    targetRate: %v,\n", *p.TargetRate)
+		}
+		out.WriteString("  },\n")
+	}
+	out.WriteString("]);\n\n")
+	fmt.Fprintf(out, "definition.session(%s, function (session) {\n", literal(defaultSession))
+	out.WriteString(steps)
+	out.WriteString("});\n")
+	return out.Bytes(), nil
+}
+
+func parseOptions(src string) (*options, error) {
+	o := &options{}
+	loc := reOptions.FindStringIndex(src)
+	if loc == nil {
+		return o, nil
+	}
+	obj, err := balanced(src, loc[1]-1)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseOptions)
+	}
+	j, err := toJSON(obj)
+	if err != nil {
+		return nil, errors.Wrap(err, errParseOptions)
+	}
+	return o, errors.Wrap(json.Unmarshal(j, o), errParseOptions)
+}
+
+// arrivals returns the arrival phases of the supplied options.
+func arrivals(o *options) ([]phase, error) {
+	for _, vu := range []struct {
+		name string
+		set  bool
+	}{{"vus", o.VUs != nil}, {"duration", o.Duration != nil}, {"iterations", o.Iterations != nil}, {"stages", o.Stages != nil}} {
+		if vu.set {
+			return nil, errors.Errorf(errVUOptionFmt, vu.name)
+		}
+	}
+	switch len(o.Scenarios) {
+	case 0:
+		return nil, errors.New(errNoScenario)
+	case 1:
+	default:
+		return nil, errors.New(errManyScenarios)
+	}
+
+	for name, s := range o.Scenarios {
+		if s.Exec != "" && s.Exec != "default" {
+			return nil, errors.Errorf(errScenarioExecFmt, name, s.Exec)
+		}
+		if s.TimeUnit == "" {
+			s.TimeUnit = defaultTimeUnit
+		}
+		unit, err := seconds(s.TimeUnit)
+		if err != nil {
+			return nil, err
+		}
+		switch s.Executor {
+		case executorConstantRate:
+			d, err := seconds(s.Duration)
+			if err != nil {
+				return nil, err
+			}
+			return []phase{{Duration: int(d), Rate: s.Rate / unit}}, nil
+		case executorRampingRate:
+			phases := make([]phase, 0, len(s.Stages))
+			rate := s.StartRate / unit
+			for _, st := range s.Stages {
+				d, err := seconds(st.Duration)
+				if err != nil {
+					return nil, err
+				}
+				target := st.Target / unit
+				p := phase{Duration: int(d), Rate: rate}
+				if target != rate {
+					p.TargetRate = &target
+				}
+				phases = append(phases, p)
+				rate = target
+			}
+			return phases, nil
+		default:
+			return nil, errors.Errorf(errExecutorFmt, name, s.Executor)
+		}
+	}
+	return nil, errors.New(errNoScenario)
+}
+
+// session returns the target, session steps and checks of the supplied
+// default function body.
+func session(body string) (string, string, []string, error) {
+	b := &strings.Builder{}
+	checks := []string{}
+	target := ""
+	requests := 0
+	for _, m := range reCall.FindAllStringSubmatchIndex(body, -1) {
+		call := body[m[2]:m[3]]
+		args, err := balanced(body, m[1]-1)
+		if err != nil {
+			return "", "", nil, err
+		}
+		inner := strings.TrimSpace(args[1 : len(args)-1])
+		switch {
+		case call == "check":
+			checks = append(checks, strings.Join(strings.Fields(inner), " "))
+		case call == "sleep":
+			var secs float64
+			if err := json.Unmarshal([]byte(inner), &secs); err != nil {
+				return "", "", nil, errors.Errorf(errSleepFmt, inner)
+			}
+			fmt.Fprintf(b, "  session.wait(%v);\n", secs)
+		default:
+			method := body[m[4]:m[5]]
+			t, step, err := request(method, splitArgs(inner), target)
+			if err != nil {
+				return "", "", nil, errors.Wrapf(err, errRequestFmt, requests)
+			}
+			target = t
+			requests++
+			b.WriteString(step)
+		}
+	}
+	if requests == 0 {
+		return "", "", nil, errors.New(errNoRequests)
+	}
+	return target, b.String(), checks, nil
+}
+
+// request returns the target and session step of a k6 http call with the
+// supplied arguments. The target is the origin of the first request.
+func request(method string, args []string, target string) (string, string, error) {
+	if method == "request" {
+		if len(args) == 0 {
+			return "", "", errors.Errorf(errRequestMethodFmt, "")
+		}
+		m, err := unquote(args[0])
+		if err != nil {
+			return "", "", err
+		}
+		method = strings.ToLower(m)
+		if method == "delete" {
+			method = "del"
+		}
+		if _, ok := methods[method]; !ok {
+			return "", "", errors.Errorf(errRequestMethodFmt, m)
+		}
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return "", "", errors.Errorf(errRequestURLFmt, "")
+	}
+	raw, err := unquote(args[0])
+	if err != nil {
+		return "", "", err
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", "", errors.Errorf(errRequestURLFmt, raw)
+	}
+	origin := u.Scheme + "://" + u.Host
+	if target == "" {
+		target = origin
+	}
+	path := u.RequestURI()
+	if origin != target {
+		path = u.String()
+	}
+
+	step := fmt.Sprintf("  session.%s(%s", methods[method], literal(path))
+	// Only methods that take a body accept a payload argument.
+	if len(args) > 1 && method != "get" && method != "head" && method != "options" {
+		payload, ct, err := body(args[1])
+		if err != nil {
+			return "", "", err
+		}
+		if payload != nil {
+			step += fmt.Sprintf(", {\n    headers: { \"Content-Type\": %s },\n    payload: %s,\n  }", literal(ct), literal(*payload))
+		}
+	}
+	return target, step + ");\n", nil
+}
+
+// body returns the payload and content type of the supplied request body
+// argument.
+func body(arg string) (*string, string, error) {
+	switch {
+	case arg == "null" || arg == "undefined":
+		return nil, "", nil
+	case strings.HasPrefix(arg, "JSON.stringify("):
+		inner := strings.TrimSuffix(strings.TrimPrefix(arg, "JSON.stringify("), ")")
+		j, err := toJSON(inner)
+		if err != nil {
+			return nil, "", errors.Wrap(err, errRequestPayload)
+		}
+		c := &bytes.Buffer{}
+		if err := json.Compact(c, j); err != nil {
+			return nil, "", errors.Wrap(err, errRequestPayload)
+		}
+		s := c.String()
+		return &s, "application/json", nil
+	}
+	s, err := unquote(arg)
+	if err != nil {
+		return nil, "", errors.Wrap(err, errRequestPayload)
+	}
+	return &s, "text/plain", nil
+}
+
+// splitArgs splits the supplied call arguments at top level commas.
+func splitArgs(args string) []string {
+	out := []string{}
+	start := 0
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case '"', '\'', '`':
+			if end, err := skipString(args, i); err == nil {
+				i = end - 1
+			}
+		case '{', '[', '(':
+			if s, err := balanced(args, i); err == nil {
+				i += len(s) - 1
+			}
+		case ',':
+			out = append(out, strings.TrimSpace(args[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(args[start:]); last != "" {
+		out = append(out, last)
+	}
+	return out
+}
+
+// seconds parses a k6 duration such as 30s or 1m30s.
+func seconds(d string) (float64, error) {
+	td, err := time.ParseDuration(d)
+	if err != nil {
+		return 0, errors.Errorf(errDurationFmt, d)
+	}
+	return td.Seconds(), nil
+}
+
+// literal returns the supplied string as a JavaScript string literal. JSON
+// strings are valid JavaScript string literals.
+func literal(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func sortedKeys(m map[string]json.RawMessage) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
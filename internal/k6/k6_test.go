@@ -0,0 +1,165 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k6
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+)
+
+func TestConvert(t *testing.T) {
+	type want struct {
+		definition string
+		err        error
+	}
+
+	cases := map[string]struct {
+		reason string
+		script string
+		want   want
+	}{
+		"RampingArrivalRate": {
+			reason: "Arrival rate scenarios, requests and sleeps should be translated, and checks and thresholds kept as comments.",
+			script: `
+import http from 'k6/http';
+import { check, sleep } from 'k6';
+
+export const options = {
+  scenarios: {
+    shop: {
+      executor: 'ramping-arrival-rate',
+      startRate: 60,
+      timeUnit: '1m',
+      stages: [
+        { target: 120, duration: '2m' }, // ramp up
+        { target: 120, duration: '5m' },
+      ],
+    },
+  },
+  thresholds: {
+    http_req_duration: ['p(95)<500'],
+  },
+};
+
+export default function () {
+  const res = http.get("https://shop.example/");
+  check(res, { 'is 200': (r) => r.status === 200 });
+  sleep(1.5);
+  http.post('https://shop.example/cart', JSON.stringify({ sku: '42', qty: 1 }), { headers: { 'Content-Type': 'application/json' } });
+  http.del(` + "`https://cdn.example/cache`" + `);
+}
+`,
+			want: want{definition: `// Translated from a k6 script.
+//
+// The following are evaluated by k6 only and are not enforced by StormForge:
+//   check: res, { 'is 200': (r) => r.status === 200 }
+//   threshold: http_req_duration: ["p(95)<500"]
+
+definition.setTarget("https://shop.example");
+
+definition.setArrivalPhases([
+  {
+    duration: 120,
+    rate: 1,
+    targetRate: 2,
+  },
+  {
+    duration: 300,
+    rate: 2,
+  },
+]);
+
+definition.session("k6", function (session) {
+  session.get("/");
+  session.wait(1.5);
+  session.post("/cart", {
+    headers: { "Content-Type": "application/json" },
+    payload: "{\"sku\":\"42\",\"qty\":1}",
+  });
+  session.delete("https://cdn.example/cache");
+});
+`},
+		},
+		"ConstantArrivalRate": {
+			reason: "A constant arrival rate scenario should become a single phase.",
+			script: `
+import http from 'k6/http';
+export const options = { scenarios: { c: { executor: 'constant-arrival-rate', rate: 10, duration: '1m' } } };
+export default function () { http.request('GET', 'https://shop.example/health'); }
+`,
+			want: want{definition: `// Translated from a k6 script.
+
+definition.setTarget("https://shop.example");
+
+definition.setArrivalPhases([
+  {
+    duration: 60,
+    rate: 10,
+  },
+]);
+
+definition.session("k6", function (session) {
+  session.get("/health");
+});
+`},
+		},
+		"VirtualUsers": {
+			reason: "Virtual user options should be rejected with a hint to use an arrival rate scenario.",
+			script: `export const options = { vus: 10, duration: '30s' };
+export default function () { http.get('https://shop.example/'); }`,
+			want: want{err: errors.Errorf(errVUOptionFmt, "vus")},
+		},
+		"VirtualUserExecutor": {
+			reason: "Virtual user executors should be rejected.",
+			script: `export const options = { scenarios: { s: { executor: 'constant-vus', vus: 10, duration: '30s' } } };
+export default function () { http.get('https://shop.example/'); }`,
+			want: want{err: errors.Errorf(errExecutorFmt, "s", "constant-vus")},
+		},
+		"NoScenario": {
+			reason: "Scripts without a scenario should be rejected.",
+			script: `export default function () { http.get('https://shop.example/'); }`,
+			want:   want{err: errors.New(errNoScenario)},
+		},
+		"WebSockets": {
+			reason: "Non-HTTP protocols should be rejected.",
+			script: `import ws from 'k6/ws';`,
+			want:   want{err: errors.Errorf(errUnsupportedFmt, "k6/ws", "StormForge test cases only issue HTTP requests")},
+		},
+		"DynamicURL": {
+			reason: "Requests to URLs that are not literals should be rejected.",
+			script: `export const options = { scenarios: { c: { executor: 'constant-arrival-rate', rate: 1, duration: '1m' } } };
+export default function () { http.get(` + "`${__ENV.BASE}/`" + `); }`,
+			want: want{err: errors.Wrapf(errors.Errorf(errNotLiteralFmt, "`${__ENV.BASE}/`"), errRequestFmt, 0)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Convert([]byte(tc.script))
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.definition, string(got)); diff != "" {
+				t.Errorf("\n%s\nConvert(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package k6
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	errUnterminatedFmt = "unterminated %s"
+	errNotLiteralFmt   = "%q is not a literal; only literal values can be translated"
+)
+
+// balanced returns the text of src starting at the open bracket at index i up
+// to and including its matching close bracket. Brackets within strings and
+// comments are ignored.
+func balanced(src string, i int) (string, error) {
+	open, close := src[i], map[byte]byte{'{': '}', '[': ']', '(': ')'}[src[i]]
+	depth := 0
+	for j := i; j < len(src); j++ {
+		switch c := src[j]; {
+		case c == '"' || c == '\'' || c == '`':
+			end, err := skipString(src, j)
+			if err != nil {
+				return "", err
+			}
+			j = end - 1
+		case c == '/' && j+1 < len(src) && (src[j+1] == '/' || src[j+1] == '*'):
+			j = skipComment(src, j) - 1
+		case c == open:
+			depth++
+		case c == close:
+			depth--
+			if depth == 0 {
+				return src[i : j+1], nil
+			}
+		}
+	}
+	return "", errors.Errorf(errUnterminatedFmt, string(open))
+}
+
+// skipString returns the index following the string literal starting at i.
+func skipString(src string, i int) (int, error) {
+	q := src[i]
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case q:
+			return j + 1, nil
+		}
+	}
+	return 0, errors.Errorf(errUnterminatedFmt, "string")
+}
+
+// skipComment returns the index following the comment starting at i.
+func skipComment(src string, i int) int {
+	if src[i+1] == '/' {
+		if end := strings.IndexByte(src[i:], '\n'); end >= 0 {
+			return i + end
+		}
+		return len(src)
+	}
+	if end := strings.Index(src[i+2:], "*/"); end >= 0 {
+		return i + 2 + end + 2
+	}
+	return len(src)
+}
+
+// unquote returns the value of the supplied JavaScript string literal. Template
+// literals are only supported if they contain no substitutions.
+func unquote(lit string) (string, error) {
+	if len(lit) < 2 || lit[0] != lit[len(lit)-1] {
+		return "", errors.Errorf(errNotLiteralFmt, lit)
+	}
+	body := lit[1 : len(lit)-1]
+	switch lit[0] {
+	case '"':
+		s := ""
+		err := json.Unmarshal([]byte(lit), &s)
+		return s, errors.Wrapf(err, errNotLiteralFmt, lit)
+	case '\'':
+		body = strings.ReplaceAll(body, `\'`, `'`)
+		body = strings.ReplaceAll(body, `"`, `\"`)
+		s := ""
+		err := json.Unmarshal([]byte(`"`+body+`"`), &s)
+		return s, errors.Wrapf(err, errNotLiteralFmt, lit)
+	case '`':
+		if strings.Contains(body, "${") {
+			return "", errors.Errorf(errNotLiteralFmt, lit)
+		}
+		return body, nil
+	}
+	return "", errors.Errorf(errNotLiteralFmt, lit)
+}
+
+// toJSON converts the supplied JavaScript object or array literal into JSON.
+// Unquoted keys, single quoted strings, comments and trailing commas are
+// supported; expressions are not.
+func toJSON(src string) ([]byte, error) {
+	out := &strings.Builder{}
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '/' && i+1 < len(src) && (src[i+1] == '/' || src[i+1] == '*'):
+			i = skipComment(src, i)
+		case c == '"' || c == '\'' || c == '`':
+			end, err := skipString(src, i)
+			if err != nil {
+				return nil, err
+			}
+			s, err := unquote(src[i:end])
+			if err != nil {
+				return nil, err
+			}
+			quote(out, s)
+			i = end
+		case c == ',':
+			// Drop trailing commas, which JSON does not allow.
+			j := i + 1
+			for j < len(src) && strings.IndexByte(" \t\r\n", src[j]) >= 0 {
+				j++
+			}
+			if j < len(src) && (src[j] == '}' || src[j] == ']') {
+				i = j
+				continue
+			}
+			out.WriteByte(c)
+			i++
+		case strings.IndexByte("{}[]:", c) >= 0:
+			out.WriteByte(c)
+			i++
+		default:
+			j := i
+			for j < len(src) && strings.IndexByte(" \t\r\n,:{}[]", src[j]) < 0 {
+				j++
+			}
+			word := src[i:j]
+			k := j
+			for k < len(src) && strings.IndexByte(" \t\r\n", src[k]) >= 0 {
+				k++
+			}
+			switch {
+			case k < len(src) && src[k] == ':':
+				quote(out, word)
+			case json.Valid([]byte(word)):
+				out.WriteString(word)
+			default:
+				return nil, errors.Errorf(errNotLiteralFmt, word)
+			}
+			i = j
+		}
+	}
+	return []byte(out.String()), nil
+}
+
+// quote writes the supplied string as a JSON string without escaping HTML, so
+// that thresholds such as p(95)<500 remain readable.
+func quote(out *strings.Builder, s string) {
+	b := &bytes.Buffer{}
+	e := json.NewEncoder(b)
+	e.SetEscapeHTML(false)
+	_ = e.Encode(s)
+	// Encode terminates each value with a newline.
+	out.Write(bytes.TrimSuffix(b.Bytes(), []byte("\n")))
+}
@@ -0,0 +1,110 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package pause lets reconciliation of managed resources be paused using the
+// crossplane.io/paused annotation.
+package pause
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/reconciler/managed"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+
+	"github.com/luebken/provider-stormforge/internal/poll"
+)
+
+// AnnotationKey may be set to "true" on a managed resource to pause its
+// reconciliation. Its external resource is neither observed nor changed, and
+// the managed resource is not finalized if it is deleted, until the annotation
+// is removed or set to another value.
+const AnnotationKey = "crossplane.io/paused"
+
+// ReasonPaused is the reason of the Synced condition of a paused managed
+// resource.
+const ReasonPaused xpv1.ConditionReason = "ReconcilePaused"
+
+const (
+	errGetManaged   = "cannot get managed resource"
+	errUpdateStatus = "cannot update status of paused managed resource"
+)
+
+// IsPaused returns true if reconciliation of the supplied object is paused.
+func IsPaused(o metav1.Object) bool {
+	return o.GetAnnotations()[AnnotationKey] == "true"
+}
+
+// Paused returns a condition indicating that reconciliation is paused.
+func Paused() xpv1.Condition {
+	return xpv1.Condition{
+		Type:               xpv1.TypeSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ReasonPaused,
+		Message:            "Reconciliation is paused by the " + AnnotationKey + " annotation",
+	}
+}
+
+// A Reconciler skips paused managed resources, setting their Synced condition
+// to explain why, and reconciles all others using the wrapped reconciler. The
+// external resources of those it reconciles are polled at the interval set by
+// their poll.AnnotationKey annotation, if any.
+type Reconciler struct {
+	kube       client.Client
+	newManaged func() resource.Managed
+	wrapped    reconcile.Reconciler
+}
+
+// NewReconciler returns a Reconciler that reconciles managed resources of the
+// supplied kind that are not paused using a managed reconciler configured by
+// the supplied options.
+func NewReconciler(m manager.Manager, of resource.ManagedKind, o ...managed.ReconcilerOption) *Reconciler {
+	return &Reconciler{
+		kube: m.GetClient(),
+		newManaged: func() resource.Managed {
+			return resource.MustCreateObject(schema.GroupVersionKind(of), m.GetScheme()).(resource.Managed)
+		},
+		wrapped: managed.NewReconciler(m, of, o...),
+	}
+}
+
+// Reconcile the managed resource of the supplied request, unless it is paused.
+func (r *Reconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	mg := r.newManaged()
+	if err := r.kube.Get(ctx, req.NamespacedName, mg); err != nil {
+		// There is nothing to reconcile if the managed resource no longer
+		// exists.
+		return reconcile.Result{}, errors.Wrap(resource.IgnoreNotFound(err), errGetManaged)
+	}
+	if !IsPaused(mg) {
+		res, err := r.wrapped.Reconcile(ctx, req)
+		return poll.Requeue(mg, res), err
+	}
+	if c := mg.GetCondition(xpv1.TypeSynced); c.Reason == ReasonPaused {
+		return reconcile.Result{}, nil
+	}
+	mg.SetConditions(Paused())
+	return reconcile.Result{}, errors.Wrap(r.kube.Status().Update(ctx, mg), errUpdateStatus)
+}
@@ -0,0 +1,131 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pause
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/resource"
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/internal/poll"
+)
+
+func TestReconcile(t *testing.T) {
+	errBoom := errors.New("boom")
+	paused := map[string]string{AnnotationKey: "true"}
+
+	type want struct {
+		reconciled bool
+		result     reconcile.Result
+		conditions []xpv1.Condition
+		err        error
+	}
+
+	cases := map[string]struct {
+		reason      string
+		get         error
+		annotations map[string]string
+		conditions  []xpv1.Condition
+		want        want
+	}{
+		"NotPaused": {
+			reason: "A managed resource that is not paused should be reconciled.",
+			want:   want{reconciled: true, result: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"PollInterval": {
+			reason:      "A managed resource that is not paused should be polled at the interval set by its annotation.",
+			annotations: map[string]string{poll.AnnotationKey: "24h"},
+			want:        want{reconciled: true, result: reconcile.Result{RequeueAfter: 24 * time.Hour}},
+		},
+		"PausedFalse": {
+			reason:      "A managed resource whose pause annotation is not true should be reconciled.",
+			annotations: map[string]string{AnnotationKey: "false"},
+			want:        want{reconciled: true, result: reconcile.Result{RequeueAfter: time.Minute}},
+		},
+		"Paused": {
+			reason:      "A paused managed resource should not be reconciled, and its Synced condition should explain why.",
+			annotations: paused,
+			want:        want{conditions: []xpv1.Condition{Paused()}},
+		},
+		"AlreadyPaused": {
+			reason:      "The status of a managed resource that is already reported as paused should not be updated again.",
+			annotations: paused,
+			conditions:  []xpv1.Condition{Paused()},
+		},
+		"NotFound": {
+			reason: "There is nothing to reconcile if the managed resource no longer exists.",
+			get:    kerrors.NewNotFound(schema.GroupResource{}, "cool"),
+		},
+		"GetError": {
+			reason: "Errors getting the managed resource should be wrapped.",
+			get:    errBoom,
+			want:   want{err: errors.Wrap(errBoom, errGetManaged)},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var updated []xpv1.Condition
+			reconciled := false
+			r := &Reconciler{
+				kube: &test.MockClient{
+					MockGet: test.NewMockGetFn(tc.get, func(obj client.Object) error {
+						mg := obj.(*fake.Managed)
+						mg.SetAnnotations(tc.annotations)
+						mg.SetConditions(tc.conditions...)
+						return nil
+					}),
+					MockStatusUpdate: func(_ context.Context, obj client.Object, _ ...client.UpdateOption) error {
+						updated = obj.(*fake.Managed).Conditions
+						return nil
+					},
+				},
+				newManaged: func() resource.Managed { return &fake.Managed{} },
+				wrapped: reconcile.Func(func(_ context.Context, _ reconcile.Request) (reconcile.Result, error) {
+					reconciled = true
+					return reconcile.Result{RequeueAfter: time.Minute}, nil
+				}),
+			}
+			got, err := r.Reconcile(context.Background(), reconcile.Request{})
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.result, got); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want result, +got result:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.reconciled, reconciled); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want reconciled, +got reconciled:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.conditions, updated, cmpopts.IgnoreFields(xpv1.Condition{}, "LastTransitionTime")); diff != "" {
+				t.Errorf("\n%s\nr.Reconcile(...): -want conditions, +got conditions:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
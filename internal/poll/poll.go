@@ -0,0 +1,68 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package poll lets the interval at which a managed resource's external
+// resource is polled be set per resource using the
+// stormforge.crossplane.io/poll-interval annotation.
+package poll
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// AnnotationKey may be set on a managed resource to a duration, such as 30s
+// or 24h, to poll its external resource at that interval instead of the
+// interval of its controller.
+const AnnotationKey = "stormforge.crossplane.io/poll-interval"
+
+// MinInterval is the shortest interval a managed resource may be polled at, so
+// that a single resource cannot exhaust the rate limit of the StormForge API.
+const MinInterval = 10 * time.Second
+
+// Interval returns the poll interval set by the annotation of the supplied
+// object. It returns false if the annotation is unset or is not a positive
+// duration. Intervals shorter than MinInterval are raised to MinInterval.
+func Interval(o metav1.Object) (time.Duration, bool) {
+	v, ok := o.GetAnnotations()[AnnotationKey]
+	if !ok {
+		return 0, false
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d <= 0 {
+		return 0, false
+	}
+	if d < MinInterval {
+		d = MinInterval
+	}
+	return d, true
+}
+
+// Requeue returns the supplied result of reconciling the supplied object with
+// the poll interval set by its annotation, if any. Only results that requeue
+// after an interval are changed; they are returned when the external resource
+// has been observed successfully.
+func Requeue(o metav1.Object, r reconcile.Result) reconcile.Result {
+	if r.RequeueAfter <= 0 {
+		return r
+	}
+	if d, ok := Interval(o); ok {
+		r.RequeueAfter = d
+	}
+	return r
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package poll
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/crossplane/crossplane-runtime/pkg/resource/fake"
+)
+
+func TestRequeue(t *testing.T) {
+	cases := map[string]struct {
+		reason      string
+		annotations map[string]string
+		r           reconcile.Result
+		want        reconcile.Result
+	}{
+		"NoAnnotation": {
+			reason: "A result should be unchanged if the poll interval annotation is not set.",
+			r:      reconcile.Result{RequeueAfter: time.Minute},
+			want:   reconcile.Result{RequeueAfter: time.Minute},
+		},
+		"Annotation": {
+			reason:      "A result that requeues after the poll interval should use the annotated interval.",
+			annotations: map[string]string{AnnotationKey: "24h"},
+			r:           reconcile.Result{RequeueAfter: time.Minute},
+			want:        reconcile.Result{RequeueAfter: 24 * time.Hour},
+		},
+		"TooShort": {
+			reason:      "An annotated interval shorter than the minimum should be raised to the minimum.",
+			annotations: map[string]string{AnnotationKey: "1s"},
+			r:           reconcile.Result{RequeueAfter: time.Minute},
+			want:        reconcile.Result{RequeueAfter: MinInterval},
+		},
+		"Invalid": {
+			reason:      "An annotation that is not a duration should be ignored.",
+			annotations: map[string]string{AnnotationKey: "daily"},
+			r:           reconcile.Result{RequeueAfter: time.Minute},
+			want:        reconcile.Result{RequeueAfter: time.Minute},
+		},
+		"Negative": {
+			reason:      "An annotation that is not a positive duration should be ignored.",
+			annotations: map[string]string{AnnotationKey: "-5m"},
+			r:           reconcile.Result{RequeueAfter: time.Minute},
+			want:        reconcile.Result{RequeueAfter: time.Minute},
+		},
+		"Requeue": {
+			reason:      "A result that requeues immediately, for example after a create, should be unchanged.",
+			annotations: map[string]string{AnnotationKey: "24h"},
+			r:           reconcile.Result{Requeue: true},
+			want:        reconcile.Result{Requeue: true},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			mg := &fake.Managed{}
+			mg.SetAnnotations(tc.annotations)
+			got := Requeue(mg, tc.r)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nRequeue(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package runphase simplifies the states of StormForge test runs into phases,
+// and their summaries into results.
+package runphase
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+// Of returns the phase of a run in the supplied StormForge state.
+func Of(state string) v1alpha1.TestRunPhase {
+	switch state {
+	case "created", "queued", "launching", "preparing", "starting":
+		return v1alpha1.TestRunPending
+	case "running", "finishing":
+		return v1alpha1.TestRunRunning
+	case "done", "finished":
+		return v1alpha1.TestRunSucceeded
+	case "failed", "error":
+		return v1alpha1.TestRunFailed
+	case "aborting", "aborted":
+		return v1alpha1.TestRunAborted
+	default:
+		return v1alpha1.TestRunUnknown
+	}
+}
+
+// DefaultResultMetrics are the metrics of a run's result that are reported
+// unless others are selected.
+var DefaultResultMetrics = []v1alpha1.ResultMetric{
+	v1alpha1.ResultMetricRequests,
+	v1alpha1.ResultMetricErrors,
+	v1alpha1.ResultMetricLatencyP95,
+}
+
+// Result returns the supplied summary of a run's results, if any, including
+// every metric.
+func Result(s *stormforge.RunSummary) *v1alpha1.TestRunResult {
+	if s == nil {
+		return nil
+	}
+	requests, errors := s.Requests, s.Errors
+	return &v1alpha1.TestRunResult{
+		Requests:   &requests,
+		Errors:     &errors,
+		LatencyP50: &metav1.Duration{Duration: s.LatencyP50},
+		LatencyP95: &metav1.Duration{Duration: s.LatencyP95},
+		LatencyP99: &metav1.Duration{Duration: s.LatencyP99},
+	}
+}
+
+// Select returns a copy of the supplied result, if any, with only the
+// supplied metrics. The DefaultResultMetrics are selected if none are
+// supplied.
+func Select(r *v1alpha1.TestRunResult, metrics []v1alpha1.ResultMetric) *v1alpha1.TestRunResult {
+	if r == nil {
+		return nil
+	}
+	if len(metrics) == 0 {
+		metrics = DefaultResultMetrics
+	}
+	out := &v1alpha1.TestRunResult{}
+	for _, m := range metrics {
+		switch m {
+		case v1alpha1.ResultMetricRequests:
+			out.Requests = r.Requests
+		case v1alpha1.ResultMetricErrors:
+			out.Errors = r.Errors
+		case v1alpha1.ResultMetricLatencyP50:
+			out.LatencyP50 = r.LatencyP50
+		case v1alpha1.ResultMetricLatencyP95:
+			out.LatencyP95 = r.LatencyP95
+		case v1alpha1.ResultMetricLatencyP99:
+			out.LatencyP99 = r.LatencyP99
+		}
+	}
+	return out.DeepCopy()
+}
+
+// Active returns true if a run in the supplied phase may still generate load.
+func Active(p v1alpha1.TestRunPhase) bool {
+	return p == v1alpha1.TestRunPending || p == v1alpha1.TestRunRunning || p == v1alpha1.TestRunUnknown
+}
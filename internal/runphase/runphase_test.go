@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runphase
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+func TestOf(t *testing.T) {
+	cases := map[string]struct {
+		reason string
+		state  string
+		want   v1alpha1.TestRunPhase
+		active bool
+	}{
+		"Launching": {reason: "A launching run is pending and may generate load.", state: "launching", want: v1alpha1.TestRunPending, active: true},
+		"Running":   {reason: "A running run may generate load.", state: "running", want: v1alpha1.TestRunRunning, active: true},
+		"Done":      {reason: "A finished run no longer generates load.", state: "done", want: v1alpha1.TestRunSucceeded},
+		"Error":     {reason: "A failed run no longer generates load.", state: "error", want: v1alpha1.TestRunFailed},
+		"Aborting":  {reason: "An aborting run is considered aborted.", state: "aborting", want: v1alpha1.TestRunAborted},
+		"Unknown":   {reason: "A run in an unknown state may still generate load.", state: "paused", want: v1alpha1.TestRunUnknown, active: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Of(tc.state)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nOf(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.active, Active(got)); diff != "" {
+				t.Errorf("\n%s\nActive(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestSelect(t *testing.T) {
+	full := Result(&stormforge.RunSummary{Requests: 1200, Errors: 3, LatencyP50: 12 * time.Millisecond, LatencyP95: 80 * time.Millisecond, LatencyP99: 250 * time.Millisecond})
+	count := func(n int64) *int64 { return &n }
+	latency := func(ms int) *metav1.Duration { return &metav1.Duration{Duration: time.Duration(ms) * time.Millisecond} }
+
+	cases := map[string]struct {
+		reason  string
+		r       *v1alpha1.TestRunResult
+		metrics []v1alpha1.ResultMetric
+		want    *v1alpha1.TestRunResult
+	}{
+		"NoResult": {
+			reason:  "A run without a result should have none selected.",
+			metrics: []v1alpha1.ResultMetric{v1alpha1.ResultMetricRequests},
+		},
+		"Default": {
+			reason: "The default metrics should be selected if none are.",
+			r:      full,
+			want:   &v1alpha1.TestRunResult{Requests: count(1200), Errors: count(3), LatencyP95: latency(80)},
+		},
+		"Subset": {
+			reason:  "Only the selected metrics should be kept.",
+			r:       full,
+			metrics: []v1alpha1.ResultMetric{v1alpha1.ResultMetricLatencyP50, v1alpha1.ResultMetricLatencyP99},
+			want:    &v1alpha1.TestRunResult{LatencyP50: latency(12), LatencyP99: latency(250)},
+		},
+		"All": {
+			reason: "Every metric should be kept if all are selected.",
+			r:      full,
+			metrics: []v1alpha1.ResultMetric{
+				v1alpha1.ResultMetricRequests, v1alpha1.ResultMetricErrors,
+				v1alpha1.ResultMetricLatencyP50, v1alpha1.ResultMetricLatencyP95, v1alpha1.ResultMetricLatencyP99,
+			},
+			want: full,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Select(tc.r, tc.metrics)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nSelect(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package slo resolves the SLOs referenced by TestCases and TestRuns into the
+// thresholds StormForge enforces on their runs.
+package slo
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+)
+
+const (
+	errGetSLOFmt     = "cannot get SLO %q"
+	errParseFmt      = "cannot parse %s of SLO %q"
+	errPercentileFmt = "unknown latency percentile %q of SLO %q"
+)
+
+// latencies are the thresholds of each latency percentile.
+var latencies = map[v1alpha1.LatencyPercentile]string{
+	v1alpha1.LatencyP50: stormforge.ThresholdLatencyP50,
+	v1alpha1.LatencyP95: stormforge.ThresholdLatencyP95,
+	v1alpha1.LatencyP99: stormforge.ThresholdLatencyP99,
+}
+
+// Thresholds returns the thresholds of the SLOs the supplied references refer
+// to, or nil if there are none. Where several SLOs set the same threshold the
+// strictest applies; the lowest latency, error rate and Apdex threshold, and
+// the highest Apdex score.
+func Thresholds(ctx context.Context, kube client.Reader, refs ...xpv1.Reference) (map[string]float64, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	t := map[string]float64{}
+	for _, ref := range refs {
+		s := &v1alpha1.SLO{}
+		if err := kube.Get(ctx, types.NamespacedName{Name: ref.Name}, s); err != nil {
+			return nil, errors.Wrapf(err, errGetSLOFmt, ref.Name)
+		}
+		if err := merge(t, s); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// merge the thresholds of the supplied SLO into t.
+func merge(t map[string]float64, s *v1alpha1.SLO) error {
+	for _, l := range s.Spec.Latency {
+		m, ok := latencies[l.Percentile]
+		if !ok {
+			return errors.Errorf(errPercentileFmt, l.Percentile, s.GetName())
+		}
+		atMost(t, m, ms(l.Max.Duration))
+	}
+	if r := s.Spec.MaxErrorRate; r != "" {
+		v, err := strconv.ParseFloat(r, 64)
+		if err != nil {
+			return errors.Wrapf(err, errParseFmt, "maxErrorRate", s.GetName())
+		}
+		atMost(t, stormforge.ThresholdErrorRate, v)
+	}
+	if a := s.Spec.Apdex; a != nil {
+		v, err := strconv.ParseFloat(a.MinScore, 64)
+		if err != nil {
+			return errors.Wrapf(err, errParseFmt, "apdex.minScore", s.GetName())
+		}
+		atMost(t, stormforge.ThresholdApdexT, ms(a.Threshold.Duration))
+		if cur, ok := t[stormforge.ThresholdApdexScore]; !ok || v > cur {
+			t[stormforge.ThresholdApdexScore] = v
+		}
+	}
+	return nil
+}
+
+// atMost sets the threshold of the supplied metric to v, unless it is already
+// lower.
+func atMost(t map[string]float64, metric string, v float64) {
+	if cur, ok := t[metric]; !ok || v < cur {
+		t[metric] = v
+	}
+}
+
+// ms returns the supplied duration in milliseconds.
+func ms(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+// Verdicts returns whether the supplied result met each of the supplied
+// thresholds, ordered by metric. The Apdex threshold T configures the Apdex
+// score rather than bounding a metric, so it has no verdict. The Apdex score
+// is not summarized, so its verdict is unknown, as is that of any metric the
+// result omits.
+func Verdicts(t map[string]float64, r *v1alpha1.TestRunResult) []v1alpha1.SLOVerdict {
+	if len(t) == 0 || r == nil {
+		return nil
+	}
+	actual := map[string]float64{}
+	for m, d := range map[string]*metav1.Duration{
+		stormforge.ThresholdLatencyP50: r.LatencyP50,
+		stormforge.ThresholdLatencyP95: r.LatencyP95,
+		stormforge.ThresholdLatencyP99: r.LatencyP99,
+	} {
+		if d != nil {
+			actual[m] = ms(d.Duration)
+		}
+	}
+	if rate, ok := ErrorRate(r); ok {
+		actual[stormforge.ThresholdErrorRate] = rate
+	}
+	metrics := make([]string, 0, len(t))
+	for m := range t {
+		if m != stormforge.ThresholdApdexT {
+			metrics = append(metrics, m)
+		}
+	}
+	sort.Strings(metrics)
+
+	v := make([]v1alpha1.SLOVerdict, 0, len(metrics))
+	for _, m := range metrics {
+		verdict := v1alpha1.SLOVerdict{Metric: m, Threshold: format(t[m]), Result: v1alpha1.SLOUnknown}
+		if a, ok := actual[m]; ok {
+			verdict.Actual = format(a)
+			verdict.Result = v1alpha1.SLOMet
+			if a > t[m] {
+				verdict.Result = v1alpha1.SLOMissed
+			}
+		}
+		v = append(v, verdict)
+	}
+	return v
+}
+
+// ErrorRate returns the percentage of the requests of the supplied result
+// that failed, and whether the result includes the requests and errors it is
+// computed from.
+func ErrorRate(r *v1alpha1.TestRunResult) (float64, bool) {
+	if r.Requests == nil || r.Errors == nil {
+		return 0, false
+	}
+	if *r.Requests == 0 {
+		return 0, true
+	}
+	return float64(*r.Errors) / float64(*r.Requests) * 100, true
+}
+
+// format returns the supplied value as a decimal string.
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
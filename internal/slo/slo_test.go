@@ -0,0 +1,181 @@
+/*
+Copyright 2020 The Crossplane Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package slo
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"github.com/crossplane/crossplane-runtime/pkg/test"
+
+	"github.com/luebken/provider-stormforge/apis/load/v1alpha1"
+	"github.com/luebken/provider-stormforge/internal/clients/stormforge"
+	"github.com/luebken/provider-stormforge/internal/runphase"
+)
+
+// slos returns a client that serves the supplied SLOs by name.
+func slos(s map[string]v1alpha1.SLOSpec) client.Reader {
+	return &test.MockClient{MockGet: func(_ context.Context, key client.ObjectKey, obj client.Object) error {
+		spec, ok := s[key.Name]
+		if !ok {
+			return errors.New("not found")
+		}
+		obj.SetName(key.Name)
+		obj.(*v1alpha1.SLO).Spec = spec
+		return nil
+	}}
+}
+
+func TestThresholds(t *testing.T) {
+	checkout := v1alpha1.SLOSpec{
+		Latency: []v1alpha1.LatencyObjective{
+			{Percentile: v1alpha1.LatencyP95, Max: metav1.Duration{Duration: 250 * time.Millisecond}},
+			{Percentile: v1alpha1.LatencyP99, Max: metav1.Duration{Duration: time.Second}},
+		},
+		MaxErrorRate: "1",
+		Apdex:        &v1alpha1.ApdexObjective{Threshold: metav1.Duration{Duration: 500 * time.Millisecond}, MinScore: "0.85"},
+	}
+	strict := v1alpha1.SLOSpec{
+		Latency:      []v1alpha1.LatencyObjective{{Percentile: v1alpha1.LatencyP95, Max: metav1.Duration{Duration: 200 * time.Millisecond}}},
+		MaxErrorRate: "0.5",
+		Apdex:        &v1alpha1.ApdexObjective{Threshold: metav1.Duration{Duration: time.Second}, MinScore: "0.9"},
+	}
+
+	_, errParse := strconv.ParseFloat("one", 64)
+
+	type want struct {
+		t   map[string]float64
+		err error
+	}
+
+	cases := map[string]struct {
+		reason string
+		kube   client.Reader
+		refs   []xpv1.Reference
+		want   want
+	}{
+		"NoRefs": {
+			reason: "No thresholds should be returned when no SLOs are referenced.",
+			kube:   slos(nil),
+			want:   want{},
+		},
+		"Single": {
+			reason: "The thresholds of a single SLO should be returned.",
+			kube:   slos(map[string]v1alpha1.SLOSpec{"checkout": checkout}),
+			refs:   []xpv1.Reference{{Name: "checkout"}},
+			want: want{t: map[string]float64{
+				stormforge.ThresholdLatencyP95: 250,
+				stormforge.ThresholdLatencyP99: 1000,
+				stormforge.ThresholdErrorRate:  1,
+				stormforge.ThresholdApdexT:     500,
+				stormforge.ThresholdApdexScore: 0.85,
+			}},
+		},
+		"Strictest": {
+			reason: "Where several SLOs set the same threshold the strictest should apply.",
+			kube:   slos(map[string]v1alpha1.SLOSpec{"checkout": checkout, "strict": strict}),
+			refs:   []xpv1.Reference{{Name: "checkout"}, {Name: "strict"}},
+			want: want{t: map[string]float64{
+				stormforge.ThresholdLatencyP95: 200,
+				stormforge.ThresholdLatencyP99: 1000,
+				stormforge.ThresholdErrorRate:  0.5,
+				stormforge.ThresholdApdexT:     500,
+				stormforge.ThresholdApdexScore: 0.9,
+			}},
+		},
+		"GetError": {
+			reason: "Errors getting a referenced SLO should be wrapped.",
+			kube:   slos(nil),
+			refs:   []xpv1.Reference{{Name: "missing"}},
+			want:   want{err: errors.Wrapf(errors.New("not found"), errGetSLOFmt, "missing")},
+		},
+		"InvalidErrorRate": {
+			reason: "An error rate that is not a number should be rejected.",
+			kube:   slos(map[string]v1alpha1.SLOSpec{"broken": {MaxErrorRate: "one"}}),
+			refs:   []xpv1.Reference{{Name: "broken"}},
+			want:   want{err: errors.Wrapf(errParse, errParseFmt, "maxErrorRate", "broken")},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := Thresholds(context.Background(), tc.kube, tc.refs...)
+			if diff := cmp.Diff(tc.want.err, err, test.EquateErrors()); diff != "" {
+				t.Errorf("\n%s\nThresholds(...): -want error, +got error:\n%s\n", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.t, got); diff != "" {
+				t.Errorf("\n%s\nThresholds(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestVerdicts(t *testing.T) {
+	r := runphase.Result(&stormforge.RunSummary{
+		Requests: 1000, Errors: 5, LatencyP50: 40 * time.Millisecond, LatencyP95: 300 * time.Millisecond, LatencyP99: 900 * time.Millisecond,
+	})
+
+	cases := map[string]struct {
+		reason     string
+		thresholds map[string]float64
+		result     *v1alpha1.TestRunResult
+		want       []v1alpha1.SLOVerdict
+	}{
+		"NoThresholds": {
+			reason: "A run without thresholds should have no verdicts.",
+			result: r,
+		},
+		"NoResult": {
+			reason:     "A run without a result should have no verdicts.",
+			thresholds: map[string]float64{stormforge.ThresholdErrorRate: 1},
+		},
+		"Verdicts": {
+			reason: "Each threshold should be met or missed by its metric, ignoring the Apdex threshold T and leaving the Apdex score unknown.",
+			thresholds: map[string]float64{
+				stormforge.ThresholdLatencyP95: 250,
+				stormforge.ThresholdLatencyP99: 1000,
+				stormforge.ThresholdErrorRate:  0.5,
+				stormforge.ThresholdApdexT:     500,
+				stormforge.ThresholdApdexScore: 0.9,
+			},
+			result: r,
+			want: []v1alpha1.SLOVerdict{
+				{Metric: stormforge.ThresholdApdexScore, Threshold: "0.9", Result: v1alpha1.SLOUnknown},
+				{Metric: stormforge.ThresholdErrorRate, Threshold: "0.5", Actual: "0.5", Result: v1alpha1.SLOMet},
+				{Metric: stormforge.ThresholdLatencyP95, Threshold: "250", Actual: "300", Result: v1alpha1.SLOMissed},
+				{Metric: stormforge.ThresholdLatencyP99, Threshold: "1000", Actual: "900", Result: v1alpha1.SLOMet},
+			},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := Verdicts(tc.thresholds, tc.result)
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("\n%s\nVerdicts(...): -want, +got:\n%s\n", tc.reason, diff)
+			}
+		})
+	}
+}